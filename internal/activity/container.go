@@ -0,0 +1,17 @@
+package activity
+
+import "gorm.io/gorm"
+
+type ActivityContainer struct {
+	Handler *Handler
+	Service Service
+}
+
+func NewActivityContainer(db *gorm.DB) *ActivityContainer {
+	service := NewService(NewRepository(db))
+
+	return &ActivityContainer{
+		Handler: NewHandler(service),
+		Service: service,
+	}
+}
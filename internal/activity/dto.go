@@ -0,0 +1,71 @@
+package activity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidLimit  = errors.New("invalid limit")
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// TimelineQuery pages through a timeline from the newest entry back.
+type TimelineQuery struct {
+	Limit  int
+	Cursor *Cursor
+}
+
+// Cursor is the position after the last entry of a page.
+type Cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+}
+
+type Timeline struct {
+	Items      []*Entry `json:"items"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// ParseTimelineQuery reads the limit and cursor query parameters.
+func ParseTimelineQuery(values url.Values) (*TimelineQuery, error) {
+	q := &TimelineQuery{Limit: DefaultPageSize}
+
+	if v := values.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return nil, ErrInvalidLimit
+		}
+		q.Limit = min(limit, MaxPageSize)
+	}
+
+	if v := values.Get("cursor"); v != "" {
+		b, err := base64.RawURLEncoding.DecodeString(v)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		var c Cursor
+		if err := json.Unmarshal(b, &c); err != nil || c.ID == uuid.Nil {
+			return nil, ErrInvalidCursor
+		}
+		q.Cursor = &c
+	}
+
+	return q, nil
+}
+
+func encodeCursor(e *Entry) string {
+	b, _ := json.Marshal(Cursor{CreatedAt: e.CreatedAt, ID: e.ID})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
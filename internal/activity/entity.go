@@ -0,0 +1,84 @@
+package activity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EntityType names the kind of record an entry is about.
+type EntityType string
+
+const (
+	TaskEntity    EntityType = "task"
+	ProjectEntity EntityType = "project"
+)
+
+// entityPaths maps the plural URL segment to its entity type.
+var entityPaths = map[string]EntityType{
+	"tasks":    TaskEntity,
+	"projects": ProjectEntity,
+}
+
+// EntityFromPath reads the entity type from a URL segment such as "tasks".
+func EntityFromPath(segment string) (EntityType, bool) {
+	t, ok := entityPaths[segment]
+	return t, ok
+}
+
+type Action string
+
+const (
+	Created            Action = "created"
+	Updated            Action = "updated"
+	Commented          Action = "commented"
+	CalendarSynced     Action = "calendar_synced"
+	CalendarSyncFailed Action = "calendar_sync_failed"
+)
+
+// Change is a single field that changed, with its values before and after
+// formatted for display. An empty From means the field was unset.
+type Change struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// Changes collects the fields that differ between two versions of a record.
+type Changes []Change
+
+// Add records the field if the two values differ.
+func (c *Changes) Add(field, from, to string) {
+	if from != to {
+		*c = append(*c, Change{Field: field, From: from, To: to})
+	}
+}
+
+// Entry is a system-generated event in an entity's timeline. Detail holds
+// free text such as a calendar error or a comment excerpt.
+type Entry struct {
+	ID         uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
+	UserID     uuid.UUID  `gorm:"column:user_id;not null" json:"-"`
+	EntityType EntityType `json:"entity_type"`
+	EntityID   uuid.UUID  `gorm:"type:uuid;not null" json:"entity_id"`
+	Action     Action     `json:"action"`
+	Changes    Changes    `gorm:"type:jsonb;serializer:json" json:"changes,omitempty"`
+	Detail     string     `json:"detail,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (Entry) TableName() string {
+	return "activity_log"
+}
+
+func NewEntry(userID uuid.UUID, entityType EntityType, entityID uuid.UUID, action Action, changes Changes) *Entry {
+	return &Entry{
+		ID:         uuid.New(),
+		UserID:     userID,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Changes:    changes,
+		CreatedAt:  time.Now(),
+	}
+}
@@ -0,0 +1,74 @@
+package activity
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/i18n"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+func (h *Handler) UserTimeline(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	q, err := ParseTimelineQuery(r.URL.Query())
+	if err != nil {
+		i18n.Error(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	timeline, err := h.service.UserTimeline(r.Context(), q)
+	if err != nil {
+		if !writeActivityError(w, r, err) {
+			log.WithError(err).Error("Failed to list activity")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, timeline)
+}
+
+func (h *Handler) EntityTimeline(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	q, err := ParseTimelineQuery(r.URL.Query())
+	if err != nil {
+		i18n.Error(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	timeline, err := h.service.EntityTimeline(r.Context(), chi.URLParam(r, "entity"), chi.URLParam(r, "id"), q)
+	if err != nil {
+		if !writeActivityError(w, r, err) {
+			log.WithError(err).Error("Failed to list activity")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, timeline)
+}
+
+func writeActivityError(w http.ResponseWriter, r *http.Request, err error) bool {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrInvalidID):
+		i18n.Error(w, r, "invalid id", http.StatusBadRequest)
+	case errors.Is(err, ErrInvalidEntityType):
+		i18n.Error(w, r, err.Error(), http.StatusBadRequest)
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+package activity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(e *Entry) error
+	// List returns up to limit+1 entries of the user, newest first, the
+	// extra one signalling another page. A nil entityID lists every entity.
+	List(userID uuid.UUID, entityType EntityType, entityID *uuid.UUID, q *TimelineQuery) ([]*Entry, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(e *Entry) error {
+	return r.db.Create(e).Error
+}
+
+func (r *repository) List(userID uuid.UUID, entityType EntityType, entityID *uuid.UUID, q *TimelineQuery) ([]*Entry, error) {
+	query := r.db.Where("user_id = ?", userID)
+	if entityID != nil {
+		query = query.Where("entity_type = ? AND entity_id = ?", entityType, *entityID)
+	}
+	if q.Cursor != nil {
+		query = query.Where("(created_at, id) < (?, ?)", q.Cursor.CreatedAt, q.Cursor.ID)
+	}
+
+	var entries []*Entry
+	err := query.Order("created_at DESC, id DESC").Limit(q.Limit + 1).Find(&entries).Error
+	return entries, err
+}
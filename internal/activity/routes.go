@@ -0,0 +1,14 @@
+package activity
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+func Routes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.UserTimeline)
+	r.Get("/{entity}/{id}", h.EntityTimeline)
+
+	return r
+}
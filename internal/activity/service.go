@@ -0,0 +1,88 @@
+package activity
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+)
+
+var (
+	ErrUnauthorized      = errors.New("unauthorized")
+	ErrInvalidID         = errors.New("invalid id format")
+	ErrInvalidEntityType = errors.New("invalid entity type")
+)
+
+type Service interface {
+	// UserTimeline is everything that happened to the user's tasks and
+	// projects, newest first.
+	UserTimeline(ctx context.Context, q *TimelineQuery) (*Timeline, error)
+	EntityTimeline(ctx context.Context, entityPath, id string, q *TimelineQuery) (*Timeline, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) UserTimeline(ctx context.Context, q *TimelineQuery) (*Timeline, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.timeline(ctx, userID, "", nil, q)
+}
+
+func (s *service) EntityTimeline(ctx context.Context, entityPath, id string, q *TimelineQuery) (*Timeline, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entityType, ok := EntityFromPath(entityPath)
+	if !ok {
+		return nil, ErrInvalidEntityType
+	}
+
+	entityID, err := uuid.Parse(id)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Warnf("Invalid ID: %s", id)
+		return nil, ErrInvalidID
+	}
+
+	return s.timeline(ctx, userID, entityType, &entityID, q)
+}
+
+// ============= Helper Methods =============
+
+func (s *service) timeline(ctx context.Context, userID uuid.UUID, entityType EntityType, entityID *uuid.UUID, q *TimelineQuery) (*Timeline, error) {
+	entries, err := s.repo.List(userID, entityType, entityID, q)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list activity")
+		return nil, err
+	}
+
+	timeline := &Timeline{Items: entries}
+	if len(entries) > q.Limit {
+		timeline.Items = entries[:q.Limit]
+		timeline.NextCursor = encodeCursor(timeline.Items[q.Limit-1])
+	}
+	if timeline.Items == nil {
+		timeline.Items = make([]*Entry, 0)
+	}
+	return timeline, nil
+}
+
+func (s *service) getUserID(ctx context.Context) (uuid.UUID, error) {
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Warn("Unauthorized access attempt")
+		return uuid.Nil, ErrUnauthorized
+	}
+	return uuid.MustParse(claims.UserID), nil
+}
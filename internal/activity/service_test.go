@@ -0,0 +1,143 @@
+package activity_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/activity"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+)
+
+func TestMain(m *testing.M) {
+	config.Init()
+	os.Exit(m.Run())
+}
+
+// fakeActivityRepo pages through in-memory entries the way the SQL query does.
+type fakeActivityRepo struct {
+	entries []*activity.Entry
+}
+
+func (f *fakeActivityRepo) Create(e *activity.Entry) error {
+	f.entries = append(f.entries, e)
+	return nil
+}
+
+func (f *fakeActivityRepo) List(userID uuid.UUID, entityType activity.EntityType, entityID *uuid.UUID, q *activity.TimelineQuery) ([]*activity.Entry, error) {
+	var result []*activity.Entry
+	for _, e := range f.entries {
+		if e.UserID != userID {
+			continue
+		}
+		if entityID != nil && (e.EntityType != entityType || e.EntityID != *entityID) {
+			continue
+		}
+		if c := q.Cursor; c != nil && !(e.CreatedAt.Before(c.CreatedAt) || e.CreatedAt.Equal(c.CreatedAt) && e.ID.String() < c.ID.String()) {
+			continue
+		}
+		result = append(result, e)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].ID.String() > result[j].ID.String()
+	})
+	if len(result) > q.Limit+1 {
+		result = result[:q.Limit+1]
+	}
+	return result, nil
+}
+
+func TestTimeline(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.UserDataKeyID, userID.String())
+	ctx = context.WithValue(ctx, auth.UserDataKeyRole, "user")
+
+	taskID := uuid.New()
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	repo := &fakeActivityRepo{}
+	for i := 0; i < 5; i++ {
+		e := activity.NewEntry(userID, activity.TaskEntity, taskID, activity.Updated, nil)
+		e.CreatedAt = start.Add(time.Duration(i) * time.Minute)
+		repo.Create(e)
+	}
+	repo.Create(activity.NewEntry(userID, activity.ProjectEntity, uuid.New(), activity.Created, nil))
+	repo.Create(activity.NewEntry(uuid.New(), activity.TaskEntity, taskID, activity.Created, nil))
+	svc := activity.NewService(repo)
+
+	t.Run("PagesThroughEntityTimeline", func(t *testing.T) {
+		q, _ := activity.ParseTimelineQuery(url.Values{"limit": {"2"}})
+
+		var seen []*activity.Entry
+		for page := 0; page < 5; page++ {
+			timeline, err := svc.EntityTimeline(ctx, "tasks", taskID.String(), q)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+			seen = append(seen, timeline.Items...)
+			if timeline.NextCursor == "" {
+				break
+			}
+			if q, err = activity.ParseTimelineQuery(url.Values{"limit": {"2"}, "cursor": {timeline.NextCursor}}); err != nil {
+				t.Fatalf("Cursor devolvido deveria ser válido: %v", err)
+			}
+		}
+
+		if len(seen) != 5 {
+			t.Fatalf("Esperadas 5 entradas da tarefa, recebidas %d", len(seen))
+		}
+		for i := 1; i < len(seen); i++ {
+			if !seen[i].CreatedAt.Before(seen[i-1].CreatedAt) {
+				t.Errorf("Linha do tempo deveria vir da mais recente para a mais antiga")
+			}
+		}
+	})
+
+	t.Run("UserTimelineOnlyHasOwnEntries", func(t *testing.T) {
+		q, _ := activity.ParseTimelineQuery(url.Values{})
+		timeline, err := svc.UserTimeline(ctx, q)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if len(timeline.Items) != 6 || timeline.NextCursor != "" {
+			t.Errorf("Esperadas 6 entradas do usuário sem próxima página, recebidas %d (%q)", len(timeline.Items), timeline.NextCursor)
+		}
+	})
+
+	t.Run("RejectsUnknownEntity", func(t *testing.T) {
+		q, _ := activity.ParseTimelineQuery(url.Values{})
+		if _, err := svc.EntityTimeline(ctx, "quizzes", taskID.String(), q); !errors.Is(err, activity.ErrInvalidEntityType) {
+			t.Errorf("Tipo de item desconhecido deveria falhar, erro: %v", err)
+		}
+	})
+}
+
+func TestParseTimelineQuery(t *testing.T) {
+	q, err := activity.ParseTimelineQuery(url.Values{"limit": {"1000"}})
+	if err != nil || q.Limit != activity.MaxPageSize {
+		t.Errorf("Limite deveria ser reduzido para %d, recebido %v (%v)", activity.MaxPageSize, q, err)
+	}
+	if _, err := activity.ParseTimelineQuery(url.Values{"limit": {"0"}}); !errors.Is(err, activity.ErrInvalidLimit) {
+		t.Errorf("Limite zero deveria falhar, erro: %v", err)
+	}
+	if _, err := activity.ParseTimelineQuery(url.Values{"cursor": {"não-é-cursor"}}); !errors.Is(err, activity.ErrInvalidCursor) {
+		t.Errorf("Cursor inválido deveria falhar, erro: %v", err)
+	}
+}
+
+func TestChangesSkipsUnchangedFields(t *testing.T) {
+	var changes activity.Changes
+	changes.Add("status", "TODO", "TODO")
+	changes.Add("priority", "LOW", "HIGH")
+	if len(changes) != 1 || changes[0].Field != "priority" {
+		t.Errorf("Só campos alterados deveriam ser registrados: %+v", changes)
+	}
+}
@@ -1,15 +1,33 @@
 package aiquiz
 
-import "context"
+import (
+	"context"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/quiz"
+	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+)
 
 type AIQuizContainer struct {
 	Handler *Handler
 }
 
-func NewAIQuizContainer() *AIQuizContainer {
+func NewAIQuizContainer(
+	quizService quiz.QuizService,
+	subjectRepo studysubject.StudySubjectRepository,
+	topicRepo studytopic.StudyTopicRepository,
+	taskRepo task.TaskRepository,
+) *AIQuizContainer {
 	ctx := context.Background()
-	provider, _ := NewGeminiProvider(ctx)
-	service := NewService(provider)
+	cfg := ProviderConfigFromEnv()
+	provider, err := NewProvider(ctx, cfg)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Warnf("[AIQUIZ] Provedor %q indisponível, geração de perguntas desativada", cfg.Name)
+		provider = nil
+	}
+	service := NewService(provider, quizService, subjectRepo, topicRepo, taskRepo)
 	handler := NewHandler(service)
 
 	return &AIQuizContainer{
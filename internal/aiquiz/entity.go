@@ -1,5 +1,7 @@
 package aiquiz
 
+import "github.com/google/uuid"
+
 type Question struct {
 	Tema            string   `json:"tema"`
 	Dificuldade     string   `json:"dificuldade"`
@@ -7,6 +9,9 @@ type Question struct {
 	Alternativas    []string `json:"alternativas"`
 	RespostaCorreta string   `json:"resposta_correta"`
 	Explicacao      string   `json:"explicacao"`
+	Fonte           string   `json:"fonte,omitempty"`
+
+	Source *SourceRef `json:"source,omitempty"`
 }
 
 type QuestionRequest struct {
@@ -14,8 +19,28 @@ type QuestionRequest struct {
 	Dificuldade   string `json:"dificuldade"`
 	Quantidade    int    `json:"quantidade"`
 	ContextoProva string `json:"contexto_prova"`
+	Idioma        string `json:"idioma,omitempty"`
+
+	// Material grounds the questions in the user's own content. It is filled
+	// by the service, never by the client.
+	Material []MaterialChunk `json:"-"`
 }
 
 type QuestionResponse struct {
 	Questions []Question `json:"questions"`
 }
+
+// GenerateQuizRequest generates questions for a study subject (optionally
+// narrowed to a topic) and stores them as a playable quiz. When QuizID is set
+// the questions are appended to that quiz instead. FromMaterial grounds the
+// questions in the topic's description, tasks and notes.
+type GenerateQuizRequest struct {
+	SubjectID     uuid.UUID  `json:"subject_id"`
+	StudyTopicID  *uuid.UUID `json:"study_topic_id,omitempty"`
+	QuizID        *uuid.UUID `json:"quiz_id,omitempty"`
+	Dificuldade   string     `json:"dificuldade"`
+	Quantidade    int        `json:"quantidade"`
+	ContextoProva string     `json:"contexto_prova"`
+	Idioma        string     `json:"idioma,omitempty"`
+	FromMaterial  bool       `json:"from_material"`
+}
@@ -0,0 +1,78 @@
+package aiquiz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+var defaultFixtureQuestions = []Question{
+	{
+		Tema:            "fixture",
+		Dificuldade:     "fácil",
+		Pergunta:        "Quanto é 2 + 2?",
+		Alternativas:    []string{"A) 3", "B) 4", "C) 5", "D) 22"},
+		RespostaCorreta: "B",
+		Explicacao:      "A soma de 2 com 2 é 4.",
+	},
+	{
+		Tema:            "fixture",
+		Dificuldade:     "fácil",
+		Pergunta:        "Qual é a capital da França?",
+		Alternativas:    []string{"A) Lisboa", "B) Madri", "C) Paris", "D) Roma"},
+		RespostaCorreta: "C",
+		Explicacao:      "Paris é a capital da França.",
+	},
+	{
+		Tema:            "fixture",
+		Dificuldade:     "médio",
+		Pergunta:        "Qual é a fórmula química da água?",
+		Alternativas:    []string{"A) H2O", "B) CO2", "C) O2", "D) NaCl"},
+		RespostaCorreta: "A",
+		Explicacao:      "A molécula de água tem dois átomos de hidrogênio e um de oxigênio.",
+	},
+}
+
+// fixtureProvider returns a fixed set of questions without touching the
+// network, for tests and local runs.
+type fixtureProvider struct {
+	questions []Question
+}
+
+// NewFixtureProvider loads questions from a JSON file when path is set and
+// falls back to a built-in set otherwise.
+func NewFixtureProvider(path string) (Provider, error) {
+	if path == "" {
+		return &fixtureProvider{questions: defaultFixtureQuestions}, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler fixture de perguntas: %w", err)
+	}
+
+	var questions []Question
+	if err := json.Unmarshal(content, &questions); err != nil {
+		return nil, fmt.Errorf("fixture de perguntas inválida: %w", err)
+	}
+	return &fixtureProvider{questions: questions}, nil
+}
+
+// SendPrompt answers with the fixture questions, cycling through them or
+// cutting them short to match the count recorded in ctx, if any.
+func (p *fixtureProvider) SendPrompt(ctx context.Context, system, user string) (string, error) {
+	questions := p.questions
+	if n, ok := questionCountFrom(ctx); ok && len(questions) > 0 {
+		questions = make([]Question, n)
+		for i := range questions {
+			questions[i] = p.questions[i%len(p.questions)]
+		}
+	}
+
+	raw, err := json.Marshal(questions)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
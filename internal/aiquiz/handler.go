@@ -2,9 +2,13 @@ package aiquiz
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/i18n"
+	"github.com/saulo-duarte/chronos-lambda/internal/quiz"
+	"github.com/sirupsen/logrus"
 )
 
 type Handler struct {
@@ -19,16 +23,105 @@ func (h *Handler) GenerateQuestions(w http.ResponseWriter, r *http.Request) {
 	log := config.WithContext(r.Context())
 	var req QuestionRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
+	if req.Idioma == "" {
+		req.Idioma = i18n.FromContext(r.Context())
+	}
+
 	questions, err := h.service.GenerateQuestions(r.Context(), req)
 	if err != nil {
-		http.Error(w, "failed to generate questions", http.StatusInternalServerError)
-		log.WithError(err).Errorf("Failed to generate questions: %v", err)
+		status := writeGenerationError(w, r, err)
+		if status == 0 {
+			status = http.StatusInternalServerError
+			i18n.Error(w, r, "failed to generate questions", status)
+		}
+		logGenerationError(log, err, status, "Failed to generate questions")
 		return
 	}
 
 	config.JSON(w, http.StatusCreated, questions)
 }
+
+func (h *Handler) GenerateQuiz(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+	var req GenerateQuizRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Idioma == "" {
+		req.Idioma = i18n.FromContext(r.Context())
+	}
+
+	result, err := h.service.GenerateQuiz(r.Context(), req)
+	if err != nil {
+		status := writeGenerationError(w, r, err)
+		if status == 0 {
+			status = writeGenerateQuizError(w, r, err)
+		}
+		logGenerationError(log, err, status, "Failed to generate quiz")
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, result)
+}
+
+// writeGenerateQuizError maps the errors of building a quiz from the
+// user's subjects and topics, and returns the status written.
+func writeGenerateQuizError(w http.ResponseWriter, r *http.Request, err error) int {
+	msg, status := err.Error(), http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ErrUnauthorized), errors.Is(err, quiz.ErrUnauthorized):
+		msg, status = "unauthorized", http.StatusUnauthorized
+	case errors.Is(err, ErrSubjectNotFound), errors.Is(err, ErrStudyTopicNotFound), errors.Is(err, quiz.ErrQuizNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrSubjectRequired), errors.Is(err, ErrStudyTopicRequired):
+		status = http.StatusBadRequest
+	case errors.Is(err, ErrNoStudyMaterial):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, ErrInvalidQuestion), errors.Is(err, ErrNoQuestionsGenerated):
+		status = http.StatusBadGateway
+	default:
+		msg = "failed to generate quiz"
+	}
+	i18n.Error(w, r, msg, status)
+	return status
+}
+
+// writeGenerationError maps errors coming from the model itself. It returns
+// the status written, or zero when err is not one of them.
+func writeGenerationError(w http.ResponseWriter, r *http.Request, err error) int {
+	var refusal *RefusalError
+	var invalid *ValidationError
+
+	switch {
+	case errors.Is(err, ErrProviderNotConfigured):
+		i18n.Error(w, r, "ai provider not configured", http.StatusServiceUnavailable)
+		return http.StatusServiceUnavailable
+	case errors.As(err, &refusal):
+		config.JSON(w, http.StatusUnprocessableEntity, map[string]string{"error": refusal.Message})
+		return http.StatusUnprocessableEntity
+	case errors.As(err, &invalid):
+		config.JSON(w, http.StatusBadGateway, map[string]interface{}{
+			"error":    i18n.T(r.Context(), "model returned invalid questions"),
+			"problems": invalid.Problems,
+		})
+		return http.StatusBadGateway
+	}
+	return 0
+}
+
+// logGenerationError logs requests the client got wrong as warnings and
+// everything else as errors.
+func logGenerationError(log *logrus.Entry, err error, status int, msg string) {
+	entry := log.WithError(err).WithField("status", status)
+	if status < http.StatusInternalServerError {
+		entry.Warn(msg)
+		return
+	}
+	entry.Error(msg)
+}
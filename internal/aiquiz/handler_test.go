@@ -0,0 +1,49 @@
+package aiquiz_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/aiquiz"
+	"github.com/saulo-duarte/chronos-lambda/internal/quiz"
+)
+
+// fakeGenerator fails every quiz generation with err.
+type fakeGenerator struct {
+	aiquiz.Service
+	err error
+}
+
+func (f *fakeGenerator) GenerateQuiz(ctx context.Context, req aiquiz.GenerateQuizRequest) (*quiz.QuizWithQuestionsDTO, error) {
+	return nil, f.err
+}
+
+func TestGenerateQuizHandler(t *testing.T) {
+	cases := map[string]struct {
+		err    error
+		status int
+	}{
+		"MaterialSemTopico": {aiquiz.ErrStudyTopicRequired, http.StatusBadRequest},
+		"TopicoSemMaterial": {aiquiz.ErrNoStudyMaterial, http.StatusUnprocessableEntity},
+		"TopicoInexistente": {aiquiz.ErrStudyTopicNotFound, http.StatusNotFound},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := aiquiz.NewHandler(&fakeGenerator{err: c.err})
+			req := httptest.NewRequest(http.MethodPost, "/quizzes", strings.NewReader(`{"from_material": true}`))
+			rec := httptest.NewRecorder()
+
+			h.GenerateQuiz(rec, req)
+
+			if rec.Code != c.status {
+				t.Errorf("Status esperado %d, recebido %d", c.status, rec.Code)
+			}
+			if got := strings.TrimSpace(rec.Body.String()); got != c.err.Error() {
+				t.Errorf("Mensagem esperada %q, recebida %q", c.err.Error(), got)
+			}
+		})
+	}
+}
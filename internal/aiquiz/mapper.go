@@ -0,0 +1,48 @@
+package aiquiz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/quiz"
+	"gorm.io/datatypes"
+)
+
+var ErrInvalidQuestion = errors.New("pergunta gerada inválida")
+
+// ToQuizQuestions reshapes generated questions into quiz rows. OrderIndex is
+// relative to the batch; the quiz service shifts it when appending.
+func ToQuizQuestions(questions []Question) ([]*quiz.QuizQuestion, error) {
+	result := make([]*quiz.QuizQuestion, 0, len(questions))
+	for i, q := range questions {
+		if strings.TrimSpace(q.Pergunta) == "" || len(q.Alternativas) < 2 || strings.TrimSpace(q.RespostaCorreta) == "" {
+			return nil, fmt.Errorf("%w: pergunta %d incompleta", ErrInvalidQuestion, i+1)
+		}
+
+		options, err := json.Marshal(q.Alternativas)
+		if err != nil {
+			return nil, err
+		}
+
+		qq := &quiz.QuizQuestion{
+			ID:            uuid.New(),
+			Content:       strings.TrimSpace(q.Pergunta),
+			Options:       datatypes.JSON(options),
+			CorrectAnswer: strings.TrimSpace(q.RespostaCorreta),
+			OrderIndex:    i,
+		}
+		if explanation := strings.TrimSpace(q.Explicacao); explanation != "" {
+			qq.Explanation = &explanation
+		}
+		if q.Source != nil {
+			sourceType, sourceID := q.Source.Type, q.Source.ID
+			qq.SourceType = &sourceType
+			qq.SourceID = &sourceID
+		}
+		result = append(result, qq)
+	}
+	return result, nil
+}
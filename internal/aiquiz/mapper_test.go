@@ -0,0 +1,60 @@
+package aiquiz_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/aiquiz"
+)
+
+func TestToQuizQuestions(t *testing.T) {
+	t.Run("MapsFields", func(t *testing.T) {
+		generated := []aiquiz.Question{
+			{
+				Pergunta:        "Qual é a capital da França?",
+				Alternativas:    []string{"A) Lisboa", "B) Madri", "C) Paris", "D) Roma"},
+				RespostaCorreta: "C",
+				Explicacao:      "Paris é a capital da França.",
+			},
+			{
+				Pergunta:        "Quanto é 2 + 2?",
+				Alternativas:    []string{"A) 3", "B) 4"},
+				RespostaCorreta: "B",
+			},
+		}
+
+		questions, err := aiquiz.ToQuizQuestions(generated)
+		if err != nil {
+			t.Fatalf("ToQuizQuestions falhou: %v", err)
+		}
+		if len(questions) != 2 {
+			t.Fatalf("Quantidade incorreta. Esperado: 2, Recebido: %d", len(questions))
+		}
+
+		first := questions[0]
+		if first.Content != generated[0].Pergunta || first.CorrectAnswer != "C" {
+			t.Errorf("Campos mapeados incorretamente: %+v", first)
+		}
+		var options []string
+		if err := json.Unmarshal(first.Options, &options); err != nil || len(options) != 4 {
+			t.Errorf("Alternativas mapeadas incorretamente: %s", first.Options)
+		}
+		if first.Explanation == nil || *first.Explanation != generated[0].Explicacao {
+			t.Error("Explicação deveria ser mapeada")
+		}
+		if questions[1].Explanation != nil {
+			t.Error("Explicação vazia deveria ser nil")
+		}
+		if questions[1].OrderIndex != 1 {
+			t.Errorf("OrderIndex incorreto. Esperado: 1, Recebido: %d", questions[1].OrderIndex)
+		}
+	})
+
+	t.Run("RejectsIncompleteQuestion", func(t *testing.T) {
+		_, err := aiquiz.ToQuizQuestions([]aiquiz.Question{{Pergunta: "Sem alternativas", RespostaCorreta: "A"}})
+		if !errors.Is(err, aiquiz.ErrInvalidQuestion) {
+			t.Errorf("Esperado ErrInvalidQuestion, recebido: %v", err)
+		}
+	})
+}
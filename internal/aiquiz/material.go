@@ -0,0 +1,170 @@
+package aiquiz
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	SourceTopic = "topic"
+	SourceTask  = "task"
+	SourceNote  = "note"
+
+	defaultChunkSize = 1500
+	defaultMaxChunks = 12
+)
+
+// Source is one piece of the user's study material.
+type Source struct {
+	Type    string
+	ID      uuid.UUID
+	Title   string
+	Content string
+}
+
+// SourceRef identifies the material a generated question was grounded in.
+type SourceRef struct {
+	Type  string    `json:"type"`
+	ID    uuid.UUID `json:"id"`
+	Title string    `json:"title"`
+}
+
+// MaterialChunk is a labelled excerpt of a Source that fits in the prompt.
+type MaterialChunk struct {
+	Label  string
+	Source SourceRef
+	Text   string
+}
+
+// ChunkMaterial splits sources into chunks of at most chunkSize runes on
+// paragraph boundaries. When there are more than maxChunks, chunks are taken
+// round-robin so every source is represented.
+func ChunkMaterial(sources []Source, chunkSize, maxChunks int) []MaterialChunk {
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+	if maxChunks <= 0 {
+		maxChunks = defaultMaxChunks
+	}
+
+	perSource := make([][]MaterialChunk, 0, len(sources))
+	for _, src := range sources {
+		ref := SourceRef{Type: src.Type, ID: src.ID, Title: src.Title}
+		var chunks []MaterialChunk
+		for _, text := range splitText(src.Content, chunkSize) {
+			chunks = append(chunks, MaterialChunk{Source: ref, Text: text})
+		}
+		if len(chunks) > 0 {
+			perSource = append(perSource, chunks)
+		}
+	}
+
+	var result []MaterialChunk
+	for round := 0; len(result) < maxChunks; round++ {
+		added := false
+		for _, chunks := range perSource {
+			if round < len(chunks) && len(result) < maxChunks {
+				result = append(result, chunks[round])
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+
+	for i := range result {
+		result[i].Label = fmt.Sprintf("S%d", i+1)
+	}
+	return result
+}
+
+func splitText(content string, size int) []string {
+	var chunks []string
+	var current strings.Builder
+
+	flush := func() {
+		if text := strings.TrimSpace(current.String()); text != "" {
+			chunks = append(chunks, text)
+		}
+		current.Reset()
+	}
+
+	for _, paragraph := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+
+		for _, piece := range splitLong(paragraph, size) {
+			if current.Len() > 0 && runeLen(current.String())+runeLen(piece)+2 > size {
+				flush()
+			}
+			if current.Len() > 0 {
+				current.WriteString("\n\n")
+			}
+			current.WriteString(piece)
+		}
+	}
+	flush()
+
+	return chunks
+}
+
+// splitLong cuts a paragraph longer than size at the last space before the
+// limit, or hard at the limit when there is none.
+func splitLong(text string, size int) []string {
+	var pieces []string
+	runes := []rune(text)
+	for len(runes) > size {
+		cut := size
+		for i := size; i > size/2; i-- {
+			if runes[i] == ' ' || runes[i] == '\n' {
+				cut = i
+				break
+			}
+		}
+		pieces = append(pieces, strings.TrimSpace(string(runes[:cut])))
+		runes = []rune(strings.TrimSpace(string(runes[cut:])))
+	}
+	if len(runes) > 0 {
+		pieces = append(pieces, string(runes))
+	}
+	return pieces
+}
+
+func runeLen(s string) int {
+	return len([]rune(s))
+}
+
+// ValidateSources checks that every question cites one of the chunk labels.
+func ValidateSources(questions []Question, chunks []MaterialChunk) []string {
+	labels := make(map[string]bool, len(chunks))
+	for _, c := range chunks {
+		labels[c.Label] = true
+	}
+
+	var problems []string
+	for i, q := range questions {
+		if !labels[strings.ToUpper(strings.TrimSpace(q.Fonte))] {
+			problems = append(problems, fmt.Sprintf("pergunta %d: \"fonte\" deve ser um dos identificadores do material, recebido %q", i+1, q.Fonte))
+		}
+	}
+	return problems
+}
+
+// resolveSources replaces each question's chunk label with the source it
+// points to.
+func resolveSources(questions []Question, chunks []MaterialChunk) {
+	byLabel := make(map[string]SourceRef, len(chunks))
+	for _, c := range chunks {
+		byLabel[c.Label] = c.Source
+	}
+	for i := range questions {
+		if ref, ok := byLabel[strings.ToUpper(strings.TrimSpace(questions[i].Fonte))]; ok {
+			questions[i].Source = &ref
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package aiquiz_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/aiquiz"
+)
+
+func TestChunkMaterial(t *testing.T) {
+	t.Run("SplitsOnParagraphs", func(t *testing.T) {
+		content := strings.Repeat("a", 60) + "\n\n" + strings.Repeat("b", 60) + "\n\n" + strings.Repeat("c", 30)
+		chunks := aiquiz.ChunkMaterial([]aiquiz.Source{{Type: aiquiz.SourceNote, ID: uuid.New(), Title: "Notas", Content: content}}, 100, 10)
+
+		if len(chunks) != 2 {
+			t.Fatalf("Esperados 2 trechos, recebidos: %d", len(chunks))
+		}
+		if chunks[0].Label != "S1" || chunks[1].Label != "S2" {
+			t.Errorf("Identificadores incorretos: %s, %s", chunks[0].Label, chunks[1].Label)
+		}
+		for _, c := range chunks {
+			if len([]rune(c.Text)) > 100 {
+				t.Errorf("Trecho excede o tamanho máximo: %d", len([]rune(c.Text)))
+			}
+		}
+	})
+
+	t.Run("SplitsLongParagraph", func(t *testing.T) {
+		content := strings.Repeat("palavra ", 50)
+		chunks := aiquiz.ChunkMaterial([]aiquiz.Source{{Type: aiquiz.SourceTopic, Content: content}}, 100, 10)
+
+		if len(chunks) < 4 {
+			t.Errorf("Parágrafo longo deveria ser dividido, recebidos %d trechos", len(chunks))
+		}
+		for _, c := range chunks {
+			if strings.HasPrefix(c.Text, "alavra") {
+				t.Error("Corte deveria acontecer em espaço")
+			}
+		}
+	})
+
+	t.Run("RoundRobinAcrossSources", func(t *testing.T) {
+		big := strings.Repeat("x", 90) + "\n\n" + strings.Repeat("y", 90) + "\n\n" + strings.Repeat("z", 90)
+		task := aiquiz.Source{Type: aiquiz.SourceTask, ID: uuid.New(), Content: "Resolver lista de exercícios"}
+		note := aiquiz.Source{Type: aiquiz.SourceNote, ID: uuid.New(), Content: big}
+
+		chunks := aiquiz.ChunkMaterial([]aiquiz.Source{note, task}, 100, 2)
+		if len(chunks) != 2 {
+			t.Fatalf("Esperados 2 trechos, recebidos: %d", len(chunks))
+		}
+		if chunks[1].Source.Type != aiquiz.SourceTask {
+			t.Error("Todas as fontes deveriam estar representadas")
+		}
+	})
+}
+
+func TestGenerateQuestionsFromMaterial(t *testing.T) {
+	noteID := uuid.New()
+	material := aiquiz.ChunkMaterial([]aiquiz.Source{{Type: aiquiz.SourceNote, ID: noteID, Title: "Resumo", Content: "2 + 2 = 4"}}, 0, 0)
+
+	withoutSource := validOutput
+	withSource := strings.Replace(validOutput, `"explicacao"`, `"fonte":"S1","explicacao"`, 1)
+	provider := &scriptedProvider{answers: []string{withoutSource, withSource}}
+	service := aiquiz.NewService(provider, nil, nil, nil, nil)
+
+	questions, err := service.GenerateQuestions(context.Background(), aiquiz.QuestionRequest{Tema: "matemática", Material: material})
+	if err != nil {
+		t.Fatalf("GenerateQuestions falhou: %v", err)
+	}
+
+	if !strings.Contains(provider.prompts[0], "[S1]") {
+		t.Error("Prompt deveria conter o material identificado")
+	}
+	if len(provider.prompts) != 2 {
+		t.Errorf("Pergunta sem fonte deveria gerar nova tentativa, chamadas: %d", len(provider.prompts))
+	}
+	if questions[0].Source == nil || questions[0].Source.ID != noteID || questions[0].Source.Type != aiquiz.SourceNote {
+		t.Errorf("Fonte não resolvida corretamente: %+v", questions[0].Source)
+	}
+}
@@ -0,0 +1,109 @@
+package aiquiz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+)
+
+// openAIProvider talks to any server implementing the OpenAI chat completions
+// API (OpenAI itself, llama.cpp server, Ollama, vLLM...).
+type openAIProvider struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatCompletionRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature float64       `json:"temperature"`
+}
+
+type chatCompletionResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+func NewOpenAIProvider(baseURL, apiKey, model string, client *http.Client) (Provider, error) {
+	if baseURL == "" {
+		return nil, errors.New("AIQUIZ_BASE_URL é obrigatório para o provedor openai")
+	}
+	if model == "" {
+		return nil, errors.New("AIQUIZ_MODEL é obrigatório para o provedor openai")
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 60 * time.Second}
+	}
+	return &openAIProvider{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		client:  client,
+	}, nil
+}
+
+func (p *openAIProvider) SendPrompt(ctx context.Context, system, user string) (string, error) {
+	log := config.WithContext(ctx)
+
+	body, err := json.Marshal(chatCompletionRequest{
+		Model: p.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: system},
+			{Role: "user", Content: user},
+		},
+		Temperature: 0.7,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		log.WithError(err).Error("falha ao chamar provedor compatível com OpenAI")
+		return "", fmt.Errorf("falha ao gerar conteúdo: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("provedor retornou status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var completion chatCompletionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+		return "", fmt.Errorf("falha ao decodificar resposta do provedor: %w", err)
+	}
+	if len(completion.Choices) == 0 {
+		return "", errors.New("resposta vazia do modelo")
+	}
+
+	raw := completion.Choices[0].Message.Content
+	log.Debugf("[AIQUIZ] Resposta bruta do provedor OpenAI:\n%s", raw)
+
+	return raw, nil
+}
@@ -1,6 +1,11 @@
 package aiquiz
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/i18n"
+)
 
 const systemPrompt = `
 Você é um gerador de perguntas de múltipla escolha educativas para um aplicativo de estudos.
@@ -56,24 +61,162 @@ Diretrizes para qualidade:
   {"erro": "tema inválido, apenas conteúdos educativos são permitidos"}
 `
 
-func BuildUserPrompt(req QuestionRequest) string {
-	qtd := req.Quantidade
-	if qtd <= 0 {
-		qtd = 3
+const systemPromptEN = `
+You are a generator of educational multiple-choice questions for a study app.
+
+Your role is to create **clear, challenging and educational** questions aimed at real learning.
+
+General rules:
+1. Only generate questions about study subjects (e.g. mathematics, physics, chemistry, biology, history, geography, literature, languages, etc.).
+2. Each question must have a **single correct answer**.
+3. Rate the difficulty as **easy**, **medium** or **hard**.
+4. Each question must have:
+   - "descricao": educational context for the question (required)
+   - "detalhamento_usuario": optional extra notes provided by the user
+   - "pergunta": the question statement
+   - "alternativas": 4 plausible options, including the correct one
+   - "resposta_correta": letter of the correct option
+   - "explicacao": short, clear and objective explanation of the correct answer
+
+Keep the JSON keys exactly as shown (in Portuguese) but write every value in English.
+
+Expected JSON format:
+
+[
+  {
+    "tema": "<topic>",
+    "dificuldade": "<easy | medium | hard>",
+    "descricao": "<educational context for the question>",
+    "detalhamento_usuario": "<optional, user notes>",
+    "pergunta": "<question text>",
+    "alternativas": [
+      "A) ...",
+      "B) ...",
+      "C) ...",
+      "D) ..."
+    ],
+    "resposta_correta": "C",
+    "explicacao": "<short, clear and objective explanation of why this option is correct>"
+  }
+]
+
+Quality guidelines:
+- **Do not make the correct answer obvious.**
+  - All options must have similar length and structure.
+  - Avoid making the correct one look longer, more technical or more explanatory.
+  - Use **plausible distractors**: wrong but reasonable answers.
+- **Difficulty:**
+  - Easy → basic concepts or direct definitions.
+  - Medium → applying or interpreting concepts.
+  - Hard → analysis, deduction, connecting ideas or calculations.
+- **Vary the question style** (theoretical, applied, conceptual, analytical or hybrid).
+- Never reveal the answer or explanation in the statement.
+- Explain only in the "explicacao" field.
+- Always produce **pure, valid JSON**, with no text outside the JSON.
+- If the topic is not educational, return:
+  {"erro": "invalid topic, only educational content is allowed"}
+`
+
+// promptTemplate holds every piece of text sent to the model for one
+// language. The JSON keys stay in Portuguese for all languages so parsing and
+// validation do not change.
+type promptTemplate struct {
+	system       string
+	user         string
+	context      string
+	material     string
+	repair       string
+	sourceLabels map[string]string
+}
+
+var promptTemplates = map[string]promptTemplate{
+	i18n.Portuguese: {
+		system: systemPrompt,
+		user: "Gere %d perguntas de múltipla escolha sobre o tema \"%s\" com dificuldade \"%s\". %s" +
+			"As perguntas devem seguir o formato especificado no system prompt, incluindo os campos 'descricao' (obrigatório) e 'detalhamento_usuario' (opcional), com explicação no campo 'explicacao'. " +
+			"As alternativas devem ser plausíveis, e a resposta correta não deve ser óbvia. Use estilo híbrido: contextualizado, direto ou analítico.",
+		context: "Use o seguinte contexto para contextualizar as perguntas: %s. ",
+		material: "Baseie as perguntas exclusivamente no material de estudo abaixo. " +
+			"Cada trecho tem um identificador entre colchetes. " +
+			"Inclua em cada pergunta o campo \"fonte\" com o identificador do trecho usado (ex: \"S1\").",
+		repair: "%s\n\nSua resposta anterior foi:\n%s\n\nEla não passou na validação pelos seguintes motivos:\n- %s\n\n" +
+			"Corrija todos os problemas e responda novamente apenas com o JSON válido, sem texto fora do JSON.",
+		sourceLabels: map[string]string{SourceTopic: "tópico", SourceTask: "tarefa", SourceNote: "anotação"},
+	},
+	i18n.English: {
+		system: systemPromptEN,
+		user: "Generate %d multiple-choice questions about the topic \"%s\" with \"%s\" difficulty. %s" +
+			"The questions must follow the format specified in the system prompt, including the 'descricao' (required) and 'detalhamento_usuario' (optional) fields, with the explanation in the 'explicacao' field. " +
+			"The options must be plausible and the correct answer must not be obvious. Use a hybrid style: contextual, direct or analytical.",
+		context: "Use the following context for the questions: %s. ",
+		material: "Base the questions exclusively on the study material below. " +
+			"Each excerpt has an identifier in square brackets. " +
+			"Include in every question the \"fonte\" field with the identifier of the excerpt used (e.g. \"S1\").",
+		repair: "%s\n\nYour previous answer was:\n%s\n\nIt failed validation for the following reasons:\n- %s\n\n" +
+			"Fix every problem and answer again with valid JSON only, with no text outside the JSON.",
+		sourceLabels: map[string]string{SourceTopic: "topic", SourceTask: "task", SourceNote: "note"},
+	},
+}
+
+// templateFor returns the template for lang, falling back to Portuguese.
+func templateFor(lang string) promptTemplate {
+	if tmpl, ok := promptTemplates[i18n.Normalize(lang)]; ok {
+		return tmpl
 	}
-	if qtd > 10 {
-		qtd = 10
+	return promptTemplates[i18n.Portuguese]
+}
+
+// SystemPrompt returns the system prompt for lang.
+func SystemPrompt(lang string) string {
+	return templateFor(lang).system
+}
+
+// QuestionCount is how many questions the prompt asks for: the requested
+// quantity, 3 by default and at most 10.
+func QuestionCount(req QuestionRequest) int {
+	switch {
+	case req.Quantidade <= 0:
+		return 3
+	case req.Quantidade > 10:
+		return 10
 	}
+	return req.Quantidade
+}
+
+func BuildUserPrompt(req QuestionRequest) string {
+	tmpl := templateFor(req.Idioma)
+
+	qtd := QuestionCount(req)
 
 	contexto := ""
 	if req.ContextoProva != "" {
-		contexto = fmt.Sprintf("Use o seguinte contexto para contextualizar as perguntas: %s. ", req.ContextoProva)
+		contexto = fmt.Sprintf(tmpl.context, req.ContextoProva)
 	}
 
-	return fmt.Sprintf(
-		"Gere %d perguntas de múltipla escolha sobre o tema \"%s\" com dificuldade \"%s\". %s"+
-			"As perguntas devem seguir o formato especificado no system prompt, incluindo os campos 'descricao' (obrigatório) e 'detalhamento_usuario' (opcional), com explicação no campo 'explicacao'. "+
-			"As alternativas devem ser plausíveis, e a resposta correta não deve ser óbvia. Use estilo híbrido: contextualizado, direto ou analítico.",
-		qtd, req.Tema, req.Dificuldade, contexto,
-	)
+	prompt := fmt.Sprintf(tmpl.user, qtd, req.Tema, req.Dificuldade, contexto)
+
+	if len(req.Material) > 0 {
+		prompt += "\n\n" + buildMaterialSection(tmpl, req.Material)
+	}
+
+	return prompt
+}
+
+// BuildRepairPrompt asks the model to fix its previous answer.
+func BuildRepairPrompt(lang, original, previous string, problems []string) string {
+	return fmt.Sprintf(templateFor(lang).repair, original, previous, strings.Join(problems, "\n- "))
+}
+
+func buildMaterialSection(tmpl promptTemplate, chunks []MaterialChunk) string {
+	var b strings.Builder
+	b.WriteString(tmpl.material)
+	b.WriteString("\n\n")
+	for _, c := range chunks {
+		label := tmpl.sourceLabels[c.Source.Type]
+		if label == "" {
+			label = c.Source.Type
+		}
+		fmt.Fprintf(&b, "[%s] (%s: %s)\n%s\n\n", c.Label, label, c.Source.Title, c.Text)
+	}
+	return strings.TrimSpace(b.String())
 }
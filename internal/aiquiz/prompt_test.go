@@ -0,0 +1,30 @@
+package aiquiz_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/aiquiz"
+)
+
+func TestBuildUserPromptLanguage(t *testing.T) {
+	t.Run("DefaultsToPortuguese", func(t *testing.T) {
+		prompt := aiquiz.BuildUserPrompt(aiquiz.QuestionRequest{Tema: "frações", Quantidade: 2})
+		if !strings.HasPrefix(prompt, "Gere 2 perguntas") {
+			t.Errorf("Prompt deveria estar em português: %q", prompt)
+		}
+		if !strings.Contains(aiquiz.SystemPrompt(""), "Você é um gerador") {
+			t.Error("System prompt padrão deveria estar em português")
+		}
+	})
+
+	t.Run("English", func(t *testing.T) {
+		prompt := aiquiz.BuildUserPrompt(aiquiz.QuestionRequest{Tema: "fractions", Quantidade: 2, Idioma: "en-US"})
+		if !strings.HasPrefix(prompt, "Generate 2 multiple-choice questions") {
+			t.Errorf("Prompt deveria estar em inglês: %q", prompt)
+		}
+		if !strings.Contains(aiquiz.SystemPrompt("en"), "You are a generator") {
+			t.Error("System prompt deveria estar em inglês")
+		}
+	})
+}
@@ -2,64 +2,68 @@ package aiquiz
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
 	"google.golang.org/genai"
 )
 
+const defaultGeminiModel = "gemini-2.0-flash"
+
+// Provider sends a prompt to an LLM and returns its raw text answer. Parsing
+// and validation happen in the service so every backend gets the same repair
+// loop.
 type Provider interface {
-	SendPrompt(ctx context.Context, system, user string) ([]Question, error)
+	SendPrompt(ctx context.Context, system, user string) (string, error)
+}
+
+type questionCountKey struct{}
+
+// WithQuestionCount records how many questions the prompts sent with ctx ask
+// for. Models read it from the prompt; offline providers use it to answer
+// with as many questions.
+func WithQuestionCount(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, questionCountKey{}, n)
+}
+
+func questionCountFrom(ctx context.Context) (int, bool) {
+	n, ok := ctx.Value(questionCountKey{}).(int)
+	return n, ok && n > 0
 }
 
 type geminiProvider struct {
 	client *genai.Client
+	model  string
 }
 
-func NewGeminiProvider(ctx context.Context) (Provider, error) {
+func NewGeminiProvider(ctx context.Context, model string) (Provider, error) {
 	client, err := genai.NewClient(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar cliente Gemini: %w", err)
 	}
-	return &geminiProvider{client: client}, nil
+	if model == "" {
+		model = defaultGeminiModel
+	}
+	return &geminiProvider{client: client, model: model}, nil
 }
 
-func (p *geminiProvider) SendPrompt(ctx context.Context, system, user string) ([]Question, error) {
+func (p *geminiProvider) SendPrompt(ctx context.Context, system, user string) (string, error) {
 	log := config.WithContext(ctx)
 	prompt := system + "\n\n" + user
 
 	result, err := p.client.Models.GenerateContent(
 		ctx,
-		"gemini-2.0-flash",
+		p.model,
 		genai.Text(prompt),
 		nil,
 	)
 	if err != nil {
 		log.WithError(err).Error("falha ao gerar conteúdo do Gemini")
-		return nil, fmt.Errorf("falha ao gerar conteúdo: %w", err)
+		return "", fmt.Errorf("falha ao gerar conteúdo: %w", err)
 	}
 
 	raw := result.Text()
 	log.Debugf("[AIQUIZ] Resposta bruta do Gemini:\n%s", raw)
 
-	if raw == "" {
-		return nil, errors.New("resposta vazia do modelo")
-	}
-
-	clean := strings.TrimSpace(raw)
-	clean = strings.TrimPrefix(clean, "```json")
-	clean = strings.TrimSuffix(clean, "```")
-	clean = strings.Trim(clean, "`")
-
-	var questions []Question
-	if err := json.Unmarshal([]byte(clean), &questions); err != nil {
-		log.WithError(err).Errorf("[AIQUIZ] Falha ao decodificar JSON. Conteúdo limpo:\n%s", clean)
-		return nil, fmt.Errorf("falha ao decodificar JSON: %w", err)
-	}
-
-	log.Infof("[AIQUIZ] Geradas %d perguntas com sucesso", len(questions))
-	return questions, nil
+	return raw, nil
 }
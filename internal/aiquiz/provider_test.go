@@ -0,0 +1,141 @@
+package aiquiz_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/aiquiz"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+)
+
+func TestMain(m *testing.M) {
+	config.Init()
+	os.Exit(m.Run())
+}
+
+func TestOpenAIProvider(t *testing.T) {
+	var received struct {
+		Model    string `json:"model"`
+		Messages []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("Caminho incorreto: %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer segredo" {
+			t.Errorf("Authorization incorreto: %q", got)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("Corpo inválido: %v", err)
+		}
+
+		content := "```json\n[{\"pergunta\":\"Quanto é 1 + 1?\",\"alternativas\":[\"A) 1\",\"B) 2\"],\"resposta_correta\":\"B\"}]\n```"
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"role": "assistant", "content": content}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	provider, err := aiquiz.NewOpenAIProvider(server.URL+"/v1/", "segredo", "llama3", server.Client())
+	if err != nil {
+		t.Fatalf("NewOpenAIProvider falhou: %v", err)
+	}
+
+	raw, err := provider.SendPrompt(context.Background(), "sistema", "usuário")
+	if err != nil {
+		t.Fatalf("SendPrompt falhou: %v", err)
+	}
+	questions, err := aiquiz.ParseQuestions(raw)
+	if err != nil {
+		t.Fatalf("ParseQuestions falhou: %v", err)
+	}
+
+	if received.Model != "llama3" || len(received.Messages) != 2 || received.Messages[0].Role != "system" {
+		t.Errorf("Requisição montada incorretamente: %+v", received)
+	}
+	if len(questions) != 1 || questions[0].RespostaCorreta != "B" {
+		t.Errorf("Perguntas decodificadas incorretamente: %+v", questions)
+	}
+}
+
+func TestOpenAIProviderErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "modelo não carregado", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	provider, _ := aiquiz.NewOpenAIProvider(server.URL, "", "llama3", server.Client())
+	if _, err := provider.SendPrompt(context.Background(), "s", "u"); err == nil {
+		t.Error("Esperado erro para status diferente de 200")
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	t.Run("Fixture", func(t *testing.T) {
+		provider, err := aiquiz.NewProvider(context.Background(), aiquiz.ProviderConfig{Name: "fixture"})
+		if err != nil {
+			t.Fatalf("NewProvider falhou: %v", err)
+		}
+
+		first, _ := provider.SendPrompt(context.Background(), "s", "u")
+		second, _ := provider.SendPrompt(context.Background(), "s", "outro")
+		if first == "" || first != second {
+			t.Error("Provedor fixture deveria ser determinístico")
+		}
+
+		questions, err := aiquiz.ParseQuestions(first)
+		if err != nil {
+			t.Fatalf("Fixture deveria ser JSON válido: %v", err)
+		}
+		if problems := aiquiz.ValidateQuestions(questions); len(problems) > 0 {
+			t.Errorf("Fixture deveria passar na validação: %v", problems)
+		}
+	})
+
+	t.Run("FixtureFollowsQuantity", func(t *testing.T) {
+		provider, _ := aiquiz.NewProvider(context.Background(), aiquiz.ProviderConfig{Name: "fixture"})
+
+		for _, n := range []int{1, 5} {
+			ctx := aiquiz.WithQuestionCount(context.Background(), n)
+			raw, err := provider.SendPrompt(ctx, "s", "u")
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+			questions, _ := aiquiz.ParseQuestions(raw)
+			if len(questions) != n {
+				t.Errorf("Esperadas %d perguntas, recebidas %d", n, len(questions))
+			}
+		}
+	})
+
+	t.Run("NotConfigured", func(t *testing.T) {
+		_, err := aiquiz.NewProvider(context.Background(), aiquiz.ProviderConfig{Name: "none"})
+		if !errors.Is(err, aiquiz.ErrProviderNotConfigured) {
+			t.Errorf("Esperado ErrProviderNotConfigured, recebido: %v", err)
+		}
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		_, err := aiquiz.NewProvider(context.Background(), aiquiz.ProviderConfig{Name: "desconhecido"})
+		if !errors.Is(err, aiquiz.ErrUnknownProvider) {
+			t.Errorf("Esperado ErrUnknownProvider, recebido: %v", err)
+		}
+	})
+
+	t.Run("OpenAIRequiresBaseURL", func(t *testing.T) {
+		if _, err := aiquiz.NewProvider(context.Background(), aiquiz.ProviderConfig{Name: "openai", Model: "x"}); err == nil {
+			t.Error("Esperado erro sem AIQUIZ_BASE_URL")
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package aiquiz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrProviderNotConfigured = errors.New("nenhum provedor de IA configurado")
+	ErrUnknownProvider       = errors.New("provedor de IA desconhecido")
+)
+
+// ProviderConfig selects and configures the LLM backend.
+type ProviderConfig struct {
+	Name        string
+	Model       string
+	BaseURL     string
+	APIKey      string
+	FixturePath string
+}
+
+type ProviderFactory func(ctx context.Context, cfg ProviderConfig) (Provider, error)
+
+var providers = map[string]ProviderFactory{
+	"gemini": func(ctx context.Context, cfg ProviderConfig) (Provider, error) {
+		return NewGeminiProvider(ctx, cfg.Model)
+	},
+	"openai": func(ctx context.Context, cfg ProviderConfig) (Provider, error) {
+		return NewOpenAIProvider(cfg.BaseURL, cfg.APIKey, cfg.Model, nil)
+	},
+	"fixture": func(ctx context.Context, cfg ProviderConfig) (Provider, error) {
+		return NewFixtureProvider(cfg.FixturePath)
+	},
+}
+
+// RegisterProvider makes an additional backend selectable by name.
+func RegisterProvider(name string, factory ProviderFactory) {
+	providers[strings.ToLower(name)] = factory
+}
+
+// ProviderConfigFromEnv reads AIQUIZ_PROVIDER (gemini by default),
+// AIQUIZ_MODEL, AIQUIZ_BASE_URL, AIQUIZ_API_KEY and AIQUIZ_FIXTURE_PATH.
+func ProviderConfigFromEnv() ProviderConfig {
+	name := os.Getenv("AIQUIZ_PROVIDER")
+	if name == "" {
+		name = "gemini"
+	}
+	return ProviderConfig{
+		Name:        name,
+		Model:       os.Getenv("AIQUIZ_MODEL"),
+		BaseURL:     os.Getenv("AIQUIZ_BASE_URL"),
+		APIKey:      os.Getenv("AIQUIZ_API_KEY"),
+		FixturePath: os.Getenv("AIQUIZ_FIXTURE_PATH"),
+	}
+}
+
+func NewProvider(ctx context.Context, cfg ProviderConfig) (Provider, error) {
+	name := strings.ToLower(strings.TrimSpace(cfg.Name))
+	if name == "" || name == "none" {
+		return nil, ErrProviderNotConfigured
+	}
+
+	factory, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q (disponíveis: %s)", ErrUnknownProvider, cfg.Name, strings.Join(providerNames(), ", "))
+	}
+	return factory(ctx, cfg)
+}
+
+func providerNames() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
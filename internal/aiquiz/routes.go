@@ -4,11 +4,13 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
 )
 
 func Routes(h *Handler) http.Handler {
 	r := chi.NewRouter()
 
 	r.Post("/", h.GenerateQuestions)
+	r.With(auth.AuthMiddleware).Post("/quizzes", h.GenerateQuiz)
 	return r
 }
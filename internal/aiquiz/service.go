@@ -2,23 +2,246 @@ package aiquiz
 
 import (
 	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/quiz"
+	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+)
+
+var (
+	ErrUnauthorized         = errors.New("unauthorized")
+	ErrSubjectRequired      = errors.New("subject_id ou quiz_id é obrigatório")
+	ErrSubjectNotFound      = errors.New("matéria não encontrada")
+	ErrStudyTopicNotFound   = errors.New("tópico não encontrado")
+	ErrNoQuestionsGenerated = errors.New("o modelo não gerou perguntas")
+	ErrStudyTopicRequired   = errors.New("study_topic_id é obrigatório para gerar a partir do material")
+	ErrNoStudyMaterial      = errors.New("o tópico não possui material de estudo")
 )
 
+// maxRepairAttempts bounds how many times an invalid answer is sent back to
+// the model with the validation errors before giving up.
+const maxRepairAttempts = 2
+
 type Service interface {
 	GenerateQuestions(ctx context.Context, req QuestionRequest) ([]Question, error)
+	GenerateQuiz(ctx context.Context, req GenerateQuizRequest) (*quiz.QuizWithQuestionsDTO, error)
 }
 
 type service struct {
-	provider Provider
+	provider    Provider
+	quizService quiz.QuizService
+	subjectRepo studysubject.StudySubjectRepository
+	topicRepo   studytopic.StudyTopicRepository
+	taskRepo    task.TaskRepository
 }
 
-func NewService(provider Provider) Service {
-	return &service{provider: provider}
+func NewService(
+	provider Provider,
+	quizService quiz.QuizService,
+	subjectRepo studysubject.StudySubjectRepository,
+	topicRepo studytopic.StudyTopicRepository,
+	taskRepo task.TaskRepository,
+) Service {
+	return &service{
+		provider:    provider,
+		quizService: quizService,
+		subjectRepo: subjectRepo,
+		topicRepo:   topicRepo,
+		taskRepo:    taskRepo,
+	}
 }
 
 func (s *service) GenerateQuestions(ctx context.Context, req QuestionRequest) ([]Question, error) {
-	system := systemPrompt
+	if s.provider == nil {
+		return nil, ErrProviderNotConfigured
+	}
+
+	log := config.WithContext(ctx)
+	ctx = WithQuestionCount(ctx, QuestionCount(req))
+	system := SystemPrompt(req.Idioma)
 	user := BuildUserPrompt(req)
+	prompt := user
+
+	var problems []string
+	for attempt := 0; attempt <= maxRepairAttempts; attempt++ {
+		raw, err := s.provider.SendPrompt(ctx, system, prompt)
+		if err != nil {
+			return nil, err
+		}
+		log.Debugf("[AIQUIZ] Resposta bruta (tentativa %d):\n%s", attempt+1, raw)
+
+		questions, err := ParseQuestions(raw)
+		var refusal *RefusalError
+		switch {
+		case errors.As(err, &refusal):
+			log.Warnf("[AIQUIZ] Modelo recusou o tema: %s", refusal.Message)
+			return nil, err
+		case err != nil:
+			problems = []string{err.Error()}
+		default:
+			problems = ValidateQuestions(questions)
+			if len(req.Material) > 0 {
+				problems = append(problems, ValidateSources(questions, req.Material)...)
+			}
+		}
+
+		if len(problems) == 0 {
+			resolveSources(questions, req.Material)
+			log.Infof("[AIQUIZ] Geradas %d perguntas com sucesso", len(questions))
+			return questions, nil
+		}
+
+		log.Warnf("[AIQUIZ] Resposta inválida na tentativa %d: %s", attempt+1, strings.Join(problems, "; "))
+		prompt = BuildRepairPrompt(req.Idioma, user, raw, problems)
+	}
+
+	return nil, &ValidationError{Problems: problems}
+}
+
+func (s *service) GenerateQuiz(ctx context.Context, req GenerateQuizRequest) (*quiz.QuizWithQuestionsDTO, error) {
+	log := config.WithContext(ctx)
+
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+	userID := uuid.MustParse(claims.UserID)
+
+	var existing *quiz.Quiz
+	if req.QuizID != nil {
+		current, err := s.quizService.GetQuizWithQuestions(ctx, req.QuizID.String())
+		if err != nil {
+			return nil, err
+		}
+		if current == nil {
+			return nil, quiz.ErrQuizNotFound
+		}
+		if current.Quiz.UserID != userID {
+			return nil, quiz.ErrQuizNotFound
+		}
+		existing = current.Quiz
+		req.SubjectID = existing.SubjectID
+		if req.StudyTopicID == nil {
+			req.StudyTopicID = existing.StudyTopicID
+		}
+	}
+
+	if req.SubjectID == uuid.Nil {
+		return nil, ErrSubjectRequired
+	}
+
+	subject, err := s.subjectRepo.GetByID(req.SubjectID.String())
+	if err != nil {
+		return nil, err
+	}
+	// Another user's subject or topic reads as missing, as quizzes do.
+	if subject == nil || subject.UserID != userID {
+		return nil, ErrSubjectNotFound
+	}
+
+	if req.FromMaterial && req.StudyTopicID == nil {
+		return nil, ErrStudyTopicRequired
+	}
+
+	tema := subject.Name
+	var material []MaterialChunk
+	if req.StudyTopicID != nil {
+		topic, err := s.topicRepo.GetByID(req.StudyTopicID.String())
+		if err != nil {
+			return nil, err
+		}
+		if topic == nil || topic.StudySubjectID != subject.ID || topic.UserID != userID {
+			return nil, ErrStudyTopicNotFound
+		}
+		tema = subject.Name + ": " + topic.Name
+
+		if req.FromMaterial {
+			material, err = s.collectMaterial(topic)
+			if err != nil {
+				return nil, err
+			}
+			if len(material) == 0 {
+				return nil, ErrNoStudyMaterial
+			}
+			log.Infof("[AIQUIZ] Material do tópico %s dividido em %d trechos", topic.ID, len(material))
+		}
+	}
+
+	generated, err := s.GenerateQuestions(ctx, QuestionRequest{
+		Tema:          tema,
+		Dificuldade:   req.Dificuldade,
+		Quantidade:    req.Quantidade,
+		ContextoProva: req.ContextoProva,
+		Idioma:        req.Idioma,
+		Material:      material,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(generated) == 0 {
+		return nil, ErrNoQuestionsGenerated
+	}
+
+	questions, err := ToQuizQuestions(generated)
+	if err != nil {
+		log.WithError(err).Warn("[AIQUIZ] Perguntas geradas não puderam ser convertidas")
+		return nil, err
+	}
+
+	if existing != nil {
+		return s.quizService.AppendQuestions(ctx, existing.ID.String(), questions)
+	}
+
+	qz := &quiz.Quiz{
+		ID:             uuid.New(),
+		UserID:         userID,
+		SubjectID:      subject.ID,
+		StudyTopicID:   req.StudyTopicID,
+		Topic:          tema,
+		TotalQuestions: len(questions),
+	}
+	for _, q := range questions {
+		q.QuizID = qz.ID
+	}
+
+	if err := s.quizService.CreateQuizWithQuestions(ctx, qz, questions); err != nil {
+		return nil, err
+	}
+
+	log.Infof("[AIQUIZ] Quiz %s criado com %d perguntas geradas", qz.ID, len(questions))
+	return &quiz.QuizWithQuestionsDTO{Quiz: qz, Questions: questions}, nil
+}
+
+// collectMaterial gathers the topic description, the tasks under the topic
+// and the uploaded notes, and chunks them for the prompt.
+func (s *service) collectMaterial(topic *studytopic.StudyTopic) ([]MaterialChunk, error) {
+	var sources []Source
+	if strings.TrimSpace(topic.Description) != "" {
+		sources = append(sources, Source{Type: SourceTopic, ID: topic.ID, Title: topic.Name, Content: topic.Description})
+	}
+
+	tasks, err := s.taskRepo.ListByStudyTopicAndUser(topic.ID, topic.UserID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tasks {
+		content := strings.TrimSpace(t.Name + "\n\n" + t.Description)
+		sources = append(sources, Source{Type: SourceTask, ID: t.ID, Title: t.Name, Content: content})
+	}
+
+	notes, err := s.topicRepo.ListNotes(topic.ID.String(), topic.UserID.String())
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range notes {
+		sources = append(sources, Source{Type: SourceNote, ID: n.ID, Title: n.Title, Content: n.Content})
+	}
 
-	return s.provider.SendPrompt(ctx, system, user)
+	return ChunkMaterial(sources, defaultChunkSize, defaultMaxChunks), nil
 }
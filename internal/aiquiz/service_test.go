@@ -0,0 +1,62 @@
+package aiquiz_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/aiquiz"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+)
+
+type fakeSubjectRepo struct {
+	studysubject.StudySubjectRepository
+	subjects map[string]*studysubject.StudySubject
+}
+
+func (f *fakeSubjectRepo) GetByID(id string) (*studysubject.StudySubject, error) {
+	return f.subjects[id], nil
+}
+
+type fakeTopicRepo struct {
+	studytopic.StudyTopicRepository
+	topics map[string]*studytopic.StudyTopic
+}
+
+func (f *fakeTopicRepo) GetByID(id string) (*studytopic.StudyTopic, error) {
+	return f.topics[id], nil
+}
+
+func TestGenerateQuizHidesOtherUsersData(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.UserDataKeyID, userID.String())
+	ctx = context.WithValue(ctx, auth.UserDataKeyRole, "user")
+
+	own := &studysubject.StudySubject{ID: uuid.New(), UserID: userID, Name: "História"}
+	foreign := &studysubject.StudySubject{ID: uuid.New(), UserID: uuid.New(), Name: "Química"}
+	foreignTopic := &studytopic.StudyTopic{ID: uuid.New(), UserID: uuid.New(), StudySubjectID: own.ID}
+
+	provider, _ := aiquiz.NewFixtureProvider("")
+	svc := aiquiz.NewService(provider, nil,
+		&fakeSubjectRepo{subjects: map[string]*studysubject.StudySubject{own.ID.String(): own, foreign.ID.String(): foreign}},
+		&fakeTopicRepo{topics: map[string]*studytopic.StudyTopic{foreignTopic.ID.String(): foreignTopic}},
+		nil,
+	)
+
+	t.Run("OtherUsersSubject", func(t *testing.T) {
+		_, err := svc.GenerateQuiz(ctx, aiquiz.GenerateQuizRequest{SubjectID: foreign.ID})
+		if !errors.Is(err, aiquiz.ErrSubjectNotFound) {
+			t.Errorf("Matéria de outro usuário deveria parecer inexistente, erro: %v", err)
+		}
+	})
+
+	t.Run("OtherUsersTopic", func(t *testing.T) {
+		_, err := svc.GenerateQuiz(ctx, aiquiz.GenerateQuizRequest{SubjectID: own.ID, StudyTopicID: &foreignTopic.ID})
+		if !errors.Is(err, aiquiz.ErrStudyTopicNotFound) {
+			t.Errorf("Tópico de outro usuário deveria parecer inexistente, erro: %v", err)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package aiquiz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const expectedAlternatives = 4
+
+var optionLetters = []string{"A", "B", "C", "D"}
+
+var ErrEmptyResponse = errors.New("resposta vazia do modelo")
+
+// RefusalError is returned when the model answers with the {"erro": "..."}
+// object the system prompt defines for non-educational topics.
+type RefusalError struct {
+	Message string
+}
+
+func (e *RefusalError) Error() string {
+	return "o modelo recusou a solicitação: " + e.Message
+}
+
+// ValidationError lists every schema problem found in the model's output.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "resposta do modelo inválida: " + strings.Join(e.Problems, "; ")
+}
+
+// ParseQuestions decodes the model's raw answer, tolerating the markdown code
+// fences most models wrap JSON in. A refusal object yields a *RefusalError.
+func ParseQuestions(raw string) ([]Question, error) {
+	clean := strings.TrimSpace(raw)
+	clean = strings.TrimPrefix(clean, "```json")
+	clean = strings.TrimSuffix(clean, "```")
+	clean = strings.TrimSpace(strings.Trim(clean, "`"))
+
+	if clean == "" {
+		return nil, ErrEmptyResponse
+	}
+
+	if strings.HasPrefix(clean, "{") {
+		var refusal struct {
+			Erro string `json:"erro"`
+		}
+		if err := json.Unmarshal([]byte(clean), &refusal); err == nil && refusal.Erro != "" {
+			return nil, &RefusalError{Message: refusal.Erro}
+		}
+	}
+
+	var questions []Question
+	if err := json.Unmarshal([]byte(clean), &questions); err != nil {
+		return nil, fmt.Errorf("falha ao decodificar JSON: %w", err)
+	}
+	return questions, nil
+}
+
+// ValidateQuestions checks each question against the schema described in the
+// system prompt and returns a human-readable list of problems, empty when the
+// output is usable.
+func ValidateQuestions(questions []Question) []string {
+	var problems []string
+	if len(questions) == 0 {
+		return []string{"nenhuma pergunta foi retornada"}
+	}
+
+	for i, q := range questions {
+		prefix := fmt.Sprintf("pergunta %d", i+1)
+
+		if strings.TrimSpace(q.Pergunta) == "" {
+			problems = append(problems, prefix+": campo \"pergunta\" vazio")
+		}
+		if strings.TrimSpace(q.Explicacao) == "" {
+			problems = append(problems, prefix+": campo \"explicacao\" vazio")
+		}
+
+		if len(q.Alternativas) != expectedAlternatives {
+			problems = append(problems, fmt.Sprintf("%s: esperadas %d alternativas, recebidas %d", prefix, expectedAlternatives, len(q.Alternativas)))
+		} else {
+			for j, alt := range q.Alternativas {
+				if !strings.HasPrefix(strings.TrimSpace(alt), optionLetters[j]+")") {
+					problems = append(problems, fmt.Sprintf("%s: alternativa %d deve começar com \"%s)\"", prefix, j+1, optionLetters[j]))
+				}
+			}
+		}
+
+		answer := strings.ToUpper(strings.TrimSpace(q.RespostaCorreta))
+		if !isValidAnswerLetter(answer, len(q.Alternativas)) {
+			problems = append(problems, fmt.Sprintf("%s: \"resposta_correta\" deve ser uma letra entre A e D, recebido %q", prefix, q.RespostaCorreta))
+		}
+	}
+
+	return problems
+}
+
+func isValidAnswerLetter(answer string, alternatives int) bool {
+	for i, letter := range optionLetters {
+		if i >= alternatives {
+			break
+		}
+		if answer == letter {
+			return true
+		}
+	}
+	return false
+}
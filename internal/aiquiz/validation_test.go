@@ -0,0 +1,114 @@
+package aiquiz_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/aiquiz"
+)
+
+const validOutput = `[{"pergunta":"Quanto é 2 + 2?","alternativas":["A) 3","B) 4","C) 5","D) 6"],"resposta_correta":"B","explicacao":"2 + 2 = 4."}]`
+
+// scriptedProvider returns the given answers in order and records prompts.
+type scriptedProvider struct {
+	answers []string
+	prompts []string
+}
+
+func (p *scriptedProvider) SendPrompt(ctx context.Context, system, user string) (string, error) {
+	p.prompts = append(p.prompts, user)
+	answer := p.answers[0]
+	if len(p.answers) > 1 {
+		p.answers = p.answers[1:]
+	}
+	return answer, nil
+}
+
+func TestParseQuestions(t *testing.T) {
+	t.Run("StripsCodeFences", func(t *testing.T) {
+		questions, err := aiquiz.ParseQuestions("```json\n" + validOutput + "\n```")
+		if err != nil || len(questions) != 1 {
+			t.Fatalf("Esperada 1 pergunta, recebido: %v, %v", questions, err)
+		}
+	})
+
+	t.Run("Refusal", func(t *testing.T) {
+		_, err := aiquiz.ParseQuestions(`{"erro": "tema inválido, apenas conteúdos educativos são permitidos"}`)
+		var refusal *aiquiz.RefusalError
+		if !errors.As(err, &refusal) {
+			t.Fatalf("Esperado RefusalError, recebido: %v", err)
+		}
+		if !strings.Contains(refusal.Message, "tema inválido") {
+			t.Errorf("Mensagem da recusa incorreta: %q", refusal.Message)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		if _, err := aiquiz.ParseQuestions("  "); !errors.Is(err, aiquiz.ErrEmptyResponse) {
+			t.Errorf("Esperado ErrEmptyResponse, recebido: %v", err)
+		}
+	})
+}
+
+func TestValidateQuestions(t *testing.T) {
+	questions, _ := aiquiz.ParseQuestions(validOutput)
+	if problems := aiquiz.ValidateQuestions(questions); len(problems) != 0 {
+		t.Fatalf("Pergunta válida rejeitada: %v", problems)
+	}
+
+	invalid := []aiquiz.Question{{
+		Pergunta:        "Pergunta",
+		Alternativas:    []string{"A) 1", "B) 2", "C) 3"},
+		RespostaCorreta: "E",
+		Explicacao:      "",
+	}}
+	problems := aiquiz.ValidateQuestions(invalid)
+	if len(problems) != 3 {
+		t.Errorf("Esperados 3 problemas (alternativas, resposta, explicação), recebidos: %v", problems)
+	}
+}
+
+func TestGenerateQuestionsRepairLoop(t *testing.T) {
+	t.Run("RepairsInvalidOutput", func(t *testing.T) {
+		provider := &scriptedProvider{answers: []string{"não é json", validOutput}}
+		service := aiquiz.NewService(provider, nil, nil, nil, nil)
+
+		questions, err := service.GenerateQuestions(context.Background(), aiquiz.QuestionRequest{Tema: "matemática"})
+		if err != nil {
+			t.Fatalf("GenerateQuestions falhou: %v", err)
+		}
+		if len(questions) != 1 {
+			t.Errorf("Esperada 1 pergunta, recebidas: %d", len(questions))
+		}
+		if len(provider.prompts) != 2 || !strings.Contains(provider.prompts[1], "não passou na validação") {
+			t.Error("Segunda chamada deveria conter os erros de validação")
+		}
+	})
+
+	t.Run("GivesUpAfterBoundedAttempts", func(t *testing.T) {
+		provider := &scriptedProvider{answers: []string{`[{"pergunta":"x"}]`}}
+		service := aiquiz.NewService(provider, nil, nil, nil, nil)
+
+		_, err := service.GenerateQuestions(context.Background(), aiquiz.QuestionRequest{Tema: "matemática"})
+		var invalid *aiquiz.ValidationError
+		if !errors.As(err, &invalid) {
+			t.Fatalf("Esperado ValidationError, recebido: %v", err)
+		}
+		if len(provider.prompts) != 3 {
+			t.Errorf("Esperadas 3 chamadas ao modelo, recebidas: %d", len(provider.prompts))
+		}
+	})
+
+	t.Run("RefusalIsNotRetried", func(t *testing.T) {
+		provider := &scriptedProvider{answers: []string{`{"erro": "tema inválido"}`}}
+		service := aiquiz.NewService(provider, nil, nil, nil, nil)
+
+		_, err := service.GenerateQuestions(context.Background(), aiquiz.QuestionRequest{Tema: "futebol"})
+		var refusal *aiquiz.RefusalError
+		if !errors.As(err, &refusal) || len(provider.prompts) != 1 {
+			t.Errorf("Recusa deveria retornar imediatamente, recebido: %v após %d chamadas", err, len(provider.prompts))
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/saulo-duarte/chronos-lambda/internal/user"
+	"gorm.io/gorm"
 )
 
 type AnnualGoal struct {
@@ -17,4 +18,5 @@ type AnnualGoal struct {
 	User        user.User        `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-"`
 	CreatedAt   time.Time        `json:"created_at"`
 	UpdatedAt   time.Time        `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt   `gorm:"index" json:"-"`
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/saulo-duarte/chronos-lambda/internal/auth"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/i18n"
 )
 
 type Handler struct {
@@ -24,14 +25,14 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	claims, err := auth.GetUserClaimsFromContext(r.Context())
 	if err != nil {
 		log.Warn("User not authenticated")
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
 		return
 	}
 
 	var dto CreateAnnualGoalDTO
 	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
 		log.WithError(err).Error("Invalid request body")
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
@@ -39,7 +40,7 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	response, err := h.service.Create(userID, dto)
 	if err != nil {
 		log.WithError(err).Error("Failed to create annual goal")
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
@@ -52,7 +53,7 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 	claims, err := auth.GetUserClaimsFromContext(r.Context())
 	if err != nil {
 		log.Warn("User not authenticated")
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
 		return
 	}
 
@@ -60,7 +61,7 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 	responses, err := h.service.List(userID)
 	if err != nil {
 		log.WithError(err).Error("Failed to list annual goals")
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
@@ -73,25 +74,25 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	claims, err := auth.GetUserClaimsFromContext(r.Context())
 	if err != nil {
 		log.Warn("User not authenticated")
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
 		return
 	}
 
 	idStr := chi.URLParam(r, "id")
 	if idStr == "" {
-		http.Error(w, "id required", http.StatusBadRequest)
+		i18n.Error(w, r, "id required", http.StatusBadRequest)
 		return
 	}
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		http.Error(w, "invalid id", http.StatusBadRequest)
+		i18n.Error(w, r, "invalid id", http.StatusBadRequest)
 		return
 	}
 
 	var dto UpdateAnnualGoalDTO
 	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
 		log.WithError(err).Error("Invalid request body")
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
@@ -99,11 +100,11 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	response, err := h.service.Update(id, userID, dto)
 	if err != nil {
 		if err.Error() == "unauthorized" {
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
 			return
 		}
 		log.WithError(err).Error("Failed to update annual goal")
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
@@ -116,29 +117,29 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	claims, err := auth.GetUserClaimsFromContext(r.Context())
 	if err != nil {
 		log.Warn("User not authenticated")
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
 		return
 	}
 
 	idStr := chi.URLParam(r, "id")
 	if idStr == "" {
-		http.Error(w, "id required", http.StatusBadRequest)
+		i18n.Error(w, r, "id required", http.StatusBadRequest)
 		return
 	}
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		http.Error(w, "invalid id", http.StatusBadRequest)
+		i18n.Error(w, r, "invalid id", http.StatusBadRequest)
 		return
 	}
 
 	userID := uuid.MustParse(claims.UserID)
 	if err := h.service.Delete(id, userID); err != nil {
 		if err.Error() == "unauthorized" {
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
 			return
 		}
 		log.WithError(err).Error("Failed to delete annual goal")
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
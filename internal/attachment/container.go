@@ -0,0 +1,47 @@
+package attachment
+
+import (
+	"context"
+	"os"
+	"strconv"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/storage"
+	"gorm.io/gorm"
+)
+
+type AttachmentContainer struct {
+	Handler *Handler
+	Service Service
+}
+
+func NewAttachmentContainer(db *gorm.DB) *AttachmentContainer {
+	ctx := context.Background()
+	cfg := storage.ConfigFromEnv()
+	store, err := storage.New(ctx, cfg)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Warnf("Storage backend %q unavailable, attachments disabled", cfg.Backend)
+		store = nil
+	}
+
+	limits := limitsFromEnv()
+	service := NewService(NewRepository(db), store, limits)
+
+	return &AttachmentContainer{
+		Handler: NewHandler(service, limits.MaxFileSize),
+		Service: service,
+	}
+}
+
+// limitsFromEnv reads ATTACHMENT_MAX_FILE_MB and ATTACHMENT_QUOTA_MB,
+// falling back to the defaults when unset or invalid.
+func limitsFromEnv() Limits {
+	limits := Limits{MaxFileSize: DefaultMaxFileSize, Quota: DefaultQuota}
+	if mb, err := strconv.ParseInt(os.Getenv("ATTACHMENT_MAX_FILE_MB"), 10, 64); err == nil && mb > 0 {
+		limits.MaxFileSize = mb << 20
+	}
+	if mb, err := strconv.ParseInt(os.Getenv("ATTACHMENT_QUOTA_MB"), 10, 64); err == nil && mb > 0 {
+		limits.Quota = mb << 20
+	}
+	return limits
+}
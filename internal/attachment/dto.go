@@ -0,0 +1,157 @@
+package attachment
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/storage"
+)
+
+const (
+	DefaultMaxFileSize = 25 << 20
+	DefaultQuota       = 500 << 20
+	maxFileNameLength  = 255
+)
+
+// Limits are the per-file and per-user storage limits, in bytes.
+type Limits struct {
+	MaxFileSize int64
+	Quota       int64
+}
+
+// allowedTypes lists the accepted content types with the type
+// http.DetectContentType must report for the file's first bytes. An empty
+// value means the format cannot be told apart by sniffing.
+var allowedTypes = map[string]string{
+	"application/pdf":               "application/pdf",
+	"image/png":                     "image/png",
+	"image/jpeg":                    "image/jpeg",
+	"image/gif":                     "image/gif",
+	"image/webp":                    "image/webp",
+	"application/vnd.ms-powerpoint": "",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": "application/zip",
+	"application/msword": "",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": "application/zip",
+	"text/plain":    "text/plain",
+	"text/markdown": "text/plain",
+}
+
+// extensionTypes is used when the client sends no useful content type.
+var extensionTypes = map[string]string{
+	".pdf":  "application/pdf",
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".ppt":  "application/vnd.ms-powerpoint",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".doc":  "application/msword",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".txt":  "text/plain",
+	".md":   "text/markdown",
+}
+
+// UploadDTO asks for a pre-signed URL to upload a file directly to storage.
+type UploadDTO struct {
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// Upload is a file streamed through the API.
+type Upload struct {
+	FileName    string
+	ContentType string
+	Size        int64
+	Body        io.Reader
+}
+
+// PendingUpload is the reserved attachment and the request the client sends
+// to storage before completing it.
+type PendingUpload struct {
+	Attachment *Attachment               `json:"attachment"`
+	Upload     *storage.PresignedRequest `json:"upload"`
+}
+
+// Download holds either a pre-signed URL or the blob itself, depending on
+// the storage backend.
+type Download struct {
+	Attachment *Attachment
+	URL        string
+	Body       io.ReadCloser
+}
+
+type Usage struct {
+	UsedBytes    int64 `json:"used_bytes"`
+	QuotaBytes   int64 `json:"quota_bytes"`
+	MaxFileBytes int64 `json:"max_file_bytes"`
+}
+
+// validateFile checks the name, type and size of a new file and returns the
+// cleaned name and content type.
+func validateFile(fileName, contentType string, size int64, limits Limits) (string, string, error) {
+	name := cleanFileName(fileName)
+	if name == "" {
+		return "", "", ErrInvalidFileName
+	}
+	if size <= 0 {
+		return "", "", ErrEmptyFile
+	}
+	if size > limits.MaxFileSize {
+		return "", "", ErrFileTooLarge
+	}
+
+	contentType = normalizeContentType(contentType, name)
+	if _, ok := allowedTypes[contentType]; !ok {
+		return "", "", ErrUnsupportedType
+	}
+	return name, contentType, nil
+}
+
+// cleanFileName keeps the last path element and drops control characters.
+func cleanFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name))
+	if name == "." || name == "/" || name == ".." || len([]rune(name)) > maxFileNameLength {
+		return ""
+	}
+	return name
+}
+
+func normalizeContentType(contentType, fileName string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = strings.ToLower(mediaType)
+	}
+	if contentType == "" || contentType == "application/octet-stream" {
+		return extensionTypes[strings.ToLower(path.Ext(fileName))]
+	}
+	return contentType
+}
+
+// sniffMatches reports whether the first bytes of a file are plausible for
+// its declared type.
+func sniffMatches(contentType string, head []byte) bool {
+	expected := allowedTypes[contentType]
+	if expected == "" {
+		return true
+	}
+	return strings.HasPrefix(http.DetectContentType(head), expected)
+}
+
+// pendingUploadTTL is how long a reserved upload may stay incomplete before
+// the cleanup job drops it.
+const pendingUploadTTL = 24 * time.Hour
+
+// presignExpiry is how long pre-signed upload and download URLs stay valid.
+const presignExpiry = 15 * time.Minute
@@ -0,0 +1,67 @@
+package attachment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OwnerType string
+
+const (
+	TaskOwner       OwnerType = "task"
+	StudyTopicOwner OwnerType = "study-topic"
+)
+
+type Status string
+
+const (
+	// Pending attachments have space reserved but their blob is not
+	// confirmed yet; only Ready ones are listed and downloadable.
+	Pending Status = "pending"
+	Ready   Status = "ready"
+)
+
+type owner struct {
+	table  string
+	column string
+	// notTrashed excludes owners in the trash, themselves or through their
+	// parent. They keep their attachments until purged but take no new ones.
+	notTrashed string
+}
+
+var owners = map[OwnerType]owner{
+	TaskOwner: {table: "tasks", column: "task_id", notTrashed: "deleted_at IS NULL"},
+	StudyTopicOwner: {
+		table:      "study_topics",
+		column:     "study_topic_id",
+		notTrashed: "NOT EXISTS (SELECT 1 FROM study_subjects s WHERE s.id = study_topics.subject_id AND s.deleted_at IS NOT NULL)",
+	},
+}
+
+// ownerPaths maps the plural URL segment to the owner type.
+var ownerPaths = map[string]OwnerType{
+	"tasks":        TaskOwner,
+	"study-topics": StudyTopicOwner,
+}
+
+// Attachment is a file kept in the configured storage. The row is removed
+// with its task or study topic through the foreign key, and a trigger then
+// queues the blob for deletion.
+type Attachment struct {
+	ID           uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
+	UserID       uuid.UUID  `gorm:"column:user_id;not null" json:"-"`
+	TaskID       *uuid.UUID `gorm:"type:uuid" json:"task_id,omitempty"`
+	StudyTopicID *uuid.UUID `gorm:"type:uuid" json:"study_topic_id,omitempty"`
+	FileName     string     `json:"file_name"`
+	ContentType  string     `json:"content_type"`
+	Size         int64      `json:"size"`
+	StorageKey   string     `json:"-"`
+	Status       Status     `json:"status"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func storageKey(userID, id uuid.UUID) string {
+	return "attachments/" + userID.String() + "/" + id.String()
+}
@@ -0,0 +1,200 @@
+package attachment
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/i18n"
+)
+
+type Handler struct {
+	service     Service
+	maxFileSize int64
+}
+
+func NewHandler(s Service, maxFileSize int64) *Handler {
+	return &Handler{service: s, maxFileSize: maxFileSize}
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	attachments, err := h.service.ListAttachments(r.Context(), chi.URLParam(r, "owner"), chi.URLParam(r, "ownerID"))
+	if err != nil {
+		if !writeAttachmentError(w, r, err) {
+			log.WithError(err).Error("Failed to list attachments")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, attachments)
+}
+
+// Upload stores the multipart "file" field. Large files should use
+// CreateUpload where the storage supports it, since API Gateway limits
+// request bodies.
+func (h *Handler) Upload(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxFileSize+64*1024)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			i18n.Error(w, r, ErrFileTooLarge.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		log.WithError(err).Warn("Attachment file not provided")
+		i18n.Error(w, r, "file required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	a, err := h.service.Upload(r.Context(), chi.URLParam(r, "owner"), chi.URLParam(r, "ownerID"), &Upload{
+		FileName:    header.Filename,
+		ContentType: header.Header.Get("Content-Type"),
+		Size:        header.Size,
+		Body:        file,
+	})
+	if err != nil {
+		if !writeAttachmentError(w, r, err) {
+			log.WithError(err).Error("Failed to upload attachment")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, a)
+}
+
+func (h *Handler) CreateUpload(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload UploadDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	pending, err := h.service.CreateUpload(r.Context(), chi.URLParam(r, "owner"), chi.URLParam(r, "ownerID"), &payload)
+	if err != nil {
+		if !writeAttachmentError(w, r, err) {
+			log.WithError(err).Error("Failed to create attachment upload")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, pending)
+}
+
+func (h *Handler) CompleteUpload(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	a, err := h.service.CompleteUpload(r.Context(), chi.URLParam(r, "attachmentID"))
+	if err != nil {
+		if !writeAttachmentError(w, r, err) {
+			log.WithError(err).Error("Failed to complete attachment upload")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, a)
+}
+
+// Download redirects to a pre-signed URL when the storage has one and
+// streams the file otherwise.
+func (h *Handler) Download(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	download, err := h.service.Download(r.Context(), chi.URLParam(r, "attachmentID"))
+	if err != nil {
+		if !writeAttachmentError(w, r, err) {
+			log.WithError(err).Error("Failed to download attachment")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if download.URL != "" {
+		http.Redirect(w, r, download.URL, http.StatusFound)
+		return
+	}
+	defer download.Body.Close()
+
+	a := download.Attachment
+	w.Header().Set("Content-Type", a.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(a.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, download.Body); err != nil {
+		log.WithError(err).Warn("Attachment download interrupted")
+	}
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	if err := h.service.DeleteAttachment(r.Context(), chi.URLParam(r, "attachmentID")); err != nil {
+		if !writeAttachmentError(w, r, err) {
+			log.WithError(err).Error("Failed to delete attachment")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) Usage(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	usage, err := h.service.Usage(r.Context())
+	if err != nil {
+		if !writeAttachmentError(w, r, err) {
+			log.WithError(err).Error("Failed to get storage usage")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, usage)
+}
+
+func writeAttachmentError(w http.ResponseWriter, r *http.Request, err error) bool {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrInvalidID):
+		i18n.Error(w, r, "invalid id", http.StatusBadRequest)
+	case errors.Is(err, ErrAttachmentNotFound), errors.Is(err, ErrOwnerNotFound):
+		i18n.Error(w, r, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrInvalidOwner),
+		errors.Is(err, ErrInvalidFileName),
+		errors.Is(err, ErrEmptyFile),
+		errors.Is(err, ErrContentMismatch):
+		i18n.Error(w, r, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrFileTooLarge):
+		i18n.Error(w, r, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, ErrUnsupportedType):
+		i18n.Error(w, r, err.Error(), http.StatusUnsupportedMediaType)
+	case errors.Is(err, ErrQuotaExceeded), errors.Is(err, ErrUploadIncomplete):
+		i18n.Error(w, r, err.Error(), http.StatusConflict)
+	case errors.Is(err, ErrDirectUploadUnsupported):
+		i18n.Error(w, r, err.Error(), http.StatusNotImplemented)
+	case errors.Is(err, ErrStorageUnavailable):
+		i18n.Error(w, r, err.Error(), http.StatusServiceUnavailable)
+	default:
+		return false
+	}
+	return true
+}
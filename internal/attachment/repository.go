@@ -0,0 +1,139 @@
+package attachment
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrNotFound = errors.New("attachment not found")
+
+type Repository interface {
+	// Reserve records a pending attachment unless the user's attachments
+	// would then exceed quota bytes, in which case it returns
+	// ErrQuotaExceeded. Reservations for the same user are serialized.
+	Reserve(a *Attachment, quota int64) error
+	MarkReady(id uuid.UUID, now time.Time) error
+	// Delete removes the attachment; its blob is queued for deletion.
+	Delete(id, userID uuid.UUID) error
+	FindByIDAndUser(id, userID uuid.UUID) (*Attachment, error)
+	// ListByOwner returns the ready attachments of a task or study topic,
+	// oldest first.
+	ListByOwner(t OwnerType, ownerID, userID uuid.UUID) ([]*Attachment, error)
+	// OwnsOwner reports whether the task or study topic exists, is not in
+	// the trash and belongs to the user.
+	OwnsOwner(t OwnerType, id, userID uuid.UUID) (bool, error)
+	// UsedBytes is the size of all the user's attachments, pending ones
+	// included since their space is reserved.
+	UsedBytes(userID uuid.UUID) (int64, error)
+	DeletePendingBefore(cutoff time.Time) (int64, error)
+
+	// ListBlobDeletions returns up to limit storage keys whose attachment
+	// is gone.
+	ListBlobDeletions(limit int) ([]string, error)
+	ForgetBlobDeletions(keys []string) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Reserve(a *Attachment, quota int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Locking the user row makes concurrent uploads wait for each
+		// other's usage check and insert.
+		if err := tx.Exec("SELECT 1 FROM users WHERE id = ? FOR UPDATE", a.UserID).Error; err != nil {
+			return err
+		}
+		used, err := usedBytes(tx, a.UserID)
+		if err != nil {
+			return err
+		}
+		if used+a.Size > quota {
+			return ErrQuotaExceeded
+		}
+		return tx.Create(a).Error
+	})
+}
+
+func (r *repository) MarkReady(id uuid.UUID, now time.Time) error {
+	return r.db.Model(&Attachment{}).Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"status": Ready, "updated_at": now}).Error
+}
+
+func (r *repository) Delete(id, userID uuid.UUID) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&Attachment{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *repository) FindByIDAndUser(id, userID uuid.UUID) (*Attachment, error) {
+	var a Attachment
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&a).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *repository) ListByOwner(t OwnerType, ownerID, userID uuid.UUID) ([]*Attachment, error) {
+	var attachments []*Attachment
+	err := r.db.Where(owners[t].column+" = ? AND user_id = ? AND status = ?", ownerID, userID, Ready).
+		Order("created_at ASC, id ASC").
+		Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *repository) OwnsOwner(t OwnerType, id, userID uuid.UUID) (bool, error) {
+	o := owners[t]
+	var count int64
+	err := r.db.Table(o.table).
+		Where("id = ? AND user_id = ?", id, userID).
+		Where(o.notTrashed).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *repository) UsedBytes(userID uuid.UUID) (int64, error) {
+	return usedBytes(r.db, userID)
+}
+
+func usedBytes(db *gorm.DB, userID uuid.UUID) (int64, error) {
+	var used int64
+	err := db.Model(&Attachment{}).Where("user_id = ?", userID).
+		Select("COALESCE(SUM(size), 0)").Scan(&used).Error
+	return used, err
+}
+
+func (r *repository) DeletePendingBefore(cutoff time.Time) (int64, error) {
+	result := r.db.Where("status = ? AND created_at < ?", Pending, cutoff).Delete(&Attachment{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *repository) ListBlobDeletions(limit int) ([]string, error) {
+	var keys []string
+	err := r.db.Table("attachment_blob_deletions").
+		Order("created_at").Limit(limit).
+		Pluck("storage_key", &keys).Error
+	return keys, err
+}
+
+func (r *repository) ForgetBlobDeletions(keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return r.db.Exec("DELETE FROM attachment_blob_deletions WHERE storage_key IN ?", keys).Error
+}
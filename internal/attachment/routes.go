@@ -0,0 +1,19 @@
+package attachment
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+func Routes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/usage", h.Usage)
+	r.Get("/{owner}/{ownerID}", h.List)
+	r.Post("/{owner}/{ownerID}", h.Upload)
+	r.Post("/{owner}/{ownerID}/uploads", h.CreateUpload)
+	r.Post("/{attachmentID}/complete", h.CompleteUpload)
+	r.Get("/{attachmentID}/download", h.Download)
+	r.Delete("/{attachmentID}", h.Delete)
+
+	return r
+}
@@ -0,0 +1,488 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/storage"
+)
+
+var (
+	ErrUnauthorized            = errors.New("unauthorized")
+	ErrInvalidID               = errors.New("invalid id format")
+	ErrAttachmentNotFound      = ErrNotFound
+	ErrInvalidOwner            = errors.New("invalid attachment owner")
+	ErrOwnerNotFound           = errors.New("attached item not found")
+	ErrInvalidFileName         = errors.New("invalid file name")
+	ErrEmptyFile               = errors.New("file is empty")
+	ErrFileTooLarge            = errors.New("file is too large")
+	ErrUnsupportedType         = errors.New("file type not allowed")
+	ErrContentMismatch         = errors.New("file content does not match its type")
+	ErrQuotaExceeded           = errors.New("storage quota exceeded")
+	ErrDirectUploadUnsupported = errors.New("direct uploads are not supported by this storage")
+	ErrUploadIncomplete        = errors.New("upload not completed")
+	ErrStorageUnavailable      = errors.New("file storage is unavailable")
+)
+
+// blobDeletionBatch is how many queued blobs PurgeBlobs deletes per round.
+const blobDeletionBatch = 100
+
+type Service interface {
+	ListAttachments(ctx context.Context, ownerPath, ownerID string) ([]*Attachment, error)
+	// Upload streams a file through the API into storage.
+	Upload(ctx context.Context, ownerPath, ownerID string, file *Upload) (*Attachment, error)
+	// CreateUpload reserves an attachment and returns a pre-signed request
+	// to upload it directly to storage; CompleteUpload confirms it.
+	CreateUpload(ctx context.Context, ownerPath, ownerID string, dto *UploadDTO) (*PendingUpload, error)
+	CompleteUpload(ctx context.Context, id string) (*Attachment, error)
+	Download(ctx context.Context, id string) (*Download, error)
+	DeleteAttachment(ctx context.Context, id string) error
+	Usage(ctx context.Context) (*Usage, error)
+	// PurgeBlobs drops abandoned uploads and deletes the blobs of
+	// attachments removed directly or along with their task or study topic.
+	PurgeBlobs(ctx context.Context) error
+}
+
+type service struct {
+	repo    Repository
+	storage storage.Storage
+	limits  Limits
+	now     func() time.Time
+}
+
+// NewService builds the service; a nil store disables uploads and downloads.
+func NewService(repo Repository, store storage.Storage, limits Limits) Service {
+	if limits.MaxFileSize <= 0 {
+		limits.MaxFileSize = DefaultMaxFileSize
+	}
+	if limits.Quota <= 0 {
+		limits.Quota = DefaultQuota
+	}
+	return &service{
+		repo:    repo,
+		storage: store,
+		limits:  limits,
+		now:     time.Now,
+	}
+}
+
+func (s *service) ListAttachments(ctx context.Context, ownerPath, ownerID string) ([]*Attachment, error) {
+	userID, ownerType, id, err := s.resolveOwner(ctx, ownerPath, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.repo.ListByOwner(ownerType, id, userID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list attachments")
+		return nil, err
+	}
+	if attachments == nil {
+		attachments = make([]*Attachment, 0)
+	}
+	return attachments, nil
+}
+
+func (s *service) Upload(ctx context.Context, ownerPath, ownerID string, file *Upload) (*Attachment, error) {
+	if s.storage == nil {
+		return nil, ErrStorageUnavailable
+	}
+
+	userID, ownerType, id, err := s.resolveOwner(ctx, ownerPath, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, contentType, err := validateFile(file.FileName, file.ContentType, file.Size, s.limits)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := readHead(file.Body)
+	if err != nil {
+		return nil, err
+	}
+	if !sniffMatches(contentType, head) {
+		return nil, ErrContentMismatch
+	}
+	body := io.LimitReader(io.MultiReader(bytes.NewReader(head), file.Body), file.Size)
+
+	a, err := s.reserve(ctx, userID, ownerType, id, name, contentType, file.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.Put(ctx, a.StorageKey, body, a.Size, a.ContentType); err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to store attachment")
+		s.release(ctx, a)
+		return nil, err
+	}
+
+	if err := s.markReady(ctx, a); err != nil {
+		return nil, err
+	}
+
+	config.WithContext(ctx).WithField("attachment_id", a.ID).Info("Attachment uploaded successfully")
+	return a, nil
+}
+
+func (s *service) CreateUpload(ctx context.Context, ownerPath, ownerID string, dto *UploadDTO) (*PendingUpload, error) {
+	if s.storage == nil {
+		return nil, ErrStorageUnavailable
+	}
+	presigner, ok := s.storage.(storage.Presigner)
+	if !ok {
+		return nil, ErrDirectUploadUnsupported
+	}
+
+	userID, ownerType, id, err := s.resolveOwner(ctx, ownerPath, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, contentType, err := validateFile(dto.FileName, dto.ContentType, dto.Size, s.limits)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := s.reserve(ctx, userID, ownerType, id, name, contentType, dto.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := presigner.PresignPut(ctx, a.StorageKey, a.ContentType, a.Size, presignExpiry)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to presign attachment upload")
+		s.release(ctx, a)
+		return nil, err
+	}
+
+	return &PendingUpload{Attachment: a, Upload: req}, nil
+}
+
+func (s *service) CompleteUpload(ctx context.Context, id string) (*Attachment, error) {
+	if s.storage == nil {
+		return nil, ErrStorageUnavailable
+	}
+
+	a, err := s.findAttachment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if a.Status == Ready {
+		return a, nil
+	}
+
+	obj, err := s.storage.Stat(ctx, a.StorageKey)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, ErrUploadIncomplete
+	}
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to check uploaded attachment")
+		return nil, err
+	}
+	if obj.Size != a.Size {
+		return nil, ErrUploadIncomplete
+	}
+
+	// The client chose what to PUT, so the type it declared is only
+	// trusted once the stored bytes match it.
+	matches, err := s.storedContentMatches(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	if !matches {
+		s.release(ctx, a)
+		return nil, ErrContentMismatch
+	}
+
+	if err := s.markReady(ctx, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (s *service) Download(ctx context.Context, id string) (*Download, error) {
+	if s.storage == nil {
+		return nil, ErrStorageUnavailable
+	}
+
+	a, err := s.findAttachment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if a.Status != Ready {
+		return nil, ErrAttachmentNotFound
+	}
+
+	if presigner, ok := s.storage.(storage.Presigner); ok {
+		url, err := presigner.PresignGet(ctx, a.StorageKey, a.FileName, presignExpiry)
+		if err != nil {
+			config.WithContext(ctx).WithError(err).Error("Failed to presign attachment download")
+			return nil, err
+		}
+		return &Download{Attachment: a, URL: url}, nil
+	}
+
+	body, _, err := s.storage.Get(ctx, a.StorageKey)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to read attachment")
+		return nil, err
+	}
+	return &Download{Attachment: a, Body: body}, nil
+}
+
+func (s *service) DeleteAttachment(ctx context.Context, id string) error {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	attachmentID, err := s.parseUUID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	a, err := s.repo.FindByIDAndUser(attachmentID, userID)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			config.WithContext(ctx).WithError(err).Error("Failed to look up attachment")
+		}
+		return err
+	}
+
+	if err := s.repo.Delete(a.ID, userID); err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			config.WithContext(ctx).WithError(err).Error("Failed to delete attachment")
+		}
+		return err
+	}
+	// The blob is queued for deletion with the row; removing it now just
+	// saves waiting for the cleanup job.
+	if s.storage != nil {
+		s.deleteBlobs(ctx, []string{a.StorageKey})
+	}
+
+	config.WithContext(ctx).WithField("attachment_id", a.ID).Info("Attachment deleted successfully")
+	return nil
+}
+
+func (s *service) Usage(ctx context.Context) (*Usage, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := s.repo.UsedBytes(userID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to compute storage usage")
+		return nil, err
+	}
+
+	return &Usage{UsedBytes: used, QuotaBytes: s.limits.Quota, MaxFileBytes: s.limits.MaxFileSize}, nil
+}
+
+func (s *service) PurgeBlobs(ctx context.Context) error {
+	log := config.WithContext(ctx)
+	if s.storage == nil {
+		return ErrStorageUnavailable
+	}
+
+	abandoned, err := s.repo.DeletePendingBefore(s.now().Add(-pendingUploadTTL))
+	if err != nil {
+		log.WithError(err).Error("Failed to drop abandoned uploads")
+		return err
+	}
+
+	deleted := 0
+	for {
+		keys, err := s.repo.ListBlobDeletions(blobDeletionBatch)
+		if err != nil {
+			log.WithError(err).Error("Failed to list queued blob deletions")
+			return err
+		}
+		done := s.deleteBlobs(ctx, keys)
+		deleted += done
+		// Stop when the queue is drained or storage keeps failing; the
+		// remaining keys are retried on the next run.
+		if len(keys) < blobDeletionBatch || done == 0 {
+			break
+		}
+	}
+
+	log.Infof("Attachment cleanup: %d abandoned uploads dropped, %d blobs deleted", abandoned, deleted)
+	return nil
+}
+
+// ============= Helper Methods =============
+
+// reserve records a pending attachment within the user's quota, so the space
+// counts against the user while the blob is being written.
+func (s *service) reserve(ctx context.Context, userID uuid.UUID, ownerType OwnerType, ownerID uuid.UUID, name, contentType string, size int64) (*Attachment, error) {
+	now := s.now()
+	a := &Attachment{
+		ID:          uuid.New(),
+		UserID:      userID,
+		FileName:    name,
+		ContentType: contentType,
+		Size:        size,
+		Status:      Pending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	a.StorageKey = storageKey(userID, a.ID)
+	switch ownerType {
+	case TaskOwner:
+		a.TaskID = &ownerID
+	case StudyTopicOwner:
+		a.StudyTopicID = &ownerID
+	}
+
+	if err := s.repo.Reserve(a, s.limits.Quota); err != nil {
+		if !errors.Is(err, ErrQuotaExceeded) {
+			config.WithContext(ctx).WithError(err).Error("Failed to create attachment")
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+// storedContentMatches sniffs the start of the stored blob against the
+// attachment's content type.
+func (s *service) storedContentMatches(ctx context.Context, a *Attachment) (bool, error) {
+	body, _, err := s.storage.Get(ctx, a.StorageKey)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return false, ErrUploadIncomplete
+	}
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to read uploaded attachment")
+		return false, err
+	}
+	defer body.Close()
+
+	head, err := readHead(body)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to read uploaded attachment")
+		return false, err
+	}
+	return sniffMatches(a.ContentType, head), nil
+}
+
+// readHead reads the bytes content type detection looks at.
+func readHead(r io.Reader) ([]byte, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return head[:n], nil
+}
+
+// release drops a reservation whose upload failed. Anything written so far
+// is cleaned up through the blob deletion queue.
+func (s *service) release(ctx context.Context, a *Attachment) {
+	if err := s.repo.Delete(a.ID, a.UserID); err != nil {
+		config.WithContext(ctx).WithError(err).WithField("attachment_id", a.ID).Warn("Failed to release attachment reservation")
+	}
+}
+
+func (s *service) markReady(ctx context.Context, a *Attachment) error {
+	a.Status = Ready
+	a.UpdatedAt = s.now()
+	if err := s.repo.MarkReady(a.ID, a.UpdatedAt); err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to mark attachment as uploaded")
+		return err
+	}
+	return nil
+}
+
+// deleteBlobs removes the blobs and takes the keys that succeeded off the
+// deletion queue, returning how many that was.
+func (s *service) deleteBlobs(ctx context.Context, keys []string) int {
+	done := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			config.WithContext(ctx).WithError(err).Warnf("Failed to delete blob %s", key)
+			continue
+		}
+		done = append(done, key)
+	}
+	if err := s.repo.ForgetBlobDeletions(done); err != nil {
+		config.WithContext(ctx).WithError(err).Warn("Failed to update blob deletion queue")
+		return 0
+	}
+	return len(done)
+}
+
+func (s *service) resolveOwner(ctx context.Context, ownerPath, ownerID string) (uuid.UUID, OwnerType, uuid.UUID, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return uuid.Nil, "", uuid.Nil, err
+	}
+
+	ownerType, ok := ownerPaths[ownerPath]
+	if !ok {
+		return uuid.Nil, "", uuid.Nil, ErrInvalidOwner
+	}
+
+	id, err := s.parseUUID(ctx, ownerID)
+	if err != nil {
+		return uuid.Nil, "", uuid.Nil, err
+	}
+
+	owned, err := s.repo.OwnsOwner(ownerType, id, userID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to look up attached item")
+		return uuid.Nil, "", uuid.Nil, err
+	}
+	if !owned {
+		return uuid.Nil, "", uuid.Nil, ErrOwnerNotFound
+	}
+
+	return userID, ownerType, id, nil
+}
+
+func (s *service) findAttachment(ctx context.Context, id string) (*Attachment, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	attachmentID, err := s.parseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := s.repo.FindByIDAndUser(attachmentID, userID)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			config.WithContext(ctx).WithError(err).Error("Failed to look up attachment")
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+func (s *service) getUserID(ctx context.Context) (uuid.UUID, error) {
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Warn("Unauthorized access attempt")
+		return uuid.Nil, ErrUnauthorized
+	}
+	return uuid.MustParse(claims.UserID), nil
+}
+
+func (s *service) parseUUID(ctx context.Context, id string) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Warnf("Invalid ID: %s", id)
+		return uuid.Nil, ErrInvalidID
+	}
+	return parsed, nil
+}
@@ -0,0 +1,329 @@
+package attachment_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/attachment"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/storage"
+)
+
+func TestMain(m *testing.M) {
+	config.Init()
+	os.Exit(m.Run())
+}
+
+// fakeAttachmentRepo keeps attachments in memory and, like the database
+// trigger, queues the storage key of every deleted attachment.
+type fakeAttachmentRepo struct {
+	attachment.Repository
+	attachments map[uuid.UUID]*attachment.Attachment
+	owners      map[uuid.UUID]uuid.UUID
+	queue       []string
+}
+
+func (f *fakeAttachmentRepo) Reserve(a *attachment.Attachment, quota int64) error {
+	if used, _ := f.UsedBytes(a.UserID); used+a.Size > quota {
+		return attachment.ErrQuotaExceeded
+	}
+	copied := *a
+	f.attachments[a.ID] = &copied
+	return nil
+}
+
+func (f *fakeAttachmentRepo) MarkReady(id uuid.UUID, now time.Time) error {
+	f.attachments[id].Status = attachment.Ready
+	return nil
+}
+
+func (f *fakeAttachmentRepo) Delete(id, userID uuid.UUID) error {
+	a, ok := f.attachments[id]
+	if !ok || a.UserID != userID {
+		return attachment.ErrNotFound
+	}
+	delete(f.attachments, id)
+	f.queue = append(f.queue, a.StorageKey)
+	return nil
+}
+
+func (f *fakeAttachmentRepo) FindByIDAndUser(id, userID uuid.UUID) (*attachment.Attachment, error) {
+	if a, ok := f.attachments[id]; ok && a.UserID == userID {
+		copied := *a
+		return &copied, nil
+	}
+	return nil, attachment.ErrNotFound
+}
+
+func (f *fakeAttachmentRepo) ListByOwner(t attachment.OwnerType, ownerID, userID uuid.UUID) ([]*attachment.Attachment, error) {
+	var result []*attachment.Attachment
+	for _, a := range f.attachments {
+		owner := a.TaskID
+		if t == attachment.StudyTopicOwner {
+			owner = a.StudyTopicID
+		}
+		if owner != nil && *owner == ownerID && a.UserID == userID && a.Status == attachment.Ready {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeAttachmentRepo) OwnsOwner(t attachment.OwnerType, id, userID uuid.UUID) (bool, error) {
+	return f.owners[id] == userID, nil
+}
+
+func (f *fakeAttachmentRepo) UsedBytes(userID uuid.UUID) (int64, error) {
+	var used int64
+	for _, a := range f.attachments {
+		if a.UserID == userID {
+			used += a.Size
+		}
+	}
+	return used, nil
+}
+
+func (f *fakeAttachmentRepo) DeletePendingBefore(cutoff time.Time) (int64, error) {
+	var n int64
+	for id, a := range f.attachments {
+		if a.Status == attachment.Pending && a.CreatedAt.Before(cutoff) {
+			f.Delete(id, a.UserID)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeAttachmentRepo) ListBlobDeletions(limit int) ([]string, error) {
+	return f.queue[:min(limit, len(f.queue))], nil
+}
+
+func (f *fakeAttachmentRepo) ForgetBlobDeletions(keys []string) error {
+	forget := map[string]bool{}
+	for _, key := range keys {
+		forget[key] = true
+	}
+	var kept []string
+	for _, key := range f.queue {
+		if !forget[key] {
+			kept = append(kept, key)
+		}
+	}
+	f.queue = kept
+	return nil
+}
+
+// presigningStore is local storage that also hands out fake pre-signed URLs,
+// standing in for S3 in the direct upload flow.
+type presigningStore struct {
+	*storage.Local
+}
+
+func (p *presigningStore) PresignPut(ctx context.Context, key, contentType string, size int64, expires time.Duration) (*storage.PresignedRequest, error) {
+	return &storage.PresignedRequest{Method: "PUT", URL: "https://storage.test/" + key, ExpiresAt: time.Now().Add(expires)}, nil
+}
+
+func (p *presigningStore) PresignGet(ctx context.Context, key, fileName string, expires time.Duration) (string, error) {
+	return "https://storage.test/" + key + "?download=" + fileName, nil
+}
+
+var pdf = []byte("%PDF-1.7\n1 0 obj << /Type /Catalog >> endobj\n")
+
+func TestAttachments(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.UserDataKeyID, userID.String())
+	ctx = context.WithValue(ctx, auth.UserDataKeyRole, "user")
+
+	taskID, topicID := uuid.New(), uuid.New()
+	newRepo := func() *fakeAttachmentRepo {
+		return &fakeAttachmentRepo{
+			attachments: map[uuid.UUID]*attachment.Attachment{},
+			owners:      map[uuid.UUID]uuid.UUID{taskID: userID, topicID: userID},
+		}
+	}
+	local, err := storage.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	limits := attachment.Limits{MaxFileSize: 1024, Quota: 2048}
+
+	upload := func(name, contentType string, content []byte) *attachment.Upload {
+		return &attachment.Upload{FileName: name, ContentType: contentType, Size: int64(len(content)), Body: bytes.NewReader(content)}
+	}
+
+	t.Run("StreamedUploadRoundTrip", func(t *testing.T) {
+		repo := newRepo()
+		svc := attachment.NewService(repo, local, limits)
+
+		a, err := svc.Upload(ctx, "tasks", taskID.String(), upload(`C:\Users\ana\slides.pdf`, "", pdf))
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if a.FileName != "slides.pdf" || a.ContentType != "application/pdf" || a.Status != attachment.Ready {
+			t.Errorf("Anexo com dados incorretos: %+v", a)
+		}
+
+		listed, _ := svc.ListAttachments(ctx, "tasks", taskID.String())
+		if len(listed) != 1 {
+			t.Fatalf("Anexo deveria ser listado na tarefa, recebidos %d", len(listed))
+		}
+
+		download, err := svc.Download(ctx, a.ID.String())
+		if err != nil {
+			t.Fatalf("Erro inesperado no download: %v", err)
+		}
+		defer download.Body.Close()
+		if got, _ := io.ReadAll(download.Body); !bytes.Equal(got, pdf) || download.URL != "" {
+			t.Errorf("Armazenamento local deveria devolver o conteúdo: %q", got)
+		}
+	})
+
+	t.Run("ValidatesTypeAndSize", func(t *testing.T) {
+		svc := attachment.NewService(newRepo(), local, limits)
+
+		cases := map[string]struct {
+			file *attachment.Upload
+			err  error
+		}{
+			"executável":     {upload("setup.exe", "application/x-msdownload", []byte("MZ")), attachment.ErrUnsupportedType},
+			"png como pdf":   {upload("print.pdf", "application/pdf", []byte("\x89PNG\r\n\x1a\n0000")), attachment.ErrContentMismatch},
+			"grande demais":  {upload("aula.pdf", "application/pdf", bytes.Repeat([]byte("a"), 1025)), attachment.ErrFileTooLarge},
+			"vazio":          {upload("nada.txt", "text/plain", nil), attachment.ErrEmptyFile},
+			"nome inválido":  {upload("..", "text/plain", []byte("oi")), attachment.ErrInvalidFileName},
+			"tipo pelo nome": {upload("notas.md", "application/octet-stream", []byte("# Resumo")), nil},
+		}
+		for name, c := range cases {
+			if _, err := svc.Upload(ctx, "study-topics", topicID.String(), c.file); !errors.Is(err, c.err) {
+				t.Errorf("%s: erro esperado %v, recebido %v", name, c.err, err)
+			}
+		}
+	})
+
+	t.Run("QuotaIncludesPendingUploads", func(t *testing.T) {
+		repo := newRepo()
+		svc := attachment.NewService(repo, &presigningStore{local}, limits)
+
+		if _, err := svc.CreateUpload(ctx, "tasks", taskID.String(), &attachment.UploadDTO{FileName: "a.pdf", ContentType: "application/pdf", Size: 1000}); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if _, err := svc.CreateUpload(ctx, "tasks", taskID.String(), &attachment.UploadDTO{FileName: "b.pdf", ContentType: "application/pdf", Size: 1024}); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		_, err := svc.Upload(ctx, "tasks", taskID.String(), upload("c.pdf", "application/pdf", pdf))
+		if !errors.Is(err, attachment.ErrQuotaExceeded) {
+			t.Errorf("Envios pendentes deveriam contar na cota, erro: %v", err)
+		}
+	})
+
+	t.Run("DirectUploadFlow", func(t *testing.T) {
+		repo := newRepo()
+		svc := attachment.NewService(repo, &presigningStore{local}, limits)
+
+		pending, err := svc.CreateUpload(ctx, "study-topics", topicID.String(), &attachment.UploadDTO{FileName: "resumo.pdf", ContentType: "application/pdf", Size: int64(len(pdf))})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if pending.Upload.Method != "PUT" || pending.Attachment.Status != attachment.Pending {
+			t.Fatalf("Envio direto deveria reservar o anexo e devolver um PUT: %+v", pending)
+		}
+		id := pending.Attachment.ID.String()
+
+		if _, err := svc.CompleteUpload(ctx, id); !errors.Is(err, attachment.ErrUploadIncomplete) {
+			t.Errorf("Concluir sem o arquivo no armazenamento deveria falhar, erro: %v", err)
+		}
+		if _, err := svc.Download(ctx, id); !errors.Is(err, attachment.ErrAttachmentNotFound) {
+			t.Errorf("Anexo pendente não deveria ser baixado, erro: %v", err)
+		}
+
+		key := repo.attachments[pending.Attachment.ID].StorageKey
+		local.Put(ctx, key, bytes.NewReader(pdf), int64(len(pdf)), "application/pdf")
+		a, err := svc.CompleteUpload(ctx, id)
+		if err != nil || a.Status != attachment.Ready {
+			t.Fatalf("Envio deveria ser concluído: %+v (%v)", a, err)
+		}
+
+		download, err := svc.Download(ctx, id)
+		if err != nil || !strings.Contains(download.URL, "download=resumo.pdf") {
+			t.Errorf("Download deveria usar URL pré-assinada: %+v (%v)", download, err)
+		}
+	})
+
+	t.Run("DirectUploadChecksStoredContent", func(t *testing.T) {
+		repo := newRepo()
+		svc := attachment.NewService(repo, &presigningStore{local}, limits)
+
+		png := []byte("\x89PNG\r\n\x1a\n0000")
+		pending, err := svc.CreateUpload(ctx, "tasks", taskID.String(), &attachment.UploadDTO{FileName: "prova.pdf", ContentType: "application/pdf", Size: int64(len(png))})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		key := repo.attachments[pending.Attachment.ID].StorageKey
+		local.Put(ctx, key, bytes.NewReader(png), int64(len(png)), "application/pdf")
+
+		if _, err := svc.CompleteUpload(ctx, pending.Attachment.ID.String()); !errors.Is(err, attachment.ErrContentMismatch) {
+			t.Errorf("Conteúdo diferente do tipo declarado deveria falhar, erro: %v", err)
+		}
+		if _, ok := repo.attachments[pending.Attachment.ID]; ok || len(repo.queue) != 1 {
+			t.Errorf("Reserva deveria ser liberada e o arquivo enviado à fila de exclusão, fila: %v", repo.queue)
+		}
+	})
+
+	t.Run("DirectUploadNeedsPresigningStorage", func(t *testing.T) {
+		svc := attachment.NewService(newRepo(), local, limits)
+		_, err := svc.CreateUpload(ctx, "tasks", taskID.String(), &attachment.UploadDTO{FileName: "a.pdf", ContentType: "application/pdf", Size: 10})
+		if !errors.Is(err, attachment.ErrDirectUploadUnsupported) {
+			t.Errorf("Armazenamento local não deveria aceitar envio direto, erro: %v", err)
+		}
+	})
+
+	t.Run("OnlyOwnItemsAcceptAttachments", func(t *testing.T) {
+		svc := attachment.NewService(newRepo(), local, limits)
+		if _, err := svc.Upload(ctx, "tasks", uuid.NewString(), upload("a.pdf", "application/pdf", pdf)); !errors.Is(err, attachment.ErrOwnerNotFound) {
+			t.Errorf("Tarefa de outro usuário deveria falhar, erro: %v", err)
+		}
+		if _, err := svc.ListAttachments(ctx, "projects", taskID.String()); !errors.Is(err, attachment.ErrInvalidOwner) {
+			t.Errorf("Projetos não têm anexos, erro: %v", err)
+		}
+	})
+
+	t.Run("DeletedOwnersLeaveNoBlobs", func(t *testing.T) {
+		repo := newRepo()
+		svc := attachment.NewService(repo, local, limits)
+
+		kept, _ := svc.Upload(ctx, "tasks", taskID.String(), upload("a.pdf", "application/pdf", pdf))
+		removed, _ := svc.Upload(ctx, "tasks", taskID.String(), upload("b.pdf", "application/pdf", pdf))
+		keptKey := repo.attachments[kept.ID].StorageKey
+		removedKey := repo.attachments[removed.ID].StorageKey
+
+		if err := svc.DeleteAttachment(ctx, removed.ID.String()); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if _, err := local.Stat(ctx, removedKey); !errors.Is(err, storage.ErrObjectNotFound) || len(repo.queue) != 0 {
+			t.Errorf("Arquivo do anexo removido deveria sair do armazenamento e da fila, erro: %v fila: %v", err, repo.queue)
+		}
+
+		// Removing the task cascades to its attachments in the database.
+		repo.Delete(kept.ID, userID)
+		if err := svc.PurgeBlobs(context.Background()); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if _, err := local.Stat(ctx, keptKey); !errors.Is(err, storage.ErrObjectNotFound) || len(repo.queue) != 0 {
+			t.Errorf("Limpeza deveria remover arquivos de tarefas apagadas, erro: %v fila: %v", err, repo.queue)
+		}
+	})
+
+	t.Run("StorageUnavailable", func(t *testing.T) {
+		svc := attachment.NewService(newRepo(), nil, limits)
+		if _, err := svc.Upload(ctx, "tasks", taskID.String(), upload("a.pdf", "application/pdf", pdf)); !errors.Is(err, attachment.ErrStorageUnavailable) {
+			t.Errorf("Sem armazenamento o envio deveria falhar, erro: %v", err)
+		}
+	})
+}
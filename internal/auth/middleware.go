@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"errors"
+	"github.com/saulo-duarte/chronos-lambda/internal/i18n"
 	"log"
 	"net/http"
 	"strings"
@@ -20,14 +21,14 @@ func AuthMiddleware(next http.Handler) http.Handler {
 		tokenStr, err := extractToken(r)
 		if err != nil {
 			log.Printf("[AuthMiddleware] Token não encontrado: %v", err)
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			i18n.Error(w, r, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
 
 		claims, err := ValidateJWT(tokenStr)
 		if err != nil {
 			log.Printf("[AuthMiddleware] Falha ao validar JWT: %v", err)
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			i18n.Error(w, r, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
 
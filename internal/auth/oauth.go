@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/i18n"
 	"github.com/sirupsen/logrus"
 )
 
@@ -41,7 +42,7 @@ func GoogleCodeHandler(w http.ResponseWriter, r *http.Request) {
 	var payload CallbackPayload
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 		log.WithError(err).Error("Payload inválido recebido do frontend")
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
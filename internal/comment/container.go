@@ -0,0 +1,17 @@
+package comment
+
+import "gorm.io/gorm"
+
+type CommentContainer struct {
+	Handler *Handler
+	Service Service
+}
+
+func NewCommentContainer(db *gorm.DB) *CommentContainer {
+	service := NewService(NewRepository(db))
+
+	return &CommentContainer{
+		Handler: NewHandler(service),
+		Service: service,
+	}
+}
@@ -0,0 +1,38 @@
+package comment
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// MaxBodyLength is the longest comment accepted, in characters.
+const MaxBodyLength = 10000
+
+// excerptLength is how much of a comment is copied into the activity feed.
+const excerptLength = 140
+
+type CommentDTO struct {
+	Body     string     `json:"body"`
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+func (dto *CommentDTO) Validate() error {
+	dto.Body = strings.TrimSpace(dto.Body)
+	if dto.Body == "" {
+		return ErrEmptyBody
+	}
+	if len([]rune(dto.Body)) > MaxBodyLength {
+		return ErrBodyTooLong
+	}
+	return nil
+}
+
+// excerpt flattens the body to a single line and cuts it to excerptLength.
+func excerpt(body string) string {
+	runes := []rune(strings.Join(strings.Fields(body), " "))
+	if len(runes) <= excerptLength {
+		return string(runes)
+	}
+	return string(runes[:excerptLength-1]) + "…"
+}
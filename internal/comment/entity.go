@@ -0,0 +1,23 @@
+package comment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/activity"
+)
+
+// Comment is a Markdown note on a task or project. Replies point at the
+// comment they answer through ParentID; the body is stored as written and
+// rendered by the client.
+type Comment struct {
+	ID         uuid.UUID           `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
+	UserID     uuid.UUID           `gorm:"column:user_id;not null" json:"-"`
+	EntityType activity.EntityType `json:"entity_type"`
+	EntityID   uuid.UUID           `gorm:"type:uuid;not null" json:"entity_id"`
+	ParentID   *uuid.UUID          `gorm:"type:uuid" json:"parent_id"`
+	Body       string              `json:"body"`
+	Replies    []*Comment          `gorm:"-" json:"replies"`
+	CreatedAt  time.Time           `json:"created_at"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+}
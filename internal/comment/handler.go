@@ -0,0 +1,109 @@
+package comment
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/i18n"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	comments, err := h.service.ListComments(r.Context(), chi.URLParam(r, "entity"), chi.URLParam(r, "id"))
+	if err != nil {
+		if !writeCommentError(w, r, err) {
+			log.WithError(err).Error("Failed to list comments")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, comments)
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload CommentDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	c, err := h.service.AddComment(r.Context(), chi.URLParam(r, "entity"), chi.URLParam(r, "id"), &payload)
+	if err != nil {
+		if !writeCommentError(w, r, err) {
+			log.WithError(err).Error("Failed to create comment")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, c)
+}
+
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload CommentDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	c, err := h.service.UpdateComment(r.Context(), chi.URLParam(r, "commentID"), &payload)
+	if err != nil {
+		if !writeCommentError(w, r, err) {
+			log.WithError(err).Error("Failed to update comment")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, c)
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	if err := h.service.DeleteComment(r.Context(), chi.URLParam(r, "commentID")); err != nil {
+		if !writeCommentError(w, r, err) {
+			log.WithError(err).Error("Failed to delete comment")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeCommentError(w http.ResponseWriter, r *http.Request, err error) bool {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrInvalidID):
+		i18n.Error(w, r, "invalid id", http.StatusBadRequest)
+	case errors.Is(err, ErrCommentNotFound), errors.Is(err, ErrEntityNotFound):
+		i18n.Error(w, r, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrInvalidEntityType),
+		errors.Is(err, ErrInvalidParent),
+		errors.Is(err, ErrEmptyBody),
+		errors.Is(err, ErrBodyTooLong):
+		i18n.Error(w, r, err.Error(), http.StatusBadRequest)
+	default:
+		return false
+	}
+	return true
+}
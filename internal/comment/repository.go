@@ -0,0 +1,98 @@
+package comment
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/activity"
+	"gorm.io/gorm"
+)
+
+var ErrNotFound = errors.New("comment not found")
+
+// entityTables maps each commentable entity to its table.
+var entityTables = map[activity.EntityType]string{
+	activity.TaskEntity:    "tasks",
+	activity.ProjectEntity: "projects",
+}
+
+type CommentRepository interface {
+	Create(c *Comment) error
+	Update(c *Comment) error
+	// Delete removes the comment and, through the foreign key, its replies.
+	Delete(id, userID uuid.UUID) error
+	FindByIDAndUser(id, userID uuid.UUID) (*Comment, error)
+	// ListByEntity returns every comment of the entity, oldest first.
+	ListByEntity(entityType activity.EntityType, entityID, userID uuid.UUID) ([]*Comment, error)
+	// OwnsEntity reports whether the task or project exists, is not in the
+	// trash and belongs to the user.
+	OwnsEntity(entityType activity.EntityType, id, userID uuid.UUID) (bool, error)
+	RecordActivity(e *activity.Entry) error
+
+	// Transaction runs fn with a repository bound to a single DB transaction.
+	Transaction(fn func(tx CommentRepository) error) error
+}
+
+type commentRepository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) CommentRepository {
+	return &commentRepository{db: db}
+}
+
+func (r *commentRepository) Create(c *Comment) error {
+	return r.db.Create(c).Error
+}
+
+func (r *commentRepository) Update(c *Comment) error {
+	return r.db.Save(c).Error
+}
+
+func (r *commentRepository) Delete(id, userID uuid.UUID) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&Comment{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *commentRepository) FindByIDAndUser(id, userID uuid.UUID) (*Comment, error) {
+	var c Comment
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&c).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *commentRepository) ListByEntity(entityType activity.EntityType, entityID, userID uuid.UUID) ([]*Comment, error) {
+	var comments []*Comment
+	err := r.db.Where("entity_type = ? AND entity_id = ? AND user_id = ?", entityType, entityID, userID).
+		Order("created_at ASC, id ASC").
+		Find(&comments).Error
+	return comments, err
+}
+
+func (r *commentRepository) OwnsEntity(entityType activity.EntityType, id, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Table(entityTables[entityType]).
+		Where("id = ? AND user_id = ? AND deleted_at IS NULL", id, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *commentRepository) RecordActivity(e *activity.Entry) error {
+	return r.db.Create(e).Error
+}
+
+func (r *commentRepository) Transaction(fn func(tx CommentRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&commentRepository{db: tx})
+	})
+}
@@ -0,0 +1,16 @@
+package comment
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+func Routes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Put("/{commentID}", h.Update)
+	r.Delete("/{commentID}", h.Delete)
+	r.Get("/{entity}/{id}", h.List)
+	r.Post("/{entity}/{id}", h.Create)
+
+	return r
+}
@@ -0,0 +1,236 @@
+package comment
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/activity"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+)
+
+var (
+	ErrUnauthorized      = errors.New("unauthorized")
+	ErrInvalidID         = errors.New("invalid id format")
+	ErrCommentNotFound   = ErrNotFound
+	ErrInvalidEntityType = errors.New("invalid entity type")
+	ErrEntityNotFound    = errors.New("commented item not found")
+	ErrInvalidParent     = errors.New("invalid parent comment")
+	ErrEmptyBody         = errors.New("comment body cannot be empty")
+	ErrBodyTooLong       = errors.New("comment is too long")
+)
+
+type Service interface {
+	// ListComments returns the entity's threads: top-level comments, oldest
+	// first, with their replies nested under them.
+	ListComments(ctx context.Context, entityPath, id string) ([]*Comment, error)
+	AddComment(ctx context.Context, entityPath, id string, dto *CommentDTO) (*Comment, error)
+	UpdateComment(ctx context.Context, id string, dto *CommentDTO) (*Comment, error)
+	DeleteComment(ctx context.Context, id string) error
+}
+
+type service struct {
+	repo CommentRepository
+}
+
+func NewService(repo CommentRepository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) ListComments(ctx context.Context, entityPath, id string) ([]*Comment, error) {
+	userID, entityType, entityID, err := s.resolveEntity(ctx, entityPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := s.repo.ListByEntity(entityType, entityID, userID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list comments")
+		return nil, err
+	}
+
+	return buildThreads(comments), nil
+}
+
+func (s *service) AddComment(ctx context.Context, entityPath, id string, dto *CommentDTO) (*Comment, error) {
+	userID, entityType, entityID, err := s.resolveEntity(ctx, entityPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dto.Validate(); err != nil {
+		return nil, err
+	}
+
+	if dto.ParentID != nil {
+		parent, err := s.repo.FindByIDAndUser(*dto.ParentID, userID)
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrInvalidParent
+		}
+		if err != nil {
+			config.WithContext(ctx).WithError(err).Error("Failed to look up parent comment")
+			return nil, err
+		}
+		if parent.EntityType != entityType || parent.EntityID != entityID {
+			return nil, ErrInvalidParent
+		}
+	}
+
+	now := time.Now()
+	c := &Comment{
+		ID:         uuid.New(),
+		UserID:     userID,
+		EntityType: entityType,
+		EntityID:   entityID,
+		ParentID:   dto.ParentID,
+		Body:       dto.Body,
+		Replies:    make([]*Comment, 0),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	entry := activity.NewEntry(userID, entityType, entityID, activity.Commented, nil)
+	entry.Detail = excerpt(c.Body)
+	err = s.repo.Transaction(func(tx CommentRepository) error {
+		if err := tx.Create(c); err != nil {
+			return err
+		}
+		return tx.RecordActivity(entry)
+	})
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to create comment")
+		return nil, err
+	}
+
+	config.WithContext(ctx).WithField("comment_id", c.ID).Info("Comment created successfully")
+	return c, nil
+}
+
+func (s *service) UpdateComment(ctx context.Context, id string, dto *CommentDTO) (*Comment, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	commentID, err := s.parseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dto.Validate(); err != nil {
+		return nil, err
+	}
+
+	c, err := s.repo.FindByIDAndUser(commentID, userID)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			config.WithContext(ctx).WithError(err).Error("Failed to look up comment")
+		}
+		return nil, err
+	}
+
+	c.Body = dto.Body
+	c.UpdatedAt = time.Now()
+	if err := s.repo.Update(c); err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to update comment")
+		return nil, err
+	}
+
+	c.Replies = make([]*Comment, 0)
+	return c, nil
+}
+
+func (s *service) DeleteComment(ctx context.Context, id string) error {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	commentID, err := s.parseUUID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(commentID, userID); err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			config.WithContext(ctx).WithError(err).Error("Failed to delete comment")
+		}
+		return err
+	}
+
+	config.WithContext(ctx).WithField("comment_id", commentID).Info("Comment deleted successfully")
+	return nil
+}
+
+// ============= Helper Methods =============
+
+// resolveEntity reads the entity from the URL and checks the user owns it.
+func (s *service) resolveEntity(ctx context.Context, entityPath, id string) (uuid.UUID, activity.EntityType, uuid.UUID, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return uuid.Nil, "", uuid.Nil, err
+	}
+
+	entityType, ok := activity.EntityFromPath(entityPath)
+	if !ok {
+		return uuid.Nil, "", uuid.Nil, ErrInvalidEntityType
+	}
+
+	entityID, err := s.parseUUID(ctx, id)
+	if err != nil {
+		return uuid.Nil, "", uuid.Nil, err
+	}
+
+	owned, err := s.repo.OwnsEntity(entityType, entityID, userID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to look up commented item")
+		return uuid.Nil, "", uuid.Nil, err
+	}
+	if !owned {
+		return uuid.Nil, "", uuid.Nil, ErrEntityNotFound
+	}
+
+	return userID, entityType, entityID, nil
+}
+
+func (s *service) getUserID(ctx context.Context) (uuid.UUID, error) {
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Warn("Unauthorized access attempt")
+		return uuid.Nil, ErrUnauthorized
+	}
+	return uuid.MustParse(claims.UserID), nil
+}
+
+func (s *service) parseUUID(ctx context.Context, id string) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Warnf("Invalid ID: %s", id)
+		return uuid.Nil, ErrInvalidID
+	}
+	return parsed, nil
+}
+
+// buildThreads nests replies under their parents, keeping the order of
+// comments. A reply whose parent is missing is shown at the top level.
+func buildThreads(comments []*Comment) []*Comment {
+	byID := make(map[uuid.UUID]*Comment, len(comments))
+	for _, c := range comments {
+		c.Replies = make([]*Comment, 0)
+		byID[c.ID] = c
+	}
+
+	roots := make([]*Comment, 0)
+	for _, c := range comments {
+		if c.ParentID != nil {
+			if parent, ok := byID[*c.ParentID]; ok {
+				parent.Replies = append(parent.Replies, c)
+				continue
+			}
+		}
+		roots = append(roots, c)
+	}
+	return roots
+}
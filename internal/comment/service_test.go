@@ -0,0 +1,156 @@
+package comment_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/activity"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/comment"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+)
+
+func TestMain(m *testing.M) {
+	config.Init()
+	os.Exit(m.Run())
+}
+
+// fakeCommentRepo keeps comments in insertion order, which is also the
+// order they were created in.
+type fakeCommentRepo struct {
+	comment.CommentRepository
+	comments []*comment.Comment
+	owned    map[uuid.UUID]uuid.UUID
+	activity []*activity.Entry
+}
+
+func (f *fakeCommentRepo) Create(c *comment.Comment) error {
+	f.comments = append(f.comments, c)
+	return nil
+}
+
+func (f *fakeCommentRepo) Update(c *comment.Comment) error {
+	return nil
+}
+
+func (f *fakeCommentRepo) FindByIDAndUser(id, userID uuid.UUID) (*comment.Comment, error) {
+	for _, c := range f.comments {
+		if c.ID == id && c.UserID == userID {
+			return c, nil
+		}
+	}
+	return nil, comment.ErrNotFound
+}
+
+func (f *fakeCommentRepo) ListByEntity(entityType activity.EntityType, entityID, userID uuid.UUID) ([]*comment.Comment, error) {
+	var result []*comment.Comment
+	for _, c := range f.comments {
+		if c.EntityType == entityType && c.EntityID == entityID && c.UserID == userID {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeCommentRepo) OwnsEntity(entityType activity.EntityType, id, userID uuid.UUID) (bool, error) {
+	return f.owned[id] == userID, nil
+}
+
+func (f *fakeCommentRepo) RecordActivity(e *activity.Entry) error {
+	f.activity = append(f.activity, e)
+	return nil
+}
+
+func (f *fakeCommentRepo) Transaction(fn func(tx comment.CommentRepository) error) error {
+	return fn(f)
+}
+
+func TestComments(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.UserDataKeyID, userID.String())
+	ctx = context.WithValue(ctx, auth.UserDataKeyRole, "user")
+
+	taskID, projectID, othersTask := uuid.New(), uuid.New(), uuid.New()
+	repo := &fakeCommentRepo{owned: map[uuid.UUID]uuid.UUID{taskID: userID, projectID: userID, othersTask: uuid.New()}}
+	svc := comment.NewService(repo)
+
+	var root *comment.Comment
+
+	t.Run("AddRecordsActivity", func(t *testing.T) {
+		var err error
+		root, err = svc.AddComment(ctx, "tasks", taskID.String(), &comment.CommentDTO{Body: "  **Atenção**: revisar   o escopo\n\nantes de sexta  "})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if root.Body != "**Atenção**: revisar   o escopo\n\nantes de sexta" {
+			t.Errorf("Markdown deveria ser salvo como escrito, sem espaços nas pontas: %q", root.Body)
+		}
+		if len(repo.activity) != 1 || repo.activity[0].Action != activity.Commented || repo.activity[0].EntityID != taskID {
+			t.Fatalf("Comentário deveria aparecer na atividade da tarefa: %+v", repo.activity)
+		}
+		if repo.activity[0].Detail != "**Atenção**: revisar o escopo antes de sexta" {
+			t.Errorf("Resumo do comentário incorreto: %q", repo.activity[0].Detail)
+		}
+	})
+
+	t.Run("RepliesAreNestedUnderTheirParent", func(t *testing.T) {
+		reply, err := svc.AddComment(ctx, "tasks", taskID.String(), &comment.CommentDTO{Body: "Feito", ParentID: &root.ID})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if _, err := svc.AddComment(ctx, "tasks", taskID.String(), &comment.CommentDTO{Body: "Outro assunto"}); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+
+		threads, err := svc.ListComments(ctx, "tasks", taskID.String())
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if len(threads) != 2 || threads[0].ID != root.ID {
+			t.Fatalf("Esperadas 2 conversas começando pela mais antiga, recebidas %d", len(threads))
+		}
+		if len(threads[0].Replies) != 1 || threads[0].Replies[0].ID != reply.ID {
+			t.Errorf("Resposta deveria ficar dentro do comentário pai: %+v", threads[0].Replies)
+		}
+	})
+
+	t.Run("ParentMustBelongToSameEntity", func(t *testing.T) {
+		_, err := svc.AddComment(ctx, "projects", projectID.String(), &comment.CommentDTO{Body: "Resposta", ParentID: &root.ID})
+		if !errors.Is(err, comment.ErrInvalidParent) {
+			t.Errorf("Resposta a comentário de outro item deveria falhar, erro: %v", err)
+		}
+	})
+
+	t.Run("RejectsInvalidBodies", func(t *testing.T) {
+		if _, err := svc.AddComment(ctx, "tasks", taskID.String(), &comment.CommentDTO{Body: "   "}); !errors.Is(err, comment.ErrEmptyBody) {
+			t.Errorf("Comentário vazio deveria falhar, erro: %v", err)
+		}
+		long := strings.Repeat("a", comment.MaxBodyLength+1)
+		if _, err := svc.AddComment(ctx, "tasks", taskID.String(), &comment.CommentDTO{Body: long}); !errors.Is(err, comment.ErrBodyTooLong) {
+			t.Errorf("Comentário longo demais deveria falhar, erro: %v", err)
+		}
+	})
+
+	t.Run("OnlyOwnEntitiesCanBeCommented", func(t *testing.T) {
+		if _, err := svc.AddComment(ctx, "tasks", othersTask.String(), &comment.CommentDTO{Body: "Oi"}); !errors.Is(err, comment.ErrEntityNotFound) {
+			t.Errorf("Tarefa de outro usuário deveria falhar, erro: %v", err)
+		}
+		if _, err := svc.ListComments(ctx, "study-topics", taskID.String()); !errors.Is(err, comment.ErrInvalidEntityType) {
+			t.Errorf("Tipo de item sem comentários deveria falhar, erro: %v", err)
+		}
+	})
+
+	t.Run("UpdateChangesBody", func(t *testing.T) {
+		updated, err := svc.UpdateComment(ctx, root.ID.String(), &comment.CommentDTO{Body: "Escopo revisado"})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if updated.Body != "Escopo revisado" {
+			t.Errorf("Texto do comentário não foi atualizado: %q", updated.Body)
+		}
+	})
+}
@@ -5,16 +5,23 @@ import (
 	"log"
 	"os"
 
+	"github.com/saulo-duarte/chronos-lambda/internal/activity"
 	"github.com/saulo-duarte/chronos-lambda/internal/aiquiz"
 	"github.com/saulo-duarte/chronos-lambda/internal/annual_goal"
+	"github.com/saulo-duarte/chronos-lambda/internal/attachment"
 	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/comment"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
 	googlecalendar "github.com/saulo-duarte/chronos-lambda/internal/google_calendar"
+	"github.com/saulo-duarte/chronos-lambda/internal/ical"
 	"github.com/saulo-duarte/chronos-lambda/internal/project"
 	"github.com/saulo-duarte/chronos-lambda/internal/quiz"
 	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
 	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"github.com/saulo-duarte/chronos-lambda/internal/tag"
 	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	timetracking "github.com/saulo-duarte/chronos-lambda/internal/time_tracking"
+	"github.com/saulo-duarte/chronos-lambda/internal/trash"
 	"github.com/saulo-duarte/chronos-lambda/internal/user"
 )
 
@@ -28,6 +35,13 @@ type Container struct {
 	AIQuizContainer         *aiquiz.AIQuizContainer
 	QuizContainer           *quiz.QuizContainer
 	AnnualGoalContainer     *annual_goal.Container
+	ICalContainer           *ical.ICalContainer
+	TimeTrackingContainer   *timetracking.TimeTrackingContainer
+	TrashContainer          *trash.TrashContainer
+	TagContainer            *tag.TagContainer
+	ActivityContainer       *activity.ActivityContainer
+	CommentContainer        *comment.CommentContainer
+	AttachmentContainer     *attachment.AttachmentContainer
 }
 
 func New() *Container {
@@ -41,30 +55,72 @@ func New() *Container {
 	}
 
 	userContainer := user.NewUserContainer(config.DB)
-	projectContainer := project.NewProjectContainer(config.DB)
-	studySubjectContainer := studysubject.NewStudySubjectContainer(config.DB)
+	// Projects and subjects take their tasks to the trash, but the task
+	// container depends on the project service, so this is built first.
+	parentTrash := task.NewParentTrashService(task.NewRepository(config.DB), userContainer.Repo)
+	projectContainer := project.NewProjectContainer(config.DB, parentTrash)
+	studySubjectContainer := studysubject.NewStudySubjectContainer(config.DB, parentTrash)
 	studyTopicContainer := studytopic.NewStudyTopicContainer(config.DB)
-	calendarContainer := googlecalendar.NewGoogleCalendarContainer(userContainer.Repo)
-	aiQuizContainer := aiquiz.NewAIQuizContainer()
-	quizContainer := quiz.NewQuizContainer(config.DB)
+	calendarContainer := googlecalendar.NewGoogleCalendarContainer(config.DB, userContainer.Repo)
+	quizContainer := quiz.NewQuizContainer(config.DB, studyTopicContainer.Service)
 	annualGoalContainer := annual_goal.NewContainer(config.DB)
+	tagContainer := tag.NewTagContainer(config.DB)
+	activityContainer := activity.NewActivityContainer(config.DB)
+	commentContainer := comment.NewCommentContainer(config.DB)
+	attachmentContainer := attachment.NewAttachmentContainer(config.DB)
 
 	taskContainer := task.NewTaskContainer(
 		config.DB,
 		projectContainer.Service,
 		studyTopicContainer.Repo,
 		userContainer.Repo,
+		calendarContainer.CalendarService,
 		calendarContainer.CalendarManager,
+		calendarContainer.SyncStateRepo,
 	)
 
+	aiQuizContainer := aiquiz.NewAIQuizContainer(
+		quizContainer.Service,
+		studySubjectContainer.Repo,
+		studyTopicContainer.Repo,
+		taskContainer.Repo,
+	)
+
+	icalContainer := ical.NewICalContainer(
+		config.DB,
+		taskContainer.Repo,
+		taskContainer.Service,
+		userContainer.Repo,
+		projectContainer.Service,
+		studySubjectContainer.Repo,
+	)
+
+	timeTrackingContainer := timetracking.NewTimeTrackingContainer(
+		config.DB,
+		taskContainer.Repo,
+		projectContainer.Service,
+		studyTopicContainer.Repo,
+		userContainer.Repo,
+	)
+
+	trashContainer := trash.NewTrashContainer(config.DB, taskContainer.Service, parentTrash)
+
 	return &Container{
-		UserContainer:         userContainer,
-		ProjectContainer:      projectContainer,
-		TaskContainer:         taskContainer,
-		StudySubjectContainer: studySubjectContainer,
-		StudyTopicContainer:   studyTopicContainer,
-		AIQuizContainer:       aiQuizContainer,
-		QuizContainer:         quizContainer,
-		AnnualGoalContainer:   annualGoalContainer,
+		UserContainer:           userContainer,
+		ProjectContainer:        projectContainer,
+		TaskContainer:           taskContainer,
+		StudySubjectContainer:   studySubjectContainer,
+		StudyTopicContainer:     studyTopicContainer,
+		AIQuizContainer:         aiQuizContainer,
+		QuizContainer:           quizContainer,
+		AnnualGoalContainer:     annualGoalContainer,
+		GoogleCalendarContainer: calendarContainer,
+		ICalContainer:           icalContainer,
+		TimeTrackingContainer:   timeTrackingContainer,
+		TrashContainer:          trashContainer,
+		TagContainer:            tagContainer,
+		ActivityContainer:       activityContainer,
+		CommentContainer:        commentContainer,
+		AttachmentContainer:     attachmentContainer,
 	}
 }
@@ -6,14 +6,18 @@ import (
 	"github.com/saulo-duarte/chronos-lambda/internal/user"
 	"golang.org/x/oauth2"
 	gcal "google.golang.org/api/calendar/v3"
+	"gorm.io/gorm"
 )
 
 type GoogleCalendarContainer struct {
 	CalendarService CalendarService
 	CalendarManager CalendarManager
+	SyncStateRepo   SyncStateRepository
+	Handler         *Handler
 }
 
 func NewGoogleCalendarContainer(
+	db *gorm.DB,
 	userRepo user.UserRepository,
 ) *GoogleCalendarContainer {
 	clientID := os.Getenv("GOOGLE_CLIENT_ID")
@@ -31,11 +35,15 @@ func NewGoogleCalendarContainer(
 		},
 	}
 
-	calendarService := NewCalendarService(userRepo, oauthConfig)
+	prefsRepo := NewPreferencesRepository(db)
+	calendarService := NewCalendarService(userRepo, prefsRepo, oauthConfig)
 	calendarManager := NewCalendarManager(calendarService)
+	handler := NewHandler(NewPreferencesService(prefsRepo, calendarService))
 
 	return &GoogleCalendarContainer{
 		CalendarService: calendarService,
 		CalendarManager: calendarManager,
+		SyncStateRepo:   NewSyncStateRepository(db),
+		Handler:         handler,
 	}
 }
@@ -13,4 +13,116 @@ type CalendarTask struct {
 	StartDate             *time.Time
 	DueDate               *time.Time
 	GoogleCalendarEventID *string
+	// Recurrence holds RFC 5545 lines (e.g. "RRULE:FREQ=WEEKLY;BYDAY=MO")
+	// for recurring tasks; empty for single events.
+	Recurrence []string
+	Type       string
+	Priority   string
+	// AllDay tasks are written as date-only events.
+	AllDay bool
+	// CalendarID is the calendar currently holding the event ("" means
+	// primary). Syncing updates it when the event is created or moved.
+	CalendarID string
+}
+
+// EventChange is an event created, moved or cancelled in Google Calendar
+// since the last pull.
+type EventChange struct {
+	EventID          string
+	RecurringEventID string
+	Cancelled        bool
+	AllDay           bool
+	Start            *time.Time
+	End              *time.Time
+	Updated          time.Time
+}
+
+// EventChanges is one incremental sync page set plus the token for the next
+// pull.
+type EventChanges struct {
+	Events        []EventChange
+	NextSyncToken string
+	FullSync      bool
+}
+
+// SyncState keeps the Calendar syncToken of each user calendar between pulls.
+type SyncState struct {
+	UserID       uuid.UUID  `gorm:"type:uuid;primaryKey" json:"user_id"`
+	CalendarID   string     `gorm:"primaryKey" json:"calendar_id"`
+	SyncToken    string     `json:"-"`
+	LastSyncedAt *time.Time `json:"last_synced_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func (SyncState) TableName() string {
+	return "calendar_sync_states"
+}
+
+const (
+	PrimaryCalendarID     = "primary"
+	DedicatedCalendarName = "Chronos"
+)
+
+type Reminder struct {
+	Method  string `json:"method"`
+	Minutes int64  `json:"minutes"`
+}
+
+// CalendarPreferences controls where and how a user's tasks are written to
+// Google Calendar. Maps are keyed by task type (EVENT, STUDY, PROJECT) and
+// task priority (LOW, MEDIUM, HIGH).
+type CalendarPreferences struct {
+	UserID              uuid.UUID         `gorm:"type:uuid;primaryKey" json:"user_id"`
+	CalendarIDs         map[string]string `gorm:"type:jsonb;serializer:json" json:"calendar_ids"`
+	PriorityColors      map[string]string `gorm:"type:jsonb;serializer:json" json:"priority_colors"`
+	Reminders           []Reminder        `gorm:"type:jsonb;serializer:json" json:"reminders"`
+	DedicatedCalendarID string            `json:"dedicated_calendar_id,omitempty"`
+	CreatedAt           time.Time         `json:"created_at"`
+	UpdatedAt           time.Time         `json:"updated_at"`
+}
+
+func (CalendarPreferences) TableName() string {
+	return "calendar_preferences"
+}
+
+// CalendarFor returns the calendar tasks of the given type are written to.
+func (p *CalendarPreferences) CalendarFor(taskType string) string {
+	if id := p.CalendarIDs[taskType]; id != "" {
+		return id
+	}
+	return PrimaryCalendarID
+}
+
+// CalendarIDsInUse lists the distinct calendars tasks are written to.
+func (p *CalendarPreferences) CalendarIDsInUse() []string {
+	seen := map[string]bool{}
+	var ids []string
+	for _, taskType := range TaskTypes {
+		id := p.CalendarFor(taskType)
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// TaskTypes and TaskPriorities mirror the task enums, which this package
+// cannot import.
+var (
+	TaskTypes      = []string{"EVENT", "STUDY", "PROJECT"}
+	TaskPriorities = []string{"LOW", "MEDIUM", "HIGH"}
+)
+
+type CalendarListEntry struct {
+	ID              string `json:"id"`
+	Summary         string `json:"summary"`
+	BackgroundColor string `json:"background_color,omitempty"`
+	Primary         bool   `json:"primary"`
+	AccessRole      string `json:"access_role"`
+}
+
+// Writable reports whether events can be created in the calendar.
+func (c CalendarListEntry) Writable() bool {
+	return c.AccessRole == "owner" || c.AccessRole == "writer"
 }
@@ -0,0 +1,85 @@
+package googlecalendar
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/i18n"
+)
+
+type Handler struct {
+	service PreferencesService
+}
+
+func NewHandler(s PreferencesService) *Handler {
+	return &Handler{service: s}
+}
+
+func (h *Handler) ListCalendars(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	calendars, err := h.service.ListCalendars(r.Context())
+	if err != nil {
+		if !writeCalendarError(w, r, err) {
+			log.WithError(err).Error("Failed to list calendars")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, calendars)
+}
+
+func (h *Handler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	prefs, err := h.service.GetPreferences(r.Context())
+	if err != nil {
+		if !writeCalendarError(w, r, err) {
+			log.WithError(err).Error("Failed to get calendar preferences")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, prefs)
+}
+
+func (h *Handler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var dto UpdatePreferencesDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	prefs, err := h.service.UpdatePreferences(r.Context(), &dto)
+	if err != nil {
+		if !writeCalendarError(w, r, err) {
+			log.WithError(err).Error("Failed to update calendar preferences")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, prefs)
+}
+
+// writeCalendarError maps the errors shared by all calendar endpoints. It
+// reports whether a response was written.
+func writeCalendarError(w http.ResponseWriter, r *http.Request, err error) bool {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrMissingCalendarTokens), errors.Is(err, ErrCalendarDisconnected):
+		i18n.Error(w, r, "google calendar not connected", http.StatusConflict)
+	case errors.Is(err, ErrInvalidPreferences):
+		i18n.Error(w, r, err.Error(), http.StatusBadRequest)
+	default:
+		return false
+	}
+	return true
+}
@@ -12,7 +12,7 @@ var ErrNoValidDates = errors.New("task has no valid dates for calendar event")
 
 type CalendarManager interface {
 	SyncTask(ctx context.Context, userID uuid.UUID, task *CalendarTask) (eventID string, err error)
-	RemoveTask(ctx context.Context, userID uuid.UUID, eventID string) error
+	RemoveTask(ctx context.Context, userID uuid.UUID, calendarID, eventID string) error
 }
 
 type calendarManager struct {
@@ -33,7 +33,7 @@ func (m *calendarManager) SyncTask(ctx context.Context, userID uuid.UUID, task *
 
 	if hasEventID && !hasValidDates {
 		log.Infof("Task %s no longer has valid dates, deleting calendar event", task.ID)
-		if err := m.calendarService.DeleteEventFromCalendar(ctx, userID, *task.GoogleCalendarEventID); err != nil {
+		if err := m.calendarService.DeleteEventFromCalendar(ctx, userID, task.CalendarID, *task.GoogleCalendarEventID); err != nil {
 			log.WithError(err).Warnf("Failed to delete calendar event for task %s", task.ID)
 		}
 		return "", nil
@@ -66,14 +66,14 @@ func (m *calendarManager) SyncTask(ctx context.Context, userID uuid.UUID, task *
 	return eventID, nil
 }
 
-func (m *calendarManager) RemoveTask(ctx context.Context, userID uuid.UUID, eventID string) error {
+func (m *calendarManager) RemoveTask(ctx context.Context, userID uuid.UUID, calendarID, eventID string) error {
 	if eventID == "" {
 		return nil
 	}
 
 	log := config.WithContext(ctx)
 
-	if err := m.calendarService.DeleteEventFromCalendar(ctx, userID, eventID); err != nil {
+	if err := m.calendarService.DeleteEventFromCalendar(ctx, userID, calendarID, eventID); err != nil {
 		log.WithError(err).Warnf("Failed to delete calendar event %s", eventID)
 		return err
 	}
@@ -0,0 +1,219 @@
+package googlecalendar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+)
+
+var (
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrInvalidPreferences = errors.New("invalid calendar preferences")
+)
+
+const (
+	// Google allows at most 5 reminder overrides, up to 4 weeks before.
+	maxReminders       = 5
+	maxReminderMinutes = 40320
+)
+
+// UpdatePreferencesDTO replaces the fields that are present. With
+// CreateDedicatedCalendar, every task type not given in CalendarIDs is moved
+// to a "Chronos" calendar, created on first use.
+type UpdatePreferencesDTO struct {
+	CalendarIDs             map[string]string `json:"calendar_ids"`
+	PriorityColors          map[string]string `json:"priority_colors"`
+	Reminders               *[]Reminder       `json:"reminders"`
+	CreateDedicatedCalendar bool              `json:"create_dedicated_calendar"`
+}
+
+type PreferencesService interface {
+	GetPreferences(ctx context.Context) (*CalendarPreferences, error)
+	UpdatePreferences(ctx context.Context, dto *UpdatePreferencesDTO) (*CalendarPreferences, error)
+	ListCalendars(ctx context.Context) ([]CalendarListEntry, error)
+}
+
+type preferencesService struct {
+	repo            PreferencesRepository
+	calendarService CalendarService
+}
+
+func NewPreferencesService(repo PreferencesRepository, calendarService CalendarService) PreferencesService {
+	return &preferencesService{
+		repo:            repo,
+		calendarService: calendarService,
+	}
+}
+
+func (s *preferencesService) GetPreferences(ctx context.Context) (*CalendarPreferences, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs, err := s.repo.Get(userID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to load calendar preferences")
+		return nil, err
+	}
+	return prefs, nil
+}
+
+func (s *preferencesService) ListCalendars(ctx context.Context) ([]CalendarListEntry, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.calendarService.ListCalendars(ctx, userID)
+}
+
+func (s *preferencesService) UpdatePreferences(ctx context.Context, dto *UpdatePreferencesDTO) (*CalendarPreferences, error) {
+	log := config.WithContext(ctx)
+
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidatePreferences(dto); err != nil {
+		return nil, err
+	}
+
+	prefs, err := s.repo.Get(userID)
+	if err != nil {
+		log.WithError(err).Error("Failed to load calendar preferences")
+		return nil, err
+	}
+
+	if dto.CalendarIDs != nil || dto.CreateDedicatedCalendar {
+		calendars, err := s.calendarService.ListCalendars(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		calendarIDs := map[string]string{}
+		for taskType, id := range dto.CalendarIDs {
+			if id != "" && id != PrimaryCalendarID && !isWritableCalendar(calendars, id) {
+				return nil, fmt.Errorf("%w: calendar %s is not writable", ErrInvalidPreferences, id)
+			}
+			calendarIDs[taskType] = id
+		}
+
+		if dto.CreateDedicatedCalendar {
+			dedicatedID, err := s.ensureDedicatedCalendar(ctx, userID, prefs, calendars)
+			if err != nil {
+				return nil, err
+			}
+			for _, taskType := range TaskTypes {
+				if _, ok := dto.CalendarIDs[taskType]; !ok {
+					calendarIDs[taskType] = dedicatedID
+				}
+			}
+		}
+		prefs.CalendarIDs = calendarIDs
+	}
+
+	if dto.PriorityColors != nil {
+		prefs.PriorityColors = dto.PriorityColors
+	}
+	if dto.Reminders != nil {
+		prefs.Reminders = *dto.Reminders
+	}
+
+	now := time.Now()
+	if prefs.CreatedAt.IsZero() {
+		prefs.CreatedAt = now
+	}
+	prefs.UpdatedAt = now
+
+	if err := s.repo.Save(prefs); err != nil {
+		log.WithError(err).Error("Failed to save calendar preferences")
+		return nil, err
+	}
+
+	log.WithField("user_id", userID).Info("Calendar preferences updated")
+	return prefs, nil
+}
+
+// ValidatePreferences checks keys, color IDs and reminder limits before
+// anything is sent to Google.
+func ValidatePreferences(dto *UpdatePreferencesDTO) error {
+	for taskType := range dto.CalendarIDs {
+		if !slices.Contains(TaskTypes, taskType) {
+			return fmt.Errorf("%w: unknown task type %s", ErrInvalidPreferences, taskType)
+		}
+	}
+
+	for priority, color := range dto.PriorityColors {
+		if !slices.Contains(TaskPriorities, priority) {
+			return fmt.Errorf("%w: unknown priority %s", ErrInvalidPreferences, priority)
+		}
+		if !isEventColorID(color) {
+			return fmt.Errorf("%w: color %q must be an event color id from 1 to 11", ErrInvalidPreferences, color)
+		}
+	}
+
+	if dto.Reminders != nil {
+		if len(*dto.Reminders) > maxReminders {
+			return fmt.Errorf("%w: at most %d reminders", ErrInvalidPreferences, maxReminders)
+		}
+		for _, r := range *dto.Reminders {
+			if r.Method != "popup" && r.Method != "email" {
+				return fmt.Errorf("%w: reminder method must be popup or email", ErrInvalidPreferences)
+			}
+			if r.Minutes < 0 || r.Minutes > maxReminderMinutes {
+				return fmt.Errorf("%w: reminder minutes must be between 0 and %d", ErrInvalidPreferences, maxReminderMinutes)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ensureDedicatedCalendar reuses the calendar created earlier when it still
+// exists, so toggling the option does not pile up "Chronos" calendars.
+func (s *preferencesService) ensureDedicatedCalendar(ctx context.Context, userID uuid.UUID, prefs *CalendarPreferences, calendars []CalendarListEntry) (string, error) {
+	if prefs.DedicatedCalendarID != "" && isWritableCalendar(calendars, prefs.DedicatedCalendarID) {
+		return prefs.DedicatedCalendarID, nil
+	}
+
+	id, err := s.calendarService.CreateCalendar(ctx, userID, DedicatedCalendarName)
+	if err != nil {
+		return "", err
+	}
+	prefs.DedicatedCalendarID = id
+	return id, nil
+}
+
+func (s *preferencesService) getUserID(ctx context.Context) (uuid.UUID, error) {
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Warn("Unauthorized access attempt")
+		return uuid.Nil, ErrUnauthorized
+	}
+	return uuid.MustParse(claims.UserID), nil
+}
+
+func isWritableCalendar(calendars []CalendarListEntry, id string) bool {
+	for _, c := range calendars {
+		if c.ID == id {
+			return c.Writable()
+		}
+	}
+	return false
+}
+
+// isEventColorID accepts Google's fixed event palette, ids "1" to "11".
+func isEventColorID(id string) bool {
+	switch id {
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package googlecalendar_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	googlecalendar "github.com/saulo-duarte/chronos-lambda/internal/google_calendar"
+	"golang.org/x/oauth2"
+	gcal "google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+)
+
+func TestValidatePreferences(t *testing.T) {
+	reminders := func(r ...googlecalendar.Reminder) *[]googlecalendar.Reminder { return &r }
+
+	cases := []struct {
+		name  string
+		dto   googlecalendar.UpdatePreferencesDTO
+		valid bool
+	}{
+		{
+			name: "ValidPreferences",
+			dto: googlecalendar.UpdatePreferencesDTO{
+				CalendarIDs:    map[string]string{"STUDY": "estudos@group.calendar.google.com"},
+				PriorityColors: map[string]string{"HIGH": "11", "LOW": "2"},
+				Reminders:      reminders(googlecalendar.Reminder{Method: "popup", Minutes: 30}),
+			},
+			valid: true,
+		},
+		{
+			name:  "UnknownTaskType",
+			dto:   googlecalendar.UpdatePreferencesDTO{CalendarIDs: map[string]string{"MEETING": "primary"}},
+			valid: false,
+		},
+		{
+			name:  "InvalidColor",
+			dto:   googlecalendar.UpdatePreferencesDTO{PriorityColors: map[string]string{"HIGH": "12"}},
+			valid: false,
+		},
+		{
+			name:  "InvalidReminderMethod",
+			dto:   googlecalendar.UpdatePreferencesDTO{Reminders: reminders(googlecalendar.Reminder{Method: "sms", Minutes: 10})},
+			valid: false,
+		},
+		{
+			name: "TooManyReminders",
+			dto: googlecalendar.UpdatePreferencesDTO{Reminders: reminders(
+				googlecalendar.Reminder{Method: "popup", Minutes: 1},
+				googlecalendar.Reminder{Method: "popup", Minutes: 2},
+				googlecalendar.Reminder{Method: "popup", Minutes: 3},
+				googlecalendar.Reminder{Method: "popup", Minutes: 4},
+				googlecalendar.Reminder{Method: "popup", Minutes: 5},
+				googlecalendar.Reminder{Method: "popup", Minutes: 6},
+			)},
+			valid: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := googlecalendar.ValidatePreferences(&tc.dto)
+			if tc.valid && err != nil {
+				t.Errorf("Preferências deveriam ser válidas, erro: %v", err)
+			}
+			if !tc.valid && !errors.Is(err, googlecalendar.ErrInvalidPreferences) {
+				t.Errorf("Erro esperado ErrInvalidPreferences, recebido: %v", err)
+			}
+		})
+	}
+}
+
+func TestCalendarPreferencesCalendarFor(t *testing.T) {
+	prefs := &googlecalendar.CalendarPreferences{
+		CalendarIDs: map[string]string{"STUDY": "estudos", "PROJECT": "estudos"},
+	}
+
+	if got := prefs.CalendarFor("STUDY"); got != "estudos" {
+		t.Errorf("Agenda incorreta para STUDY: %s", got)
+	}
+	if got := prefs.CalendarFor("EVENT"); got != googlecalendar.PrimaryCalendarID {
+		t.Errorf("Tipo sem preferência deveria usar a agenda principal, recebido: %s", got)
+	}
+
+	inUse := prefs.CalendarIDsInUse()
+	if len(inUse) != 2 {
+		t.Errorf("Agendas em uso deveriam ser deduplicadas, recebido: %v", inUse)
+	}
+}
+
+func TestAddEventUsesPreferences(t *testing.T) {
+	var path string
+	var sent gcal.Event
+	apiSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(gcal.Event{Id: sent.Id})
+	}))
+	defer apiSrv.Close()
+
+	expiry := time.Now().Add(time.Hour)
+	repo := &fakeUserRepo{user: newTestUser(t, &expiry)}
+	prefs := &fakePreferencesRepo{prefs: &googlecalendar.CalendarPreferences{
+		CalendarIDs:    map[string]string{"STUDY": "estudos"},
+		PriorityColors: map[string]string{"HIGH": "11"},
+		Reminders:      []googlecalendar.Reminder{{Method: "popup", Minutes: 15}},
+	}}
+	svc := googlecalendar.NewCalendarService(repo, prefs, &oauth2.Config{}, option.WithEndpoint(apiSrv.URL))
+
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	task := &googlecalendar.CalendarTask{ID: uuid.New(), Name: "Revisar", StartDate: &start, Type: "STUDY", Priority: "HIGH"}
+
+	eventID, err := svc.AddEventToCalendar(context.Background(), repo.user.ID, task)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if !strings.Contains(path, "/calendars/estudos/events") {
+		t.Errorf("Evento deveria ser criado na agenda de estudos, caminho: %s", path)
+	}
+	if task.CalendarID != "estudos" {
+		t.Errorf("CalendarID da tarefa não foi atualizado: %s", task.CalendarID)
+	}
+	if eventID != googlecalendar.EventIDForTask(task.ID) {
+		t.Errorf("Event ID deveria ser derivado da tarefa, recebido: %s", eventID)
+	}
+	if sent.ColorId != "11" {
+		t.Errorf("Cor da prioridade não aplicada: %q", sent.ColorId)
+	}
+	if sent.Reminders == nil || sent.Reminders.UseDefault || len(sent.Reminders.Overrides) != 1 || sent.Reminders.Overrides[0].Minutes != 15 {
+		t.Errorf("Lembretes não aplicados: %+v", sent.Reminders)
+	}
+}
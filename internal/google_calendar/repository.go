@@ -0,0 +1,91 @@
+package googlecalendar
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SyncStateRepository interface {
+	Get(userID uuid.UUID, calendarID string) (*SyncState, error)
+	Save(state *SyncState) error
+	ListSyncableUserIDs() ([]uuid.UUID, error)
+}
+
+type syncStateRepository struct {
+	db *gorm.DB
+}
+
+func NewSyncStateRepository(db *gorm.DB) SyncStateRepository {
+	return &syncStateRepository{db: db}
+}
+
+// Get returns the stored state, or an empty one when the calendar was never
+// synced.
+func (r *syncStateRepository) Get(userID uuid.UUID, calendarID string) (*SyncState, error) {
+	var state SyncState
+	if err := r.db.First(&state, "user_id = ? AND calendar_id = ?", userID, calendarID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &SyncState{UserID: userID, CalendarID: calendarID}, nil
+		}
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *syncStateRepository) Save(state *SyncState) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "calendar_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"sync_token", "last_synced_at", "updated_at"}),
+	}).Create(state).Error
+}
+
+// ListSyncableUserIDs returns every user with a connected Google account
+// whose grant has not been revoked.
+func (r *syncStateRepository) ListSyncableUserIDs() ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := r.db.Table("users").
+		Where("encrypted_google_access_token <> ''").
+		Where("google_calendar_disconnected_at IS NULL").
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+type PreferencesRepository interface {
+	Get(userID uuid.UUID) (*CalendarPreferences, error)
+	Save(prefs *CalendarPreferences) error
+}
+
+type preferencesRepository struct {
+	db *gorm.DB
+}
+
+func NewPreferencesRepository(db *gorm.DB) PreferencesRepository {
+	return &preferencesRepository{db: db}
+}
+
+// Get returns the stored preferences, or defaults (primary calendar, no
+// colors, Google's default reminders) when the user never set any.
+func (r *preferencesRepository) Get(userID uuid.UUID) (*CalendarPreferences, error) {
+	var prefs CalendarPreferences
+	if err := r.db.First(&prefs, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &CalendarPreferences{UserID: userID}, nil
+		}
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+func (r *preferencesRepository) Save(prefs *CalendarPreferences) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"calendar_ids", "priority_colors", "reminders", "dedicated_calendar_id", "updated_at",
+		}),
+	}).Create(prefs).Error
+}
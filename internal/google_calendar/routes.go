@@ -0,0 +1,20 @@
+package googlecalendar
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+)
+
+func Routes(h *Handler) http.Handler {
+	r := chi.NewRouter()
+
+	r.Use(auth.AuthMiddleware)
+
+	r.Get("/calendars", h.ListCalendars)
+	r.Get("/preferences", h.GetPreferences)
+	r.Put("/preferences", h.UpdatePreferences)
+
+	return r
+}
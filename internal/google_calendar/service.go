@@ -3,11 +3,13 @@ package googlecalendar
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
 	"github.com/saulo-duarte/chronos-lambda/internal/user"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
 	"golang.org/x/oauth2"
 	gcal "google.golang.org/api/calendar/v3"
 	"google.golang.org/api/googleapi"
@@ -19,48 +21,63 @@ var (
 	ErrDecryptionFailed      = errors.New("failed to decrypt user's google token")
 	ErrMissingCalendarTokens = errors.New("user has no google access token")
 	ErrInvalidEventDates     = errors.New("task must have valid start or due dates")
+	ErrCalendarDisconnected  = errors.New("google calendar access was revoked")
 )
 
 type CalendarService interface {
 	AddEventToCalendar(ctx context.Context, userID uuid.UUID, task *CalendarTask) (string, error)
 	UpdateEventInCalendar(ctx context.Context, userID uuid.UUID, task *CalendarTask) error
-	DeleteEventFromCalendar(ctx context.Context, userID uuid.UUID, googleEventID string) error
+	DeleteEventFromCalendar(ctx context.Context, userID uuid.UUID, calendarID, googleEventID string) error
+	ListEventChanges(ctx context.Context, userID uuid.UUID, calendarID, syncToken string) (*EventChanges, error)
+	ListCalendars(ctx context.Context, userID uuid.UUID) ([]CalendarListEntry, error)
+	CreateCalendar(ctx context.Context, userID uuid.UUID, summary string) (string, error)
+	SyncedCalendarIDs(ctx context.Context, userID uuid.UUID) ([]string, error)
 }
 
 type calendarService struct {
-	userRepo    user.UserRepository
-	oauthConfig *oauth2.Config
+	userRepo      user.UserRepository
+	prefsRepo     PreferencesRepository
+	oauthConfig   *oauth2.Config
+	clientOptions []option.ClientOption
 }
 
-func NewCalendarService(userRepo user.UserRepository, oauthConfig *oauth2.Config) CalendarService {
+// NewCalendarService builds the Google Calendar client. Extra client options
+// (e.g. option.WithEndpoint) are appended to every API client, which lets
+// tests point it at a fake server.
+func NewCalendarService(userRepo user.UserRepository, prefsRepo PreferencesRepository, oauthConfig *oauth2.Config, opts ...option.ClientOption) CalendarService {
 	return &calendarService{
-		userRepo:    userRepo,
-		oauthConfig: oauthConfig,
+		userRepo:      userRepo,
+		prefsRepo:     prefsRepo,
+		oauthConfig:   oauthConfig,
+		clientOptions: opts,
 	}
 }
 
 // --- Private Helper Methods ---
 
-func (s *calendarService) getUserTokens(ctx context.Context, userID uuid.UUID) (*oauth2.Token, error) {
+func (s *calendarService) getUserTokens(ctx context.Context, userID uuid.UUID) (*oauth2.Token, *user.User, error) {
 	log := config.WithContext(ctx)
 
 	u, err := s.userRepo.GetByID(userID.String())
 	if err != nil {
 		log.WithError(err).Error("Failed to retrieve user for calendar client")
-		return nil, err
+		return nil, nil, err
 	}
 	if u == nil {
-		return nil, ErrUserNotFound
+		return nil, nil, ErrUserNotFound
 	}
 
 	if u.EncryptedGoogleAccessToken == "" {
-		return nil, ErrMissingCalendarTokens
+		return nil, nil, ErrMissingCalendarTokens
+	}
+	if u.GoogleCalendarDisconnectedAt != nil {
+		return nil, nil, ErrCalendarDisconnected
 	}
 
 	accessToken, err := config.Decrypt(u.EncryptedGoogleAccessToken)
 	if err != nil {
 		log.WithError(err).Error("Failed to decrypt access token")
-		return nil, ErrDecryptionFailed
+		return nil, nil, ErrDecryptionFailed
 	}
 
 	refreshToken := ""
@@ -68,98 +85,206 @@ func (s *calendarService) getUserTokens(ctx context.Context, userID uuid.UUID) (
 		refreshToken, err = config.Decrypt(u.EncryptedGoogleRefreshToken)
 		if err != nil {
 			log.WithError(err).Error("Failed to decrypt refresh token")
-			return nil, ErrDecryptionFailed
+			return nil, nil, ErrDecryptionFailed
 		}
 	}
 
+	// Tokens saved before expiry tracking have no known expiry, so they are
+	// refreshed once and persisted with the real one.
+	expiry := time.Now().Add(-time.Hour)
+	if u.GoogleTokenExpiry != nil {
+		expiry = *u.GoogleTokenExpiry
+	}
+
 	return &oauth2.Token{
 		AccessToken:  accessToken,
 		TokenType:    "Bearer",
 		RefreshToken: refreshToken,
-		Expiry:       time.Now().Add(-time.Hour), // Force refresh
-	}, nil
+		Expiry:       expiry,
+	}, u, nil
 }
 
-func (s *calendarService) refreshTokenIfNeeded(ctx context.Context, token *oauth2.Token) (*oauth2.Token, error) {
+func (s *calendarService) refreshTokenIfNeeded(ctx context.Context, userID uuid.UUID, token *oauth2.Token) (*oauth2.Token, error) {
 	log := config.WithContext(ctx)
 
+	if token.Valid() {
+		return token, nil
+	}
+
 	tokenSource := s.oauthConfig.TokenSource(ctx, token)
 	newToken, err := tokenSource.Token()
 	if err != nil {
+		if isInvalidGrant(err) {
+			log.WithField("user_id", userID).Warn("Google grant revoked, marking calendar as disconnected")
+			if markErr := s.userRepo.MarkGoogleCalendarDisconnected(userID.String(), time.Now()); markErr != nil {
+				log.WithError(markErr).Error("Failed to mark calendar as disconnected")
+			}
+			return nil, ErrCalendarDisconnected
+		}
 		log.WithError(err).Error("Failed to refresh Google token")
 		return nil, err
 	}
 
 	if newToken.AccessToken != token.AccessToken {
 		log.Info("Google token refreshed successfully")
-		// TODO: Considere persistir o novo token aqui
+		if err := s.persistToken(userID, token, newToken); err != nil {
+			log.WithError(err).Error("Failed to persist refreshed Google token")
+		}
 	}
 
 	return newToken, nil
 }
 
-func (s *calendarService) getCalendarClient(ctx context.Context, userID uuid.UUID) (*gcal.Service, error) {
+func (s *calendarService) persistToken(userID uuid.UUID, old, refreshed *oauth2.Token) error {
+	encryptedAccess, err := config.Encrypt(refreshed.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	encryptedRefresh := ""
+	if refreshed.RefreshToken != "" && refreshed.RefreshToken != old.RefreshToken {
+		encryptedRefresh, err = config.Encrypt(refreshed.RefreshToken)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.userRepo.UpdateGoogleTokens(userID.String(), encryptedAccess, encryptedRefresh, refreshed.Expiry)
+}
+
+func isInvalidGrant(err error) bool {
+	var retrieveErr *oauth2.RetrieveError
+	return errors.As(err, &retrieveErr) && retrieveErr.ErrorCode == "invalid_grant"
+}
+
+// calendarClient is an authorized API client plus its owner's time zone.
+type calendarClient struct {
+	*gcal.Service
+	loc *time.Location
+}
+
+func (s *calendarService) getCalendarClient(ctx context.Context, userID uuid.UUID) (*calendarClient, error) {
 	log := config.WithContext(ctx)
 
-	token, err := s.getUserTokens(ctx, userID)
+	token, u, err := s.getUserTokens(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	token, err = s.refreshTokenIfNeeded(ctx, token)
+	token, err = s.refreshTokenIfNeeded(ctx, userID, token)
 	if err != nil {
 		return nil, err
 	}
 
-	client := oauth2.NewClient(ctx, s.oauthConfig.TokenSource(ctx, token))
+	client := oauth2.NewClient(ctx, oauth2.StaticTokenSource(token))
 
-	srv, err := gcal.NewService(ctx, option.WithHTTPClient(client))
+	opts := append([]option.ClientOption{option.WithHTTPClient(client)}, s.clientOptions...)
+	srv, err := gcal.NewService(ctx, opts...)
 	if err != nil {
 		log.WithError(err).Error("Failed to create Calendar service client")
 		return nil, err
 	}
 
-	return srv, nil
+	return &calendarClient{Service: srv, loc: u.Location()}, nil
 }
 
-func (s *calendarService) buildCalendarEvent(task *CalendarTask) (*gcal.Event, error) {
+func (s *calendarService) buildCalendarEvent(task *CalendarTask, prefs *CalendarPreferences, loc *time.Location) (*gcal.Event, error) {
 	if task.StartDate == nil && task.DueDate == nil {
 		return nil, ErrInvalidEventDates
 	}
 
 	event := &gcal.Event{
+		Status:      "confirmed",
 		Summary:     task.Name,
 		Description: task.Description,
-		Reminders: &gcal.EventReminders{
-			UseDefault: false,
-		},
+		ColorId:     prefs.PriorityColors[task.Priority],
+		Reminders:   buildReminders(prefs.Reminders),
 	}
+	event.Start, event.End = EventTimes(task, loc)
 
-	// Define o horário de início
-	if task.StartDate != nil {
-		event.Start = &gcal.EventDateTime{
-			DateTime: task.StartDate.Format(time.RFC3339),
-		}
-	} else if task.DueDate != nil {
-		// Se não tem StartDate, usa 1 hora antes da DueDate
-		event.Start = &gcal.EventDateTime{
-			DateTime: task.DueDate.Add(-time.Hour).Format(time.RFC3339),
-		}
+	if len(task.Recurrence) > 0 {
+		event.Recurrence = task.Recurrence
 	}
 
-	// Define o horário de término
-	if task.DueDate != nil {
-		event.End = &gcal.EventDateTime{
-			DateTime: task.DueDate.Format(time.RFC3339),
+	return event, nil
+}
+
+// EventTimes maps the task dates to the event window in loc. All-day tasks
+// become date-only events whose end is exclusive (the day after). A timed
+// task with a single date becomes a one-hour event: it starts at StartDate,
+// or ends at DueDate. The time zone is always set so recurring series expand
+// in local time.
+func EventTimes(task *CalendarTask, loc *time.Location) (start, end *gcal.EventDateTime) {
+	if task.AllDay {
+		first, last := task.StartDate, task.DueDate
+		if first == nil {
+			first = last
+		}
+		if last == nil {
+			last = first
 		}
-	} else if task.StartDate != nil {
-		// Se não tem DueDate, usa 1 hora depois da StartDate
-		event.End = &gcal.EventDateTime{
-			DateTime: task.StartDate.Add(time.Hour).Format(time.RFC3339),
+		startDay := util.DateOnly(first.In(loc))
+		endDay := util.DateOnly(last.In(loc))
+		if endDay.Before(startDay) {
+			endDay = startDay
 		}
+		return &gcal.EventDateTime{Date: util.FormatDate(startDay), TimeZone: loc.String()},
+			&gcal.EventDateTime{Date: util.FormatDate(endDay.AddDate(0, 0, 1)), TimeZone: loc.String()}
 	}
 
-	return event, nil
+	var first, last time.Time
+	switch {
+	case task.StartDate != nil && task.DueDate != nil:
+		first, last = *task.StartDate, *task.DueDate
+	case task.StartDate != nil:
+		first = *task.StartDate
+		last = first.Add(time.Hour)
+	default:
+		last = *task.DueDate
+		first = last.Add(-time.Hour)
+	}
+
+	return &gcal.EventDateTime{DateTime: first.In(loc).Format(time.RFC3339), TimeZone: loc.String()},
+		&gcal.EventDateTime{DateTime: last.In(loc).Format(time.RFC3339), TimeZone: loc.String()}
+}
+
+// buildReminders falls back to the calendar's default reminders when the user
+// configured none.
+func buildReminders(reminders []Reminder) *gcal.EventReminders {
+	if len(reminders) == 0 {
+		return &gcal.EventReminders{UseDefault: true}
+	}
+
+	overrides := make([]*gcal.EventReminder, 0, len(reminders))
+	for _, r := range reminders {
+		overrides = append(overrides, &gcal.EventReminder{
+			Method:          r.Method,
+			Minutes:         r.Minutes,
+			ForceSendFields: []string{"Minutes"},
+		})
+	}
+
+	return &gcal.EventReminders{
+		UseDefault:      false,
+		Overrides:       overrides,
+		ForceSendFields: []string{"UseDefault"},
+	}
+}
+
+func (s *calendarService) loadPreferences(ctx context.Context, userID uuid.UUID) (*CalendarPreferences, error) {
+	prefs, err := s.prefsRepo.Get(userID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to load calendar preferences")
+		return nil, err
+	}
+	return prefs, nil
+}
+
+func currentCalendar(task *CalendarTask) string {
+	if task.CalendarID == "" {
+		return PrimaryCalendarID
+	}
+	return task.CalendarID
 }
 
 func (s *calendarService) isEventNotFoundError(err error) bool {
@@ -169,6 +294,20 @@ func (s *calendarService) isEventNotFoundError(err error) bool {
 	return false
 }
 
+func (s *calendarService) isDuplicateEventError(err error) bool {
+	if apiErr, ok := err.(*googleapi.Error); ok {
+		return apiErr.Code == 409
+	}
+	return false
+}
+
+// EventIDForTask derives the Calendar event ID from the task ID. Google
+// accepts lowercase hex, so inserting with this ID makes a retried insert
+// hit the existing event instead of creating a duplicate.
+func EventIDForTask(taskID uuid.UUID) string {
+	return strings.ReplaceAll(taskID.String(), "-", "")
+}
+
 // --- Public Methods ---
 
 func (s *calendarService) AddEventToCalendar(ctx context.Context, userID uuid.UUID, task *CalendarTask) (string, error) {
@@ -179,7 +318,12 @@ func (s *calendarService) AddEventToCalendar(ctx context.Context, userID uuid.UU
 		return "", err
 	}
 
-	event, err := s.buildCalendarEvent(task)
+	prefs, err := s.loadPreferences(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	event, err := s.buildCalendarEvent(task, prefs, srv.loc)
 	if err != nil {
 		if errors.Is(err, ErrInvalidEventDates) {
 			log.Warnf("Task %s has no valid dates to create a calendar event", task.ID)
@@ -188,11 +332,18 @@ func (s *calendarService) AddEventToCalendar(ctx context.Context, userID uuid.UU
 		return "", err
 	}
 
-	calEvent, err := srv.Events.Insert("primary", event).Context(ctx).Do()
+	calendarID := prefs.CalendarFor(task.Type)
+	event.Id = EventIDForTask(task.ID)
+	calEvent, err := srv.Events.Insert(calendarID, event).Context(ctx).Do()
+	if err != nil && s.isDuplicateEventError(err) {
+		// A previous attempt already created it (or it was cancelled): update in place.
+		calEvent, err = srv.Events.Update(calendarID, event.Id, event).Context(ctx).Do()
+	}
 	if err != nil {
 		log.WithError(err).Error("Failed to insert calendar event")
 		return "", err
 	}
+	task.CalendarID = calendarID
 
 	log.WithField("event_id", calEvent.Id).Infof("Created calendar event for task %s", task.ID)
 	return calEvent.Id, nil
@@ -210,16 +361,34 @@ func (s *calendarService) UpdateEventInCalendar(ctx context.Context, userID uuid
 		return err
 	}
 
-	event, err := s.buildCalendarEvent(task)
+	prefs, err := s.loadPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	event, err := s.buildCalendarEvent(task, prefs, srv.loc)
 	if err != nil {
 		if errors.Is(err, ErrInvalidEventDates) {
 			log.Warnf("Task %s no longer has valid dates, should be deleted", task.ID)
-			return s.DeleteEventFromCalendar(ctx, userID, *task.GoogleCalendarEventID)
+			return s.DeleteEventFromCalendar(ctx, userID, currentCalendar(task), *task.GoogleCalendarEventID)
 		}
 		return err
 	}
 
-	_, err = srv.Events.Update("primary", *task.GoogleCalendarEventID, event).Context(ctx).Do()
+	// The preferred calendar for the task type may have changed since the
+	// event was created.
+	calendarID := prefs.CalendarFor(task.Type)
+	if from := currentCalendar(task); from != calendarID {
+		_, err := srv.Events.Move(from, *task.GoogleCalendarEventID, calendarID).Context(ctx).Do()
+		if err != nil && !s.isEventNotFoundError(err) {
+			log.WithError(err).Error("Failed to move calendar event")
+			return err
+		}
+		log.Infof("Moved calendar event %s from %s to %s", *task.GoogleCalendarEventID, from, calendarID)
+	}
+	task.CalendarID = calendarID
+
+	_, err = srv.Events.Update(calendarID, *task.GoogleCalendarEventID, event).Context(ctx).Do()
 	if err != nil {
 		if s.isEventNotFoundError(err) {
 			log.Warnf("Calendar event %s not found, considering as already deleted", *task.GoogleCalendarEventID)
@@ -233,7 +402,7 @@ func (s *calendarService) UpdateEventInCalendar(ctx context.Context, userID uuid
 	return nil
 }
 
-func (s *calendarService) DeleteEventFromCalendar(ctx context.Context, userID uuid.UUID, googleEventID string) error {
+func (s *calendarService) DeleteEventFromCalendar(ctx context.Context, userID uuid.UUID, calendarID, googleEventID string) error {
 	log := config.WithContext(ctx)
 
 	if googleEventID == "" {
@@ -245,7 +414,11 @@ func (s *calendarService) DeleteEventFromCalendar(ctx context.Context, userID uu
 		return err
 	}
 
-	err = srv.Events.Delete("primary", googleEventID).Context(ctx).Do()
+	if calendarID == "" {
+		calendarID = PrimaryCalendarID
+	}
+
+	err = srv.Events.Delete(calendarID, googleEventID).Context(ctx).Do()
 	if err != nil {
 		if s.isEventNotFoundError(err) {
 			log.Warnf("Calendar event %s not found, considering as already deleted", googleEventID)
@@ -258,3 +431,83 @@ func (s *calendarService) DeleteEventFromCalendar(ctx context.Context, userID uu
 	log.WithField("event_id", googleEventID).Info("Deleted calendar event successfully")
 	return nil
 }
+
+func (s *calendarService) ListEventChanges(ctx context.Context, userID uuid.UUID, calendarID, syncToken string) (*EventChanges, error) {
+	log := config.WithContext(ctx)
+
+	srv, err := s.getCalendarClient(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := FetchEventChanges(ctx, srv.Service, calendarID, syncToken, srv.loc)
+	if err != nil {
+		if !errors.Is(err, ErrSyncTokenExpired) {
+			log.WithError(err).Error("Failed to list calendar event changes")
+		}
+		return nil, err
+	}
+
+	log.WithField("user_id", userID).Infof("Fetched %d calendar event changes", len(changes.Events))
+	return changes, nil
+}
+
+func (s *calendarService) ListCalendars(ctx context.Context, userID uuid.UUID) ([]CalendarListEntry, error) {
+	log := config.WithContext(ctx)
+
+	srv, err := s.getCalendarClient(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var calendars []CalendarListEntry
+	err = srv.CalendarList.List().Context(ctx).Pages(ctx, func(page *gcal.CalendarList) error {
+		for _, item := range page.Items {
+			calendars = append(calendars, CalendarListEntry{
+				ID:              item.Id,
+				Summary:         item.Summary,
+				BackgroundColor: item.BackgroundColor,
+				Primary:         item.Primary,
+				AccessRole:      item.AccessRole,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		log.WithError(err).Error("Failed to list calendars")
+		return nil, err
+	}
+
+	return calendars, nil
+}
+
+func (s *calendarService) CreateCalendar(ctx context.Context, userID uuid.UUID, summary string) (string, error) {
+	log := config.WithContext(ctx)
+
+	srv, err := s.getCalendarClient(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	created, err := srv.Calendars.Insert(&gcal.Calendar{
+		Summary:  summary,
+		TimeZone: srv.loc.String(),
+	}).Context(ctx).Do()
+	if err != nil {
+		log.WithError(err).Error("Failed to create calendar")
+		return "", err
+	}
+
+	log.WithField("calendar_id", created.Id).Infof("Created calendar %q", summary)
+	return created.Id, nil
+}
+
+// SyncedCalendarIDs returns the calendars the user's tasks are written to,
+// which are the ones the pull sync has to watch.
+func (s *calendarService) SyncedCalendarIDs(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	prefs, err := s.loadPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return prefs.CalendarIDsInUse(), nil
+}
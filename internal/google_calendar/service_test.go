@@ -0,0 +1,229 @@
+package googlecalendar_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	googlecalendar "github.com/saulo-duarte/chronos-lambda/internal/google_calendar"
+	"github.com/saulo-duarte/chronos-lambda/internal/user"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/option"
+)
+
+func TestMain(m *testing.M) {
+	config.Init()
+	os.Setenv("CRYPTO_KEY", "01234567890123456789012345678901")
+	config.InitCrypto()
+	os.Exit(m.Run())
+}
+
+type fakeUserRepo struct {
+	user.UserRepository
+	user            *user.User
+	savedAccess     string
+	savedRefresh    string
+	savedExpiry     time.Time
+	disconnectedAt  *time.Time
+	tokenUpdateRuns int
+}
+
+func (f *fakeUserRepo) GetByID(id string) (*user.User, error) {
+	return f.user, nil
+}
+
+func (f *fakeUserRepo) UpdateGoogleTokens(id, access, refresh string, expiry time.Time) error {
+	f.tokenUpdateRuns++
+	f.savedAccess, f.savedRefresh, f.savedExpiry = access, refresh, expiry
+	return nil
+}
+
+func (f *fakeUserRepo) MarkGoogleCalendarDisconnected(id string, at time.Time) error {
+	f.disconnectedAt = &at
+	return nil
+}
+
+type fakePreferencesRepo struct {
+	prefs *googlecalendar.CalendarPreferences
+}
+
+func (f *fakePreferencesRepo) Get(userID uuid.UUID) (*googlecalendar.CalendarPreferences, error) {
+	if f.prefs == nil {
+		return &googlecalendar.CalendarPreferences{UserID: userID}, nil
+	}
+	return f.prefs, nil
+}
+
+func (f *fakePreferencesRepo) Save(prefs *googlecalendar.CalendarPreferences) error {
+	f.prefs = prefs
+	return nil
+}
+
+func newTestUser(t *testing.T, expiry *time.Time) *user.User {
+	t.Helper()
+	access, _ := config.Encrypt("old-access")
+	refresh, _ := config.Encrypt("old-refresh")
+	return &user.User{
+		ID:                          uuid.New(),
+		EncryptedGoogleAccessToken:  access,
+		EncryptedGoogleRefreshToken: refresh,
+		GoogleTokenExpiry:           expiry,
+	}
+}
+
+func newTestCalendarService(t *testing.T, repo *fakeUserRepo, tokenHandler http.HandlerFunc) (googlecalendar.CalendarService, *int) {
+	t.Helper()
+	tokenCalls := 0
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenCalls++
+		tokenHandler(w, r)
+	}))
+	t.Cleanup(tokenSrv.Close)
+
+	apiSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items":[],"nextSyncToken":"token"}`))
+	}))
+	t.Cleanup(apiSrv.Close)
+
+	oauthConfig := &oauth2.Config{
+		ClientID: "client",
+		Endpoint: oauth2.Endpoint{TokenURL: tokenSrv.URL, AuthStyle: oauth2.AuthStyleInParams},
+	}
+	return googlecalendar.NewCalendarService(repo, &fakePreferencesRepo{}, oauthConfig, option.WithEndpoint(apiSrv.URL)), &tokenCalls
+}
+
+func TestCalendarServiceTokens(t *testing.T) {
+	t.Run("PersistsRefreshedToken", func(t *testing.T) {
+		repo := &fakeUserRepo{user: newTestUser(t, nil)}
+		svc, _ := newTestCalendarService(t, repo, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"new-access","refresh_token":"new-refresh","token_type":"Bearer","expires_in":3600}`))
+		})
+
+		if _, err := svc.ListEventChanges(context.Background(), repo.user.ID, "primary", ""); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+
+		if repo.tokenUpdateRuns != 1 {
+			t.Fatalf("Token deveria ser persistido uma vez, persistido %d vezes", repo.tokenUpdateRuns)
+		}
+		if access, _ := config.Decrypt(repo.savedAccess); access != "new-access" {
+			t.Errorf("Access token salvo incorreto: %s", access)
+		}
+		if refresh, _ := config.Decrypt(repo.savedRefresh); refresh != "new-refresh" {
+			t.Errorf("Refresh token rotacionado deveria ser salvo, recebido: %s", refresh)
+		}
+		if time.Until(repo.savedExpiry) < 50*time.Minute {
+			t.Errorf("Expiração salva incorreta: %v", repo.savedExpiry)
+		}
+	})
+
+	t.Run("ValidTokenSkipsRefresh", func(t *testing.T) {
+		expiry := time.Now().Add(time.Hour)
+		repo := &fakeUserRepo{user: newTestUser(t, &expiry)}
+		svc, tokenCalls := newTestCalendarService(t, repo, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("Token válido não deveria ser renovado")
+		})
+
+		if _, err := svc.ListEventChanges(context.Background(), repo.user.ID, "primary", ""); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if *tokenCalls != 0 || repo.tokenUpdateRuns != 0 {
+			t.Errorf("Nenhuma renovação esperada, chamadas: %d", *tokenCalls)
+		}
+	})
+
+	t.Run("InvalidGrantDisconnectsCalendar", func(t *testing.T) {
+		repo := &fakeUserRepo{user: newTestUser(t, nil)}
+		svc, _ := newTestCalendarService(t, repo, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant","error_description":"Token has been expired or revoked."}`))
+		})
+
+		_, err := svc.ListEventChanges(context.Background(), repo.user.ID, "primary", "")
+		if !errors.Is(err, googlecalendar.ErrCalendarDisconnected) {
+			t.Fatalf("Erro esperado ErrCalendarDisconnected, recebido: %v", err)
+		}
+		if repo.disconnectedAt == nil {
+			t.Error("Usuário deveria ser marcado como desconectado")
+		}
+	})
+
+	t.Run("DisconnectedUserIsNotCalled", func(t *testing.T) {
+		now := time.Now()
+		u := newTestUser(t, nil)
+		u.GoogleCalendarDisconnectedAt = &now
+		repo := &fakeUserRepo{user: u}
+		svc, tokenCalls := newTestCalendarService(t, repo, func(w http.ResponseWriter, r *http.Request) {})
+
+		_, err := svc.ListEventChanges(context.Background(), u.ID, "primary", "")
+		if !errors.Is(err, googlecalendar.ErrCalendarDisconnected) {
+			t.Fatalf("Erro esperado ErrCalendarDisconnected, recebido: %v", err)
+		}
+		if *tokenCalls != 0 {
+			t.Error("Google não deveria ser chamado para usuário desconectado")
+		}
+	})
+}
+
+func TestEventTimes(t *testing.T) {
+	lisbon, err := time.LoadLocation("Europe/Lisbon")
+	if err != nil {
+		t.Skipf("tzdata indisponível: %v", err)
+	}
+
+	t.Run("AllDayUsesExclusiveEndDate", func(t *testing.T) {
+		start := time.Date(2025, 3, 10, 23, 30, 0, 0, lisbon)
+		due := time.Date(2025, 3, 12, 8, 0, 0, 0, lisbon)
+		task := &googlecalendar.CalendarTask{StartDate: &start, DueDate: &due, AllDay: true}
+
+		s, e := googlecalendar.EventTimes(task, lisbon)
+		if s.Date != "2025-03-10" || e.Date != "2025-03-13" {
+			t.Errorf("Datas do evento de dia inteiro incorretas: %s - %s", s.Date, e.Date)
+		}
+		if s.DateTime != "" || e.DateTime != "" {
+			t.Error("Evento de dia inteiro não deveria ter horário")
+		}
+	})
+
+	t.Run("TimedEventCarriesUserTimezone", func(t *testing.T) {
+		start := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+		task := &googlecalendar.CalendarTask{StartDate: &start}
+
+		s, e := googlecalendar.EventTimes(task, lisbon)
+		if s.TimeZone != "Europe/Lisbon" || e.TimeZone != "Europe/Lisbon" {
+			t.Errorf("Fuso horário do usuário não aplicado: %s", s.TimeZone)
+		}
+		if s.DateTime != "2025-03-10T12:00:00Z" || e.DateTime != "2025-03-10T13:00:00Z" {
+			t.Errorf("Evento com apenas início deveria durar uma hora: %s - %s", s.DateTime, e.DateTime)
+		}
+	})
+
+	t.Run("SingleDueDateEndsAtDue", func(t *testing.T) {
+		due := time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)
+		task := &googlecalendar.CalendarTask{DueDate: &due}
+
+		s, e := googlecalendar.EventTimes(task, lisbon)
+		if s.DateTime != "2025-03-10T17:00:00Z" || e.DateTime != "2025-03-10T18:00:00Z" {
+			t.Errorf("Evento com apenas prazo deveria começar uma hora antes: %s - %s", s.DateTime, e.DateTime)
+		}
+	})
+
+	t.Run("SingleDueDateAllDay", func(t *testing.T) {
+		due := time.Date(2025, 12, 31, 0, 0, 0, 0, lisbon)
+		task := &googlecalendar.CalendarTask{DueDate: &due, AllDay: true}
+
+		s, e := googlecalendar.EventTimes(task, lisbon)
+		if s.Date != "2025-12-31" || e.Date != "2026-01-01" {
+			t.Errorf("Datas incorretas para tarefa com apenas prazo: %s - %s", s.Date, e.Date)
+		}
+	})
+}
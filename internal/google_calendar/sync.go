@@ -0,0 +1,89 @@
+package googlecalendar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	gcal "google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// ErrSyncTokenExpired means Google invalidated the syncToken (HTTP 410) and
+// a full sync is required.
+var ErrSyncTokenExpired = errors.New("calendar sync token expired")
+
+// FetchEventChanges lists every event changed since syncToken, following
+// pagination. An empty syncToken performs a full sync, which is how the first
+// token is obtained. Times are returned in loc, the calendar owner's zone.
+func FetchEventChanges(ctx context.Context, srv *gcal.Service, calendarID, syncToken string, loc *time.Location) (*EventChanges, error) {
+	changes := &EventChanges{FullSync: syncToken == ""}
+	pageToken := ""
+
+	for {
+		call := srv.Events.List(calendarID).
+			Context(ctx).
+			ShowDeleted(true).
+			MaxResults(250)
+		if syncToken != "" {
+			call = call.SyncToken(syncToken)
+		}
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+
+		page, err := call.Do()
+		if err != nil {
+			var apiErr *googleapi.Error
+			if errors.As(err, &apiErr) && apiErr.Code == http.StatusGone {
+				return nil, ErrSyncTokenExpired
+			}
+			return nil, err
+		}
+
+		for _, item := range page.Items {
+			changes.Events = append(changes.Events, toEventChange(item, loc))
+		}
+
+		if page.NextPageToken == "" {
+			changes.NextSyncToken = page.NextSyncToken
+			return changes, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+func toEventChange(e *gcal.Event, loc *time.Location) EventChange {
+	change := EventChange{
+		EventID:          e.Id,
+		RecurringEventID: e.RecurringEventId,
+		Cancelled:        e.Status == "cancelled",
+	}
+
+	if updated, err := time.Parse(time.RFC3339, e.Updated); err == nil {
+		change.Updated = updated
+	}
+
+	change.Start, change.AllDay = parseEventTime(e.Start, loc)
+	change.End, _ = parseEventTime(e.End, loc)
+	return change
+}
+
+func parseEventTime(dt *gcal.EventDateTime, loc *time.Location) (*time.Time, bool) {
+	if dt == nil {
+		return nil, false
+	}
+	if dt.DateTime != "" {
+		if t, err := time.Parse(time.RFC3339, dt.DateTime); err == nil {
+			local := t.In(loc)
+			return &local, false
+		}
+	}
+	if dt.Date != "" {
+		if t, err := time.ParseInLocation("2006-01-02", dt.Date, loc); err == nil {
+			return &t, true
+		}
+	}
+	return nil, false
+}
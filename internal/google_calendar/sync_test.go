@@ -0,0 +1,112 @@
+package googlecalendar_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	googlecalendar "github.com/saulo-duarte/chronos-lambda/internal/google_calendar"
+	gcal "google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+)
+
+func newCalendarServer(t *testing.T, handler http.HandlerFunc) *gcal.Service {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	calendar, err := gcal.NewService(context.Background(),
+		option.WithEndpoint(srv.URL),
+		option.WithHTTPClient(srv.Client()),
+	)
+	if err != nil {
+		t.Fatalf("Erro ao criar cliente do calendar: %v", err)
+	}
+	return calendar
+}
+
+func TestFetchEventChanges(t *testing.T) {
+	t.Run("FollowsPaginationAndReturnsSyncToken", func(t *testing.T) {
+		var syncTokens []string
+		calendar := newCalendarServer(t, func(w http.ResponseWriter, r *http.Request) {
+			syncTokens = append(syncTokens, r.URL.Query().Get("syncToken"))
+			var page gcal.Events
+			if r.URL.Query().Get("pageToken") == "" {
+				page = gcal.Events{
+					Items: []*gcal.Event{{
+						Id:      "evt-1",
+						Updated: "2025-03-10T12:00:00Z",
+						Start:   &gcal.EventDateTime{DateTime: "2025-03-11T09:00:00-03:00"},
+						End:     &gcal.EventDateTime{DateTime: "2025-03-11T10:00:00-03:00"},
+					}},
+					NextPageToken: "page-2",
+				}
+			} else {
+				page = gcal.Events{
+					Items: []*gcal.Event{
+						{Id: "evt-2", Status: "cancelled"},
+						{
+							Id:    "evt-3",
+							Start: &gcal.EventDateTime{Date: "2025-03-12"},
+							End:   &gcal.EventDateTime{Date: "2025-03-13"},
+						},
+					},
+					NextSyncToken: "next-token",
+				}
+			}
+			json.NewEncoder(w).Encode(page)
+		})
+
+		changes, err := googlecalendar.FetchEventChanges(context.Background(), calendar, "primary", "old-token", time.UTC)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+
+		if changes.NextSyncToken != "next-token" {
+			t.Errorf("Sync token incorreto. Esperado: next-token, Recebido: %s", changes.NextSyncToken)
+		}
+		if changes.FullSync {
+			t.Error("Sincronização incremental não deveria ser marcada como completa")
+		}
+		if len(changes.Events) != 3 {
+			t.Fatalf("Quantidade de eventos incorreta. Esperado: 3, Recebido: %d", len(changes.Events))
+		}
+		for _, token := range syncTokens {
+			if token != "old-token" {
+				t.Errorf("Sync token deveria ser enviado em todas as páginas, recebido: %q", token)
+			}
+		}
+
+		first := changes.Events[0]
+		if first.Start == nil || first.Start.Hour() != 12 || first.AllDay {
+			t.Errorf("Início do evento com horário incorreto: %+v", first.Start)
+		}
+		if !changes.Events[1].Cancelled {
+			t.Error("Evento cancelado deveria ser marcado como cancelado")
+		}
+		allDay := changes.Events[2]
+		if !allDay.AllDay {
+			t.Error("Evento de dia inteiro deveria ser marcado como AllDay")
+		}
+		if allDay.Start.Location() != time.UTC || allDay.Start.Day() != 12 || allDay.Start.Hour() != 0 {
+			t.Errorf("Evento de dia inteiro deveria começar à meia-noite no fuso do usuário: %v", allDay.Start)
+		}
+	})
+
+	t.Run("ExpiredSyncToken", func(t *testing.T) {
+		calendar := newCalendarServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusGone)
+			w.Write([]byte(`{"error":{"code":410,"message":"Sync token is no longer valid"}}`))
+		})
+
+		_, err := googlecalendar.FetchEventChanges(context.Background(), calendar, "primary", "stale", time.UTC)
+		if !errors.Is(err, googlecalendar.ErrSyncTokenExpired) {
+			t.Errorf("Erro esperado ErrSyncTokenExpired, recebido: %v", err)
+		}
+	})
+}
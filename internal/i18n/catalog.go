@@ -0,0 +1,166 @@
+package i18n
+
+import "strings"
+
+// catalog lists every user-facing message in each supported language. Any
+// variant can be used as the lookup key, so services keep returning their
+// errors as they always did and handlers translate on the way out.
+var catalog = []map[string]string{
+	// Generic
+	{English: "unauthorized", Portuguese: "não autorizado"},
+	{English: "internal server error", Portuguese: "erro interno do servidor"},
+	{English: "internal error", Portuguese: "erro interno"},
+	{English: "invalid request body", Portuguese: "corpo da requisição inválido"},
+	{English: "invalid id", Portuguese: "id inválido"},
+	{English: "invalid id format", Portuguese: "formato de id inválido"},
+	{English: "id required", Portuguese: "id é obrigatório"},
+	{English: "file required", Portuguese: "arquivo é obrigatório"},
+	{English: "invalid file", Portuguese: "arquivo inválido"},
+
+	// Auth and users
+	{English: "invalid token", Portuguese: "token inválido"},
+	{English: "invalid refresh token", Portuguese: "refresh token inválido"},
+	{English: "authorization token not found in cookie or header", Portuguese: "token de autorização não encontrado no cookie ou cabeçalho"},
+	{English: "invalid authorization header format", Portuguese: "formato do cabeçalho de autorização inválido"},
+	{English: "no authentication data in context", Portuguese: "nenhum dado de autenticação no contexto"},
+	{English: "user not found", Portuguese: "usuário não encontrado"},
+	{English: "user id required", Portuguese: "id do usuário é obrigatório"},
+	{English: "userID is required", Portuguese: "userID é obrigatório"},
+	{English: "providerID and email are required", Portuguese: "providerID e email são obrigatórios"},
+
+	// Projects
+	{English: "project not found", Portuguese: "projeto não encontrado"},
+	{English: "project id required", Portuguese: "id do projeto é obrigatório"},
+	{English: "invalid project status", Portuguese: "status do projeto inválido"},
+	{English: "project title cannot be empty", Portuguese: "o título do projeto não pode ser vazio"},
+	{English: "title cannot be empty", Portuguese: "o título não pode ser vazio"},
+
+	// Tasks
+	{English: "task not found", Portuguese: "tarefa não encontrada"},
+	{English: "invalid task id", Portuguese: "id da tarefa inválido"},
+	{English: "projectId is required for PROJECT tasks", Portuguese: "projectId é obrigatório para tarefas do tipo PROJECT"},
+	{English: "task must have valid start or due dates", Portuguese: "a tarefa deve ter datas de início ou de entrega válidas"},
+	{English: "invalid recurrence rule", Portuguese: "regra de recorrência inválida"},
+	{English: "recurring tasks require a startDate or dueDate", Portuguese: "tarefas recorrentes exigem startDate ou dueDate"},
+	{English: "task is not recurring", Portuguese: "a tarefa não é recorrente"},
+	{English: "date is not an occurrence of this task", Portuguese: "a data não é uma ocorrência desta tarefa"},
+	{English: "occurrence not found", Portuguese: "ocorrência não encontrada"},
+	{English: "invalid occurrence range", Portuguese: "intervalo de ocorrências inválido"},
+	{English: "invalid from date", Portuguese: "data inicial inválida"},
+	{English: "invalid to date", Portuguese: "data final inválida"},
+	{English: "google calendar not connected", Portuguese: "google agenda não conectada"},
+	{English: "invalid timezone", Portuguese: "fuso horário inválido"},
+	{English: "calendar feed not found", Portuguese: "feed de calendário não encontrado"},
+	{English: "invalid calendar file", Portuguese: "arquivo de calendário inválido"},
+	{English: "calendar file has too many entries", Portuguese: "o arquivo de calendário tem entradas demais"},
+	{English: "invalid task type", Portuguese: "tipo de tarefa inválido"},
+	{English: "invalid task filter", Portuguese: "filtro de tarefas inválido"},
+	{English: "invalid task sort", Portuguese: "ordenação de tarefas inválida"},
+	{English: "invalid cursor", Portuguese: "cursor inválido"},
+	{English: "invalid parent task", Portuguese: "tarefa pai inválida"},
+	{English: "checklist item not found", Portuguese: "item do checklist não encontrado"},
+	{English: "checklist item title cannot be empty", Portuguese: "o título do item do checklist não pode ser vazio"},
+	{English: "checklist order must list every item once", Portuguese: "a ordem do checklist deve listar cada item uma vez"},
+	{English: "dependency would create a cycle", Portuguese: "a dependência criaria um ciclo"},
+	{English: "dependency already exists", Portuguese: "a dependência já existe"},
+	{English: "dependency not found", Portuguese: "dependência não encontrada"},
+	{English: "task is blocked by unfinished tasks", Portuguese: "a tarefa está bloqueada por tarefas não concluídas"},
+	{English: "invalid task status", Portuguese: "status de tarefa inválido"},
+	{English: "invalid task priority", Portuguese: "prioridade de tarefa inválida"},
+	{English: "invalid status transition", Portuguese: "transição de status inválida"},
+	{English: "estimated minutes cannot be negative", Portuguese: "os minutos estimados não podem ser negativos"},
+
+	// Time tracking
+	{English: "a timer is already running", Portuguese: "já existe um cronômetro em andamento"},
+	{English: "no timer is running", Portuguese: "nenhum cronômetro em andamento"},
+	{English: "time entry not found", Portuguese: "registro de tempo não encontrado"},
+	{English: "invalid time entry", Portuguese: "registro de tempo inválido"},
+	{English: "invalid pomodoro length", Portuguese: "duração de pomodoro inválida"},
+	{English: "invalid report range", Portuguese: "intervalo do relatório inválido"},
+	{English: "invalid report period", Portuguese: "período do relatório inválido"},
+
+	// Tags
+	{English: "tag not found", Portuguese: "etiqueta não encontrada"},
+	{English: "a tag with this name already exists", Portuguese: "já existe uma etiqueta com este nome"},
+	{English: "invalid tag name", Portuguese: "nome da etiqueta inválido"},
+	{English: "invalid tag color", Portuguese: "cor da etiqueta inválida"},
+	{English: "invalid tag target", Portuguese: "tipo de item para etiqueta inválido"},
+	{English: "tagged item not found", Portuguese: "item etiquetado não encontrado"},
+	{English: "cannot merge a tag into itself", Portuguese: "não é possível mesclar uma etiqueta com ela mesma"},
+	{English: "invalid tag filter", Portuguese: "filtro de etiqueta inválido"},
+
+	// Comments and activity
+	{English: "comment not found", Portuguese: "comentário não encontrado"},
+	{English: "commented item not found", Portuguese: "item comentado não encontrado"},
+	{English: "invalid entity type", Portuguese: "tipo de item inválido"},
+	{English: "invalid parent comment", Portuguese: "comentário pai inválido"},
+	{English: "comment body cannot be empty", Portuguese: "o comentário não pode ser vazio"},
+	{English: "comment is too long", Portuguese: "o comentário é muito longo"},
+	{English: "invalid limit", Portuguese: "limite inválido"},
+
+	// Attachments
+	{English: "attachment not found", Portuguese: "anexo não encontrado"},
+	{English: "invalid attachment owner", Portuguese: "tipo de item para anexo inválido"},
+	{English: "attached item not found", Portuguese: "item do anexo não encontrado"},
+	{English: "invalid file name", Portuguese: "nome de arquivo inválido"},
+	{English: "file is empty", Portuguese: "o arquivo está vazio"},
+	{English: "file is too large", Portuguese: "o arquivo é grande demais"},
+	{English: "file type not allowed", Portuguese: "tipo de arquivo não permitido"},
+	{English: "file content does not match its type", Portuguese: "o conteúdo do arquivo não corresponde ao tipo"},
+	{English: "storage quota exceeded", Portuguese: "cota de armazenamento excedida"},
+	{English: "direct uploads are not supported by this storage", Portuguese: "este armazenamento não suporta envio direto"},
+	{English: "upload not completed", Portuguese: "envio não concluído"},
+	{English: "file storage is unavailable", Portuguese: "armazenamento de arquivos indisponível"},
+
+	// Trash
+	{English: "trash item not found", Portuguese: "item não encontrado na lixeira"},
+	{English: "invalid trash item type", Portuguese: "tipo de item da lixeira inválido"},
+
+	// Study subjects and topics
+	{English: "study subject not found", Portuguese: "matéria não encontrada"},
+	{English: "study subject id required", Portuguese: "id da matéria é obrigatório"},
+	{English: "invalid study subject id", Portuguese: "id da matéria inválido"},
+	{English: "study subject name cannot be empty", Portuguese: "o nome da matéria não pode ser vazio"},
+	{English: "study topic not found", Portuguese: "tópico não encontrado"},
+	{English: "study topic id required", Portuguese: "id do tópico é obrigatório"},
+	{English: "study topic name cannot be empty", Portuguese: "o nome do tópico não pode ser vazio"},
+	{English: "a topic with this position already exists in this subject", Portuguese: "já existe um tópico com esta posição neste assunto"},
+	{English: "review quality must be between 0 and 5", Portuguese: "a qualidade da revisão deve estar entre 0 e 5"},
+	{English: "study note not found", Portuguese: "anotação não encontrada"},
+	{English: "study note content is empty", Portuguese: "o conteúdo da anotação está vazio"},
+	{English: "only plain text and markdown notes are supported", Portuguese: "apenas anotações em texto puro e markdown são suportadas"},
+	{English: "study note exceeds the maximum size", Portuguese: "a anotação excede o tamanho máximo"},
+
+	// Quizzes
+	{English: "quiz not found", Portuguese: "quiz não encontrado"},
+	{English: "quiz id required", Portuguese: "id do quiz é obrigatório"},
+	{English: "invalid subject_id", Portuguese: "subject_id inválido"},
+	{English: "question id required", Portuguese: "id da pergunta é obrigatório"},
+	{English: "quiz must contain at least one question", Portuguese: "o quiz deve conter pelo menos uma pergunta"},
+	{English: "quiz has no questions", Portuguese: "quiz não possui perguntas"},
+	{English: "answer for a question that does not belong to the quiz", Portuguese: "resposta para pergunta que não pertence ao quiz"},
+	{English: "attempt not found", Portuguese: "tentativa não encontrada"},
+	{English: "attempt id required", Portuguese: "id da tentativa é obrigatório"},
+	{English: "invalid attempt id", Portuguese: "id da tentativa inválido"},
+
+	// AI quiz generation
+	{English: "ai provider not configured", Portuguese: "nenhum provedor de IA configurado"},
+	{English: "failed to generate questions", Portuguese: "falha ao gerar perguntas"},
+	{English: "failed to generate quiz", Portuguese: "falha ao gerar quiz"},
+	{English: "model returned invalid questions", Portuguese: "o modelo retornou perguntas inválidas"},
+	{English: "the model did not generate any questions", Portuguese: "o modelo não gerou perguntas"},
+	{English: "invalid generated question", Portuguese: "pergunta gerada inválida"},
+	{English: "subject_id or quiz_id is required", Portuguese: "subject_id ou quiz_id é obrigatório"},
+	{English: "study_topic_id is required to generate from material", Portuguese: "study_topic_id é obrigatório para gerar a partir do material"},
+	{English: "the topic has no study material", Portuguese: "o tópico não possui material de estudo"},
+}
+
+var index = func() map[string]map[string]string {
+	idx := make(map[string]map[string]string, len(catalog)*2)
+	for _, entry := range catalog {
+		for _, msg := range entry {
+			idx[strings.ToLower(msg)] = entry
+		}
+	}
+	return idx
+}()
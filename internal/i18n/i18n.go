@@ -0,0 +1,132 @@
+package i18n
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	English    = "en"
+	Portuguese = "pt-BR"
+)
+
+type contextKey struct{}
+
+// WithLanguage stores the negotiated language in the context.
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, contextKey{}, lang)
+}
+
+// FromContext returns the negotiated language, or "" when the client did not
+// ask for a supported one.
+func FromContext(ctx context.Context) string {
+	lang, _ := ctx.Value(contextKey{}).(string)
+	return lang
+}
+
+// Normalize maps a language tag to a supported language, or "" when it is
+// not supported.
+func Normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	primary, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+	switch primary {
+	case "en":
+		return English
+	case "pt":
+		return Portuguese
+	default:
+		return ""
+	}
+}
+
+// Negotiate picks the supported language with the highest quality value in
+// an Accept-Language header.
+func Negotiate(header string) string {
+	type candidate struct {
+		lang    string
+		quality float64
+		order   int
+	}
+
+	var candidates []candidate
+	for i, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang := Normalize(tag)
+		if lang == "" {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality <= 0 {
+			continue
+		}
+		candidates = append(candidates, candidate{lang: lang, quality: quality, order: i})
+	}
+
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	return candidates[0].lang
+}
+
+// Translate returns msg in lang when it is a catalogued message in any
+// supported language. A "known: detail" message has its known prefix
+// translated. Unknown messages and an empty lang leave msg untouched.
+func Translate(lang, msg string) string {
+	if lang == "" {
+		return msg
+	}
+
+	if translated, ok := lookup(lang, msg); ok {
+		return translated
+	}
+
+	if prefix, detail, ok := strings.Cut(msg, ": "); ok {
+		if translated, ok := lookup(lang, prefix); ok {
+			return translated + ": " + detail
+		}
+	}
+
+	return msg
+}
+
+// T translates msg into the language negotiated for the request.
+func T(ctx context.Context, msg string) string {
+	return Translate(FromContext(ctx), msg)
+}
+
+// Error is http.Error with msg translated into the request's language.
+func Error(w http.ResponseWriter, r *http.Request, msg string, code int) {
+	w.Header().Set("Content-Language", contentLanguage(r.Context()))
+	http.Error(w, T(r.Context(), msg), code)
+}
+
+func contentLanguage(ctx context.Context) string {
+	if lang := FromContext(ctx); lang != "" {
+		return lang
+	}
+	return English
+}
+
+func lookup(lang, msg string) (string, bool) {
+	entry, ok := index[strings.ToLower(msg)]
+	if !ok {
+		return "", false
+	}
+	translated, ok := entry[lang]
+	return translated, ok
+}
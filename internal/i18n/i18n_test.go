@@ -0,0 +1,76 @@
+package i18n_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/i18n"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := map[string]string{
+		"":                             "",
+		"pt-BR,pt;q=0.9,en;q=0.8":      i18n.Portuguese,
+		"en-US,en;q=0.9":               i18n.English,
+		"fr-FR,en;q=0.5,pt;q=0.7":      i18n.Portuguese,
+		"de-DE":                        "",
+		"pt;q=0,en":                    i18n.English,
+		"en;q=0.8, pt-PT;q=0.8, *;q=1": i18n.English,
+	}
+
+	for header, expected := range cases {
+		if got := i18n.Negotiate(header); got != expected {
+			t.Errorf("Negotiate(%q) = %q, esperado %q", header, got, expected)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	t.Run("BothDirections", func(t *testing.T) {
+		if got := i18n.Translate(i18n.English, "quiz não encontrado"); got != "quiz not found" {
+			t.Errorf("Tradução incorreta: %q", got)
+		}
+		if got := i18n.Translate(i18n.Portuguese, "task not found"); got != "tarefa não encontrada" {
+			t.Errorf("Tradução incorreta: %q", got)
+		}
+	})
+
+	t.Run("NoLanguageKeepsMessage", func(t *testing.T) {
+		if got := i18n.Translate("", "quiz não encontrado"); got != "quiz não encontrado" {
+			t.Errorf("Mensagem não deveria mudar: %q", got)
+		}
+	})
+
+	t.Run("TranslatesWrappedPrefix", func(t *testing.T) {
+		got := i18n.Translate(i18n.Portuguese, `invalid recurrence rule: unknown frequency "YEARLY"`)
+		if got != `regra de recorrência inválida: unknown frequency "YEARLY"` {
+			t.Errorf("Prefixo não traduzido: %q", got)
+		}
+	})
+
+	t.Run("UnknownMessagePassesThrough", func(t *testing.T) {
+		if got := i18n.Translate(i18n.Portuguese, "something else"); got != "something else" {
+			t.Errorf("Mensagem desconhecida alterada: %q", got)
+		}
+	})
+}
+
+func TestError(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req = req.WithContext(i18n.WithLanguage(context.Background(), i18n.Portuguese))
+	rec := httptest.NewRecorder()
+
+	i18n.Error(rec, req, "unauthorized", 401)
+
+	if rec.Code != 401 {
+		t.Errorf("Status incorreto: %d", rec.Code)
+	}
+	if body := strings.TrimSpace(rec.Body.String()); body != "não autorizado" {
+		t.Errorf("Corpo incorreto: %q", body)
+	}
+	if rec.Header().Get("Content-Language") != i18n.Portuguese {
+		t.Errorf("Content-Language incorreto: %q", rec.Header().Get("Content-Language"))
+	}
+}
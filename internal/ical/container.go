@@ -0,0 +1,30 @@
+package ical
+
+import (
+	"github.com/saulo-duarte/chronos-lambda/internal/project"
+	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	"github.com/saulo-duarte/chronos-lambda/internal/user"
+	"gorm.io/gorm"
+)
+
+type ICalContainer struct {
+	Handler *Handler
+	Service Service
+}
+
+func NewICalContainer(
+	db *gorm.DB,
+	taskRepo task.TaskRepository,
+	taskService task.TaskService,
+	userRepo user.UserRepository,
+	projectService project.ProjectService,
+	subjectRepo studysubject.StudySubjectRepository,
+) *ICalContainer {
+	service := NewService(NewFeedTokenRepository(db), taskRepo, taskService, userRepo, projectService, subjectRepo)
+
+	return &ICalContainer{
+		Handler: NewHandler(service),
+		Service: service,
+	}
+}
@@ -0,0 +1,171 @@
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	dateLayout     = "20060102"
+	localLayout    = "20060102T150405"
+	utcLayout      = "20060102T150405Z"
+	maxLineOctets  = 75
+	contentNewline = "\r\n"
+)
+
+// encoder writes RFC 5545 content lines: CRLF line endings and lines folded
+// at 75 octets without splitting UTF-8 sequences.
+type encoder struct {
+	b strings.Builder
+}
+
+func (e *encoder) prop(name, value string) {
+	line := name + ":" + value
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		e.b.WriteString(line[:cut])
+		e.b.WriteString(contentNewline + " ")
+		line = line[cut:]
+		// The leading space of a continuation line counts towards its length.
+		limit = maxLineOctets - 1
+	}
+	e.b.WriteString(line)
+	e.b.WriteString(contentNewline)
+}
+
+func (e *encoder) text(name, value string) {
+	e.prop(name, escapeText(value))
+}
+
+func (e *encoder) begin(component string) {
+	e.prop("BEGIN", component)
+}
+
+func (e *encoder) end(component string) {
+	e.prop("END", component)
+}
+
+// dateTime writes a DATE-TIME (or DATE for all-day values) in loc. UTC is
+// written with the Z suffix; other zones reference their VTIMEZONE.
+func (e *encoder) dateTime(name string, t time.Time, loc *time.Location, allDay bool) {
+	t = t.In(loc)
+	switch {
+	case allDay:
+		e.prop(name+";VALUE=DATE", t.Format(dateLayout))
+	case loc == time.UTC:
+		e.prop(name, t.Format(utcLayout))
+	default:
+		e.prop(name+";TZID="+loc.String(), t.Format(localLayout))
+	}
+}
+
+func (e *encoder) utc(name string, t time.Time) {
+	e.prop(name, t.UTC().Format(utcLayout))
+}
+
+func (e *encoder) bytes() []byte {
+	return []byte(e.b.String())
+}
+
+// escapeText escapes a TEXT value: backslash, semicolon, comma and newlines.
+func escapeText(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, ";", `\;`)
+	s = strings.ReplaceAll(s, ",", `\,`)
+	s = strings.ReplaceAll(s, "\r\n", `\n`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+// writeTimezone emits the VTIMEZONE for loc using the rules in effect in
+// year. Yearly transitions are described as "nth weekday of the month"
+// rules, which covers every zone that currently observes DST.
+func (e *encoder) writeTimezone(loc *time.Location, year int) {
+	e.begin("VTIMEZONE")
+	e.prop("TZID", loc.String())
+
+	transitions := findTransitions(loc, year)
+	if len(transitions) == 0 {
+		name, offset := time.Date(year, 1, 1, 0, 0, 0, 0, loc).Zone()
+		e.begin("STANDARD")
+		e.prop("DTSTART", "19700101T000000")
+		e.prop("TZOFFSETFROM", formatOffset(offset))
+		e.prop("TZOFFSETTO", formatOffset(offset))
+		e.prop("TZNAME", name)
+		e.end("STANDARD")
+	}
+
+	for _, at := range transitions {
+		_, from := at.Add(-time.Second).Zone()
+		name, to := at.Zone()
+
+		component := "STANDARD"
+		if at.IsDST() {
+			component = "DAYLIGHT"
+		}
+
+		// DTSTART is the local time the change happens, read in the old offset.
+		onset := at.In(time.FixedZone("", from))
+		e.begin(component)
+		e.prop("DTSTART", onset.Format(localLayout))
+		e.prop("RRULE", yearlyRule(onset))
+		e.prop("TZOFFSETFROM", formatOffset(from))
+		e.prop("TZOFFSETTO", formatOffset(to))
+		e.prop("TZNAME", name)
+		e.end(component)
+	}
+
+	e.end("VTIMEZONE")
+}
+
+// findTransitions returns the instants in year at which loc changes its UTC
+// offset, found day by day and then narrowed to the second.
+func findTransitions(loc *time.Location, year int) []time.Time {
+	var transitions []time.Time
+
+	day := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, prev := day.In(loc).Zone()
+	for day.Year() == year {
+		next := day.Add(24 * time.Hour)
+		if _, offset := next.In(loc).Zone(); offset != prev {
+			lo, hi := day, next
+			for hi.Sub(lo) > time.Second {
+				mid := lo.Add(hi.Sub(lo) / 2)
+				if _, o := mid.In(loc).Zone(); o == prev {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			transitions = append(transitions, hi.Truncate(time.Second).In(loc))
+			prev = offset
+		}
+		day = next
+	}
+
+	return transitions
+}
+
+// yearlyRule describes t's date as "the nth (or last) weekday of its month".
+func yearlyRule(t time.Time) string {
+	weekday := strings.ToUpper(t.Weekday().String()[:2])
+	nth := (t.Day()-1)/7 + 1
+	if t.AddDate(0, 0, 7).Month() != t.Month() {
+		nth = -1
+	}
+	return fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYDAY=%d%s", int(t.Month()), nth, weekday)
+}
+
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
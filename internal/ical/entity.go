@@ -0,0 +1,43 @@
+package ical
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+)
+
+// FeedToken authorizes a user's subscription URL. Only the SHA-256 of the
+// secret is stored, so the URL is shown once when it is generated.
+type FeedToken struct {
+	UserID     uuid.UUID  `gorm:"type:uuid;primaryKey" json:"-"`
+	TokenHash  string     `gorm:"not null;uniqueIndex" json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+func (FeedToken) TableName() string {
+	return "ical_feed_tokens"
+}
+
+// FeedResponse describes the user's feed. URL is only filled right after
+// the token is generated.
+type FeedResponse struct {
+	Active     bool       `json:"active"`
+	URL        string     `json:"url,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// Export is a rendered .ics file ready to be downloaded.
+type Export struct {
+	Filename string
+	Content  []byte
+}
+
+// ImportOptions chooses where imported tasks go.
+type ImportOptions struct {
+	Type         task.TaskType
+	ProjectID    *uuid.UUID
+	StudyTopicID *uuid.UUID
+}
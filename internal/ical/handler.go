@@ -0,0 +1,245 @@
+package ical
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/i18n"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+)
+
+const (
+	contentType = "text/calendar; charset=utf-8"
+	// maxImportSize bounds the uploaded .ics file.
+	maxImportSize = 2 << 20
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+func (h *Handler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	feed, err := h.service.GetFeed(r.Context())
+	if err != nil {
+		if !writeICalError(w, r, err) {
+			log.WithError(err).Error("Failed to get calendar feed")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, feed)
+}
+
+func (h *Handler) GenerateFeed(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	feed, err := h.service.GenerateFeed(r.Context(), publicBaseURL(r))
+	if err != nil {
+		if !writeICalError(w, r, err) {
+			log.WithError(err).Error("Failed to generate calendar feed")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, feed)
+}
+
+func (h *Handler) RevokeFeed(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	if err := h.service.RevokeFeed(r.Context()); err != nil {
+		if !writeICalError(w, r, err) {
+			log.WithError(err).Error("Failed to revoke calendar feed")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Feed serves the public subscription URL. Unknown tokens get a plain 404 so
+// the route does not reveal whether a token ever existed.
+func (h *Handler) Feed(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	content, err := h.service.RenderFeed(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		if errors.Is(err, ErrFeedNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		log.WithError(err).Error("Failed to render calendar feed")
+		i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	w.Write(content)
+}
+
+func (h *Handler) ExportProject(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	export, err := h.service.ExportProject(r.Context(), chi.URLParam(r, "projectID"))
+	if err != nil {
+		if !writeICalError(w, r, err) {
+			log.WithError(err).Error("Failed to export project calendar")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeExport(w, export)
+}
+
+func (h *Handler) ExportStudySubject(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	export, err := h.service.ExportStudySubject(r.Context(), chi.URLParam(r, "studySubjectID"))
+	if err != nil {
+		if !writeICalError(w, r, err) {
+			log.WithError(err).Error("Failed to export study subject calendar")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeExport(w, export)
+}
+
+// PreviewImport reports what importing the uploaded file would do without
+// writing anything.
+func (h *Handler) PreviewImport(w http.ResponseWriter, r *http.Request) {
+	h.importFile(w, r, true)
+}
+
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	h.importFile(w, r, false)
+}
+
+// importFile reads a multipart upload with the .ics in "file" and the target
+// in "type", "projectId" and "studyTopicId".
+func (h *Handler) importFile(w http.ResponseWriter, r *http.Request, preview bool) {
+	log := config.WithContext(r.Context())
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize+64*1024)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		log.WithError(err).Warn("Calendar file not provided")
+		i18n.Error(w, r, "file required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	opts := &ImportOptions{Type: task.TaskType(strings.ToUpper(r.FormValue("type")))}
+	if opts.Type == "" {
+		opts.Type = task.EVENT
+	}
+	if opts.ProjectID, err = formUUID(r, "projectId"); err == nil {
+		opts.StudyTopicID, err = formUUID(r, "studyTopicId")
+	}
+	if err != nil {
+		log.WithError(err).Warn("Invalid import target ID")
+		i18n.Error(w, r, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.Import(r.Context(), file, opts, preview)
+	if err != nil {
+		switch {
+		case writeICalError(w, r, err):
+		case errors.Is(err, task.ErrUnauthorized):
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+		case errors.Is(err, task.ErrStudyTopicNotFound):
+			i18n.Error(w, r, "study topic not found", http.StatusNotFound)
+		case errors.Is(err, ErrInvalidCalendarFile),
+			errors.Is(err, ErrTooManyEntries),
+			errors.Is(err, task.ErrInvalidTaskType),
+			errors.Is(err, task.ErrProjectRequired):
+			i18n.Error(w, r, err.Error(), http.StatusBadRequest)
+		default:
+			log.WithError(err).Error("Failed to import calendar file")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if !preview && result.Created > 0 {
+		status = http.StatusCreated
+	}
+	config.JSON(w, status, result)
+}
+
+func formUUID(r *http.Request, key string) (*uuid.UUID, error) {
+	value := r.FormValue(key)
+	if value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func writeExport(w http.ResponseWriter, export *Export) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.Filename))
+	w.WriteHeader(http.StatusOK)
+	w.Write(export.Content)
+}
+
+// writeICalError maps the errors shared by the iCalendar endpoints. It
+// reports whether a response was written.
+func writeICalError(w http.ResponseWriter, r *http.Request, err error) bool {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrInvalidID):
+		i18n.Error(w, r, "invalid id", http.StatusBadRequest)
+	case errors.Is(err, ErrFeedNotFound):
+		i18n.Error(w, r, "calendar feed not found", http.StatusNotFound)
+	case errors.Is(err, ErrProjectNotFound):
+		i18n.Error(w, r, "project not found", http.StatusNotFound)
+	case errors.Is(err, ErrStudySubjectNotFound):
+		i18n.Error(w, r, "study subject not found", http.StatusNotFound)
+	default:
+		return false
+	}
+	return true
+}
+
+// publicBaseURL is where feed URLs point: ICAL_BASE_URL when set, otherwise
+// the address this request came in on.
+func publicBaseURL(r *http.Request) string {
+	if base := os.Getenv("ICAL_BASE_URL"); base != "" {
+		return base
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host
+}
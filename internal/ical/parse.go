@@ -0,0 +1,381 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+)
+
+var (
+	ErrInvalidCalendarFile = errors.New("invalid calendar file")
+	ErrTooManyEntries      = errors.New("calendar file has too many entries")
+)
+
+// MaxImportEntries bounds a single import so one file cannot create an
+// unbounded number of tasks.
+const MaxImportEntries = 1000
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// component holds the properties of a VEVENT or VTODO by name. Properties of
+// nested components such as VALARM are not included.
+type component struct {
+	kind  string
+	props map[string]property
+}
+
+func (c *component) get(name string) (property, bool) {
+	p, ok := c.props[name]
+	return p, ok
+}
+
+func (c *component) text(name string) string {
+	return unescapeText(c.props[name].value)
+}
+
+// ParseImport reads the VEVENTs and VTODOs of an iCalendar file as import
+// items. Floating times are read in loc. Entries that cannot become a task
+// are returned with a SkipReason so the preview can explain them.
+func ParseImport(r io.Reader, loc *time.Location) ([]*task.ImportItem, error) {
+	components, err := parseComponents(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(components) > MaxImportEntries {
+		return nil, ErrTooManyEntries
+	}
+
+	items := make([]*task.ImportItem, 0, len(components))
+	for _, c := range components {
+		items = append(items, toImportItem(c, loc))
+	}
+	return items, nil
+}
+
+func parseComponents(r io.Reader) ([]*component, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		components []*component
+		stack      []string
+		current    *component
+		sawCal     bool
+	)
+
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		p, ok := parseLine(line)
+		if !ok {
+			return nil, ErrInvalidCalendarFile
+		}
+
+		switch p.name {
+		case "BEGIN":
+			kind := strings.ToUpper(p.value)
+			if kind == "VCALENDAR" {
+				sawCal = true
+			}
+			if (kind == "VEVENT" || kind == "VTODO") && current == nil {
+				current = &component{kind: kind, props: map[string]property{}}
+			}
+			stack = append(stack, kind)
+		case "END":
+			kind := strings.ToUpper(p.value)
+			if len(stack) == 0 || stack[len(stack)-1] != kind {
+				return nil, ErrInvalidCalendarFile
+			}
+			stack = stack[:len(stack)-1]
+			if current != nil && kind == current.kind && len(stack) > 0 && stack[len(stack)-1] == "VCALENDAR" {
+				components = append(components, current)
+				current = nil
+			}
+		default:
+			// Only keep properties that belong to the entry itself.
+			if current != nil && stack[len(stack)-1] == current.kind {
+				if _, exists := current.props[p.name]; !exists {
+					current.props[p.name] = p
+				}
+			}
+		}
+	}
+
+	if !sawCal || len(stack) != 0 {
+		return nil, ErrInvalidCalendarFile
+	}
+	return components, nil
+}
+
+// unfold joins continuation lines (starting with a space or tab) and accepts
+// both CRLF and bare LF line endings.
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, ErrInvalidCalendarFile
+	}
+	return lines, nil
+}
+
+// parseLine splits "NAME;PARAM=value;PARAM="quoted:value":VALUE".
+func parseLine(line string) (property, bool) {
+	inQuotes := false
+	colon := -1
+	for i, c := range line {
+		if c == '"' {
+			inQuotes = !inQuotes
+		} else if c == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return property{}, false
+	}
+
+	head := strings.Split(line[:colon], ";")
+	p := property{
+		name:   strings.ToUpper(head[0]),
+		params: map[string]string{},
+		value:  line[colon+1:],
+	}
+	for _, param := range head[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		p.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return p, true
+}
+
+func toImportItem(c *component, loc *time.Location) *task.ImportItem {
+	item := &task.ImportItem{
+		UID:         strings.TrimSpace(c.props["UID"].value),
+		Name:        strings.TrimSpace(c.text("SUMMARY")),
+		Description: c.text("DESCRIPTION"),
+		Status:      importStatus(c),
+		Priority:    importPriority(c.props["PRIORITY"].value),
+	}
+
+	if _, ok := c.get("RECURRENCE-ID"); ok {
+		item.SkipReason = "recurrence exception"
+		return item
+	}
+	if strings.EqualFold(c.props["STATUS"].value, "CANCELLED") {
+		item.SkipReason = "cancelled"
+		return item
+	}
+
+	var err error
+	if c.kind == "VEVENT" {
+		err = applyEventTimes(item, c, loc)
+	} else {
+		err = applyTodoTimes(item, c, loc)
+	}
+	if err != nil {
+		item.SkipReason = "invalid date"
+		return item
+	}
+
+	if rrule, ok := c.get("RRULE"); ok {
+		rule, err := task.ParseRRULE(rrule.value)
+		if err != nil {
+			item.SkipReason = "unsupported recurrence rule"
+			return item
+		}
+		item.Recurrence = rule
+	}
+
+	return item
+}
+
+// applyEventTimes is the inverse of Render: DTSTART is the start date and the
+// event end, if after the start, is the due date. All-day events end on the
+// following day (exclusive).
+func applyEventTimes(item *task.ImportItem, c *component, loc *time.Location) error {
+	dtstart, ok := c.get("DTSTART")
+	if !ok {
+		return nil
+	}
+	start, allDay, err := parseDateTime(dtstart, loc)
+	if err != nil {
+		return err
+	}
+	item.StartDate = &util.LocalDateTime{Time: start}
+	item.AllDay = allDay
+
+	var end time.Time
+	if dtend, ok := c.get("DTEND"); ok {
+		if end, _, err = parseDateTime(dtend, loc); err != nil {
+			return err
+		}
+	} else if duration, ok := c.get("DURATION"); ok {
+		d, err := parseDuration(duration.value)
+		if err != nil {
+			return err
+		}
+		end = start.Add(d)
+	}
+	if end.IsZero() {
+		return nil
+	}
+
+	if allDay {
+		end = end.AddDate(0, 0, -1)
+	}
+	if end.After(start) {
+		item.DueDate = &util.LocalDateTime{Time: end}
+	}
+	return nil
+}
+
+func applyTodoTimes(item *task.ImportItem, c *component, loc *time.Location) error {
+	if due, ok := c.get("DUE"); ok {
+		t, allDay, err := parseDateTime(due, loc)
+		if err != nil {
+			return err
+		}
+		item.DueDate = &util.LocalDateTime{Time: t}
+		item.AllDay = allDay
+	}
+
+	if dtstart, ok := c.get("DTSTART"); ok {
+		t, allDay, err := parseDateTime(dtstart, loc)
+		if err != nil {
+			return err
+		}
+		if item.DueDate == nil {
+			item.AllDay = allDay
+		}
+		// Recurring to-dos repeat DUE as DTSTART to anchor the rule.
+		if item.DueDate == nil || !t.Equal(item.DueDate.Time) {
+			item.StartDate = &util.LocalDateTime{Time: t}
+		}
+	}
+	return nil
+}
+
+// parseDateTime reads DATE, UTC, TZID and floating DATE-TIME values. TZIDs
+// that are not IANA names (Outlook uses Windows names) fall back to loc.
+func parseDateTime(p property, loc *time.Location) (time.Time, bool, error) {
+	value := strings.TrimSpace(p.value)
+
+	if strings.EqualFold(p.params["VALUE"], "DATE") || len(value) == len(dateLayout) {
+		t, err := time.ParseInLocation(dateLayout, value, loc)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(utcLayout, value)
+		return t, false, err
+	}
+
+	zone := loc
+	if tzid := strings.TrimPrefix(p.params["TZID"], "/"); tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			zone = l
+		}
+	}
+	t, err := time.ParseInLocation(localLayout, value, zone)
+	return t, false, err
+}
+
+var durationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseDuration reads an RFC 5545 DURATION such as P1D, PT1H30M or P2W.
+func parseDuration(value string) (time.Duration, error) {
+	m := durationPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil || value == "P" || value == "PT" {
+		return 0, ErrInvalidCalendarFile
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+2])
+		if err != nil {
+			return 0, ErrInvalidCalendarFile
+		}
+		d += time.Duration(n) * unit
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+// importStatus prefers the status written by our own export and otherwise
+// maps the VTODO STATUS values.
+func importStatus(c *component) task.TaskStatus {
+	switch status := task.TaskStatus(c.props[statusProperty].value); status {
+	case task.TODO, task.IN_PROGRESS, task.DONE:
+		return status
+	}
+
+	switch strings.ToUpper(c.props["STATUS"].value) {
+	case "COMPLETED":
+		return task.DONE
+	case "IN-PROCESS":
+		return task.IN_PROGRESS
+	default:
+		return task.TODO
+	}
+}
+
+// importPriority maps the RFC 5545 scale: 1-4 high, 5 medium, 6-9 low.
+// Undefined (0 or missing) priorities are medium.
+func importPriority(value string) task.TaskPriority {
+	n, _ := strconv.Atoi(strings.TrimSpace(value))
+	switch {
+	case n >= 1 && n <= 4:
+		return task.HIGH
+	case n >= 6 && n <= 9:
+		return task.LOW
+	default:
+		return task.MEDIUM
+	}
+}
+
+func unescapeText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
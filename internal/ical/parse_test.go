@@ -0,0 +1,146 @@
+package ical_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/ical"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+)
+
+const importFile = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VTIMEZONE\r\n" +
+	"TZID:Europe/Lisbon\r\n" +
+	"BEGIN:STANDARD\r\n" +
+	"DTSTART:19701025T020000\r\n" +
+	"TZOFFSETFROM:+0100\r\n" +
+	"TZOFFSETTO:+0000\r\n" +
+	"END:STANDARD\r\n" +
+	"END:VTIMEZONE\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:exam-1\r\n" +
+	"SUMMARY:Prova de C\r\n" +
+	" álculo\\, parte 1\r\n" +
+	"DESCRIPTION:Sala 3\\nLevar calculadora\r\n" +
+	"DTSTART;TZID=Europe/Lisbon:20250310T090000\r\n" +
+	"DURATION:PT1H30M\r\n" +
+	"PRIORITY:2\r\n" +
+	"RRULE:FREQ=WEEKLY;COUNT=3\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"DESCRIPTION:Lembrete\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:holiday\r\n" +
+	"SUMMARY:Feriado\r\n" +
+	"DTSTART;VALUE=DATE:20250420\r\n" +
+	"DTEND;VALUE=DATE:20250421\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VTODO\r\n" +
+	"UID:essay\r\n" +
+	"SUMMARY:Redação\r\n" +
+	"DUE:20250315T120000Z\r\n" +
+	"STATUS:COMPLETED\r\n" +
+	"PRIORITY:9\r\n" +
+	"END:VTODO\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:exam-1\r\n" +
+	"RECURRENCE-ID;TZID=Europe/Lisbon:20250317T090000\r\n" +
+	"SUMMARY:Prova adiada\r\n" +
+	"DTSTART;TZID=Europe/Lisbon:20250318T090000\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:yearly\r\n" +
+	"SUMMARY:Aniversário\r\n" +
+	"DTSTART;VALUE=DATE:20250501\r\n" +
+	"RRULE:FREQ=YEARLY\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseImport(t *testing.T) {
+	lisbon, err := time.LoadLocation("Europe/Lisbon")
+	if err != nil {
+		t.Skipf("tzdata indisponível: %v", err)
+	}
+
+	items, err := ical.ParseImport(strings.NewReader(importFile), lisbon)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if len(items) != 5 {
+		t.Fatalf("Esperado 5 itens, recebido %d", len(items))
+	}
+	exam, holiday, essay, moved, yearly := items[0], items[1], items[2], items[3], items[4]
+
+	t.Run("UnfoldsAndUnescapesText", func(t *testing.T) {
+		if exam.Name != "Prova de Cálculo, parte 1" {
+			t.Errorf("Nome incorreto: %q", exam.Name)
+		}
+		if exam.Description != "Sala 3\nLevar calculadora" {
+			t.Errorf("Descrição incorreta: %q", exam.Description)
+		}
+	})
+
+	t.Run("ReadsTimedEventWithDuration", func(t *testing.T) {
+		start := time.Date(2025, 3, 10, 9, 0, 0, 0, lisbon)
+		if exam.StartDate == nil || !exam.StartDate.Time.Equal(start) {
+			t.Errorf("Início incorreto: %v", exam.StartDate)
+		}
+		if exam.DueDate == nil || !exam.DueDate.Time.Equal(start.Add(90*time.Minute)) {
+			t.Errorf("Fim incorreto: %v", exam.DueDate)
+		}
+		if exam.AllDay || exam.Priority != task.HIGH {
+			t.Errorf("Evento deveria ter horário e prioridade alta: %+v", exam)
+		}
+		if exam.Recurrence == nil || exam.Recurrence.Frequency != task.WEEKLY || *exam.Recurrence.Count != 3 {
+			t.Errorf("Recorrência incorreta: %+v", exam.Recurrence)
+		}
+	})
+
+	t.Run("ReadsSingleAllDayEvent", func(t *testing.T) {
+		if !holiday.AllDay || holiday.DueDate != nil {
+			t.Errorf("Feriado deveria ser de dia inteiro sem prazo: %+v", holiday)
+		}
+		if holiday.StartDate == nil || !holiday.StartDate.Time.Equal(time.Date(2025, 4, 20, 0, 0, 0, 0, lisbon)) {
+			t.Errorf("Data do feriado incorreta: %v", holiday.StartDate)
+		}
+	})
+
+	t.Run("ReadsTodo", func(t *testing.T) {
+		if essay.DueDate == nil || !essay.DueDate.Time.Equal(time.Date(2025, 3, 15, 12, 0, 0, 0, time.UTC)) {
+			t.Errorf("Prazo incorreto: %v", essay.DueDate)
+		}
+		if essay.Status != task.DONE || essay.Priority != task.LOW {
+			t.Errorf("Status ou prioridade incorretos: %s, %s", essay.Status, essay.Priority)
+		}
+	})
+
+	t.Run("SkipsUnsupportedEntries", func(t *testing.T) {
+		if moved.SkipReason == "" {
+			t.Error("Exceção de recorrência deveria ser ignorada")
+		}
+		if yearly.SkipReason == "" {
+			t.Error("Regra anual não suportada deveria ser ignorada")
+		}
+		if exam.SkipReason != "" || holiday.SkipReason != "" || essay.SkipReason != "" {
+			t.Error("Itens válidos não deveriam ser ignorados")
+		}
+	})
+
+	t.Run("RejectsInvalidFiles", func(t *testing.T) {
+		invalid := []string{
+			"not a calendar",
+			"BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nEND:VCALENDAR\r\n",
+			"BEGIN:VEVENT\r\nUID:x\r\nEND:VEVENT\r\n",
+		}
+		for _, content := range invalid {
+			if _, err := ical.ParseImport(strings.NewReader(content), lisbon); !errors.Is(err, ical.ErrInvalidCalendarFile) {
+				t.Errorf("Arquivo inválido deveria ser rejeitado: %q, erro: %v", content, err)
+			}
+		}
+	})
+}
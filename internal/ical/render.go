@@ -0,0 +1,176 @@
+package ical
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+)
+
+const (
+	prodID    = "-//Chronos//Tasks//EN"
+	uidDomain = "chronos"
+
+	// statusProperty keeps the task status on events, whose STATUS values
+	// (TENTATIVE, CONFIRMED, CANCELLED) cannot express it.
+	statusProperty = "X-CHRONOS-STATUS"
+)
+
+// TaskUID is the UID of a task in every feed and export, so clients update
+// the entry in place instead of duplicating it.
+func TaskUID(id uuid.UUID) string {
+	return id.String() + "@" + uidDomain
+}
+
+// Render writes the tasks that have dates as an iCalendar document. Tasks
+// with a start date become VEVENTs; tasks with only a due date become VTODOs.
+// Times are written in loc, the owner's time zone.
+func Render(name string, tasks []*task.Task, loc *time.Location, now time.Time) []byte {
+	e := &encoder{}
+	e.begin("VCALENDAR")
+	e.prop("VERSION", "2.0")
+	e.prop("PRODID", prodID)
+	e.prop("CALSCALE", "GREGORIAN")
+	e.prop("METHOD", "PUBLISH")
+	e.text("X-WR-CALNAME", name)
+	e.prop("X-WR-TIMEZONE", loc.String())
+
+	var dated []*task.Task
+	timed := false
+	for _, t := range tasks {
+		if hasDate(t.StartDate) || hasDate(t.DueDate) {
+			dated = append(dated, t)
+			timed = timed || !t.AllDay
+		}
+	}
+
+	if timed && loc != time.UTC {
+		e.writeTimezone(loc, now.In(loc).Year())
+	}
+
+	for _, t := range dated {
+		writeTask(e, t, loc, now)
+	}
+
+	e.end("VCALENDAR")
+	return e.bytes()
+}
+
+func writeTask(e *encoder, t *task.Task, loc *time.Location, now time.Time) {
+	component := "VTODO"
+	if hasDate(t.StartDate) {
+		component = "VEVENT"
+	}
+
+	e.begin(component)
+	e.prop("UID", TaskUID(t.ID))
+	e.utc("DTSTAMP", now)
+	if !t.CreatedAt.IsZero() {
+		e.utc("CREATED", t.CreatedAt)
+	}
+	if !t.UpdatedAt.IsZero() {
+		e.utc("LAST-MODIFIED", t.UpdatedAt)
+	}
+	e.text("SUMMARY", t.Name)
+	if t.Description != "" {
+		e.text("DESCRIPTION", t.Description)
+	}
+
+	if component == "VEVENT" {
+		start := t.StartDate.Time
+		e.dateTime("DTSTART", start, loc, t.AllDay)
+		if end, ok := eventEnd(t, loc); ok {
+			e.dateTime("DTEND", end, loc, t.AllDay)
+		}
+		e.prop("STATUS", "CONFIRMED")
+	} else {
+		due := t.DueDate.Time
+		if t.Recurrence != nil {
+			// Recurring components need DTSTART to anchor the rule.
+			e.dateTime("DTSTART", due, loc, t.AllDay)
+		}
+		e.dateTime("DUE", due, loc, t.AllDay)
+		e.prop("STATUS", todoStatus(t.Status))
+		if t.Status == task.DONE && !t.DoneAt.IsZero() {
+			e.utc("COMPLETED", t.DoneAt)
+		}
+	}
+	if t.Status != "" {
+		e.prop(statusProperty, string(t.Status))
+	}
+
+	if p := priorityValue(t.Priority); p > 0 {
+		e.prop("PRIORITY", strconv.Itoa(p))
+	}
+	if categories := categoriesOf(t); len(categories) > 0 {
+		e.prop("CATEGORIES", strings.Join(categories, ","))
+	}
+	if t.Recurrence != nil {
+		e.prop("RRULE", strings.TrimPrefix(t.Recurrence.RRULEFor(t.AllDay), "RRULE:"))
+	}
+
+	e.end(component)
+}
+
+// eventEnd mirrors the Google Calendar mapping: DueDate ends the event, and
+// all-day events end on the following day (exclusive). A timed event with
+// no due date has no DTEND rather than Google's one-hour default, so
+// re-importing the file does not invent a due date.
+func eventEnd(t *task.Task, loc *time.Location) (time.Time, bool) {
+	start := t.StartDate.Time
+	if t.AllDay {
+		last := start
+		if hasDate(t.DueDate) && t.DueDate.Time.After(start) {
+			last = t.DueDate.Time
+		}
+		return util.DateOnly(last.In(loc)).AddDate(0, 0, 1), true
+	}
+	if hasDate(t.DueDate) && t.DueDate.Time.After(start) {
+		return t.DueDate.Time, true
+	}
+	return time.Time{}, false
+}
+
+func todoStatus(status task.TaskStatus) string {
+	switch status {
+	case task.DONE:
+		return "COMPLETED"
+	case task.IN_PROGRESS:
+		return "IN-PROCESS"
+	default:
+		return "NEEDS-ACTION"
+	}
+}
+
+// priorityValue maps to the RFC 5545 scale: 1 is highest, 5 medium, 9 lowest.
+func priorityValue(p task.TaskPriority) int {
+	switch p {
+	case task.HIGH:
+		return 1
+	case task.MEDIUM:
+		return 5
+	case task.LOW:
+		return 9
+	default:
+		return 0
+	}
+}
+
+// categoriesOf lists the project and study topic names, escaped.
+func categoriesOf(t *task.Task) []string {
+	var categories []string
+	if t.ProjectId != nil && t.Project.Title != "" {
+		categories = append(categories, escapeText(t.Project.Title))
+	}
+	if t.StudyTopicId != nil && t.StudyTopic.Name != "" {
+		categories = append(categories, escapeText(t.StudyTopic.Name))
+	}
+	return categories
+}
+
+func hasDate(ldt *util.LocalDateTime) bool {
+	return ldt != nil && !ldt.IsZero()
+}
@@ -0,0 +1,225 @@
+package ical_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/ical"
+	"github.com/saulo-duarte/chronos-lambda/internal/project"
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	"github.com/saulo-duarte/chronos-lambda/internal/user"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+)
+
+func TestMain(m *testing.M) {
+	config.Init()
+	os.Exit(m.Run())
+}
+
+func ldt(t time.Time) *util.LocalDateTime {
+	return &util.LocalDateTime{Time: t}
+}
+
+func TestRender(t *testing.T) {
+	lisbon, err := time.LoadLocation("Europe/Lisbon")
+	if err != nil {
+		t.Skipf("tzdata indisponível: %v", err)
+	}
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	projectID := uuid.New()
+	topicID := uuid.New()
+
+	event := &task.Task{
+		ID:        uuid.New(),
+		Name:      "Reunião, planejamento; revisão",
+		Status:    task.IN_PROGRESS,
+		Priority:  task.HIGH,
+		StartDate: ldt(time.Date(2025, 3, 10, 9, 0, 0, 0, lisbon)),
+		DueDate:   ldt(time.Date(2025, 3, 10, 10, 30, 0, 0, lisbon)),
+		ProjectId: &projectID,
+		Project:   project.Project{Title: "Chronos"},
+	}
+	todo := &task.Task{
+		ID:           uuid.New(),
+		Name:         "Entregar trabalho",
+		Description:  strings.Repeat("descrição longa ", 10),
+		Status:       task.DONE,
+		Priority:     task.LOW,
+		DueDate:      ldt(time.Date(2025, 3, 12, 0, 0, 0, 0, lisbon)),
+		AllDay:       true,
+		DoneAt:       time.Date(2025, 3, 11, 18, 0, 0, 0, time.UTC),
+		StudyTopicId: &topicID,
+		StudyTopic:   studytopic.StudyTopic{Name: "Álgebra"},
+	}
+	undated := &task.Task{ID: uuid.New(), Name: "Sem data"}
+
+	out := string(ical.Render("Chronos", []*task.Task{event, todo, undated}, lisbon, now))
+
+	t.Run("UsesCRLFAndFoldsLongLines", func(t *testing.T) {
+		for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+			if strings.Contains(line, "\n") {
+				t.Fatalf("Linha com quebra sem CR: %q", line)
+			}
+			if len(line) > 75 {
+				t.Errorf("Linha com mais de 75 octetos: %q", line)
+			}
+		}
+		if !strings.Contains(out, "\r\n ") {
+			t.Error("Descrição longa deveria ser dobrada em linhas de continuação")
+		}
+	})
+
+	t.Run("RendersEventInUserTimezone", func(t *testing.T) {
+		expected := []string{
+			"BEGIN:VEVENT",
+			"UID:" + ical.TaskUID(event.ID),
+			`SUMMARY:Reunião\, planejamento\; revisão`,
+			"DTSTART;TZID=Europe/Lisbon:20250310T090000",
+			"DTEND;TZID=Europe/Lisbon:20250310T103000",
+			"X-CHRONOS-STATUS:IN_PROGRESS",
+			"PRIORITY:1",
+			"CATEGORIES:Chronos",
+			"BEGIN:VTIMEZONE",
+			"TZID:Europe/Lisbon",
+			"BEGIN:DAYLIGHT",
+			"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU",
+		}
+		for _, line := range expected {
+			if !strings.Contains(out, line+"\r\n") {
+				t.Errorf("Linha esperada não encontrada: %s", line)
+			}
+		}
+	})
+
+	t.Run("RendersDueOnlyTaskAsAllDayTodo", func(t *testing.T) {
+		expected := []string{
+			"BEGIN:VTODO",
+			"DUE;VALUE=DATE:20250312",
+			"STATUS:COMPLETED",
+			"COMPLETED:20250311T180000Z",
+			"PRIORITY:9",
+			"CATEGORIES:Álgebra",
+		}
+		for _, line := range expected {
+			if !strings.Contains(out, line+"\r\n") {
+				t.Errorf("Linha esperada não encontrada: %s", line)
+			}
+		}
+	})
+
+	t.Run("SkipsTasksWithoutDates", func(t *testing.T) {
+		if strings.Contains(out, "Sem data") {
+			t.Error("Tarefa sem data não deveria aparecer no calendário")
+		}
+	})
+}
+
+type fakeFeedRepo struct {
+	ical.FeedTokenRepository
+	tokens map[uuid.UUID]*ical.FeedToken
+}
+
+func (f *fakeFeedRepo) Save(token *ical.FeedToken) error {
+	f.tokens[token.UserID] = token
+	return nil
+}
+
+func (f *fakeFeedRepo) GetByHash(hash string) (*ical.FeedToken, error) {
+	for _, token := range f.tokens {
+		if token.TokenHash == hash {
+			return token, nil
+		}
+	}
+	return nil, ical.ErrFeedNotFound
+}
+
+func (f *fakeFeedRepo) Delete(userID uuid.UUID) error {
+	if _, ok := f.tokens[userID]; !ok {
+		return ical.ErrFeedNotFound
+	}
+	delete(f.tokens, userID)
+	return nil
+}
+
+func (f *fakeFeedRepo) MarkUsed(userID uuid.UUID, at time.Time) error {
+	f.tokens[userID].LastUsedAt = &at
+	return nil
+}
+
+type fakeTaskRepo struct {
+	task.TaskRepository
+}
+
+func (f *fakeTaskRepo) ListByUser(userID uuid.UUID) ([]*task.Task, error) {
+	return []*task.Task{{ID: uuid.New(), Name: "Prova", DueDate: ldt(time.Now())}}, nil
+}
+
+type fakeUserRepo struct {
+	user.UserRepository
+}
+
+func (f *fakeUserRepo) GetByID(id string) (*user.User, error) {
+	return &user.User{ID: uuid.MustParse(id)}, nil
+}
+
+func TestFeedToken(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.UserDataKeyID, userID.String())
+	ctx = context.WithValue(ctx, auth.UserDataKeyRole, "user")
+
+	repo := &fakeFeedRepo{tokens: map[uuid.UUID]*ical.FeedToken{}}
+	svc := ical.NewService(repo, &fakeTaskRepo{}, nil, &fakeUserRepo{}, nil, nil)
+
+	feed, err := svc.GenerateFeed(ctx, "https://api.chronos.app/")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	prefix := "https://api.chronos.app" + ical.FeedPath
+	if !strings.HasPrefix(feed.URL, prefix) || !strings.HasSuffix(feed.URL, ".ics") {
+		t.Fatalf("URL do feed incorreta: %s", feed.URL)
+	}
+	secret := strings.TrimPrefix(feed.URL, prefix)
+
+	if strings.Contains(repo.tokens[userID].TokenHash, strings.TrimSuffix(secret, ".ics")) {
+		t.Error("O token não deveria ser armazenado em texto puro")
+	}
+
+	t.Run("ServesFeedForValidToken", func(t *testing.T) {
+		content, err := svc.RenderFeed(context.Background(), secret)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if !strings.Contains(string(content), "SUMMARY:Prova") {
+			t.Error("Feed deveria conter as tarefas do usuário")
+		}
+		if repo.tokens[userID].LastUsedAt == nil {
+			t.Error("Último acesso ao feed deveria ser registrado")
+		}
+	})
+
+	t.Run("RegeneratingInvalidatesOldURL", func(t *testing.T) {
+		if _, err := svc.GenerateFeed(ctx, "https://api.chronos.app"); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if _, err := svc.RenderFeed(context.Background(), secret); !errors.Is(err, ical.ErrFeedNotFound) {
+			t.Errorf("URL antiga deveria deixar de funcionar, erro: %v", err)
+		}
+	})
+
+	t.Run("RevokedFeedIsNotServed", func(t *testing.T) {
+		if err := svc.RevokeFeed(ctx); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if err := svc.RevokeFeed(ctx); !errors.Is(err, ical.ErrFeedNotFound) {
+			t.Errorf("Revogar feed inexistente deveria retornar ErrFeedNotFound, erro: %v", err)
+		}
+	})
+}
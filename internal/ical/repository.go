@@ -0,0 +1,71 @@
+package ical
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FeedTokenRepository interface {
+	GetByUser(userID uuid.UUID) (*FeedToken, error)
+	GetByHash(hash string) (*FeedToken, error)
+	Save(token *FeedToken) error
+	Delete(userID uuid.UUID) error
+	MarkUsed(userID uuid.UUID, at time.Time) error
+}
+
+type feedTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewFeedTokenRepository(db *gorm.DB) FeedTokenRepository {
+	return &feedTokenRepository{db: db}
+}
+
+func (r *feedTokenRepository) GetByUser(userID uuid.UUID) (*FeedToken, error) {
+	var token FeedToken
+	if err := r.db.First(&token, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFeedNotFound
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *feedTokenRepository) GetByHash(hash string) (*FeedToken, error) {
+	var token FeedToken
+	if err := r.db.First(&token, "token_hash = ?", hash).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFeedNotFound
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Save replaces the user's token, which invalidates the previous URL.
+func (r *feedTokenRepository) Save(token *FeedToken) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"token_hash", "created_at", "last_used_at"}),
+	}).Create(token).Error
+}
+
+func (r *feedTokenRepository) Delete(userID uuid.UUID) error {
+	result := r.db.Where("user_id = ?", userID).Delete(&FeedToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrFeedNotFound
+	}
+	return nil
+}
+
+func (r *feedTokenRepository) MarkUsed(userID uuid.UUID, at time.Time) error {
+	return r.db.Model(&FeedToken{}).Where("user_id = ?", userID).UpdateColumn("last_used_at", at).Error
+}
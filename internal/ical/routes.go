@@ -0,0 +1,19 @@
+package ical
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+func Routes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/feed", h.GetFeed)
+	r.Post("/feed", h.GenerateFeed)
+	r.Delete("/feed", h.RevokeFeed)
+	r.Get("/projects/{projectID}", h.ExportProject)
+	r.Get("/study-subjects/{studySubjectID}", h.ExportStudySubject)
+	r.Post("/import/preview", h.PreviewImport)
+	r.Post("/import", h.Import)
+
+	return r
+}
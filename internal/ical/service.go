@@ -0,0 +1,304 @@
+package ical
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/project"
+	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	"github.com/saulo-duarte/chronos-lambda/internal/user"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+)
+
+var (
+	ErrUnauthorized         = errors.New("unauthorized")
+	ErrFeedNotFound         = errors.New("calendar feed not found")
+	ErrInvalidID            = errors.New("invalid id format")
+	ErrProjectNotFound      = project.ErrProjectNotFound
+	ErrStudySubjectNotFound = errors.New("study subject not found")
+)
+
+const (
+	feedName       = "Chronos"
+	feedTokenBytes = 32
+	// FeedPath is the public route serving the feed; the token follows it.
+	FeedPath = "/ical/feed/"
+)
+
+type Service interface {
+	GetFeed(ctx context.Context) (*FeedResponse, error)
+	// GenerateFeed creates the feed token, or replaces it so the old URL
+	// stops working. baseURL is the public API address.
+	GenerateFeed(ctx context.Context, baseURL string) (*FeedResponse, error)
+	RevokeFeed(ctx context.Context) error
+	RenderFeed(ctx context.Context, token string) ([]byte, error)
+	ExportProject(ctx context.Context, projectID string) (*Export, error)
+	ExportStudySubject(ctx context.Context, subjectID string) (*Export, error)
+	// Import creates or updates tasks from an .ics file. With preview set
+	// nothing is written.
+	Import(ctx context.Context, file io.Reader, opts *ImportOptions, preview bool) (*task.ImportResult, error)
+}
+
+type service struct {
+	repo           FeedTokenRepository
+	taskRepo       task.TaskRepository
+	taskService    task.TaskService
+	userRepo       user.UserRepository
+	projectService project.ProjectService
+	subjectRepo    studysubject.StudySubjectRepository
+	now            func() time.Time
+}
+
+func NewService(
+	repo FeedTokenRepository,
+	taskRepo task.TaskRepository,
+	taskService task.TaskService,
+	userRepo user.UserRepository,
+	projectService project.ProjectService,
+	subjectRepo studysubject.StudySubjectRepository,
+) Service {
+	return &service{
+		repo:           repo,
+		taskRepo:       taskRepo,
+		taskService:    taskService,
+		userRepo:       userRepo,
+		projectService: projectService,
+		subjectRepo:    subjectRepo,
+		now:            time.Now,
+	}
+}
+
+func (s *service) GetFeed(ctx context.Context) (*FeedResponse, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.repo.GetByUser(userID)
+	if errors.Is(err, ErrFeedNotFound) {
+		return &FeedResponse{Active: false}, nil
+	}
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to load calendar feed")
+		return nil, err
+	}
+
+	return &FeedResponse{Active: true, CreatedAt: &token.CreatedAt, LastUsedAt: token.LastUsedAt}, nil
+}
+
+func (s *service) GenerateFeed(ctx context.Context, baseURL string) (*FeedResponse, error) {
+	log := config.WithContext(ctx)
+
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := newFeedSecret()
+	if err != nil {
+		log.WithError(err).Error("Failed to generate calendar feed token")
+		return nil, err
+	}
+
+	token := &FeedToken{UserID: userID, TokenHash: hashToken(secret), CreatedAt: s.now()}
+	if err := s.repo.Save(token); err != nil {
+		log.WithError(err).Error("Failed to save calendar feed token")
+		return nil, err
+	}
+
+	log.WithField("user_id", userID).Info("Calendar feed token generated")
+	return &FeedResponse{
+		Active:    true,
+		URL:       strings.TrimSuffix(baseURL, "/") + FeedPath + secret + ".ics",
+		CreatedAt: &token.CreatedAt,
+	}, nil
+}
+
+func (s *service) RevokeFeed(ctx context.Context) error {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(userID); err != nil {
+		if !errors.Is(err, ErrFeedNotFound) {
+			config.WithContext(ctx).WithError(err).Error("Failed to revoke calendar feed")
+		}
+		return err
+	}
+
+	config.WithContext(ctx).WithField("user_id", userID).Info("Calendar feed revoked")
+	return nil
+}
+
+// RenderFeed serves the subscription: the token is the only credential.
+func (s *service) RenderFeed(ctx context.Context, secret string) ([]byte, error) {
+	log := config.WithContext(ctx)
+
+	token, err := s.repo.GetByHash(hashToken(strings.TrimSuffix(secret, ".ics")))
+	if err != nil {
+		if !errors.Is(err, ErrFeedNotFound) {
+			log.WithError(err).Error("Failed to look up calendar feed token")
+		}
+		return nil, err
+	}
+
+	tasks, err := s.taskRepo.ListByUser(token.UserID)
+	if err != nil {
+		log.WithError(err).Error("Failed to list tasks for calendar feed")
+		return nil, err
+	}
+
+	now := s.now()
+	if err := s.repo.MarkUsed(token.UserID, now); err != nil {
+		log.WithError(err).Warn("Failed to record calendar feed access")
+	}
+
+	return Render(feedName, tasks, s.userLocation(ctx, token.UserID), now), nil
+}
+
+func (s *service) ExportProject(ctx context.Context, projectID string) (*Export, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pid, err := s.parseUUID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.projectService.GetProjectByID(ctx, pid.String())
+	if err != nil {
+		if errors.Is(err, project.ErrUnauthorized) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, err
+	}
+
+	tasks, err := s.taskRepo.ListByProjectAndUser(pid, userID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list tasks for project export")
+		return nil, err
+	}
+
+	return s.export(ctx, userID, p.Title, tasks), nil
+}
+
+func (s *service) ExportStudySubject(ctx context.Context, subjectID string) (*Export, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sid, err := s.parseUUID(ctx, subjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	subject, err := s.subjectRepo.GetByID(sid.String())
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to load study subject for export")
+		return nil, err
+	}
+	if subject == nil || subject.UserID != userID {
+		return nil, ErrStudySubjectNotFound
+	}
+
+	tasks, err := s.taskRepo.ListByStudySubjectAndUser(sid, userID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list tasks for study subject export")
+		return nil, err
+	}
+
+	return s.export(ctx, userID, subject.Name, tasks), nil
+}
+
+func (s *service) Import(ctx context.Context, file io.Reader, opts *ImportOptions, preview bool) (*task.ImportResult, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := ParseImport(file, s.userLocation(ctx, userID))
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Warn("Rejected calendar import")
+		return nil, err
+	}
+
+	return s.taskService.ImportTasks(ctx, &task.ImportRequest{
+		Type:         opts.Type,
+		ProjectId:    opts.ProjectID,
+		StudyTopicId: opts.StudyTopicID,
+		Items:        items,
+	}, preview)
+}
+
+// ============= Helper Methods =============
+
+func (s *service) export(ctx context.Context, userID uuid.UUID, name string, tasks []*task.Task) *Export {
+	content := Render(name, tasks, s.userLocation(ctx, userID), s.now())
+	return &Export{Filename: exportFilename(name), Content: content}
+}
+
+func (s *service) userLocation(ctx context.Context, userID uuid.UUID) *time.Location {
+	u, err := s.userRepo.GetByID(userID.String())
+	if err != nil || u == nil {
+		config.WithContext(ctx).WithError(err).Warn("Failed to load user time zone, using default")
+		return util.DefaultLocation()
+	}
+	return u.Location()
+}
+
+func (s *service) getUserID(ctx context.Context) (uuid.UUID, error) {
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Warn("Unauthorized access attempt")
+		return uuid.Nil, ErrUnauthorized
+	}
+	return uuid.MustParse(claims.UserID), nil
+}
+
+func (s *service) parseUUID(ctx context.Context, id string) (uuid.UUID, error) {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Warnf("Invalid ID: %s", id)
+		return uuid.Nil, ErrInvalidID
+	}
+	return parsedID, nil
+}
+
+func newFeedSecret() (string, error) {
+	b := make([]byte, feedTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+var unsafeFilenameChars = regexp.MustCompile(`[^\p{L}\p{N}._-]+`)
+
+func exportFilename(name string) string {
+	base := strings.Trim(unsafeFilenameChars.ReplaceAllString(name, "-"), "-.")
+	if base == "" {
+		base = "chronos"
+	}
+	return base + ".ics"
+}
@@ -0,0 +1,36 @@
+package migrations_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/migrations"
+)
+
+func TestLoad(t *testing.T) {
+	list, err := migrations.Load()
+	if err != nil {
+		t.Fatalf("Load falhou: %v", err)
+	}
+
+	if len(list) == 0 {
+		t.Fatal("Nenhuma migração embutida foi encontrada")
+	}
+
+	t.Run("OrderedAndContiguous", func(t *testing.T) {
+		for i, m := range list {
+			expected := int64(i + 1)
+			if m.Version != expected {
+				t.Errorf("Versão fora de ordem. Esperado: %d, Recebido: %d (%s)", expected, m.Version, m.Name)
+			}
+		}
+	})
+
+	t.Run("UpAndDownPresent", func(t *testing.T) {
+		for _, m := range list {
+			if strings.TrimSpace(m.Up) == "" || strings.TrimSpace(m.Down) == "" {
+				t.Errorf("Migração %04d_%s deveria ter up e down não vazios", m.Version, m.Name)
+			}
+		}
+	})
+}
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS annual_goals;
+DROP TABLE IF EXISTS quiz_questions;
+DROP TABLE IF EXISTS quizzes;
+DROP TABLE IF EXISTS tasks;
+DROP TABLE IF EXISTS study_topics;
+DROP TABLE IF EXISTS study_subjects;
+DROP TABLE IF EXISTS projects;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,113 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS users (
+    id                             UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    provider_id                    TEXT NOT NULL UNIQUE,
+    username                       TEXT NOT NULL DEFAULT '',
+    email                          TEXT NOT NULL DEFAULT '',
+    avatar_url                     TEXT NOT NULL DEFAULT '',
+    role                           TEXT NOT NULL DEFAULT 'USER',
+    encrypted_google_access_token  TEXT NOT NULL DEFAULT '',
+    encrypted_google_refresh_token TEXT NOT NULL DEFAULT '',
+    created_at                     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at                     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS projects (
+    id          UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    title       TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    status      TEXT NOT NULL DEFAULT 'NOT_INITIALIZED',
+    user_id     UUID NOT NULL REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_projects_user_id ON projects(user_id);
+
+CREATE TABLE IF NOT EXISTS study_subjects (
+    id          UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name        TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    user_id     UUID NOT NULL REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_study_subjects_user_id ON study_subjects(user_id);
+
+CREATE TABLE IF NOT EXISTS study_topics (
+    id          UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name        TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    position    INTEGER NOT NULL DEFAULT 0,
+    user_id     UUID NOT NULL REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    subject_id  UUID NOT NULL REFERENCES study_subjects(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_study_topics_subject_id ON study_topics(subject_id);
+CREATE INDEX IF NOT EXISTS idx_study_topics_user_id ON study_topics(user_id);
+
+CREATE TABLE IF NOT EXISTS tasks (
+    id                       UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    google_calendar_event_id TEXT NOT NULL DEFAULT '',
+    name                     TEXT NOT NULL,
+    description              TEXT NOT NULL DEFAULT '',
+    status                   TEXT NOT NULL DEFAULT 'TODO',
+    type                     TEXT NOT NULL DEFAULT 'EVENT',
+    priority                 TEXT NOT NULL DEFAULT 'MEDIUM',
+    start_date               TIMESTAMPTZ,
+    due_date                 TIMESTAMPTZ,
+    project_id               UUID REFERENCES projects(id) ON UPDATE CASCADE ON DELETE SET NULL,
+    study_topic_id           UUID REFERENCES study_topics(id) ON UPDATE CASCADE ON DELETE SET NULL,
+    user_id                  UUID NOT NULL REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    done_at                  TIMESTAMPTZ,
+    created_at               TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at               TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_user_id ON tasks(user_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_project_id ON tasks(project_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_study_topic_id ON tasks(study_topic_id);
+
+CREATE TABLE IF NOT EXISTS quizzes (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id         UUID NOT NULL REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    subject_id      UUID NOT NULL REFERENCES study_subjects(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    topic           TEXT NOT NULL,
+    total_questions INTEGER NOT NULL DEFAULT 0,
+    correct_count   INTEGER NOT NULL DEFAULT 0,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_quizzes_user_id ON quizzes(user_id);
+CREATE INDEX IF NOT EXISTS idx_quizzes_subject_id ON quizzes(subject_id);
+
+CREATE TABLE IF NOT EXISTS quiz_questions (
+    id             UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    quiz_id        UUID NOT NULL REFERENCES quizzes(id) ON DELETE CASCADE,
+    content        TEXT NOT NULL,
+    options        JSONB NOT NULL,
+    correct_answer TEXT NOT NULL,
+    explanation    TEXT,
+    order_index    INTEGER NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_questions_quiz_id ON quiz_questions(quiz_id);
+
+CREATE TABLE IF NOT EXISTS annual_goals (
+    id          UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    title       TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    year        INTEGER NOT NULL,
+    status      TEXT NOT NULL DEFAULT 'ACTIVE',
+    user_id     UUID NOT NULL REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_annual_goals_user_id ON annual_goals(user_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS task_occurrences;
+ALTER TABLE tasks DROP COLUMN IF EXISTS recurrence;
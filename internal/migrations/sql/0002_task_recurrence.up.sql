@@ -0,0 +1,15 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS recurrence JSONB;
+
+CREATE TABLE IF NOT EXISTS task_occurrences (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    task_id         UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id         UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    occurrence_date TIMESTAMPTZ NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'DONE',
+    done_at         TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (task_id, occurrence_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_occurrences_user_date ON task_occurrences(user_id, occurrence_date);
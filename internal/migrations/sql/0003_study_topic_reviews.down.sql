@@ -0,0 +1,10 @@
+ALTER TABLE quizzes DROP COLUMN IF EXISTS study_topic_id;
+
+DROP INDEX IF EXISTS idx_study_topics_user_next_review;
+
+ALTER TABLE study_topics
+    DROP COLUMN IF EXISTS ease_factor,
+    DROP COLUMN IF EXISTS interval_days,
+    DROP COLUMN IF EXISTS repetitions,
+    DROP COLUMN IF EXISTS next_review_at,
+    DROP COLUMN IF EXISTS last_reviewed_at;
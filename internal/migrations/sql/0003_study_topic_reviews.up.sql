@@ -0,0 +1,13 @@
+ALTER TABLE study_topics
+    ADD COLUMN IF NOT EXISTS ease_factor      DOUBLE PRECISION NOT NULL DEFAULT 2.5,
+    ADD COLUMN IF NOT EXISTS interval_days    INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS repetitions      INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS next_review_at   TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS last_reviewed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_study_topics_user_next_review ON study_topics(user_id, next_review_at);
+
+ALTER TABLE quizzes
+    ADD COLUMN IF NOT EXISTS study_topic_id UUID REFERENCES study_topics(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_quizzes_study_topic_id ON quizzes(study_topic_id);
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS quiz_attempts (
+    id               UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    quiz_id          UUID NOT NULL REFERENCES quizzes(id) ON DELETE CASCADE,
+    user_id          UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    total_questions  INTEGER NOT NULL DEFAULT 0,
+    correct_count    INTEGER NOT NULL DEFAULT 0,
+    score            DOUBLE PRECISION NOT NULL DEFAULT 0,
+    started_at       TIMESTAMPTZ,
+    completed_at     TIMESTAMPTZ NOT NULL,
+    duration_seconds INTEGER NOT NULL DEFAULT 0,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_attempts_quiz_id ON quiz_attempts(quiz_id);
+CREATE INDEX IF NOT EXISTS idx_quiz_attempts_user_id ON quiz_attempts(user_id);
+
+CREATE TABLE IF NOT EXISTS quiz_attempt_answers (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    attempt_id      UUID NOT NULL REFERENCES quiz_attempts(id) ON DELETE CASCADE,
+    question_id     UUID NOT NULL REFERENCES quiz_questions(id) ON DELETE CASCADE,
+    selected_answer TEXT NOT NULL DEFAULT '',
+    correct_answer  TEXT NOT NULL,
+    is_correct      BOOLEAN NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_attempt_answers_attempt_id ON quiz_attempt_answers(attempt_id);
@@ -0,0 +1,4 @@
+ALTER TABLE quiz_questions DROP COLUMN IF EXISTS source_id;
+ALTER TABLE quiz_questions DROP COLUMN IF EXISTS source_type;
+
+DROP TABLE IF EXISTS study_notes;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS study_notes (
+    id             UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    study_topic_id UUID NOT NULL REFERENCES study_topics(id) ON DELETE CASCADE,
+    user_id        UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title          TEXT NOT NULL DEFAULT '',
+    content_type   TEXT NOT NULL DEFAULT 'text/plain',
+    content        TEXT NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_study_notes_study_topic_id ON study_notes(study_topic_id);
+
+ALTER TABLE quiz_questions ADD COLUMN IF NOT EXISTS source_type TEXT;
+ALTER TABLE quiz_questions ADD COLUMN IF NOT EXISTS source_id UUID;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_tasks_google_calendar_event_id;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS calendar_synced_at;
+
+DROP TABLE IF EXISTS calendar_sync_states;
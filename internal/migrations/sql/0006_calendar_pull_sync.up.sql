@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS calendar_sync_states (
+    user_id        UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    sync_token     TEXT NOT NULL DEFAULT '',
+    last_synced_at TIMESTAMPTZ,
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS calendar_synced_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_google_calendar_event_id
+    ON tasks(user_id, google_calendar_event_id)
+    WHERE google_calendar_event_id <> '';
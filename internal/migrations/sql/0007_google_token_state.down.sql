@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN IF EXISTS google_calendar_disconnected_at;
+ALTER TABLE users DROP COLUMN IF EXISTS google_token_expiry;
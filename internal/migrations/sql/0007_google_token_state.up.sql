@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS google_token_expiry TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS google_calendar_disconnected_at TIMESTAMPTZ;
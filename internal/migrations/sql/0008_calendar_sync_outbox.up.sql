@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS calendar_sync_outbox (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    -- No foreign key: DELETE entries outlive their task.
+    task_id         UUID NOT NULL,
+    user_id         UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    operation       VARCHAR(10) NOT NULL,
+    event_id        TEXT NOT NULL DEFAULT '',
+    status          VARCHAR(10) NOT NULL DEFAULT 'PENDING',
+    attempts        INT NOT NULL DEFAULT 0,
+    version         INT NOT NULL DEFAULT 1,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error      TEXT NOT NULL DEFAULT '',
+    processed_at    TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_calendar_sync_outbox_pending_task
+    ON calendar_sync_outbox(task_id)
+    WHERE status = 'PENDING';
+
+CREATE INDEX IF NOT EXISTS idx_calendar_sync_outbox_due
+    ON calendar_sync_outbox(next_attempt_at)
+    WHERE status = 'PENDING';
+
+CREATE INDEX IF NOT EXISTS idx_calendar_sync_outbox_user_status
+    ON calendar_sync_outbox(user_id, status);
@@ -0,0 +1,9 @@
+DELETE FROM calendar_sync_states WHERE calendar_id <> 'primary';
+ALTER TABLE calendar_sync_states DROP CONSTRAINT IF EXISTS calendar_sync_states_pkey;
+ALTER TABLE calendar_sync_states DROP COLUMN IF EXISTS calendar_id;
+ALTER TABLE calendar_sync_states ADD PRIMARY KEY (user_id);
+
+ALTER TABLE calendar_sync_outbox DROP COLUMN IF EXISTS calendar_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS google_calendar_id;
+
+DROP TABLE IF EXISTS calendar_preferences;
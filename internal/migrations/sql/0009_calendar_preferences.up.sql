@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS calendar_preferences (
+    user_id               UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    calendar_ids          JSONB NOT NULL DEFAULT '{}',
+    priority_colors       JSONB NOT NULL DEFAULT '{}',
+    reminders             JSONB NOT NULL DEFAULT '[]',
+    dedicated_calendar_id TEXT NOT NULL DEFAULT '',
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS google_calendar_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE calendar_sync_outbox ADD COLUMN IF NOT EXISTS calendar_id TEXT NOT NULL DEFAULT '';
+
+-- Sync tokens are now kept per calendar.
+ALTER TABLE calendar_sync_states ADD COLUMN IF NOT EXISTS calendar_id TEXT NOT NULL DEFAULT 'primary';
+ALTER TABLE calendar_sync_states DROP CONSTRAINT IF EXISTS calendar_sync_states_pkey;
+ALTER TABLE calendar_sync_states ADD PRIMARY KEY (user_id, calendar_id);
@@ -0,0 +1,2 @@
+ALTER TABLE tasks DROP COLUMN IF EXISTS all_day;
+ALTER TABLE users DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS ical_feed_tokens;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tasks_user_ical_uid;
+ALTER TABLE tasks DROP COLUMN IF EXISTS ical_uid;
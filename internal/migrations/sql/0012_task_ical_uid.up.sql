@@ -0,0 +1,3 @@
+-- UID of the calendar entry a task was imported from; empty for tasks created in the app.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS ical_uid TEXT NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_user_ical_uid ON tasks (user_id, ical_uid) WHERE ical_uid <> '';
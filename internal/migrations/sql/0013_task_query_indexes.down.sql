@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tasks_user_status;
+DROP INDEX IF EXISTS idx_tasks_user_created_at;
+DROP INDEX IF EXISTS idx_tasks_user_due_date;
@@ -0,0 +1,5 @@
+-- Keyset pagination on GET /tasks orders by these keys with id as tiebreaker.
+-- Undated tasks sort last through the same COALESCE the query uses.
+CREATE INDEX IF NOT EXISTS idx_tasks_user_due_date ON tasks (user_id, COALESCE(due_date, '9999-12-31T00:00:00Z'::timestamptz), id);
+CREATE INDEX IF NOT EXISTS idx_tasks_user_created_at ON tasks (user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_tasks_user_status ON tasks (user_id, status);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS task_checklist_items;
+DROP INDEX IF EXISTS idx_tasks_parent_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS auto_complete;
+ALTER TABLE tasks DROP COLUMN IF EXISTS parent_id;
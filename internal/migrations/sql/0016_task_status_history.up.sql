@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS task_status_history (
+    id          UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    task_id     UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id     UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_status TEXT NOT NULL DEFAULT '',
+    to_status   TEXT NOT NULL,
+    changed_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_status_history_task ON task_status_history(task_id, changed_at);
+
+-- Seed the history of existing tasks: their creation and, for finished
+-- tasks, the completion.
+INSERT INTO task_status_history (task_id, user_id, from_status, to_status, changed_at)
+SELECT id, user_id, '',
+       CASE WHEN status = 'DONE' AND done_at > created_at THEN 'TODO' ELSE status END,
+       created_at
+FROM tasks;
+
+INSERT INTO task_status_history (task_id, user_id, from_status, to_status, changed_at)
+SELECT id, user_id, 'TODO', 'DONE', done_at
+FROM tasks
+WHERE status = 'DONE' AND done_at > created_at;
@@ -0,0 +1,22 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS estimated_minutes INT;
+
+CREATE TABLE IF NOT EXISTS time_entries (
+    id               UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    task_id          UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id          UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind             VARCHAR(10) NOT NULL,
+    note             TEXT NOT NULL DEFAULT '',
+    started_at       TIMESTAMPTZ NOT NULL,
+    ended_at         TIMESTAMPTZ,
+    duration_seconds BIGINT NOT NULL DEFAULT 0,
+    work_minutes     INT NOT NULL DEFAULT 0,
+    break_minutes    INT NOT NULL DEFAULT 0,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (ended_at IS NULL OR ended_at >= started_at)
+);
+
+-- Only one timer per user may be running.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_time_entries_running ON time_entries(user_id) WHERE ended_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_time_entries_user_started ON time_entries(user_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_time_entries_task ON time_entries(task_id);
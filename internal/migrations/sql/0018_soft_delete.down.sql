@@ -0,0 +1,12 @@
+-- Trashed rows would reappear once the column is gone, so purge them first.
+DELETE FROM tasks WHERE deleted_at IS NOT NULL;
+DELETE FROM projects WHERE deleted_at IS NOT NULL;
+DELETE FROM quizzes WHERE deleted_at IS NOT NULL;
+DELETE FROM study_subjects WHERE deleted_at IS NOT NULL;
+DELETE FROM annual_goals WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE projects DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE study_subjects DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE quizzes DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE annual_goals DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS study_topic_tags;
+DROP TABLE IF EXISTS project_tags;
+DROP TABLE IF EXISTS task_tags;
+DROP TABLE IF EXISTS tags;
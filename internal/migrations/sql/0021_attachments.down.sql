@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS trg_attachments_queue_blob_deletion ON attachments;
+DROP FUNCTION IF EXISTS queue_attachment_blob_deletion();
+DROP TABLE IF EXISTS attachment_blob_deletions;
+DROP TABLE IF EXISTS attachments;
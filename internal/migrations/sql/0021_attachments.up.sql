@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS attachments (
+    id             UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id        UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    task_id        UUID REFERENCES tasks(id) ON DELETE CASCADE,
+    study_topic_id UUID REFERENCES study_topics(id) ON DELETE CASCADE,
+    file_name      TEXT NOT NULL,
+    content_type   VARCHAR(255) NOT NULL,
+    size           BIGINT NOT NULL CHECK (size > 0),
+    storage_key    TEXT NOT NULL UNIQUE,
+    status         VARCHAR(10) NOT NULL DEFAULT 'pending',
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((task_id IS NULL) <> (study_topic_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_user ON attachments(user_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_task ON attachments(task_id) WHERE task_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_attachments_study_topic ON attachments(study_topic_id) WHERE study_topic_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_attachments_pending ON attachments(created_at) WHERE status = 'pending';
+
+-- Blobs of deleted attachments, waiting for the cleanup job. Rows removed
+-- through a cascade (task purged, study topic or user deleted) land here
+-- too, so no blob is left behind.
+CREATE TABLE IF NOT EXISTS attachment_blob_deletions (
+    storage_key TEXT PRIMARY KEY,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE OR REPLACE FUNCTION queue_attachment_blob_deletion() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO attachment_blob_deletions (storage_key) VALUES (OLD.storage_key)
+    ON CONFLICT (storage_key) DO NOTHING;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_attachments_queue_blob_deletion ON attachments;
+CREATE TRIGGER trg_attachments_queue_blob_deletion
+    AFTER DELETE ON attachments
+    FOR EACH ROW EXECUTE FUNCTION queue_attachment_blob_deletion();
@@ -7,9 +7,9 @@ type ProjectContainer struct {
 	Service ProjectService
 }
 
-func NewProjectContainer(db *gorm.DB) *ProjectContainer {
+func NewProjectContainer(db *gorm.DB, tasks TaskTrasher) *ProjectContainer {
 	repo := NewRepository(db)
-	service := NewService(repo)
+	service := NewService(repo, tasks)
 	handler := NewHandler(service)
 
 	return &ProjectContainer{
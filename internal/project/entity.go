@@ -4,16 +4,20 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/tag"
 	"github.com/saulo-duarte/chronos-lambda/internal/user"
+	"gorm.io/gorm"
 )
 
 type Project struct {
-	ID          uuid.UUID     `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
-	Title       string        `json:"title"`
-	Description string        `json:"description"`
-	Status      ProjectStatus `json:"status"`
-	UserID      uuid.UUID     `gorm:"column:user_id;not null" json:"user_id"`
-	User        user.User     `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-"`
-	CreatedAt   time.Time     `json:"created_at"`
-	UpdatedAt   time.Time     `json:"updated_at"`
+	ID          uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Status      ProjectStatus  `json:"status"`
+	Tags        []tag.Tag      `gorm:"many2many:project_tags" json:"tags"`
+	UserID      uuid.UUID      `gorm:"column:user_id;not null" json:"user_id"`
+	User        user.User      `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 }
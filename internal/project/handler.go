@@ -8,6 +8,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/saulo-duarte/chronos-lambda/internal/auth"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/i18n"
+	"github.com/saulo-duarte/chronos-lambda/internal/tag"
 )
 
 type Handler struct {
@@ -24,14 +26,14 @@ func (h *Handler) CreateProject(w http.ResponseWriter, r *http.Request) {
 	claims, err := auth.GetUserClaimsFromContext(r.Context())
 	if err != nil {
 		log.Warn("Usuário não autenticado para criar projeto")
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
 		return
 	}
 
 	var payload Project
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 		log.WithError(err).Error("Corpo da requisição inválido")
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
@@ -40,7 +42,7 @@ func (h *Handler) CreateProject(w http.ResponseWriter, r *http.Request) {
 	project, err := h.service.CreateProject(r.Context(), &payload)
 	if err != nil {
 		log.WithError(err).Error("Erro ao criar projeto")
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
@@ -53,18 +55,18 @@ func (h *Handler) GetProject(w http.ResponseWriter, r *http.Request) {
 	projectID := chi.URLParam(r, "id")
 	if projectID == "" {
 		log.Warn("ID do projeto não fornecido")
-		http.Error(w, "project id required", http.StatusBadRequest)
+		i18n.Error(w, r, "project id required", http.StatusBadRequest)
 		return
 	}
 
 	project, err := h.service.GetProjectByID(r.Context(), projectID)
 	if err != nil {
 		if err == ErrProjectNotFound {
-			http.Error(w, "project not found", http.StatusNotFound)
+			i18n.Error(w, r, "project not found", http.StatusNotFound)
 			return
 		}
 		log.WithError(err).Error("Erro ao buscar projeto")
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
@@ -74,10 +76,16 @@ func (h *Handler) GetProject(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) ListProjects(w http.ResponseWriter, r *http.Request) {
 	log := config.WithContext(r.Context())
 
-	projects, err := h.service.ListProjectsByUser(r.Context())
+	tagIDs, err := tag.ParseIDs(r.URL.Query())
+	if err != nil {
+		i18n.Error(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	projects, err := h.service.ListProjectsByUser(r.Context(), tagIDs...)
 	if err != nil {
 		log.WithError(err).Error("Erro ao listar projetos")
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
@@ -93,20 +101,20 @@ func (h *Handler) UpdateProject(w http.ResponseWriter, r *http.Request) {
 	projectID := chi.URLParam(r, "id")
 	if projectID == "" {
 		log.Warn("ID do projeto não fornecido")
-		http.Error(w, "project id required", http.StatusBadRequest)
+		i18n.Error(w, r, "project id required", http.StatusBadRequest)
 		return
 	}
 
 	var payload UpdateProjectDTO
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 		log.WithError(err).Error("Corpo da requisição inválido")
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	if err := payload.Validate(); err != nil {
 		log.WithError(err).Warn("Payload inválido")
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		i18n.Error(w, r, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -114,12 +122,12 @@ func (h *Handler) UpdateProject(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		switch err {
 		case ErrProjectNotFound:
-			http.Error(w, "project not found", http.StatusNotFound)
+			i18n.Error(w, r, "project not found", http.StatusNotFound)
 		case ErrUnauthorized:
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
 		default:
 			log.WithError(err).Error("Erro ao atualizar projeto")
-			http.Error(w, "internal server error", http.StatusInternalServerError)
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		}
 		return
 	}
@@ -133,19 +141,19 @@ func (h *Handler) DeleteProject(w http.ResponseWriter, r *http.Request) {
 	projectID := chi.URLParam(r, "id")
 	if projectID == "" {
 		log.Warn("ID do projeto não fornecido")
-		http.Error(w, "project id required", http.StatusBadRequest)
+		i18n.Error(w, r, "project id required", http.StatusBadRequest)
 		return
 	}
 
 	if err := h.service.DeleteProject(r.Context(), projectID); err != nil {
 		switch err {
 		case ErrProjectNotFound:
-			http.Error(w, "project not found", http.StatusNotFound)
+			i18n.Error(w, r, "project not found", http.StatusNotFound)
 		case ErrUnauthorized:
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
 		default:
 			log.WithError(err).Error("Erro ao deletar projeto")
-			http.Error(w, "internal server error", http.StatusInternalServerError)
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		}
 		return
 	}
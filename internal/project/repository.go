@@ -4,15 +4,22 @@ import (
 	"errors"
 
 	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/activity"
 	"gorm.io/gorm"
 )
 
 type ProjectRepository interface {
 	Create(p *Project) error
 	GetByID(id string) (*Project, error)
-	ListByUser(userID uuid.UUID) ([]*Project, error)
+	// ListByUser returns the user's projects; given tag IDs, only those
+	// tagged with any of them.
+	ListByUser(userID uuid.UUID, tagIDs ...uuid.UUID) ([]*Project, error)
 	Update(p *Project) error
 	Delete(id string) error
+	RecordActivity(e *activity.Entry) error
+
+	// Transaction runs fn with a repository bound to a single DB transaction.
+	Transaction(fn func(tx ProjectRepository) error) error
 }
 
 type projectRepository struct {
@@ -24,12 +31,12 @@ func NewRepository(db *gorm.DB) ProjectRepository {
 }
 
 func (r *projectRepository) Create(p *Project) error {
-	return r.db.Create(p).Error
+	return r.db.Omit("Tags").Create(p).Error
 }
 
 func (r *projectRepository) GetByID(id string) (*Project, error) {
 	var p Project
-	if err := r.db.First(&p, "id = ?", id).Error; err != nil {
+	if err := r.db.Preload("Tags").First(&p, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
@@ -38,18 +45,32 @@ func (r *projectRepository) GetByID(id string) (*Project, error) {
 	return &p, nil
 }
 
-func (r *projectRepository) ListByUser(userID uuid.UUID) ([]*Project, error) {
+func (r *projectRepository) ListByUser(userID uuid.UUID, tagIDs ...uuid.UUID) ([]*Project, error) {
 	var projects []*Project
-	if err := r.db.Where("user_id = ?", userID).Find(&projects).Error; err != nil {
+	query := r.db.Preload("Tags").Where("user_id = ?", userID)
+	if len(tagIDs) > 0 {
+		query = query.Where("id IN (SELECT project_id FROM project_tags WHERE tag_id IN ?)", tagIDs)
+	}
+	if err := query.Find(&projects).Error; err != nil {
 		return nil, err
 	}
 	return projects, nil
 }
 
 func (r *projectRepository) Update(p *Project) error {
-	return r.db.Save(p).Error
+	return r.db.Omit("Tags").Save(p).Error
 }
 
 func (r *projectRepository) Delete(id string) error {
 	return r.db.Delete(&Project{}, "id = ?", id).Error
 }
+
+func (r *projectRepository) RecordActivity(e *activity.Entry) error {
+	return r.db.Create(e).Error
+}
+
+func (r *projectRepository) Transaction(fn func(tx ProjectRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&projectRepository{db: tx})
+	})
+}
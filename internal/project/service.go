@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/activity"
 	"github.com/saulo-duarte/chronos-lambda/internal/auth"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
 	"github.com/sirupsen/logrus"
@@ -19,17 +20,26 @@ var (
 type ProjectService interface {
 	CreateProject(ctx context.Context, p *Project) (*Project, error)
 	GetProjectByID(ctx context.Context, id string) (*Project, error)
-	ListProjectsByUser(ctx context.Context) ([]*Project, error)
+	// ListProjectsByUser lists the user's projects, optionally only those
+	// tagged with any of tagIDs.
+	ListProjectsByUser(ctx context.Context, tagIDs ...uuid.UUID) ([]*Project, error)
 	UpdateProject(ctx context.Context, id string, dto *UpdateProjectDTO) (*Project, error)
 	DeleteProject(ctx context.Context, id string) error
 }
 
+// TaskTrasher moves a project to the trash together with its tasks. It is
+// implemented by the task package, which depends on this one.
+type TaskTrasher interface {
+	TrashProject(ctx context.Context, projectID, userID uuid.UUID) error
+}
+
 type projectService struct {
-	repo ProjectRepository
+	repo  ProjectRepository
+	tasks TaskTrasher
 }
 
-func NewService(repo ProjectRepository) ProjectService {
-	return &projectService{repo: repo}
+func NewService(repo ProjectRepository, tasks TaskTrasher) ProjectService {
+	return &projectService{repo: repo, tasks: tasks}
 }
 
 func (s *projectService) CreateProject(ctx context.Context, p *Project) (*Project, error) {
@@ -55,7 +65,13 @@ func (s *projectService) CreateProject(ctx context.Context, p *Project) (*Projec
 	p.CreatedAt = time.Now()
 	p.UpdatedAt = time.Now()
 
-	if err := s.repo.Create(p); err != nil {
+	err = s.repo.Transaction(func(tx ProjectRepository) error {
+		if err := tx.Create(p); err != nil {
+			return err
+		}
+		return tx.RecordActivity(newProjectActivity(p, activity.Created, activityChanges(Project{}, p)))
+	})
+	if err != nil {
 		log.WithError(err).Error("Falha ao criar projeto")
 		return nil, err
 	}
@@ -97,7 +113,7 @@ func (s *projectService) GetProjectByID(ctx context.Context, id string) (*Projec
 	return project, nil
 }
 
-func (s *projectService) ListProjectsByUser(ctx context.Context) ([]*Project, error) {
+func (s *projectService) ListProjectsByUser(ctx context.Context, tagIDs ...uuid.UUID) ([]*Project, error) {
 	log := config.WithContext(ctx)
 
 	claims, err := auth.GetUserClaimsFromContext(ctx)
@@ -107,7 +123,7 @@ func (s *projectService) ListProjectsByUser(ctx context.Context) ([]*Project, er
 	}
 
 	userID, _ := uuid.Parse(claims.UserID)
-	projects, err := s.repo.ListByUser(userID)
+	projects, err := s.repo.ListByUser(userID, tagIDs...)
 	if err != nil {
 		log.WithError(err).Error("Erro ao listar projetos do usuário")
 		return nil, err
@@ -151,12 +167,26 @@ func (s *projectService) UpdateProject(ctx context.Context, id string, dto *Upda
 		return nil, ErrUnauthorized
 	}
 
+	before := *existing
 	existing.Title = dto.Title
 	existing.Description = dto.Description
+	if dto.Status != "" {
+		existing.Status = dto.Status
+	}
 
 	existing.UpdatedAt = time.Now()
 
-	if err := s.repo.Update(existing); err != nil {
+	changes := activityChanges(before, existing)
+	err = s.repo.Transaction(func(tx ProjectRepository) error {
+		if err := tx.Update(existing); err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		return tx.RecordActivity(newProjectActivity(existing, activity.Updated, changes))
+	})
+	if err != nil {
 		log.WithError(err).Error("Falha ao atualizar projeto")
 		return nil, err
 	}
@@ -195,7 +225,7 @@ func (s *projectService) DeleteProject(ctx context.Context, id string) error {
 		return ErrUnauthorized
 	}
 
-	if err := s.repo.Delete(id); err != nil {
+	if err := s.tasks.TrashProject(ctx, project.ID, project.UserID); err != nil {
 		log.WithError(err).Error("Falha ao deletar projeto")
 		return err
 	}
@@ -207,3 +237,18 @@ func (s *projectService) DeleteProject(ctx context.Context, id string) error {
 
 	return nil
 }
+
+// ============= Helper Methods =============
+
+// activityChanges lists the fields shown in the project's timeline that
+// differ between before and after.
+func activityChanges(before Project, after *Project) activity.Changes {
+	var changes activity.Changes
+	changes.Add("title", before.Title, after.Title)
+	changes.Add("status", string(before.Status), string(after.Status))
+	return changes
+}
+
+func newProjectActivity(p *Project, action activity.Action, changes activity.Changes) *activity.Entry {
+	return activity.NewEntry(p.UserID, activity.ProjectEntity, p.ID, action, changes)
+}
@@ -1,17 +1,22 @@
 package quiz
 
-import "gorm.io/gorm"
+import (
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"gorm.io/gorm"
+)
 
 type QuizContainer struct {
 	Handler *Handler
+	Service QuizService
 }
 
-func NewQuizContainer(db *gorm.DB) *QuizContainer {
+func NewQuizContainer(db *gorm.DB, topicService studytopic.StudyTopicService) *QuizContainer {
 	repo := NewRepository(db)
-	service := NewService(db, repo)
+	service := NewService(db, repo, topicService)
 	handler := NewHandler(service)
 
 	return &QuizContainer{
 		Handler: handler,
+		Service: service,
 	}
 }
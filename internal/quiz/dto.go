@@ -1,6 +1,22 @@
 package quiz
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
 type QuizWithQuestionsDTO struct {
 	Quiz      *Quiz           `json:"quiz"`
 	Questions []*QuizQuestion `json:"questions"`
 }
+
+type AnswerDTO struct {
+	QuestionID uuid.UUID `json:"question_id"`
+	Answer     string    `json:"answer"`
+}
+
+type SubmitAttemptDTO struct {
+	StartedAt *time.Time  `json:"started_at"`
+	Answers   []AnswerDTO `json:"answers"`
+}
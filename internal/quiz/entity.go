@@ -5,17 +5,20 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/datatypes"
+	"gorm.io/gorm"
 )
 
 type Quiz struct {
-	ID             uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	UserID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
-	SubjectID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"subject_id"`
-	Topic          string     `gorm:"type:text;not null" json:"topic"`
-	TotalQuestions int        `gorm:"not null;default:0" json:"total_questions"`
-	CorrectCount   int        `gorm:"not null;default:0" json:"correct_count"`
-	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
-	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	ID             uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID         uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
+	SubjectID      uuid.UUID      `gorm:"type:uuid;not null;index" json:"subject_id"`
+	StudyTopicID   *uuid.UUID     `gorm:"type:uuid;index" json:"study_topic_id,omitempty"`
+	Topic          string         `gorm:"type:text;not null" json:"topic"`
+	TotalQuestions int            `gorm:"not null;default:0" json:"total_questions"`
+	CorrectCount   int            `gorm:"not null;default:0" json:"correct_count"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	CompletedAt    *time.Time     `json:"completed_at,omitempty"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
 
 	Questions []QuizQuestion `gorm:"foreignKey:QuizID;constraint:OnDelete:CASCADE" json:"questions,omitempty"`
 }
@@ -27,6 +30,32 @@ type QuizQuestion struct {
 	Options       datatypes.JSON `gorm:"type:jsonb;not null" json:"options"`
 	CorrectAnswer string         `gorm:"type:text;not null" json:"correct_answer"`
 	Explanation   *string        `gorm:"type:text" json:"explanation,omitempty"`
+	SourceType    *string        `gorm:"type:text" json:"source_type,omitempty"`
+	SourceID      *uuid.UUID     `gorm:"type:uuid" json:"source_id,omitempty"`
 	OrderIndex    int            `gorm:"not null" json:"order_index"`
 	CreatedAt     time.Time      `gorm:"autoCreateTime" json:"created_at"`
 }
+
+type QuizAttempt struct {
+	ID              uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	QuizID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"quiz_id"`
+	UserID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	TotalQuestions  int        `gorm:"not null;default:0" json:"total_questions"`
+	CorrectCount    int        `gorm:"not null;default:0" json:"correct_count"`
+	Score           float64    `gorm:"not null;default:0" json:"score"`
+	StartedAt       *time.Time `json:"started_at,omitempty"`
+	CompletedAt     time.Time  `gorm:"not null" json:"completed_at"`
+	DurationSeconds int        `gorm:"not null;default:0" json:"duration_seconds"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+
+	Answers []QuizAttemptAnswer `gorm:"foreignKey:AttemptID;constraint:OnDelete:CASCADE" json:"answers,omitempty"`
+}
+
+type QuizAttemptAnswer struct {
+	ID             uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	AttemptID      uuid.UUID `gorm:"type:uuid;not null;index" json:"attempt_id"`
+	QuestionID     uuid.UUID `gorm:"type:uuid;not null" json:"question_id"`
+	SelectedAnswer string    `gorm:"type:text;not null" json:"selected_answer"`
+	CorrectAnswer  string    `gorm:"type:text;not null" json:"correct_answer"`
+	IsCorrect      bool      `gorm:"not null" json:"is_correct"`
+}
@@ -0,0 +1,74 @@
+package quiz
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+var ErrUnknownQuestion = errors.New("resposta para pergunta que não pertence ao quiz")
+
+// GradeAnswers checks each answer against QuizQuestion.CorrectAnswer.
+// Unanswered questions are recorded as wrong so the score is always out of
+// the full quiz.
+func GradeAnswers(questions []*QuizQuestion, answers []AnswerDTO) ([]QuizAttemptAnswer, int, error) {
+	selected := make(map[uuid.UUID]string, len(answers))
+	known := make(map[uuid.UUID]bool, len(questions))
+	for _, q := range questions {
+		known[q.ID] = true
+	}
+	for _, a := range answers {
+		if !known[a.QuestionID] {
+			return nil, 0, ErrUnknownQuestion
+		}
+		selected[a.QuestionID] = a.Answer
+	}
+
+	graded := make([]QuizAttemptAnswer, 0, len(questions))
+	correct := 0
+	for _, q := range questions {
+		answer := selected[q.ID]
+		isCorrect := answer != "" && IsCorrectAnswer(answer, q.CorrectAnswer)
+		if isCorrect {
+			correct++
+		}
+		graded = append(graded, QuizAttemptAnswer{
+			ID:             uuid.New(),
+			QuestionID:     q.ID,
+			SelectedAnswer: answer,
+			CorrectAnswer:  q.CorrectAnswer,
+			IsCorrect:      isCorrect,
+		})
+	}
+
+	return graded, correct, nil
+}
+
+// IsCorrectAnswer compares by option letter when both sides carry one
+// ("C" vs "C) Paris"), otherwise by case-insensitive text.
+func IsCorrectAnswer(selected, correct string) bool {
+	selectedLetter, selectedOK := optionLetter(selected)
+	correctLetter, correctOK := optionLetter(correct)
+	if selectedOK && correctOK {
+		return selectedLetter == correctLetter
+	}
+	return strings.EqualFold(strings.TrimSpace(selected), strings.TrimSpace(correct))
+}
+
+func optionLetter(s string) (rune, bool) {
+	s = strings.TrimSpace(s)
+	runes := []rune(s)
+	if len(runes) == 0 || !unicode.IsLetter(runes[0]) {
+		return 0, false
+	}
+	if len(runes) == 1 {
+		return unicode.ToUpper(runes[0]), true
+	}
+	switch runes[1] {
+	case ')', '.', ':', '-':
+		return unicode.ToUpper(runes[0]), true
+	}
+	return 0, false
+}
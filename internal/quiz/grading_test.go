@@ -0,0 +1,67 @@
+package quiz_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/quiz"
+)
+
+func TestIsCorrectAnswer(t *testing.T) {
+	cases := []struct {
+		selected string
+		correct  string
+		expected bool
+	}{
+		{"C", "C) Paris", true},
+		{"c) paris", "C) Paris", true},
+		{"B", "C) Paris", false},
+		{"Paris", "paris", true},
+		{"Lisboa", "Paris", false},
+		{"Azul", "Amarelo", false},
+	}
+
+	for _, c := range cases {
+		if got := quiz.IsCorrectAnswer(c.selected, c.correct); got != c.expected {
+			t.Errorf("IsCorrectAnswer(%q, %q) = %v, esperado %v", c.selected, c.correct, got, c.expected)
+		}
+	}
+}
+
+func TestGradeAnswers(t *testing.T) {
+	q1 := &quiz.QuizQuestion{ID: uuid.New(), CorrectAnswer: "A"}
+	q2 := &quiz.QuizQuestion{ID: uuid.New(), CorrectAnswer: "B"}
+	q3 := &quiz.QuizQuestion{ID: uuid.New(), CorrectAnswer: "C"}
+	questions := []*quiz.QuizQuestion{q1, q2, q3}
+
+	t.Run("CountsCorrectAndUnanswered", func(t *testing.T) {
+		answers := []quiz.AnswerDTO{
+			{QuestionID: q1.ID, Answer: "A"},
+			{QuestionID: q2.ID, Answer: "D"},
+		}
+
+		graded, correct, err := quiz.GradeAnswers(questions, answers)
+		if err != nil {
+			t.Fatalf("GradeAnswers falhou: %v", err)
+		}
+		if correct != 1 {
+			t.Errorf("Acertos incorretos. Esperado: 1, Recebido: %d", correct)
+		}
+		if len(graded) != 3 {
+			t.Fatalf("Todas as perguntas devem ser registradas. Esperado: 3, Recebido: %d", len(graded))
+		}
+		if graded[2].IsCorrect || graded[2].SelectedAnswer != "" {
+			t.Error("Pergunta sem resposta deve ser registrada como errada")
+		}
+	})
+
+	t.Run("RejectsUnknownQuestion", func(t *testing.T) {
+		answers := []quiz.AnswerDTO{{QuestionID: uuid.New(), Answer: "A"}}
+
+		_, _, err := quiz.GradeAnswers(questions, answers)
+		if !errors.Is(err, quiz.ErrUnknownQuestion) {
+			t.Errorf("Esperado ErrUnknownQuestion, recebido: %v", err)
+		}
+	})
+}
@@ -2,12 +2,14 @@ package quiz
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/saulo-duarte/chronos-lambda/internal/auth"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/i18n"
 )
 
 type Handler struct {
@@ -24,7 +26,7 @@ func (h *Handler) CreateQuiz(w http.ResponseWriter, r *http.Request) {
 	claims, err := auth.GetUserClaimsFromContext(r.Context())
 	if err != nil {
 		log.Warn("Usuário não autenticado para criar quiz")
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
 		return
 	}
 
@@ -35,13 +37,13 @@ func (h *Handler) CreateQuiz(w http.ResponseWriter, r *http.Request) {
 
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 		log.WithError(err).Error("Corpo da requisição inválido para criar quiz")
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	if len(payload.Questions) == 0 {
 		log.Warn("Tentativa de criar quiz sem perguntas")
-		http.Error(w, "quiz must contain at least one question", http.StatusBadRequest)
+		i18n.Error(w, r, "quiz must contain at least one question", http.StatusBadRequest)
 		return
 	}
 
@@ -53,7 +55,7 @@ func (h *Handler) CreateQuiz(w http.ResponseWriter, r *http.Request) {
 
 	if payload.Quiz.SubjectID == uuid.Nil {
 		log.Warn("SubjectID inválido ou ausente")
-		http.Error(w, "invalid subject_id", http.StatusBadRequest)
+		i18n.Error(w, r, "invalid subject_id", http.StatusBadRequest)
 		return
 	}
 
@@ -66,7 +68,7 @@ func (h *Handler) CreateQuiz(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.service.CreateQuizWithQuestions(r.Context(), &payload.Quiz, payload.Questions); err != nil {
 		log.WithError(err).Error("Erro ao criar quiz com perguntas")
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
@@ -82,13 +84,13 @@ func (h *Handler) DeleteQuiz(w http.ResponseWriter, r *http.Request) {
 	quizID := chi.URLParam(r, "id")
 	if quizID == "" {
 		log.Warn("ID do quiz não fornecido")
-		http.Error(w, "quiz id required", http.StatusBadRequest)
+		i18n.Error(w, r, "quiz id required", http.StatusBadRequest)
 		return
 	}
 
 	if err := h.service.DeleteQuiz(r.Context(), quizID); err != nil {
 		log.WithError(err).Error("Erro ao deletar quiz")
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
@@ -103,14 +105,14 @@ func (h *Handler) AddQuestion(w http.ResponseWriter, r *http.Request) {
 	quizID := chi.URLParam(r, "id")
 	if quizID == "" {
 		log.Warn("ID do quiz não fornecido para adicionar pergunta")
-		http.Error(w, "quiz id required", http.StatusBadRequest)
+		i18n.Error(w, r, "quiz id required", http.StatusBadRequest)
 		return
 	}
 
 	var question QuizQuestion
 	if err := json.NewDecoder(r.Body).Decode(&question); err != nil {
 		log.WithError(err).Error("Corpo da requisição inválido para adicionar pergunta")
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
@@ -120,7 +122,7 @@ func (h *Handler) AddQuestion(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.service.AddQuestionToQuiz(r.Context(), quizID, &question); err != nil {
 		log.WithError(err).Error("Erro ao adicionar pergunta ao quiz")
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
@@ -136,13 +138,13 @@ func (h *Handler) RemoveQuestion(w http.ResponseWriter, r *http.Request) {
 	questionID := chi.URLParam(r, "questionID")
 	if questionID == "" {
 		log.Warn("ID da pergunta não fornecido")
-		http.Error(w, "question id required", http.StatusBadRequest)
+		i18n.Error(w, r, "question id required", http.StatusBadRequest)
 		return
 	}
 
 	if err := h.service.RemoveQuestion(r.Context(), questionID); err != nil {
 		log.WithError(err).Error("Erro ao remover pergunta do quiz")
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
@@ -157,18 +159,18 @@ func (h *Handler) GetQuizWithQuestions(w http.ResponseWriter, r *http.Request) {
 	quizID := chi.URLParam(r, "id")
 	if quizID == "" {
 		log.Warn("ID do quiz não fornecido")
-		http.Error(w, "quiz id required", http.StatusBadRequest)
+		i18n.Error(w, r, "quiz id required", http.StatusBadRequest)
 		return
 	}
 
 	quizWithQuestions, err := h.service.GetQuizWithQuestions(r.Context(), quizID)
 	if err != nil {
 		log.WithError(err).Error("Erro ao buscar quiz com perguntas")
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		return
 	}
 	if quizWithQuestions == nil {
-		http.Error(w, "quiz not found", http.StatusNotFound)
+		i18n.Error(w, r, "quiz not found", http.StatusNotFound)
 		return
 	}
 
@@ -181,23 +183,115 @@ func (h *Handler) ListQuizzesByUser(w http.ResponseWriter, r *http.Request) {
 	claims, err := auth.GetUserClaimsFromContext(r.Context())
 	if err != nil {
 		log.Warn("Usuário não autenticado para listar quizzes")
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
 		return
 	}
 
 	userID := claims.UserID
 	if userID == "" {
 		log.Warn("ID do usuário não fornecido")
-		http.Error(w, "user id required", http.StatusBadRequest)
+		i18n.Error(w, r, "user id required", http.StatusBadRequest)
 		return
 	}
 
 	quizzes, err := h.service.ListQuizzesByUser(r.Context(), userID)
 	if err != nil {
 		log.WithError(err).Error("Erro ao listar quizzes do usuário")
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
 	config.JSON(w, http.StatusOK, quizzes)
 }
+
+func (h *Handler) SubmitAttempt(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	quizID := chi.URLParam(r, "id")
+	if quizID == "" {
+		log.Warn("ID do quiz não fornecido")
+		i18n.Error(w, r, "quiz id required", http.StatusBadRequest)
+		return
+	}
+
+	var payload SubmitAttemptDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Corpo da requisição inválido para enviar respostas")
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	attempt, err := h.service.SubmitAttempt(r.Context(), quizID, &payload)
+	if err != nil {
+		if !h.writeAttemptError(w, r, err) {
+			log.WithError(err).Error("Erro ao corrigir tentativa do quiz")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, attempt)
+}
+
+func (h *Handler) ListAttempts(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	quizID := chi.URLParam(r, "id")
+	if quizID == "" {
+		log.Warn("ID do quiz não fornecido")
+		i18n.Error(w, r, "quiz id required", http.StatusBadRequest)
+		return
+	}
+
+	attempts, err := h.service.ListAttempts(r.Context(), quizID)
+	if err != nil {
+		if !h.writeAttemptError(w, r, err) {
+			log.WithError(err).Error("Erro ao listar tentativas do quiz")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]interface{}{
+		"count":    len(attempts),
+		"attempts": attempts,
+	})
+}
+
+func (h *Handler) GetAttempt(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	attemptID := chi.URLParam(r, "attemptID")
+	if attemptID == "" {
+		log.Warn("ID da tentativa não fornecido")
+		i18n.Error(w, r, "attempt id required", http.StatusBadRequest)
+		return
+	}
+
+	attempt, err := h.service.GetAttempt(r.Context(), attemptID)
+	if err != nil {
+		if !h.writeAttemptError(w, r, err) {
+			log.WithError(err).Error("Erro ao buscar tentativa")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, attempt)
+}
+
+func (h *Handler) writeAttemptError(w http.ResponseWriter, r *http.Request, err error) bool {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrQuizNotFound):
+		i18n.Error(w, r, "quiz not found", http.StatusNotFound)
+	case errors.Is(err, ErrAttemptNotFound):
+		i18n.Error(w, r, "attempt not found", http.StatusNotFound)
+	case errors.Is(err, ErrQuizHasNoQuestions), errors.Is(err, ErrUnknownQuestion), errors.Is(err, ErrInvalidAttemptID):
+		i18n.Error(w, r, err.Error(), http.StatusBadRequest)
+	default:
+		return false
+	}
+	return true
+}
@@ -16,6 +16,9 @@ type QuizRepository interface {
 	ListQuestionsByQuiz(quizID string) ([]*QuizQuestion, error)
 	DeleteQuestion(id string) error
 	ListQuizzesByUser(userID string) ([]*Quiz, error)
+
+	ListAttemptsByQuiz(quizID, userID string) ([]*QuizAttempt, error)
+	GetAttempt(id, userID string) (*QuizAttempt, error)
 }
 
 type quizRepository struct {
@@ -88,3 +91,25 @@ func (r *quizRepository) ListQuizzesByUser(userID string) ([]*Quiz, error) {
 	}
 	return quizzes, nil
 }
+
+func (r *quizRepository) ListAttemptsByQuiz(quizID, userID string) ([]*QuizAttempt, error) {
+	var attempts []*QuizAttempt
+	if err := r.db.
+		Where("quiz_id = ? AND user_id = ?", quizID, userID).
+		Order("completed_at DESC").
+		Find(&attempts).Error; err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+func (r *quizRepository) GetAttempt(id, userID string) (*QuizAttempt, error) {
+	var attempt QuizAttempt
+	if err := r.db.Preload("Answers").First(&attempt, "id = ? AND user_id = ?", id, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &attempt, nil
+}
@@ -17,6 +17,9 @@ func Routes(h *Handler) http.Handler {
 	r.Get("/{id}", h.GetQuizWithQuestions)
 	r.Delete("/{id}", h.DeleteQuiz)
 	r.Post("/{id}/questions", h.AddQuestion)
+	r.Post("/{id}/attempts", h.SubmitAttempt)
+	r.Get("/{id}/attempts", h.ListAttempts)
+	r.Get("/attempts/{attemptID}", h.GetAttempt)
 	r.Delete("/questions/{questionID}", h.RemoveQuestion)
 	return r
 }
@@ -3,12 +3,23 @@ package quiz
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrQuizNotFound       = errors.New("quiz não encontrado")
+	ErrAttemptNotFound    = errors.New("tentativa não encontrada")
+	ErrInvalidAttemptID   = errors.New("id da tentativa inválido")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrQuizHasNoQuestions = errors.New("quiz não possui perguntas")
+)
+
 type QuizService interface {
 	CreateQuizWithQuestions(ctx context.Context, quiz *Quiz, questions []*QuizQuestion) error
 	DeleteQuiz(ctx context.Context, quizID string) error
@@ -16,17 +27,23 @@ type QuizService interface {
 	RemoveQuestion(ctx context.Context, questionID string) error
 	GetQuizWithQuestions(ctx context.Context, quizID string) (*QuizWithQuestionsDTO, error)
 	ListQuizzesByUser(ctx context.Context, userID string) ([]*Quiz, error)
+	SubmitAttempt(ctx context.Context, quizID string, dto *SubmitAttemptDTO) (*QuizAttempt, error)
+	ListAttempts(ctx context.Context, quizID string) ([]*QuizAttempt, error)
+	GetAttempt(ctx context.Context, attemptID string) (*QuizAttempt, error)
+	AppendQuestions(ctx context.Context, quizID string, questions []*QuizQuestion) (*QuizWithQuestionsDTO, error)
 }
 
 type quizService struct {
-	repo QuizRepository
-	db   *gorm.DB
+	repo         QuizRepository
+	db           *gorm.DB
+	topicService studytopic.StudyTopicService
 }
 
-func NewService(db *gorm.DB, repo QuizRepository) QuizService {
+func NewService(db *gorm.DB, repo QuizRepository, topicService studytopic.StudyTopicService) QuizService {
 	return &quizService{
-		repo: repo,
-		db:   db,
+		repo:         repo,
+		db:           db,
+		topicService: topicService,
 	}
 }
 
@@ -79,9 +96,8 @@ func (s *quizService) AddQuestionToQuiz(ctx context.Context, quizID string, ques
 		return err
 	}
 	if qz == nil {
-		err := errors.New("quiz não encontrado")
-		log.Warnf("Quiz não encontrado: %v", err)
-		return err
+		log.Warnf("Quiz não encontrado: %s", quizID)
+		return ErrQuizNotFound
 	}
 
 	question.QuizID = qz.ID
@@ -148,3 +164,211 @@ func (s *quizService) ListQuizzesByUser(ctx context.Context, userID string) ([]*
 
 	return quizzes, nil
 }
+
+func (s *quizService) SubmitAttempt(ctx context.Context, quizID string, dto *SubmitAttemptDTO) (*QuizAttempt, error) {
+	log := config.WithContext(ctx)
+	log.Info("Corrigindo tentativa de quiz...", "quiz_id", quizID)
+
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	qz, err := s.getOwnedQuiz(ctx, quizID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	questions, err := s.repo.ListQuestionsByQuiz(quizID)
+	if err != nil {
+		log.Errorf("Erro ao listar perguntas do quiz: %v", err)
+		return nil, err
+	}
+	if len(questions) == 0 {
+		return nil, ErrQuizHasNoQuestions
+	}
+
+	answers, correct, err := GradeAnswers(questions, dto.Answers)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	attempt := &QuizAttempt{
+		ID:             uuid.New(),
+		QuizID:         qz.ID,
+		UserID:         userID,
+		TotalQuestions: len(questions),
+		CorrectCount:   correct,
+		Score:          float64(correct) / float64(len(questions)) * 100,
+		StartedAt:      dto.StartedAt,
+		CompletedAt:    now,
+	}
+	if dto.StartedAt != nil && dto.StartedAt.Before(now) {
+		attempt.DurationSeconds = int(now.Sub(*dto.StartedAt).Seconds())
+	}
+	for i := range answers {
+		answers[i].AttemptID = attempt.ID
+	}
+	attempt.Answers = answers
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(attempt).Error; err != nil {
+			log.Errorf("Erro ao salvar tentativa: %v", err)
+			return err
+		}
+
+		return tx.Model(&Quiz{}).Where("id = ?", qz.ID).Updates(map[string]interface{}{
+			"correct_count":   correct,
+			"total_questions": len(questions),
+			"completed_at":    now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.feedTopicReview(ctx, qz, correct, len(questions))
+
+	log.Info("Tentativa corrigida com sucesso", "attempt_id", attempt.ID.String())
+	return attempt, nil
+}
+
+func (s *quizService) ListAttempts(ctx context.Context, quizID string) ([]*QuizAttempt, error) {
+	log := config.WithContext(ctx)
+
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getOwnedQuiz(ctx, quizID, userID); err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.repo.ListAttemptsByQuiz(quizID, userID.String())
+	if err != nil {
+		log.Errorf("Erro ao listar tentativas do quiz: %v", err)
+		return nil, err
+	}
+
+	return attempts, nil
+}
+
+func (s *quizService) GetAttempt(ctx context.Context, attemptID string) (*QuizAttempt, error) {
+	log := config.WithContext(ctx)
+
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uuid.Parse(attemptID); err != nil {
+		log.Warnf("ID de tentativa inválido: %s", attemptID)
+		return nil, ErrInvalidAttemptID
+	}
+
+	attempt, err := s.repo.GetAttempt(attemptID, userID.String())
+	if err != nil {
+		log.Errorf("Erro ao buscar tentativa: %v", err)
+		return nil, err
+	}
+	if attempt == nil {
+		return nil, ErrAttemptNotFound
+	}
+
+	return attempt, nil
+}
+
+// AppendQuestions adds questions after the quiz's current last question and
+// keeps TotalQuestions in sync, all in one transaction.
+func (s *quizService) AppendQuestions(ctx context.Context, quizID string, questions []*QuizQuestion) (*QuizWithQuestionsDTO, error) {
+	log := config.WithContext(ctx)
+	log.Info("Adicionando perguntas ao quiz...", "quiz_id", quizID)
+
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	qz, err := s.getOwnedQuiz(ctx, quizID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var next int
+		if err := tx.Model(&QuizQuestion{}).
+			Where("quiz_id = ?", qz.ID).
+			Select("COALESCE(MAX(order_index) + 1, 0)").
+			Scan(&next).Error; err != nil {
+			return err
+		}
+
+		for i, q := range questions {
+			if q.ID == uuid.Nil {
+				q.ID = uuid.New()
+			}
+			q.QuizID = qz.ID
+			q.OrderIndex = next + i
+		}
+
+		if len(questions) > 0 {
+			if err := tx.Create(&questions).Error; err != nil {
+				log.Errorf("Erro ao adicionar perguntas: %v", err)
+				return err
+			}
+		}
+
+		var total int64
+		if err := tx.Model(&QuizQuestion{}).Where("quiz_id = ?", qz.ID).Count(&total).Error; err != nil {
+			return err
+		}
+		return tx.Model(&Quiz{}).Where("id = ?", qz.ID).Update("total_questions", total).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Perguntas adicionadas com sucesso", "quiz_id", quizID)
+	return s.GetQuizWithQuestions(ctx, quizID)
+}
+
+func (s *quizService) getUserID(ctx context.Context) (uuid.UUID, error) {
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Warn("Usuário não autenticado")
+		return uuid.Nil, ErrUnauthorized
+	}
+	return uuid.MustParse(claims.UserID), nil
+}
+
+func (s *quizService) getOwnedQuiz(ctx context.Context, quizID string, userID uuid.UUID) (*Quiz, error) {
+	qz, err := s.repo.GetByID(quizID)
+	if err != nil {
+		config.WithContext(ctx).Errorf("Erro ao buscar quiz: %v", err)
+		return nil, err
+	}
+	if qz == nil {
+		return nil, ErrQuizNotFound
+	}
+	// Someone else's quiz is reported as missing so its existence does not
+	// leak.
+	if qz.UserID != userID {
+		config.WithContext(ctx).Warnf("Usuário %s tentou acessar quiz de outro usuário", userID)
+		return nil, ErrQuizNotFound
+	}
+	return qz, nil
+}
+
+// feedTopicReview turns the graded score into an SM-2 rating for the quiz's
+// study topic. Failures are only logged: the attempt is already saved.
+func (s *quizService) feedTopicReview(ctx context.Context, qz *Quiz, correct, total int) {
+	if s.topicService == nil || qz.StudyTopicID == nil {
+		return
+	}
+
+	if _, err := s.topicService.ReviewFromQuizResult(ctx, qz.StudyTopicID.String(), correct, total); err != nil {
+		config.WithContext(ctx).WithError(err).Warnf("Falha ao atualizar revisão do tópico %s", qz.StudyTopicID)
+	}
+}
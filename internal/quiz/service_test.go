@@ -0,0 +1,48 @@
+package quiz_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/quiz"
+)
+
+func TestMain(m *testing.M) {
+	config.Init()
+	os.Exit(m.Run())
+}
+
+type fakeQuizRepo struct {
+	quiz.QuizRepository
+	quizzes map[string]*quiz.Quiz
+}
+
+func (f *fakeQuizRepo) GetByID(id string) (*quiz.Quiz, error) {
+	return f.quizzes[id], nil
+}
+
+func TestQuizAccess(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.UserDataKeyID, userID.String())
+	ctx = context.WithValue(ctx, auth.UserDataKeyRole, "user")
+
+	foreign := &quiz.Quiz{ID: uuid.New(), UserID: uuid.New()}
+	svc := quiz.NewService(nil, &fakeQuizRepo{quizzes: map[string]*quiz.Quiz{foreign.ID.String(): foreign}}, nil)
+
+	t.Run("OtherUsersQuizIsNotFound", func(t *testing.T) {
+		if _, err := svc.ListAttempts(ctx, foreign.ID.String()); !errors.Is(err, quiz.ErrQuizNotFound) {
+			t.Errorf("Quiz de outro usuário deveria parecer inexistente, erro: %v", err)
+		}
+	})
+
+	t.Run("MalformedAttemptID", func(t *testing.T) {
+		if _, err := svc.GetAttempt(ctx, "nao-e-uuid"); !errors.Is(err, quiz.ErrInvalidAttemptID) {
+			t.Errorf("ID de tentativa inválido deveria falhar, erro: %v", err)
+		}
+	})
+}
@@ -7,15 +7,23 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	httpSwagger "github.com/swaggo/http-swagger"
 
+	"github.com/saulo-duarte/chronos-lambda/internal/activity"
 	"github.com/saulo-duarte/chronos-lambda/internal/aiquiz"
 	"github.com/saulo-duarte/chronos-lambda/internal/annual_goal"
+	"github.com/saulo-duarte/chronos-lambda/internal/attachment"
 	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/comment"
+	googlecalendar "github.com/saulo-duarte/chronos-lambda/internal/google_calendar"
+	"github.com/saulo-duarte/chronos-lambda/internal/ical"
 	"github.com/saulo-duarte/chronos-lambda/internal/middlewares"
 	"github.com/saulo-duarte/chronos-lambda/internal/project"
 	"github.com/saulo-duarte/chronos-lambda/internal/quiz"
 	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
 	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"github.com/saulo-duarte/chronos-lambda/internal/tag"
 	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	timetracking "github.com/saulo-duarte/chronos-lambda/internal/time_tracking"
+	"github.com/saulo-duarte/chronos-lambda/internal/trash"
 	"github.com/saulo-duarte/chronos-lambda/internal/user"
 )
 
@@ -28,6 +36,14 @@ type RouterConfig struct {
 	AIQuizHandler       *aiquiz.Handler
 	QuizHandler         *quiz.Handler
 	AnnualGoalHandler   *annual_goal.Handler
+	CalendarHandler     *googlecalendar.Handler
+	ICalHandler         *ical.Handler
+	TimeTrackingHandler *timetracking.Handler
+	TrashHandler        *trash.Handler
+	TagHandler          *tag.Handler
+	ActivityHandler     *activity.Handler
+	CommentHandler      *comment.Handler
+	AttachmentHandler   *attachment.Handler
 }
 
 func New(cfg RouterConfig) http.Handler {
@@ -38,6 +54,7 @@ func New(cfg RouterConfig) http.Handler {
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(middlewares.CorsMiddleware)
+	r.Use(middlewares.LanguageMiddleware)
 
 	r.Get("/swagger/*", httpSwagger.WrapHandler)
 
@@ -51,6 +68,10 @@ func New(cfg RouterConfig) http.Handler {
 		r.Post("/logout", auth.NewHandler().Logout)
 	})
 
+	// Calendar apps cannot send our auth cookie; the secret token in the
+	// path authorizes the feed.
+	r.Get(ical.FeedPath+"{token}", cfg.ICalHandler.Feed)
+
 	r.Group(func(r chi.Router) {
 		r.Use(auth.AuthMiddleware)
 
@@ -61,6 +82,14 @@ func New(cfg RouterConfig) http.Handler {
 		r.Mount("/users", user.Routes(cfg.UserHandler))
 		r.Mount("/quizzes", quiz.Routes(cfg.QuizHandler))
 		r.Mount("/annual-goals", annual_goal.Routes(cfg.AnnualGoalHandler))
+		r.Mount("/calendar", googlecalendar.Routes(cfg.CalendarHandler))
+		r.Mount("/ical", ical.Routes(cfg.ICalHandler))
+		r.Mount("/time-entries", timetracking.Routes(cfg.TimeTrackingHandler))
+		r.Mount("/trash", trash.Routes(cfg.TrashHandler))
+		r.Mount("/tags", tag.Routes(cfg.TagHandler))
+		r.Mount("/activity", activity.Routes(cfg.ActivityHandler))
+		r.Mount("/comments", comment.Routes(cfg.CommentHandler))
+		r.Mount("/attachments", attachment.Routes(cfg.AttachmentHandler))
 
 		r.Get("/study-subjects/{studySubjectId}/topics", cfg.StudyTopicHandler.ListStudyTopics)
 		r.Get("/study-topics/{studyTopicId}/tasks", cfg.TaskHandler.ListTasksByStudyTopic)
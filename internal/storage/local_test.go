@@ -0,0 +1,65 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/storage"
+)
+
+func TestLocal(t *testing.T) {
+	local, err := storage.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	ctx := context.Background()
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		if err := local.Put(ctx, "attachments/u1/a1", strings.NewReader("anotações"), int64(len("anotações")), "text/plain"); err != nil {
+			t.Fatalf("Erro inesperado no upload: %v", err)
+		}
+		body, obj, err := local.Get(ctx, "attachments/u1/a1")
+		if err != nil {
+			t.Fatalf("Erro inesperado no download: %v", err)
+		}
+		defer body.Close()
+		if got, _ := io.ReadAll(body); string(got) != "anotações" || obj.Size != int64(len("anotações")) {
+			t.Errorf("Conteúdo ou tamanho incorretos: %q %d", got, obj.Size)
+		}
+	})
+
+	t.Run("ShortBodyIsRejected", func(t *testing.T) {
+		if err := local.Put(ctx, "attachments/u1/a2", strings.NewReader("abc"), 10, "text/plain"); err == nil {
+			t.Error("Upload menor que o tamanho informado deveria falhar")
+		}
+		if _, err := local.Stat(ctx, "attachments/u1/a2"); !errors.Is(err, storage.ErrObjectNotFound) {
+			t.Errorf("Upload incompleto não deveria deixar arquivo, erro: %v", err)
+		}
+	})
+
+	t.Run("DeleteIsIdempotent", func(t *testing.T) {
+		if err := local.Delete(ctx, "attachments/u1/a1"); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if err := local.Delete(ctx, "attachments/u1/a1"); err != nil {
+			t.Errorf("Remover arquivo já removido não deveria falhar: %v", err)
+		}
+	})
+
+	t.Run("RejectsPathTraversal", func(t *testing.T) {
+		for _, key := range []string{"../fora", "/etc/passwd", "a//b", `a\..\b`} {
+			if _, err := local.Stat(ctx, key); !errors.Is(err, storage.ErrInvalidKey) {
+				t.Errorf("Chave %q deveria ser rejeitada, erro: %v", key, err)
+			}
+		}
+	})
+}
+
+func TestNewUnknownBackend(t *testing.T) {
+	if _, err := storage.New(context.Background(), storage.Config{Backend: "ftp"}); !errors.Is(err, storage.ErrUnknownBackend) {
+		t.Errorf("Backend desconhecido deveria falhar, erro: %v", err)
+	}
+}
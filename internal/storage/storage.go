@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrObjectNotFound       = errors.New("storage object not found")
+	ErrInvalidKey           = errors.New("invalid storage key")
+	ErrUnknownBackend       = errors.New("unknown storage backend")
+	ErrBackendMisconfigured = errors.New("storage backend misconfigured")
+)
+
+// Object describes a stored blob. ContentType is empty when the backend
+// does not keep it.
+type Object struct {
+	Size        int64
+	ContentType string
+}
+
+// Storage keeps blobs under slash-separated keys.
+type Storage interface {
+	// Put stores size bytes read from r under key, replacing any blob
+	// already there.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, *Object, error)
+	Stat(ctx context.Context, key string) (*Object, error)
+	// Delete removes the blob; deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// PresignedRequest is a request the client sends straight to the backend.
+// Header lists the headers that were signed and must be sent as given.
+type PresignedRequest struct {
+	Method    string      `json:"method"`
+	URL       string      `json:"url"`
+	Header    http.Header `json:"headers"`
+	ExpiresAt time.Time   `json:"expires_at"`
+}
+
+// Presigner is implemented by backends that can hand out short-lived URLs,
+// so large files go between the client and the backend without passing
+// through the API.
+type Presigner interface {
+	PresignPut(ctx context.Context, key, contentType string, size int64, expires time.Duration) (*PresignedRequest, error)
+	// PresignGet returns a download URL; a non-empty fileName is suggested
+	// to the browser through Content-Disposition.
+	PresignGet(ctx context.Context, key, fileName string, expires time.Duration) (string, error)
+}
+
+type Config struct {
+	Backend  string
+	LocalDir string
+	S3       S3Config
+}
+
+type BackendFactory func(ctx context.Context, cfg Config) (Storage, error)
+
+var backends = map[string]BackendFactory{
+	"local": func(ctx context.Context, cfg Config) (Storage, error) {
+		local, err := NewLocal(cfg.LocalDir)
+		if err != nil {
+			return nil, err
+		}
+		return local, nil
+	},
+	"s3": func(ctx context.Context, cfg Config) (Storage, error) {
+		s3, err := NewS3(cfg.S3)
+		if err != nil {
+			return nil, err
+		}
+		return s3, nil
+	},
+}
+
+// RegisterBackend makes an additional backend selectable by name.
+func RegisterBackend(name string, factory BackendFactory) {
+	backends[strings.ToLower(name)] = factory
+}
+
+// ConfigFromEnv reads STORAGE_BACKEND (local by default) and the settings of
+// each backend: STORAGE_LOCAL_DIR for local; STORAGE_S3_ENDPOINT,
+// STORAGE_S3_REGION, STORAGE_S3_BUCKET, STORAGE_S3_ACCESS_KEY_ID,
+// STORAGE_S3_SECRET_ACCESS_KEY and STORAGE_S3_PATH_STYLE for s3. The S3
+// credentials and region fall back to the standard AWS variables, which is
+// how Lambda hands out the role's credentials.
+func ConfigFromEnv() Config {
+	backend := os.Getenv("STORAGE_BACKEND")
+	if backend == "" {
+		backend = "local"
+	}
+	localDir := os.Getenv("STORAGE_LOCAL_DIR")
+	if localDir == "" {
+		localDir = filepath.Join(os.TempDir(), "chronos-attachments")
+	}
+	return Config{
+		Backend:  backend,
+		LocalDir: localDir,
+		S3: S3Config{
+			Endpoint:        os.Getenv("STORAGE_S3_ENDPOINT"),
+			Region:          envOr("STORAGE_S3_REGION", "AWS_REGION"),
+			Bucket:          os.Getenv("STORAGE_S3_BUCKET"),
+			AccessKeyID:     envOr("STORAGE_S3_ACCESS_KEY_ID", "AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: envOr("STORAGE_S3_SECRET_ACCESS_KEY", "AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+			PathStyle:       os.Getenv("STORAGE_S3_PATH_STYLE") == "true",
+		},
+	}
+}
+
+func New(ctx context.Context, cfg Config) (Storage, error) {
+	name := strings.ToLower(strings.TrimSpace(cfg.Backend))
+	factory, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q (available: %s)", ErrUnknownBackend, cfg.Backend, strings.Join(backendNames(), ", "))
+	}
+	return factory(ctx, cfg)
+}
+
+func backendNames() []string {
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func envOr(names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// validKey rejects keys that could escape the bucket or directory.
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
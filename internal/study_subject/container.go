@@ -4,14 +4,16 @@ import "gorm.io/gorm"
 
 type StudySubjectContainer struct {
 	Handler *Handler
+	Repo    StudySubjectRepository
 }
 
-func NewStudySubjectContainer(db *gorm.DB) *StudySubjectContainer {
+func NewStudySubjectContainer(db *gorm.DB, tasks TaskTrasher) *StudySubjectContainer {
 	repo := NewRepository(db)
-	service := NewService(repo)
+	service := NewService(repo, tasks)
 	handler := NewHandler(service)
 
 	return &StudySubjectContainer{
 		Handler: handler,
+		Repo:    repo,
 	}
 }
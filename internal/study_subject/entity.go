@@ -5,14 +5,16 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/saulo-duarte/chronos-lambda/internal/user"
+	"gorm.io/gorm"
 )
 
 type StudySubject struct {
-	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	UserID      uuid.UUID `gorm:"column:user_id;not null" json:"user_id"`
-	User        user.User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	UserID      uuid.UUID      `gorm:"column:user_id;not null" json:"user_id"`
+	User        user.User      `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/saulo-duarte/chronos-lambda/internal/auth"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/i18n"
 )
 
 type Handler struct {
@@ -24,18 +25,18 @@ func (h *Handler) CreateStudySubject(w http.ResponseWriter, r *http.Request) {
 	var payload StudySubject
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 		log.WithError(err).Error("Invalid request body")
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	subject, err := h.service.CreateStudySubject(r.Context(), &payload)
 	if err != nil {
 		if err == ErrUnauthorized {
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
 			return
 		}
 		log.WithError(err).Error("Error creating study subject")
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
@@ -48,18 +49,18 @@ func (h *Handler) ListStudySubjects(w http.ResponseWriter, r *http.Request) {
 	claims, err := auth.GetUserClaimsFromContext(r.Context())
 	if err != nil {
 		log.WithError(err).Warn("Attempt to list study subjects without authentication")
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
 		return
 	}
 
 	subjects, err := h.service.ListStudySubjectsByUser(r.Context(), claims.UserID)
 	if err != nil {
 		if err == ErrUnauthorized {
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
 			return
 		}
 		log.WithError(err).Error("Error listing study subjects")
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
@@ -75,14 +76,14 @@ func (h *Handler) UpdateStudySubject(w http.ResponseWriter, r *http.Request) {
 	subjectID := chi.URLParam(r, "id")
 	if subjectID == "" {
 		log.Warn("Study subject ID not provided")
-		http.Error(w, "study subject id required", http.StatusBadRequest)
+		i18n.Error(w, r, "study subject id required", http.StatusBadRequest)
 		return
 	}
 
 	var payload StudySubject
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 		log.WithError(err).Error("Invalid request body")
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
@@ -92,12 +93,12 @@ func (h *Handler) UpdateStudySubject(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		switch err {
 		case ErrStudySubjectNotFound:
-			http.Error(w, "study subject not found", http.StatusNotFound)
+			i18n.Error(w, r, "study subject not found", http.StatusNotFound)
 		case ErrUnauthorized:
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
 		default:
 			log.WithError(err).Error("Error updating study subject")
-			http.Error(w, "internal server error", http.StatusInternalServerError)
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		}
 		return
 	}
@@ -111,19 +112,19 @@ func (h *Handler) DeleteStudySubject(w http.ResponseWriter, r *http.Request) {
 	subjectID := chi.URLParam(r, "id")
 	if subjectID == "" {
 		log.Warn("Study subject ID not provided")
-		http.Error(w, "study subject id required", http.StatusBadRequest)
+		i18n.Error(w, r, "study subject id required", http.StatusBadRequest)
 		return
 	}
 
 	if err := h.service.DeleteStudySubject(r.Context(), subjectID); err != nil {
 		switch err {
 		case ErrStudySubjectNotFound:
-			http.Error(w, "study subject not found", http.StatusNotFound)
+			i18n.Error(w, r, "study subject not found", http.StatusNotFound)
 		case ErrUnauthorized:
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
 		default:
 			log.WithError(err).Error("Error deleting study subject")
-			http.Error(w, "internal server error", http.StatusInternalServerError)
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		}
 		return
 	}
@@ -23,12 +23,20 @@ type StudySubjectService interface {
 	DeleteStudySubject(ctx context.Context, id string) error
 }
 
+// TaskTrasher moves a subject to the trash together with its quizzes and
+// the tasks of its topics. It is implemented by the task package, which
+// depends on this one.
+type TaskTrasher interface {
+	TrashStudySubject(ctx context.Context, subjectID, userID uuid.UUID) error
+}
+
 type studySubjectService struct {
-	repo StudySubjectRepository
+	repo  StudySubjectRepository
+	tasks TaskTrasher
 }
 
-func NewService(repo StudySubjectRepository) StudySubjectService {
-	return &studySubjectService{repo: repo}
+func NewService(repo StudySubjectRepository, tasks TaskTrasher) StudySubjectService {
+	return &studySubjectService{repo: repo, tasks: tasks}
 }
 
 func (s *studySubjectService) CreateStudySubject(ctx context.Context, subj *StudySubject) (*StudySubject, error) {
@@ -149,7 +157,7 @@ func (s *studySubjectService) DeleteStudySubject(ctx context.Context, id string)
 		return ErrUnauthorized
 	}
 
-	if err := s.repo.Delete(id); err != nil {
+	if err := s.tasks.TrashStudySubject(ctx, subject.ID, subject.UserID); err != nil {
 		log.WithError(err).Error("failed to delete study subject")
 		return err
 	}
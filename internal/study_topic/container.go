@@ -2,22 +2,25 @@ package studytopic
 
 import (
 	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
+	"github.com/saulo-duarte/chronos-lambda/internal/user"
 	"gorm.io/gorm"
 )
 
 type StudyTopicContainer struct {
 	Handler *Handler
 	Repo    StudyTopicRepository
+	Service StudyTopicService
 }
 
 func NewStudyTopicContainer(db *gorm.DB) *StudyTopicContainer {
 	studySubjectRepo := studysubject.NewRepository(db)
 	repo := NewRepository(db)
-	service := NewService(repo, studySubjectRepo)
+	service := NewService(repo, studySubjectRepo, user.NewRepository(db))
 	handler := NewHandler(service)
 
 	return &StudyTopicContainer{
 		Handler: handler,
 		Repo:    repo,
+		Service: service,
 	}
 }
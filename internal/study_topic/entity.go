@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
+	"github.com/saulo-duarte/chronos-lambda/internal/tag"
 	"github.com/saulo-duarte/chronos-lambda/internal/user"
 )
 
@@ -13,10 +14,28 @@ type StudyTopic struct {
 	Name           string                    `json:"name"`
 	Description    string                    `json:"description"`
 	Position       int                       `gorm:"default:0" json:"position"`
+	Tags           []tag.Tag                 `gorm:"many2many:study_topic_tags" json:"tags"`
 	UserID         uuid.UUID                 `gorm:"column:user_id;not null" json:"user_id"`
 	User           user.User                 `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-"`
 	StudySubjectID uuid.UUID                 `gorm:"column:subject_id;not null" json:"subject_id"`
 	StudySubject   studysubject.StudySubject `gorm:"foreignKey:StudySubjectID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-"`
+	EaseFactor     float64                   `gorm:"default:2.5" json:"ease_factor"`
+	IntervalDays   int                       `gorm:"default:0" json:"interval_days"`
+	Repetitions    int                       `gorm:"default:0" json:"repetitions"`
+	NextReviewAt   *time.Time                `json:"next_review_at"`
+	LastReviewedAt *time.Time                `json:"last_reviewed_at"`
 	CreatedAt      time.Time                 `json:"created_at"`
 	UpdatedAt      time.Time                 `json:"updated_at"`
 }
+
+// StudyNote is a plain-text or Markdown note uploaded to a topic, used as
+// source material for quiz generation.
+type StudyNote struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
+	StudyTopicID uuid.UUID `gorm:"type:uuid;not null;index" json:"study_topic_id"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
+	Title        string    `json:"title"`
+	ContentType  string    `json:"content_type"`
+	Content      string    `gorm:"type:text;not null" json:"content"`
+	CreatedAt    time.Time `json:"created_at"`
+}
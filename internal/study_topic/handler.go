@@ -3,12 +3,17 @@ package studytopic
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"path/filepath"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/i18n"
 	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
+	"github.com/saulo-duarte/chronos-lambda/internal/tag"
 )
 
 type Handler struct {
@@ -32,14 +37,14 @@ func (h *Handler) CreateStudyTopic(w http.ResponseWriter, r *http.Request) {
 	var payload createStudyTopicPayload
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 		log.WithError(err).Error("Invalid request body")
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	subjectID, err := uuid.Parse(payload.StudySubjectID)
 	if err != nil {
 		log.WithError(err).Warn("Invalid study subject ID format")
-		http.Error(w, "invalid study subject id", http.StatusBadRequest)
+		i18n.Error(w, r, "invalid study subject id", http.StatusBadRequest)
 		return
 	}
 
@@ -54,14 +59,14 @@ func (h *Handler) CreateStudyTopic(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		switch {
 		case errors.Is(err, ErrUnauthorized):
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
 		case errors.Is(err, studysubject.ErrStudySubjectNotFound):
-			http.Error(w, "study subject not found", http.StatusNotFound)
+			i18n.Error(w, r, "study subject not found", http.StatusNotFound)
 		case err.Error() == "já existe um tópico com esta posição neste assunto":
-			http.Error(w, err.Error(), http.StatusConflict)
+			i18n.Error(w, r, err.Error(), http.StatusConflict)
 		default:
 			log.WithError(err).Error("Error creating study topic")
-			http.Error(w, "internal server error", http.StatusInternalServerError)
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		}
 		return
 	}
@@ -75,7 +80,7 @@ func (h *Handler) GetStudyTopic(w http.ResponseWriter, r *http.Request) {
 	topicID := chi.URLParam(r, "id")
 	if topicID == "" {
 		log.Warn("Study topic ID not provided")
-		http.Error(w, "study topic id required", http.StatusBadRequest)
+		i18n.Error(w, r, "study topic id required", http.StatusBadRequest)
 		return
 	}
 
@@ -83,12 +88,12 @@ func (h *Handler) GetStudyTopic(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		switch {
 		case errors.Is(err, ErrUnauthorized):
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
 		case errors.Is(err, ErrStudyTopicNotFound):
-			http.Error(w, "study topic not found", http.StatusNotFound)
+			i18n.Error(w, r, "study topic not found", http.StatusNotFound)
 		default:
 			log.WithError(err).Error("Error fetching study topic")
-			http.Error(w, "internal server error", http.StatusInternalServerError)
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		}
 		return
 	}
@@ -102,20 +107,26 @@ func (h *Handler) ListStudyTopics(w http.ResponseWriter, r *http.Request) {
 	subjectID := chi.URLParam(r, "studySubjectId")
 	if subjectID == "" {
 		log.Warn("Study subject ID not provided for listing topics")
-		http.Error(w, "study subject id required", http.StatusBadRequest)
+		i18n.Error(w, r, "study subject id required", http.StatusBadRequest)
 		return
 	}
 
-	topics, err := h.service.ListStudyTopicsBySubject(r.Context(), subjectID)
+	tagIDs, err := tag.ParseIDs(r.URL.Query())
+	if err != nil {
+		i18n.Error(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	topics, err := h.service.ListStudyTopicsBySubject(r.Context(), subjectID, tagIDs...)
 	if err != nil {
 		switch {
 		case errors.Is(err, ErrUnauthorized):
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
 		case errors.Is(err, studysubject.ErrStudySubjectNotFound):
-			http.Error(w, "study subject not found", http.StatusNotFound)
+			i18n.Error(w, r, "study subject not found", http.StatusNotFound)
 		default:
 			log.WithError(err).Error("Error listing study topics")
-			http.Error(w, "internal server error", http.StatusInternalServerError)
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		}
 		return
 	}
@@ -132,14 +143,14 @@ func (h *Handler) UpdateStudyTopic(w http.ResponseWriter, r *http.Request) {
 	topicID := chi.URLParam(r, "id")
 	if topicID == "" {
 		log.Warn("Study topic ID not provided")
-		http.Error(w, "study topic id required", http.StatusBadRequest)
+		i18n.Error(w, r, "study topic id required", http.StatusBadRequest)
 		return
 	}
 
 	var payload StudyTopic
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 		log.WithError(err).Error("Invalid request body")
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
 		return
 	}
 	payload.ID = uuid.MustParse(topicID)
@@ -148,12 +159,12 @@ func (h *Handler) UpdateStudyTopic(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		switch {
 		case errors.Is(err, ErrUnauthorized):
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
 		case errors.Is(err, ErrStudyTopicNotFound):
-			http.Error(w, "study topic not found", http.StatusNotFound)
+			i18n.Error(w, r, "study topic not found", http.StatusNotFound)
 		default:
 			log.WithError(err).Error("Error updating study topic")
-			http.Error(w, "internal server error", http.StatusInternalServerError)
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		}
 		return
 	}
@@ -167,19 +178,19 @@ func (h *Handler) DeleteStudyTopic(w http.ResponseWriter, r *http.Request) {
 	topicID := chi.URLParam(r, "id")
 	if topicID == "" {
 		log.Warn("Study topic ID not provided")
-		http.Error(w, "study topic id required", http.StatusBadRequest)
+		i18n.Error(w, r, "study topic id required", http.StatusBadRequest)
 		return
 	}
 
 	if err := h.service.DeleteStudyTopic(r.Context(), topicID); err != nil {
 		switch {
 		case errors.Is(err, ErrUnauthorized):
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
 		case errors.Is(err, ErrStudyTopicNotFound):
-			http.Error(w, "study topic not found", http.StatusNotFound)
+			i18n.Error(w, r, "study topic not found", http.StatusNotFound)
 		default:
 			log.WithError(err).Error("Error deleting study topic")
-			http.Error(w, "internal server error", http.StatusInternalServerError)
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
 		}
 		return
 	}
@@ -188,3 +199,180 @@ func (h *Handler) DeleteStudyTopic(w http.ResponseWriter, r *http.Request) {
 		"message": "study topic deleted successfully",
 	})
 }
+
+type reviewStudyTopicPayload struct {
+	Quality *int `json:"quality"`
+}
+
+func (h *Handler) ReviewStudyTopic(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	topicID := chi.URLParam(r, "id")
+	if topicID == "" {
+		log.Warn("Study topic ID not provided")
+		i18n.Error(w, r, "study topic id required", http.StatusBadRequest)
+		return
+	}
+
+	var payload reviewStudyTopicPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.Quality == nil {
+		log.WithError(err).Error("Invalid request body")
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	topic, err := h.service.ReviewStudyTopic(r.Context(), topicID, *payload.Quality)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUnauthorized):
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+		case errors.Is(err, ErrStudyTopicNotFound):
+			i18n.Error(w, r, "study topic not found", http.StatusNotFound)
+		case errors.Is(err, ErrInvalidReviewQuality):
+			i18n.Error(w, r, err.Error(), http.StatusBadRequest)
+		default:
+			log.WithError(err).Error("Error reviewing study topic")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, topic)
+}
+
+func (h *Handler) ListDueStudyTopics(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	topics, err := h.service.ListDueStudyTopics(r.Context())
+	if err != nil {
+		if errors.Is(err, ErrUnauthorized) {
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		log.WithError(err).Error("Error listing due study topics")
+		i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]interface{}{
+		"count":  len(topics),
+		"topics": topics,
+	})
+}
+
+type addStudyNotePayload struct {
+	Title       string `json:"title"`
+	ContentType string `json:"content_type"`
+	Content     string `json:"content"`
+}
+
+// AddStudyNote accepts either a JSON body or a multipart upload with a
+// "file" field containing a .txt or .md file.
+func (h *Handler) AddStudyNote(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	topicID := chi.URLParam(r, "id")
+	if topicID == "" {
+		log.Warn("Study topic ID not provided")
+		i18n.Error(w, r, "study topic id required", http.StatusBadRequest)
+		return
+	}
+
+	var payload addStudyNotePayload
+	r.Body = http.MaxBytesReader(w, r.Body, MaxStudyNoteSize+64*1024)
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			log.WithError(err).Warn("Study note file not provided")
+			i18n.Error(w, r, "file required", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		content, err := io.ReadAll(file)
+		if err != nil {
+			log.WithError(err).Warn("Failed to read study note upload")
+			i18n.Error(w, r, "invalid file", http.StatusBadRequest)
+			return
+		}
+
+		payload.Title = r.FormValue("title")
+		if payload.Title == "" {
+			payload.Title = header.Filename
+		}
+		payload.ContentType = header.Header.Get("Content-Type")
+		if ext := filepath.Ext(header.Filename); ext != "" {
+			payload.ContentType = ext
+		}
+		payload.Content = string(content)
+	} else if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Invalid request body")
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	note, err := h.service.AddStudyNote(r.Context(), topicID, &StudyNote{
+		Title:       payload.Title,
+		ContentType: payload.ContentType,
+		Content:     payload.Content,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUnauthorized):
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+		case errors.Is(err, ErrStudyTopicNotFound):
+			i18n.Error(w, r, "study topic not found", http.StatusNotFound)
+		case errors.Is(err, ErrEmptyStudyNote), errors.Is(err, ErrInvalidNoteType):
+			i18n.Error(w, r, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, ErrStudyNoteTooLarge):
+			i18n.Error(w, r, err.Error(), http.StatusRequestEntityTooLarge)
+		default:
+			log.WithError(err).Error("Error adding study note")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, note)
+}
+
+func (h *Handler) ListStudyNotes(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	notes, err := h.service.ListStudyNotes(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUnauthorized):
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+		case errors.Is(err, ErrStudyTopicNotFound):
+			i18n.Error(w, r, "study topic not found", http.StatusNotFound)
+		default:
+			log.WithError(err).Error("Error listing study notes")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, notes)
+}
+
+func (h *Handler) DeleteStudyNote(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	err := h.service.DeleteStudyNote(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "noteID"))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUnauthorized):
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+		case errors.Is(err, ErrStudyTopicNotFound), errors.Is(err, ErrStudyNoteNotFound):
+			i18n.Error(w, r, err.Error(), http.StatusNotFound)
+		default:
+			log.WithError(err).Error("Error deleting study note")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,18 +2,34 @@ package studytopic
 
 import (
 	"errors"
+	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type StudyTopicRepository interface {
 	Create(t *StudyTopic) error
 	GetByID(id string) (*StudyTopic, error)
-	ListBySubject(studySubjectID string) ([]*StudyTopic, error)
+	// ListBySubject returns the subject's topics; given tag IDs, only those
+	// tagged with any of them.
+	ListBySubject(studySubjectID string, tagIDs ...uuid.UUID) ([]*StudyTopic, error)
 	Update(t *StudyTopic) error
 	Delete(id string) error
+	// ListDueByUser returns the user's topics due for review by until,
+	// including topics never reviewed, those first.
+	ListDueByUser(userID string, until time.Time) ([]*StudyTopic, error)
+
+	CreateNote(n *StudyNote) error
+	ListNotes(topicID, userID string) ([]*StudyNote, error)
+	DeleteNote(id, topicID, userID string) error
 }
 
+// subjectNotTrashed hides topics whose subject is in the trash. The topics
+// are not trashed themselves and come back when the subject is restored.
+const subjectNotTrashed = "NOT EXISTS (SELECT 1 FROM study_subjects s " +
+	"WHERE s.id = study_topics.subject_id AND s.deleted_at IS NOT NULL)"
+
 type studyTopicRepository struct {
 	db *gorm.DB
 }
@@ -23,12 +39,12 @@ func NewRepository(db *gorm.DB) StudyTopicRepository {
 }
 
 func (r *studyTopicRepository) Create(t *StudyTopic) error {
-	return r.db.Create(t).Error
+	return r.db.Omit("Tags").Create(t).Error
 }
 
 func (r *studyTopicRepository) GetByID(id string) (*StudyTopic, error) {
 	var topic StudyTopic
-	if err := r.db.First(&topic, "id = ?", id).Error; err != nil {
+	if err := r.db.Preload("Tags").Where(subjectNotTrashed).First(&topic, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
@@ -37,18 +53,60 @@ func (r *studyTopicRepository) GetByID(id string) (*StudyTopic, error) {
 	return &topic, nil
 }
 
-func (r *studyTopicRepository) ListBySubject(studySubjectID string) ([]*StudyTopic, error) {
+func (r *studyTopicRepository) ListBySubject(studySubjectID string, tagIDs ...uuid.UUID) ([]*StudyTopic, error) {
 	var topics []*StudyTopic
-	if err := r.db.Where("subject_id = ?", studySubjectID).Find(&topics).Error; err != nil {
+	query := r.db.Preload("Tags").Where("subject_id = ?", studySubjectID)
+	if len(tagIDs) > 0 {
+		query = query.Where("id IN (SELECT study_topic_id FROM study_topic_tags WHERE tag_id IN ?)", tagIDs)
+	}
+	if err := query.Find(&topics).Error; err != nil {
 		return nil, err
 	}
 	return topics, nil
 }
 
 func (r *studyTopicRepository) Update(t *StudyTopic) error {
-	return r.db.Save(t).Error
+	return r.db.Omit("Tags").Save(t).Error
 }
 
 func (r *studyTopicRepository) Delete(id string) error {
 	return r.db.Delete(&StudyTopic{}, "id = ?", id).Error
 }
+
+func (r *studyTopicRepository) ListDueByUser(userID string, until time.Time) ([]*StudyTopic, error) {
+	var topics []*StudyTopic
+	if err := r.db.Preload("Tags").
+		Where("user_id = ? AND (next_review_at IS NULL OR next_review_at <= ?)", userID, until).
+		Where(subjectNotTrashed).
+		Order("next_review_at ASC NULLS FIRST, created_at ASC").
+		Find(&topics).Error; err != nil {
+		return nil, err
+	}
+	return topics, nil
+}
+
+func (r *studyTopicRepository) CreateNote(n *StudyNote) error {
+	return r.db.Create(n).Error
+}
+
+func (r *studyTopicRepository) ListNotes(topicID, userID string) ([]*StudyNote, error) {
+	var notes []*StudyNote
+	if err := r.db.
+		Where("study_topic_id = ? AND user_id = ?", topicID, userID).
+		Order("created_at ASC").
+		Find(&notes).Error; err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+func (r *studyTopicRepository) DeleteNote(id, topicID, userID string) error {
+	result := r.db.Delete(&StudyNote{}, "id = ? AND study_topic_id = ? AND user_id = ?", id, topicID, userID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
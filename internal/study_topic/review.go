@@ -0,0 +1,81 @@
+package studytopic
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+const (
+	DefaultEaseFactor = 2.5
+	MinEaseFactor     = 1.3
+	MaxReviewQuality  = 5
+)
+
+var ErrInvalidReviewQuality = errors.New("review quality must be between 0 and 5")
+
+// ReviewState is the SM-2 scheduling state kept for each topic.
+type ReviewState struct {
+	EaseFactor   float64
+	IntervalDays int
+	Repetitions  int
+}
+
+// Schedule applies the SM-2 algorithm to a recall rating (0 = total blackout,
+// 5 = perfect recall) and returns the new state and the next review date.
+func Schedule(state ReviewState, quality int, now time.Time) (ReviewState, time.Time, error) {
+	if quality < 0 || quality > MaxReviewQuality {
+		return state, time.Time{}, ErrInvalidReviewQuality
+	}
+
+	if state.EaseFactor == 0 {
+		state.EaseFactor = DefaultEaseFactor
+	}
+
+	if quality < 3 {
+		state.Repetitions = 0
+		state.IntervalDays = 1
+	} else {
+		switch state.Repetitions {
+		case 0:
+			state.IntervalDays = 1
+		case 1:
+			state.IntervalDays = 6
+		default:
+			state.IntervalDays = int(math.Round(float64(state.IntervalDays) * state.EaseFactor))
+		}
+		state.Repetitions++
+	}
+
+	miss := float64(MaxReviewQuality - quality)
+	state.EaseFactor += 0.1 - miss*(0.08+miss*0.02)
+	if state.EaseFactor < MinEaseFactor {
+		state.EaseFactor = MinEaseFactor
+	}
+
+	return state, now.AddDate(0, 0, state.IntervalDays), nil
+}
+
+// QualityFromQuizScore maps a quiz result to an SM-2 rating so graded quizzes
+// can feed the review schedule without asking the user.
+func QualityFromQuizScore(correct, total int) int {
+	if total <= 0 {
+		return 0
+	}
+
+	ratio := float64(correct) / float64(total)
+	switch {
+	case ratio >= 0.95:
+		return 5
+	case ratio >= 0.8:
+		return 4
+	case ratio >= 0.6:
+		return 3
+	case ratio >= 0.4:
+		return 2
+	case ratio >= 0.2:
+		return 1
+	default:
+		return 0
+	}
+}
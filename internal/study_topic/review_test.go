@@ -0,0 +1,84 @@
+package studytopic_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+)
+
+func TestSchedule(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("SuccessfulReviewsGrowInterval", func(t *testing.T) {
+		state := studytopic.ReviewState{}
+		expectedIntervals := []int{1, 6, 15}
+
+		for i, expected := range expectedIntervals {
+			var err error
+			state, _, err = studytopic.Schedule(state, 4, now)
+			if err != nil {
+				t.Fatalf("Schedule falhou: %v", err)
+			}
+			if state.IntervalDays != expected {
+				t.Errorf("Revisão %d: intervalo incorreto. Esperado: %d, Recebido: %d", i+1, expected, state.IntervalDays)
+			}
+		}
+
+		if state.Repetitions != 3 {
+			t.Errorf("Repetições incorretas. Esperado: 3, Recebido: %d", state.Repetitions)
+		}
+	})
+
+	t.Run("FailedRecallResetsRepetitions", func(t *testing.T) {
+		state := studytopic.ReviewState{EaseFactor: 2.5, IntervalDays: 15, Repetitions: 3}
+
+		state, next, err := studytopic.Schedule(state, 1, now)
+		if err != nil {
+			t.Fatalf("Schedule falhou: %v", err)
+		}
+		if state.Repetitions != 0 || state.IntervalDays != 1 {
+			t.Errorf("Estado deveria ter sido reiniciado, Recebido: %+v", state)
+		}
+		if !next.Equal(now.AddDate(0, 0, 1)) {
+			t.Errorf("Próxima revisão incorreta: %v", next)
+		}
+	})
+
+	t.Run("EaseFactorHasFloor", func(t *testing.T) {
+		state := studytopic.ReviewState{EaseFactor: studytopic.MinEaseFactor}
+
+		state, _, _ = studytopic.Schedule(state, 0, now)
+		if state.EaseFactor != studytopic.MinEaseFactor {
+			t.Errorf("Fator de facilidade abaixo do mínimo: %v", state.EaseFactor)
+		}
+	})
+
+	t.Run("InvalidQuality", func(t *testing.T) {
+		_, _, err := studytopic.Schedule(studytopic.ReviewState{}, 6, now)
+		if !errors.Is(err, studytopic.ErrInvalidReviewQuality) {
+			t.Errorf("Erro incorreto. Esperado: %v, Recebido: %v", studytopic.ErrInvalidReviewQuality, err)
+		}
+	})
+}
+
+func TestQualityFromQuizScore(t *testing.T) {
+	cases := []struct {
+		correct, total, expected int
+	}{
+		{10, 10, 5},
+		{8, 10, 4},
+		{6, 10, 3},
+		{4, 10, 2},
+		{2, 10, 1},
+		{0, 10, 0},
+		{0, 0, 0},
+	}
+
+	for _, c := range cases {
+		if got := studytopic.QualityFromQuizScore(c.correct, c.total); got != c.expected {
+			t.Errorf("QualityFromQuizScore(%d, %d): Esperado: %d, Recebido: %d", c.correct, c.total, c.expected, got)
+		}
+	}
+}
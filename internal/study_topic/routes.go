@@ -13,6 +13,11 @@ func Routes(h *Handler) http.Handler {
 	r.Use(auth.AuthMiddleware)
 
 	r.Post("/", h.CreateStudyTopic)
+	r.Get("/due", h.ListDueStudyTopics)
+	r.Post("/{id}/reviews", h.ReviewStudyTopic)
+	r.Post("/{id}/notes", h.AddStudyNote)
+	r.Get("/{id}/notes", h.ListStudyNotes)
+	r.Delete("/{id}/notes/{noteID}", h.DeleteStudyNote)
 	r.Get("/{id}", h.ListStudyTopics)
 	r.Put("/{id}", h.UpdateStudyTopic)
 	r.Delete("/{id}", h.DeleteStudyTopic)
@@ -3,36 +3,56 @@ package studytopic
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/saulo-duarte/chronos-lambda/internal/auth"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
 	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
+	"github.com/saulo-duarte/chronos-lambda/internal/user"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 var (
 	ErrStudyTopicNotFound   = errors.New("study topic not found")
 	ErrStudySubjectNotFound = studysubject.ErrStudySubjectNotFound
 	ErrUnauthorized         = errors.New("unauthorized")
+	ErrStudyNoteNotFound    = errors.New("study note not found")
+	ErrEmptyStudyNote       = errors.New("study note content is empty")
+	ErrInvalidNoteType      = errors.New("only plain text and markdown notes are supported")
+	ErrStudyNoteTooLarge    = errors.New("study note exceeds the maximum size")
 )
 
+// MaxStudyNoteSize limits uploaded notes to 1 MiB.
+const MaxStudyNoteSize = 1 << 20
+
 type StudyTopicService interface {
 	CreateStudyTopic(ctx context.Context, topic *StudyTopic) (*StudyTopic, error)
 	GetStudyTopicByID(ctx context.Context, id string) (*StudyTopic, error)
-	ListStudyTopicsBySubject(ctx context.Context, studySubjectID string) ([]*StudyTopic, error)
+	// ListStudyTopicsBySubject lists the subject's topics, optionally only
+	// those tagged with any of tagIDs.
+	ListStudyTopicsBySubject(ctx context.Context, studySubjectID string, tagIDs ...uuid.UUID) ([]*StudyTopic, error)
 	UpdateStudyTopic(ctx context.Context, topic *StudyTopic) (*StudyTopic, error)
 	DeleteStudyTopic(ctx context.Context, id string) error
+	ReviewStudyTopic(ctx context.Context, id string, quality int) (*StudyTopic, error)
+	ReviewFromQuizResult(ctx context.Context, id string, correct, total int) (*StudyTopic, error)
+	ListDueStudyTopics(ctx context.Context) ([]*StudyTopic, error)
+	AddStudyNote(ctx context.Context, topicID string, note *StudyNote) (*StudyNote, error)
+	ListStudyNotes(ctx context.Context, topicID string) ([]*StudyNote, error)
+	DeleteStudyNote(ctx context.Context, topicID, noteID string) error
 }
 
 type studyTopicService struct {
 	repo        StudyTopicRepository
 	subjectRepo studysubject.StudySubjectRepository
+	userRepo    user.UserRepository
 }
 
-func NewService(repo StudyTopicRepository, subjectRepo studysubject.StudySubjectRepository) StudyTopicService {
-	return &studyTopicService{repo: repo, subjectRepo: subjectRepo}
+func NewService(repo StudyTopicRepository, subjectRepo studysubject.StudySubjectRepository, userRepo user.UserRepository) StudyTopicService {
+	return &studyTopicService{repo: repo, subjectRepo: subjectRepo, userRepo: userRepo}
 }
 
 func (s *studyTopicService) CreateStudyTopic(ctx context.Context, topic *StudyTopic) (*StudyTopic, error) {
@@ -73,6 +93,7 @@ func (s *studyTopicService) CreateStudyTopic(ctx context.Context, topic *StudyTo
 
 	topic.ID = uuid.New()
 	topic.UserID = uuid.MustParse(claims.UserID)
+	topic.EaseFactor = DefaultEaseFactor
 	topic.CreatedAt = time.Now()
 	topic.UpdatedAt = time.Now()
 
@@ -119,7 +140,7 @@ func (s *studyTopicService) GetStudyTopicByID(ctx context.Context, id string) (*
 	return topic, nil
 }
 
-func (s *studyTopicService) ListStudyTopicsBySubject(ctx context.Context, studySubjectID string) ([]*StudyTopic, error) {
+func (s *studyTopicService) ListStudyTopicsBySubject(ctx context.Context, studySubjectID string, tagIDs ...uuid.UUID) ([]*StudyTopic, error) {
 	log := config.WithContext(ctx)
 
 	claims, err := auth.GetUserClaimsFromContext(ctx)
@@ -144,7 +165,7 @@ func (s *studyTopicService) ListStudyTopicsBySubject(ctx context.Context, studyS
 		return nil, ErrUnauthorized
 	}
 
-	topics, err := s.repo.ListBySubject(studySubjectID)
+	topics, err := s.repo.ListBySubject(studySubjectID, tagIDs...)
 	if err != nil {
 		log.WithError(err).Error("Error listing study topics by subject")
 		return nil, err
@@ -253,6 +274,166 @@ func (s *studyTopicService) DeleteStudyTopic(ctx context.Context, id string) err
 	return nil
 }
 
+func (s *studyTopicService) ReviewStudyTopic(ctx context.Context, id string, quality int) (*StudyTopic, error) {
+	log := config.WithContext(ctx)
+
+	topic, err := s.GetStudyTopicByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	state, nextReview, err := Schedule(ReviewState{
+		EaseFactor:   topic.EaseFactor,
+		IntervalDays: topic.IntervalDays,
+		Repetitions:  topic.Repetitions,
+	}, quality, now)
+	if err != nil {
+		return nil, err
+	}
+
+	topic.EaseFactor = state.EaseFactor
+	topic.IntervalDays = state.IntervalDays
+	topic.Repetitions = state.Repetitions
+	topic.NextReviewAt = &nextReview
+	topic.LastReviewedAt = &now
+	topic.UpdatedAt = now
+
+	if err := s.repo.Update(topic); err != nil {
+		log.WithError(err).Error("Failed to save study topic review")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"topic_id":       topic.ID,
+		"quality":        quality,
+		"interval_days":  topic.IntervalDays,
+		"next_review_at": nextReview,
+	}).Info("Study topic reviewed successfully")
+
+	return topic, nil
+}
+
+func (s *studyTopicService) ReviewFromQuizResult(ctx context.Context, id string, correct, total int) (*StudyTopic, error) {
+	return s.ReviewStudyTopic(ctx, id, QualityFromQuizScore(correct, total))
+}
+
+func (s *studyTopicService) ListDueStudyTopics(ctx context.Context) ([]*StudyTopic, error) {
+	log := config.WithContext(ctx)
+
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		log.WithError(err).Warn("Attempt to list due study topics without authentication")
+		return nil, ErrUnauthorized
+	}
+
+	now := time.Now().In(s.userLocation(ctx, claims.UserID))
+	endOfToday := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
+
+	topics, err := s.repo.ListDueByUser(claims.UserID, endOfToday)
+	if err != nil {
+		log.WithError(err).Error("Error listing due study topics")
+		return nil, err
+	}
+
+	return topics, nil
+}
+
+func (s *studyTopicService) AddStudyNote(ctx context.Context, topicID string, note *StudyNote) (*StudyNote, error) {
+	log := config.WithContext(ctx)
+
+	topic, err := s.GetStudyTopicByID(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+
+	note.Content = strings.TrimSpace(note.Content)
+	if note.Content == "" {
+		return nil, ErrEmptyStudyNote
+	}
+	if len(note.Content) > MaxStudyNoteSize {
+		return nil, ErrStudyNoteTooLarge
+	}
+	contentType, ok := NormalizeNoteContentType(note.ContentType)
+	if !ok {
+		return nil, ErrInvalidNoteType
+	}
+
+	note.ID = uuid.New()
+	note.StudyTopicID = topic.ID
+	note.UserID = topic.UserID
+	note.ContentType = contentType
+	if note.Title == "" {
+		note.Title = topic.Name
+	}
+
+	if err := s.repo.CreateNote(note); err != nil {
+		log.WithError(err).Error("Failed to save study note")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"topic_id": topic.ID,
+		"note_id":  note.ID,
+	}).Info("Study note added successfully")
+
+	return note, nil
+}
+
+func (s *studyTopicService) ListStudyNotes(ctx context.Context, topicID string) ([]*StudyNote, error) {
+	log := config.WithContext(ctx)
+
+	topic, err := s.GetStudyTopicByID(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := s.repo.ListNotes(topic.ID.String(), topic.UserID.String())
+	if err != nil {
+		log.WithError(err).Error("Error listing study notes")
+		return nil, err
+	}
+
+	return notes, nil
+}
+
+func (s *studyTopicService) DeleteStudyNote(ctx context.Context, topicID, noteID string) error {
+	log := config.WithContext(ctx)
+
+	topic, err := s.GetStudyTopicByID(ctx, topicID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteNote(noteID, topic.ID.String(), topic.UserID.String()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrStudyNoteNotFound
+		}
+		log.WithError(err).Error("Error deleting study note")
+		return err
+	}
+
+	return nil
+}
+
+// NormalizeNoteContentType maps upload content types and file extensions to
+// text/plain or text/markdown.
+func NormalizeNoteContentType(value string) (string, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if i := strings.Index(value, ";"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+
+	switch value {
+	case "", "text/plain", ".txt", "txt":
+		return "text/plain", true
+	case "text/markdown", "text/x-markdown", ".md", "md", ".markdown", "markdown":
+		return "text/markdown", true
+	default:
+		return "", false
+	}
+}
+
 func (s *studyTopicService) validateUniquePosition(position int, studySubjectID string, userID string, excludeID string) error {
 	topics, err := s.repo.ListBySubject(studySubjectID)
 	if err != nil {
@@ -270,3 +451,14 @@ func (s *studyTopicService) validateUniquePosition(position int, studySubjectID
 
 	return nil
 }
+
+// userLocation is the user's time zone, which decides when their day, and so
+// the list of due topics, rolls over.
+func (s *studyTopicService) userLocation(ctx context.Context, userID string) *time.Location {
+	u, err := s.userRepo.GetByID(userID)
+	if err != nil || u == nil {
+		config.WithContext(ctx).WithError(err).Warn("Failed to load user time zone, using default")
+		return util.DefaultLocation()
+	}
+	return u.Location()
+}
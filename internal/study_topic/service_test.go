@@ -0,0 +1,61 @@
+package studytopic_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"github.com/saulo-duarte/chronos-lambda/internal/user"
+)
+
+func TestMain(m *testing.M) {
+	config.Init()
+	os.Exit(m.Run())
+}
+
+// fakeDueRepo records the cutoff the service asks due topics for.
+type fakeDueRepo struct {
+	studytopic.StudyTopicRepository
+	until time.Time
+}
+
+func (f *fakeDueRepo) ListDueByUser(userID string, until time.Time) ([]*studytopic.StudyTopic, error) {
+	f.until = until
+	return nil, nil
+}
+
+type fakeTimezoneUserRepo struct {
+	user.UserRepository
+	timezone string
+}
+
+func (f *fakeTimezoneUserRepo) GetByID(id string) (*user.User, error) {
+	return &user.User{ID: uuid.MustParse(id), Timezone: f.timezone}, nil
+}
+
+func TestListDueStudyTopics(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.UserDataKeyID, userID.String())
+	ctx = context.WithValue(ctx, auth.UserDataKeyRole, "user")
+
+	t.Run("DayEndsInUserTimezone", func(t *testing.T) {
+		repo := &fakeDueRepo{}
+		svc := studytopic.NewService(repo, nil, &fakeTimezoneUserRepo{timezone: "Pacific/Kiritimati"})
+
+		if _, err := svc.ListDueStudyTopics(ctx); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+
+		loc, _ := time.LoadLocation("Pacific/Kiritimati")
+		now := time.Now().In(loc)
+		expected := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, loc)
+		if !repo.until.Equal(expected) {
+			t.Errorf("Fim do dia deveria seguir o fuso do usuário. Esperado: %v, Recebido: %v", expected, repo.until)
+		}
+	})
+}
@@ -0,0 +1,17 @@
+package tag
+
+import "gorm.io/gorm"
+
+type TagContainer struct {
+	Handler *Handler
+	Service Service
+}
+
+func NewTagContainer(db *gorm.DB) *TagContainer {
+	service := NewService(NewRepository(db))
+
+	return &TagContainer{
+		Handler: NewHandler(service),
+		Service: service,
+	}
+}
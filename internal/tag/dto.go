@@ -0,0 +1,54 @@
+package tag
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidTagFilter = errors.New("invalid tag filter")
+
+const (
+	DefaultColor     = "#6B7280"
+	maxTagNameLength = 50
+)
+
+var colorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// TagDTO creates a tag or, on update, renames and recolors it; an empty
+// color keeps the current one.
+type TagDTO struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type MergeDTO struct {
+	IntoID uuid.UUID `json:"into_id"`
+}
+
+// SetTagsDTO replaces every tag of a task, project or study topic.
+type SetTagsDTO struct {
+	TagIDs []uuid.UUID `json:"tag_ids"`
+}
+
+// ParseIDs reads the tag filter of the list endpoints: repeated or
+// comma-separated "tag" parameters holding tag IDs.
+func ParseIDs(values url.Values) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, raw := range values["tag"] {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v == "" {
+				continue
+			}
+			id, err := uuid.Parse(v)
+			if err != nil {
+				return nil, ErrInvalidTagFilter
+			}
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
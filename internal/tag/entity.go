@@ -0,0 +1,56 @@
+package tag
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tag is a user-defined label shared by tasks, projects and study topics.
+// Links point at the tag's ID, so a rename shows up everywhere at once.
+type Tag struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
+	UserID    uuid.UUID `gorm:"column:user_id;not null" json:"user_id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TagCount is how often a tag is used, for the dashboard tag cloud.
+type TagCount struct {
+	TagID       uuid.UUID `json:"tag_id"`
+	Name        string    `json:"name"`
+	Color       string    `json:"color"`
+	Tasks       int64     `json:"tasks"`
+	Projects    int64     `json:"projects"`
+	StudyTopics int64     `json:"study_topics"`
+	Total       int64     `json:"total" gorm:"-"`
+}
+
+// TargetType names a kind of record tags can be attached to.
+type TargetType string
+
+const (
+	TaskTarget       TargetType = "tasks"
+	ProjectTarget    TargetType = "projects"
+	StudyTopicTarget TargetType = "study-topics"
+)
+
+// target is the table a TargetType lives in and its link table.
+type target struct {
+	table     string
+	linkTable string
+	column    string
+}
+
+var targets = map[TargetType]target{
+	TaskTarget:       {table: "tasks", linkTable: "task_tags", column: "task_id"},
+	ProjectTarget:    {table: "projects", linkTable: "project_tags", column: "project_id"},
+	StudyTopicTarget: {table: "study_topics", linkTable: "study_topic_tags", column: "study_topic_id"},
+}
+
+func (t TargetType) IsValid() bool {
+	_, ok := targets[t]
+	return ok
+}
@@ -0,0 +1,168 @@
+package tag
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/i18n"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	tags, err := h.service.ListTags(r.Context())
+	if err != nil {
+		if !writeTagError(w, r, err) {
+			log.WithError(err).Error("Failed to list tags")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, tags)
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload TagDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	t, err := h.service.CreateTag(r.Context(), &payload)
+	if err != nil {
+		if !writeTagError(w, r, err) {
+			log.WithError(err).Error("Failed to create tag")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, t)
+}
+
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload TagDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	t, err := h.service.UpdateTag(r.Context(), chi.URLParam(r, "tagID"), &payload)
+	if err != nil {
+		if !writeTagError(w, r, err) {
+			log.WithError(err).Error("Failed to update tag")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, t)
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	if err := h.service.DeleteTag(r.Context(), chi.URLParam(r, "tagID")); err != nil {
+		if !writeTagError(w, r, err) {
+			log.WithError(err).Error("Failed to delete tag")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) Merge(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload MergeDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.IntoID == uuid.Nil {
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	t, err := h.service.MergeTags(r.Context(), chi.URLParam(r, "tagID"), payload.IntoID)
+	if err != nil {
+		if !writeTagError(w, r, err) {
+			log.WithError(err).Error("Failed to merge tags")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, t)
+}
+
+func (h *Handler) Counts(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	counts, err := h.service.TagCounts(r.Context())
+	if err != nil {
+		if !writeTagError(w, r, err) {
+			log.WithError(err).Error("Failed to count tags")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, counts)
+}
+
+func (h *Handler) SetTags(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload SetTagsDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	target := TargetType(chi.URLParam(r, "target"))
+	tags, err := h.service.SetTags(r.Context(), target, chi.URLParam(r, "id"), payload.TagIDs)
+	if err != nil {
+		if !writeTagError(w, r, err) {
+			log.WithError(err).Error("Failed to set tags")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, tags)
+}
+
+func writeTagError(w http.ResponseWriter, r *http.Request, err error) bool {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrInvalidID):
+		i18n.Error(w, r, "invalid id", http.StatusBadRequest)
+	case errors.Is(err, ErrTagNotFound), errors.Is(err, ErrTargetNotFound):
+		i18n.Error(w, r, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrTagExists):
+		i18n.Error(w, r, err.Error(), http.StatusConflict)
+	case errors.Is(err, ErrInvalidTagName), errors.Is(err, ErrInvalidTagColor),
+		errors.Is(err, ErrInvalidTarget), errors.Is(err, ErrMergeIntoItself):
+		i18n.Error(w, r, err.Error(), http.StatusBadRequest)
+	default:
+		return false
+	}
+	return true
+}
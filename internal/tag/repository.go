@@ -0,0 +1,169 @@
+package tag
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrNotFound = errors.New("tag not found")
+
+type TagRepository interface {
+	// Create and Update fail with ErrTagExists when the user already has a
+	// tag with the same name, ignoring case.
+	Create(t *Tag) error
+	Update(t *Tag) error
+	Delete(id, userID uuid.UUID) error
+	FindByIDAndUser(id, userID uuid.UUID) (*Tag, error)
+	ListByUser(userID uuid.UUID) ([]*Tag, error)
+	// CountOwned counts how many of ids are tags of the user.
+	CountOwned(userID uuid.UUID, ids []uuid.UUID) (int64, error)
+	Counts(userID uuid.UUID) ([]*TagCount, error)
+	// Merge moves every link of source to into and deletes source.
+	Merge(sourceID, intoID uuid.UUID) error
+
+	// OwnsTarget reports whether the task, project or study topic exists
+	// and belongs to the user.
+	OwnsTarget(t TargetType, id, userID uuid.UUID) (bool, error)
+	SetTargetTags(t TargetType, id uuid.UUID, tagIDs []uuid.UUID) error
+	ListTargetTags(t TargetType, id uuid.UUID) ([]*Tag, error)
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) Create(t *Tag) error {
+	return r.translate(r.db.Create(t).Error)
+}
+
+func (r *tagRepository) Update(t *Tag) error {
+	return r.translate(r.db.Save(t).Error)
+}
+
+func (r *tagRepository) Delete(id, userID uuid.UUID) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&Tag{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *tagRepository) FindByIDAndUser(id, userID uuid.UUID) (*Tag, error) {
+	var t Tag
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&t).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *tagRepository) ListByUser(userID uuid.UUID) ([]*Tag, error) {
+	var tags []*Tag
+	err := r.db.Where("user_id = ?", userID).Order("LOWER(name)").Find(&tags).Error
+	return tags, err
+}
+
+func (r *tagRepository) CountOwned(userID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	var count int64
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	err := r.db.Model(&Tag{}).Where("user_id = ? AND id IN ?", userID, ids).Count(&count).Error
+	return count, err
+}
+
+// Counts leaves out trashed tasks and projects, and topics of trashed
+// subjects.
+func (r *tagRepository) Counts(userID uuid.UUID) ([]*TagCount, error) {
+	var counts []*TagCount
+	err := r.db.Raw(`
+		SELECT g.id AS tag_id, g.name, g.color,
+			(SELECT COUNT(*) FROM task_tags x JOIN tasks t ON t.id = x.task_id
+				WHERE x.tag_id = g.id AND t.deleted_at IS NULL) AS tasks,
+			(SELECT COUNT(*) FROM project_tags x JOIN projects p ON p.id = x.project_id
+				WHERE x.tag_id = g.id AND p.deleted_at IS NULL) AS projects,
+			(SELECT COUNT(*) FROM study_topic_tags x JOIN study_topics st ON st.id = x.study_topic_id
+				JOIN study_subjects s ON s.id = st.subject_id
+				WHERE x.tag_id = g.id AND s.deleted_at IS NULL) AS study_topics
+		FROM tags g WHERE g.user_id = ?`,
+		userID,
+	).Scan(&counts).Error
+	return counts, err
+}
+
+func (r *tagRepository) Merge(sourceID, intoID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, t := range targets {
+			if err := tx.Exec(
+				"INSERT INTO "+t.linkTable+" ("+t.column+", tag_id) SELECT "+t.column+", ? FROM "+t.linkTable+
+					" WHERE tag_id = ? ON CONFLICT DO NOTHING",
+				intoID, sourceID,
+			).Error; err != nil {
+				return err
+			}
+		}
+		// The source's own links go with it (ON DELETE CASCADE).
+		return tx.Delete(&Tag{}, "id = ?", sourceID).Error
+	})
+}
+
+func (r *tagRepository) OwnsTarget(t TargetType, id, userID uuid.UUID) (bool, error) {
+	query := r.db.Table(targets[t].table).Where("id = ? AND user_id = ?", id, userID)
+	if t == StudyTopicTarget {
+		query = query.Where("NOT EXISTS (SELECT 1 FROM study_subjects s " +
+			"WHERE s.id = study_topics.subject_id AND s.deleted_at IS NOT NULL)")
+	} else {
+		query = query.Where("deleted_at IS NULL")
+	}
+	var count int64
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+func (r *tagRepository) SetTargetTags(t TargetType, id uuid.UUID, tagIDs []uuid.UUID) error {
+	target := targets[t]
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM "+target.linkTable+" WHERE "+target.column+" = ?", id).Error; err != nil {
+			return err
+		}
+		for _, tagID := range tagIDs {
+			if err := tx.Exec(
+				"INSERT INTO "+target.linkTable+" ("+target.column+", tag_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+				id, tagID,
+			).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *tagRepository) ListTargetTags(t TargetType, id uuid.UUID) ([]*Tag, error) {
+	target := targets[t]
+	var tags []*Tag
+	err := r.db.Where("id IN (?)", r.db.Table(target.linkTable).Select("tag_id").Where(target.column+" = ?", id)).
+		Order("LOWER(name)").
+		Find(&tags).Error
+	return tags, err
+}
+
+// translate maps the unique index on (user_id, LOWER(name)) to ErrTagExists.
+func (r *tagRepository) translate(err error) error {
+	if translator, ok := r.db.Dialector.(gorm.ErrorTranslator); ok && err != nil {
+		if errors.Is(translator.Translate(err), gorm.ErrDuplicatedKey) {
+			return ErrTagExists
+		}
+	}
+	return err
+}
@@ -0,0 +1,19 @@
+package tag
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+func Routes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.List)
+	r.Post("/", h.Create)
+	r.Get("/counts", h.Counts)
+	r.Put("/{tagID}", h.Update)
+	r.Delete("/{tagID}", h.Delete)
+	r.Post("/{tagID}/merge", h.Merge)
+	r.Put("/{target}/{id}", h.SetTags)
+
+	return r
+}
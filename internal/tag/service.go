@@ -0,0 +1,305 @@
+package tag
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+)
+
+var (
+	ErrUnauthorized    = errors.New("unauthorized")
+	ErrInvalidID       = errors.New("invalid id format")
+	ErrTagNotFound     = ErrNotFound
+	ErrTagExists       = errors.New("a tag with this name already exists")
+	ErrInvalidTagName  = errors.New("invalid tag name")
+	ErrInvalidTagColor = errors.New("invalid tag color")
+	ErrInvalidTarget   = errors.New("invalid tag target")
+	ErrTargetNotFound  = errors.New("tagged item not found")
+	ErrMergeIntoItself = errors.New("cannot merge a tag into itself")
+)
+
+type Service interface {
+	ListTags(ctx context.Context) ([]*Tag, error)
+	CreateTag(ctx context.Context, dto *TagDTO) (*Tag, error)
+	UpdateTag(ctx context.Context, id string, dto *TagDTO) (*Tag, error)
+	DeleteTag(ctx context.Context, id string) error
+	// MergeTags moves every task, project and topic tagged with id to the
+	// tag into, then deletes id.
+	MergeTags(ctx context.Context, id string, into uuid.UUID) (*Tag, error)
+	// TagCounts returns the user's tags, most used first.
+	TagCounts(ctx context.Context) ([]*TagCount, error)
+	// SetTags replaces the tags of a task, project or study topic and
+	// returns the new set.
+	SetTags(ctx context.Context, target TargetType, id string, tagIDs []uuid.UUID) ([]*Tag, error)
+}
+
+type service struct {
+	repo TagRepository
+}
+
+func NewService(repo TagRepository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) ListTags(ctx context.Context) ([]*Tag, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := s.repo.ListByUser(userID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list tags")
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (s *service) CreateTag(ctx context.Context, dto *TagDTO) (*Tag, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := normalizeName(dto.Name)
+	if err != nil {
+		return nil, err
+	}
+	color := DefaultColor
+	if dto.Color != "" {
+		if color, err = normalizeColor(dto.Color); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	t := &Tag{ID: uuid.New(), UserID: userID, Name: name, Color: color, CreatedAt: now, UpdatedAt: now}
+	if err := s.repo.Create(t); err != nil {
+		if !errors.Is(err, ErrTagExists) {
+			config.WithContext(ctx).WithError(err).Error("Failed to create tag")
+		}
+		return nil, err
+	}
+
+	config.WithContext(ctx).WithField("tag_id", t.ID).Info("Tag created successfully")
+	return t, nil
+}
+
+func (s *service) UpdateTag(ctx context.Context, id string, dto *TagDTO) (*Tag, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := s.findTag(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Name, err = normalizeName(dto.Name); err != nil {
+		return nil, err
+	}
+	if dto.Color != "" {
+		if t.Color, err = normalizeColor(dto.Color); err != nil {
+			return nil, err
+		}
+	}
+	t.UpdatedAt = time.Now()
+
+	if err := s.repo.Update(t); err != nil {
+		if !errors.Is(err, ErrTagExists) {
+			config.WithContext(ctx).WithError(err).Error("Failed to update tag")
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *service) DeleteTag(ctx context.Context, id string) error {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tagID, err := s.parseUUID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(tagID, userID); err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			config.WithContext(ctx).WithError(err).Error("Failed to delete tag")
+		}
+		return err
+	}
+
+	config.WithContext(ctx).WithField("tag_id", tagID).Info("Tag deleted successfully")
+	return nil
+}
+
+func (s *service) MergeTags(ctx context.Context, id string, into uuid.UUID) (*Tag, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := s.findTag(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if source.ID == into {
+		return nil, ErrMergeIntoItself
+	}
+	target, err := s.findTag(ctx, into.String(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Merge(source.ID, target.ID); err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to merge tags")
+		return nil, err
+	}
+
+	config.WithContext(ctx).WithField("tag_id", source.ID).WithField("into_id", target.ID).Info("Tags merged successfully")
+	return target, nil
+}
+
+func (s *service) TagCounts(ctx context.Context) ([]*TagCount, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.repo.Counts(userID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to count tags")
+		return nil, err
+	}
+
+	for _, c := range counts {
+		c.Total = c.Tasks + c.Projects + c.StudyTopics
+	}
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].Total != counts[j].Total {
+			return counts[i].Total > counts[j].Total
+		}
+		return strings.ToLower(counts[i].Name) < strings.ToLower(counts[j].Name)
+	})
+	return counts, nil
+}
+
+func (s *service) SetTags(ctx context.Context, target TargetType, id string, tagIDs []uuid.UUID) ([]*Tag, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !target.IsValid() {
+		return nil, ErrInvalidTarget
+	}
+
+	targetID, err := s.parseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	owned, err := s.repo.OwnsTarget(target, targetID, userID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to look up tagged item")
+		return nil, err
+	}
+	if !owned {
+		return nil, ErrTargetNotFound
+	}
+
+	tagIDs = uniqueIDs(tagIDs)
+	count, err := s.repo.CountOwned(userID, tagIDs)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to look up tags")
+		return nil, err
+	}
+	if count != int64(len(tagIDs)) {
+		return nil, ErrTagNotFound
+	}
+
+	if err := s.repo.SetTargetTags(target, targetID, tagIDs); err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to set tags")
+		return nil, err
+	}
+
+	tags, err := s.repo.ListTargetTags(target, targetID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list tags")
+		return nil, err
+	}
+	return tags, nil
+}
+
+// ============= Helper Methods =============
+
+func (s *service) getUserID(ctx context.Context) (uuid.UUID, error) {
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Warn("Unauthorized access attempt")
+		return uuid.Nil, ErrUnauthorized
+	}
+	return uuid.MustParse(claims.UserID), nil
+}
+
+func (s *service) parseUUID(ctx context.Context, id string) (uuid.UUID, error) {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Warnf("Invalid ID: %s", id)
+		return uuid.Nil, ErrInvalidID
+	}
+	return parsedID, nil
+}
+
+func (s *service) findTag(ctx context.Context, id string, userID uuid.UUID) (*Tag, error) {
+	tagID, err := s.parseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := s.repo.FindByIDAndUser(tagID, userID)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			config.WithContext(ctx).WithError(err).Error("Error finding tag")
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+func normalizeName(name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" || len([]rune(name)) > maxTagNameLength {
+		return "", ErrInvalidTagName
+	}
+	return name, nil
+}
+
+func normalizeColor(color string) (string, error) {
+	if !colorPattern.MatchString(color) {
+		return "", ErrInvalidTagColor
+	}
+	return strings.ToUpper(color), nil
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,200 @@
+package tag_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/tag"
+)
+
+func TestMain(m *testing.M) {
+	config.Init()
+	os.Exit(m.Run())
+}
+
+// fakeTagRepo keeps tags and their links to tasks in memory.
+type fakeTagRepo struct {
+	tag.TagRepository
+	tags   map[uuid.UUID]*tag.Tag
+	links  map[uuid.UUID][]uuid.UUID
+	tasks  map[uuid.UUID]uuid.UUID
+	counts []*tag.TagCount
+}
+
+func (f *fakeTagRepo) Create(t *tag.Tag) error {
+	for _, other := range f.tags {
+		if other.UserID == t.UserID && strings.EqualFold(other.Name, t.Name) {
+			return tag.ErrTagExists
+		}
+	}
+	f.tags[t.ID] = t
+	return nil
+}
+
+func (f *fakeTagRepo) FindByIDAndUser(id, userID uuid.UUID) (*tag.Tag, error) {
+	if t, ok := f.tags[id]; ok && t.UserID == userID {
+		return t, nil
+	}
+	return nil, tag.ErrNotFound
+}
+
+func (f *fakeTagRepo) CountOwned(userID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	var count int64
+	for _, id := range ids {
+		if t, ok := f.tags[id]; ok && t.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeTagRepo) OwnsTarget(target tag.TargetType, id, userID uuid.UUID) (bool, error) {
+	return f.tasks[id] == userID, nil
+}
+
+func (f *fakeTagRepo) SetTargetTags(target tag.TargetType, id uuid.UUID, tagIDs []uuid.UUID) error {
+	f.links[id] = tagIDs
+	return nil
+}
+
+func (f *fakeTagRepo) ListTargetTags(target tag.TargetType, id uuid.UUID) ([]*tag.Tag, error) {
+	var tags []*tag.Tag
+	for _, tagID := range f.links[id] {
+		tags = append(tags, f.tags[tagID])
+	}
+	return tags, nil
+}
+
+func (f *fakeTagRepo) Merge(sourceID, intoID uuid.UUID) error {
+	for id, tagIDs := range f.links {
+		for i, tagID := range tagIDs {
+			if tagID == sourceID {
+				f.links[id][i] = intoID
+			}
+		}
+	}
+	delete(f.tags, sourceID)
+	return nil
+}
+
+func (f *fakeTagRepo) Counts(userID uuid.UUID) ([]*tag.TagCount, error) {
+	return f.counts, nil
+}
+
+func TestTags(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.UserDataKeyID, userID.String())
+	ctx = context.WithValue(ctx, auth.UserDataKeyRole, "user")
+
+	taskID := uuid.New()
+	repo := &fakeTagRepo{
+		tags:  map[uuid.UUID]*tag.Tag{},
+		links: map[uuid.UUID][]uuid.UUID{},
+		tasks: map[uuid.UUID]uuid.UUID{taskID: userID},
+	}
+	svc := tag.NewService(repo)
+
+	var work *tag.Tag
+	t.Run("CreateNormalizesNameAndColor", func(t *testing.T) {
+		var err error
+		work, err = svc.CreateTag(ctx, &tag.TagDTO{Name: "  trabalho   urgente ", Color: "#ff8800"})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if work.Name != "trabalho urgente" || work.Color != "#FF8800" {
+			t.Errorf("Etiqueta não normalizada: %q %q", work.Name, work.Color)
+		}
+
+		plain, err := svc.CreateTag(ctx, &tag.TagDTO{Name: "Leitura"})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if plain.Color != tag.DefaultColor {
+			t.Errorf("Cor padrão esperada, recebida %q", plain.Color)
+		}
+	})
+
+	t.Run("RejectsInvalidTags", func(t *testing.T) {
+		cases := map[string]struct {
+			dto *tag.TagDTO
+			err error
+		}{
+			"NomeVazio":    {&tag.TagDTO{Name: "   "}, tag.ErrInvalidTagName},
+			"NomeLongo":    {&tag.TagDTO{Name: strings.Repeat("a", 51)}, tag.ErrInvalidTagName},
+			"CorInvalida":  {&tag.TagDTO{Name: "Cor", Color: "vermelho"}, tag.ErrInvalidTagColor},
+			"NomeRepetido": {&tag.TagDTO{Name: "LEITURA"}, tag.ErrTagExists},
+		}
+		for name, tc := range cases {
+			if _, err := svc.CreateTag(ctx, tc.dto); !errors.Is(err, tc.err) {
+				t.Errorf("%s: esperado %v, recebido %v", name, tc.err, err)
+			}
+		}
+	})
+
+	t.Run("SetTagsChecksOwnership", func(t *testing.T) {
+		foreign := &tag.Tag{ID: uuid.New(), UserID: uuid.New(), Name: "Alheia"}
+		repo.tags[foreign.ID] = foreign
+
+		if _, err := svc.SetTags(ctx, tag.TaskTarget, taskID.String(), []uuid.UUID{foreign.ID}); !errors.Is(err, tag.ErrTagNotFound) {
+			t.Errorf("Etiqueta de outro usuário deveria falhar, erro: %v", err)
+		}
+		if _, err := svc.SetTags(ctx, tag.TaskTarget, uuid.NewString(), nil); !errors.Is(err, tag.ErrTargetNotFound) {
+			t.Errorf("Tarefa inexistente deveria falhar, erro: %v", err)
+		}
+		if _, err := svc.SetTags(ctx, "folders", taskID.String(), nil); !errors.Is(err, tag.ErrInvalidTarget) {
+			t.Errorf("Tipo desconhecido deveria falhar, erro: %v", err)
+		}
+
+		tags, err := svc.SetTags(ctx, tag.TaskTarget, taskID.String(), []uuid.UUID{work.ID, work.ID})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if len(tags) != 1 || tags[0].ID != work.ID {
+			t.Errorf("Esperada uma etiqueta sem duplicatas, recebido %v", tags)
+		}
+	})
+
+	t.Run("MergeMovesLinks", func(t *testing.T) {
+		into, err := svc.CreateTag(ctx, &tag.TagDTO{Name: "Trabalho"})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if _, err := svc.MergeTags(ctx, into.ID.String(), into.ID); !errors.Is(err, tag.ErrMergeIntoItself) {
+			t.Errorf("Mesclar com ela mesma deveria falhar, erro: %v", err)
+		}
+
+		if _, err := svc.MergeTags(ctx, work.ID.String(), into.ID); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if _, ok := repo.tags[work.ID]; ok {
+			t.Error("Etiqueta de origem deveria ser removida")
+		}
+		if links := repo.links[taskID]; len(links) != 1 || links[0] != into.ID {
+			t.Errorf("Tarefa deveria ficar com a etiqueta de destino: %v", links)
+		}
+	})
+
+	t.Run("CountsAreSortedByUse", func(t *testing.T) {
+		repo.counts = []*tag.TagCount{
+			{Name: "Leitura", Tasks: 1},
+			{Name: "trabalho", Tasks: 2, Projects: 1},
+			{Name: "Academia", Tasks: 1},
+		}
+		counts, err := svc.TagCounts(ctx)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if counts[0].Name != "trabalho" || counts[0].Total != 3 {
+			t.Errorf("Etiqueta mais usada deveria vir primeiro: %+v", counts[0])
+		}
+		if counts[1].Name != "Academia" || counts[2].Name != "Leitura" {
+			t.Errorf("Empates deveriam seguir ordem alfabética: %s, %s", counts[1].Name, counts[2].Name)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package task
+
+import (
+	"github.com/saulo-duarte/chronos-lambda/internal/activity"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+)
+
+// activityFields are the values of a task shown in its activity timeline.
+type activityFields struct {
+	status    string
+	priority  string
+	startDate string
+	dueDate   string
+}
+
+func snapshotActivity(t *Task) activityFields {
+	return activityFields{
+		status:    string(t.Status),
+		priority:  string(t.Priority),
+		startDate: formatActivityDate(t.StartDate),
+		dueDate:   formatActivityDate(t.DueDate),
+	}
+}
+
+// activityChanges lists the tracked fields that differ between before and
+// the task's current values.
+func activityChanges(before activityFields, t *Task) activity.Changes {
+	after := snapshotActivity(t)
+
+	var changes activity.Changes
+	changes.Add("status", before.status, after.status)
+	changes.Add("priority", before.priority, after.priority)
+	changes.Add("startDate", before.startDate, after.startDate)
+	changes.Add("dueDate", before.dueDate, after.dueDate)
+	return changes
+}
+
+func newTaskActivity(t *Task, action activity.Action, changes activity.Changes) *activity.Entry {
+	return activity.NewEntry(t.UserID, activity.TaskEntity, t.ID, action, changes)
+}
+
+// formatActivityDate writes the wall clock the way the task's JSON does, or
+// an empty string when the date is unset.
+func formatActivityDate(ldt *util.LocalDateTime) string {
+	if !hasDate(ldt) {
+		return ""
+	}
+	return ldt.Format("2006-01-02T15:04:05")
+}
@@ -0,0 +1,75 @@
+package task_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/activity"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+)
+
+func TestTaskActivity(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.UserDataKeyID, userID.String())
+	ctx = context.WithValue(ctx, auth.UserDataKeyRole, "user")
+
+	repo := &fakeTreeRepo{tasks: map[uuid.UUID]*task.Task{}, items: map[uuid.UUID][]*task.ChecklistItem{}}
+	svc := task.NewService(repo, nil, &fakeLocationUserRepo{}, nil)
+
+	t.Run("UpdateRecordsChangedFields", func(t *testing.T) {
+		repo.activity = nil
+		existing := repo.add(&task.Task{ID: uuid.New(), UserID: userID, Name: "Relatório", Status: task.TODO, Priority: task.LOW})
+		due := util.LocalDateTime{Time: time.Date(2026, 5, 10, 18, 0, 0, 0, time.UTC)}
+
+		_, err := svc.UpdateTask(ctx, &task.TaskUpdateDTO{ID: existing.ID, Status: task.IN_PROGRESS, Priority: task.LOW, DueDate: due})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+
+		if len(repo.activity) != 1 || repo.activity[0].Action != activity.Updated {
+			t.Fatalf("Esperada uma entrada de atualização, recebido: %+v", repo.activity)
+		}
+		changes := map[string]activity.Change{}
+		for _, c := range repo.activity[0].Changes {
+			changes[c.Field] = c
+		}
+		if len(changes) != 2 {
+			t.Errorf("Só status e prazo mudaram, recebido: %+v", repo.activity[0].Changes)
+		}
+		if c := changes["status"]; c.From != string(task.TODO) || c.To != string(task.IN_PROGRESS) {
+			t.Errorf("Mudança de status incorreta: %+v", c)
+		}
+		if c := changes["dueDate"]; c.From != "" || c.To == "" {
+			t.Errorf("Prazo definido deveria sair de vazio: %+v", c)
+		}
+	})
+
+	t.Run("UnchangedTrackedFieldsRecordNothing", func(t *testing.T) {
+		repo.activity = nil
+		existing := repo.add(&task.Task{ID: uuid.New(), UserID: userID, Name: "Leitura", Status: task.TODO})
+
+		if _, err := svc.UpdateTask(ctx, &task.TaskUpdateDTO{ID: existing.ID, Name: "Leitura do capítulo 2"}); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if len(repo.activity) != 0 {
+			t.Errorf("Renomear não deveria gerar atividade, recebido: %+v", repo.activity)
+		}
+	})
+
+	t.Run("AutoCompletedParentIsRecorded", func(t *testing.T) {
+		repo.activity = nil
+		parent := repo.add(&task.Task{ID: uuid.New(), UserID: userID, Name: "Fase", Status: task.IN_PROGRESS, AutoComplete: true})
+		child := repo.add(&task.Task{ID: uuid.New(), UserID: userID, Name: "Passo", Status: task.IN_PROGRESS, ParentID: &parent.ID})
+
+		if _, err := svc.UpdateTask(ctx, &task.TaskUpdateDTO{ID: child.ID, Status: task.DONE}); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if len(repo.activity) != 2 || repo.activity[1].EntityID != parent.ID {
+			t.Fatalf("Conclusão automática do pai deveria gerar atividade, recebido: %+v", repo.activity)
+		}
+	})
+}
@@ -0,0 +1,265 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	googlecalendar "github.com/saulo-duarte/chronos-lambda/internal/google_calendar"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+)
+
+// SyncDecision is what the pull job does with one changed event.
+type SyncDecision int
+
+const (
+	// SyncSkip ignores the change: unknown event, recurring instance or an
+	// echo of our own push.
+	SyncSkip SyncDecision = iota
+	// SyncApplyRemote copies the Calendar change into the task.
+	SyncApplyRemote
+	// SyncKeepLocal re-pushes the task because it was edited more recently
+	// than the event.
+	SyncKeepLocal
+)
+
+// PullResult summarises one pull for a user.
+type PullResult struct {
+	Updated   int  `json:"updated"`
+	Deleted   int  `json:"deleted"`
+	Conflicts int  `json:"conflicts"`
+	Skipped   int  `json:"skipped"`
+	FullSync  bool `json:"fullSync"`
+}
+
+type CalendarSyncService interface {
+	PullChanges(ctx context.Context, userID uuid.UUID) (*PullResult, error)
+	PullAllUsers(ctx context.Context) error
+}
+
+type calendarSyncService struct {
+	repo            TaskRepository
+	calendarService googlecalendar.CalendarService
+	syncRepo        googlecalendar.SyncStateRepository
+}
+
+func NewCalendarSyncService(
+	repo TaskRepository,
+	calendarService googlecalendar.CalendarService,
+	syncRepo googlecalendar.SyncStateRepository,
+) CalendarSyncService {
+	return &calendarSyncService{
+		repo:            repo,
+		calendarService: calendarService,
+		syncRepo:        syncRepo,
+	}
+}
+
+// ResolveCalendarChange applies the conflict rules:
+//   - a change older than the task's last sync is our own push echoing back;
+//   - a remote change with no local edit since the last sync wins;
+//   - when both sides changed, the most recent edit wins and ties go to the
+//     task.
+func ResolveCalendarChange(t *Task, change googlecalendar.EventChange) SyncDecision {
+	if change.RecurringEventID != "" || t.Recurrence != nil {
+		return SyncSkip
+	}
+
+	if t.CalendarSyncedAt == nil {
+		return SyncApplyRemote
+	}
+
+	remoteChanged := change.Updated.After(*t.CalendarSyncedAt)
+	localChanged := t.UpdatedAt.After(*t.CalendarSyncedAt)
+
+	switch {
+	case !remoteChanged:
+		return SyncSkip
+	case !localChanged:
+		return SyncApplyRemote
+	case change.Updated.After(t.UpdatedAt):
+		return SyncApplyRemote
+	default:
+		return SyncKeepLocal
+	}
+}
+
+func (s *calendarSyncService) PullChanges(ctx context.Context, userID uuid.UUID) (*PullResult, error) {
+	log := config.WithContext(ctx).WithField("user_id", userID)
+
+	calendarIDs, err := s.calendarService.SyncedCalendarIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PullResult{}
+	for _, calendarID := range calendarIDs {
+		if err := s.pullCalendar(ctx, userID, calendarID, result); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Infof("Calendar pull finished: %d updated, %d deleted, %d conflicts, %d skipped",
+		result.Updated, result.Deleted, result.Conflicts, result.Skipped)
+	return result, nil
+}
+
+func (s *calendarSyncService) pullCalendar(ctx context.Context, userID uuid.UUID, calendarID string, result *PullResult) error {
+	log := config.WithContext(ctx).WithFields(map[string]interface{}{"user_id": userID, "calendar_id": calendarID})
+
+	state, err := s.syncRepo.Get(userID, calendarID)
+	if err != nil {
+		log.WithError(err).Error("Failed to load calendar sync state")
+		return err
+	}
+
+	changes, err := s.calendarService.ListEventChanges(ctx, userID, calendarID, state.SyncToken)
+	if errors.Is(err, googlecalendar.ErrSyncTokenExpired) {
+		log.Warn("Calendar sync token expired, running full sync")
+		changes, err = s.calendarService.ListEventChanges(ctx, userID, calendarID, "")
+	}
+	if err != nil {
+		return err
+	}
+
+	result.FullSync = result.FullSync || changes.FullSync
+	for _, change := range changes.Events {
+		if err := s.applyChange(ctx, userID, calendarID, change, result); err != nil {
+			log.WithError(err).Errorf("Failed to apply calendar change for event %s", change.EventID)
+			return err
+		}
+	}
+
+	now := time.Now()
+	state.SyncToken = changes.NextSyncToken
+	state.LastSyncedAt = &now
+	state.UpdatedAt = now
+	if err := s.syncRepo.Save(state); err != nil {
+		log.WithError(err).Error("Failed to save calendar sync state")
+		return err
+	}
+	return nil
+}
+
+func (s *calendarSyncService) PullAllUsers(ctx context.Context) error {
+	log := config.WithContext(ctx)
+
+	userIDs, err := s.syncRepo.ListSyncableUserIDs()
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, userID := range userIDs {
+		if _, err := s.PullChanges(ctx, userID); err != nil {
+			failed++
+			log.WithError(err).Warnf("Calendar pull failed for user %s", userID)
+		}
+	}
+
+	log.Infof("Calendar pull finished for %d users (%d failed)", len(userIDs), failed)
+	return nil
+}
+
+func (s *calendarSyncService) applyChange(ctx context.Context, userID uuid.UUID, calendarID string, change googlecalendar.EventChange, result *PullResult) error {
+	t, err := s.repo.FindByCalendarEventID(userID, change.EventID)
+	if errors.Is(err, ErrNotFound) {
+		result.Skipped++
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Moving an event between calendars shows up as a cancellation in the
+	// calendar it left.
+	if t.CalendarID() != calendarID {
+		result.Skipped++
+		return nil
+	}
+
+	decision := ResolveCalendarChange(t, change)
+	bothChanged := t.CalendarSyncedAt != nil && t.UpdatedAt.After(*t.CalendarSyncedAt) && change.Updated.After(*t.CalendarSyncedAt)
+	if bothChanged && decision != SyncSkip {
+		result.Conflicts++
+	}
+
+	switch decision {
+	case SyncSkip:
+		result.Skipped++
+		return nil
+
+	case SyncKeepLocal:
+		config.WithContext(ctx).Infof("Task %s changed after event %s, keeping local version", t.ID, change.EventID)
+		return s.repo.Transaction(func(tx TaskRepository) error {
+			if change.Cancelled {
+				// The worker re-reads the task, so the dropped event ID must be saved.
+				if err := tx.MarkCalendarSynced(t.ID, "", "", *t.CalendarSyncedAt); err != nil {
+					return err
+				}
+			}
+			return tx.EnqueueCalendarSync(newCalendarOutboxEntry(t, CalendarSyncUpsert))
+		})
+	}
+
+	if change.Cancelled {
+		// The task's own event is already gone; only its subtasks' events
+		// need removing.
+		descendants, err := s.repo.ListDescendants(t.ID, userID)
+		if err != nil {
+			return err
+		}
+		if err := trashTask(s.repo, t, descendants); err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		result.Deleted++
+		return nil
+	}
+
+	if applyEventDates(t, change) {
+		t.UpdatedAt = time.Now()
+		if err := s.repo.Update(t); err != nil {
+			return err
+		}
+		result.Updated++
+	} else {
+		result.Skipped++
+	}
+
+	return s.repo.MarkCalendarSynced(t.ID, t.GoogleCalendarID, t.GoogleCalendarEventID, time.Now())
+}
+
+// applyEventDates mirrors EventTimes: StartDate maps to the event start and
+// DueDate to the event end. Only the dates the task already uses are
+// updated, so the hour EventTimes pads a single-date task with is not saved
+// back as a second date. AllDay follows the event. It reports whether
+// anything changed.
+func applyEventDates(t *Task, change googlecalendar.EventChange) bool {
+	end := change.End
+	if change.AllDay && end != nil {
+		// All-day events end on the following day (exclusive).
+		last := end.AddDate(0, 0, -1)
+		end = &last
+	}
+
+	changed := false
+	hasStart := t.StartDate != nil && !t.StartDate.IsZero()
+	hasDue := t.DueDate != nil && !t.DueDate.IsZero()
+
+	if hasStart && change.Start != nil && !t.StartDate.Time.Equal(*change.Start) {
+		t.StartDate = &util.LocalDateTime{Time: *change.Start}
+		changed = true
+	}
+	if hasDue && end != nil && !t.DueDate.Time.Equal(*end) {
+		t.DueDate = &util.LocalDateTime{Time: *end}
+		changed = true
+	}
+	if (hasStart || hasDue) && t.AllDay != change.AllDay {
+		t.AllDay = change.AllDay
+		changed = true
+	}
+
+	return changed
+}
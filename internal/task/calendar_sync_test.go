@@ -0,0 +1,179 @@
+package task_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	googlecalendar "github.com/saulo-duarte/chronos-lambda/internal/google_calendar"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	"github.com/saulo-duarte/chronos-lambda/internal/user"
+)
+
+func TestResolveCalendarChange(t *testing.T) {
+	synced := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	newTask := func(updatedAt time.Time) *task.Task {
+		s := synced
+		return &task.Task{UpdatedAt: updatedAt, CalendarSyncedAt: &s}
+	}
+
+	cases := []struct {
+		name     string
+		task     *task.Task
+		change   googlecalendar.EventChange
+		expected task.SyncDecision
+	}{
+		{
+			name:     "EchoOfOwnPushIsSkipped",
+			task:     newTask(synced.Add(-time.Minute)),
+			change:   googlecalendar.EventChange{Updated: synced.Add(-time.Second)},
+			expected: task.SyncSkip,
+		},
+		{
+			name:     "RemoteOnlyChangeIsApplied",
+			task:     newTask(synced.Add(-time.Minute)),
+			change:   googlecalendar.EventChange{Updated: synced.Add(time.Hour)},
+			expected: task.SyncApplyRemote,
+		},
+		{
+			name:     "NewerRemoteWinsConflict",
+			task:     newTask(synced.Add(time.Minute)),
+			change:   googlecalendar.EventChange{Updated: synced.Add(time.Hour)},
+			expected: task.SyncApplyRemote,
+		},
+		{
+			name:     "NewerLocalWinsConflict",
+			task:     newTask(synced.Add(time.Hour)),
+			change:   googlecalendar.EventChange{Updated: synced.Add(time.Minute)},
+			expected: task.SyncKeepLocal,
+		},
+		{
+			name:     "TieGoesToLocal",
+			task:     newTask(synced.Add(time.Hour)),
+			change:   googlecalendar.EventChange{Updated: synced.Add(time.Hour)},
+			expected: task.SyncKeepLocal,
+		},
+		{
+			name:     "RecurringInstanceIsSkipped",
+			task:     newTask(synced.Add(-time.Minute)),
+			change:   googlecalendar.EventChange{RecurringEventID: "abc", Updated: synced.Add(time.Hour)},
+			expected: task.SyncSkip,
+		},
+		{
+			name:     "NeverSyncedAppliesRemote",
+			task:     &task.Task{UpdatedAt: synced},
+			change:   googlecalendar.EventChange{Updated: synced.Add(-time.Hour)},
+			expected: task.SyncApplyRemote,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := task.ResolveCalendarChange(tc.task, tc.change)
+			if got != tc.expected {
+				t.Errorf("Decisão incorreta. Esperado: %d, Recebido: %d", tc.expected, got)
+			}
+		})
+	}
+}
+
+type fakeConnectedUserRepo struct {
+	user.UserRepository
+}
+
+func (f *fakeConnectedUserRepo) GetByID(id string) (*user.User, error) {
+	return &user.User{ID: uuid.MustParse(id), EncryptedGoogleAccessToken: "token"}, nil
+}
+
+func TestPriorityChangeQueuesCalendarSync(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.UserDataKeyID, userID.String())
+	ctx = context.WithValue(ctx, auth.UserDataKeyRole, "user")
+
+	repo := &fakeTreeRepo{tasks: map[uuid.UUID]*task.Task{}}
+	svc := task.NewService(repo, nil, &fakeConnectedUserRepo{}, nil)
+	existing := repo.add(&task.Task{ID: uuid.New(), UserID: userID, Name: "Prova", Status: task.TODO, Priority: task.LOW})
+
+	if _, err := svc.UpdateTask(ctx, &task.TaskUpdateDTO{ID: existing.ID, Priority: task.HIGH}); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if len(repo.queued) != 1 || repo.queued[0].Operation != task.CalendarSyncUpsert {
+		t.Errorf("Mudar a prioridade deveria atualizar a cor do evento, fila: %+v", repo.queued)
+	}
+}
+
+func (f *fakeTreeRepo) FindByCalendarEventID(userId uuid.UUID, eventID string) (*task.Task, error) {
+	for _, t := range f.tasks {
+		if t.UserID == userId && t.GoogleCalendarEventID == eventID {
+			return t, nil
+		}
+	}
+	return nil, task.ErrNotFound
+}
+
+func (f *fakeTreeRepo) Delete(id, userId uuid.UUID) error {
+	t, ok := f.tasks[id]
+	if !ok || t.UserID != userId {
+		return task.ErrNotFound
+	}
+	descendants, _ := f.ListDescendants(id, userId)
+	if f.trashed == nil {
+		f.trashed = map[uuid.UUID]*task.Task{}
+	}
+	for _, d := range append([]*task.Task{t}, descendants...) {
+		delete(f.tasks, d.ID)
+		f.trashed[d.ID] = d
+	}
+	return nil
+}
+
+type fakePullCalendar struct {
+	googlecalendar.CalendarService
+	changes *googlecalendar.EventChanges
+}
+
+func (f *fakePullCalendar) SyncedCalendarIDs(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	return []string{googlecalendar.PrimaryCalendarID}, nil
+}
+
+func (f *fakePullCalendar) ListEventChanges(ctx context.Context, userID uuid.UUID, calendarID, syncToken string) (*googlecalendar.EventChanges, error) {
+	return f.changes, nil
+}
+
+type fakeSyncStateRepo struct {
+	googlecalendar.SyncStateRepository
+}
+
+func (f *fakeSyncStateRepo) Get(userID uuid.UUID, calendarID string) (*googlecalendar.SyncState, error) {
+	return &googlecalendar.SyncState{UserID: userID, CalendarID: calendarID}, nil
+}
+
+func (f *fakeSyncStateRepo) Save(state *googlecalendar.SyncState) error {
+	return nil
+}
+
+func TestPullCancelledEventRemovesSubtaskEvents(t *testing.T) {
+	userID := uuid.New()
+	repo := &fakeTreeRepo{tasks: map[uuid.UUID]*task.Task{}}
+	parent := repo.add(&task.Task{ID: uuid.New(), UserID: userID, Name: "Projeto", GoogleCalendarEventID: "parent-event"})
+	child := repo.add(&task.Task{ID: uuid.New(), UserID: userID, Name: "Etapa", ParentID: &parent.ID, GoogleCalendarEventID: "child-event"})
+
+	calendar := &fakePullCalendar{changes: &googlecalendar.EventChanges{
+		Events: []googlecalendar.EventChange{{EventID: "parent-event", Cancelled: true, Updated: time.Now()}},
+	}}
+	svc := task.NewCalendarSyncService(repo, calendar, &fakeSyncStateRepo{})
+
+	result, err := svc.PullChanges(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if result.Deleted != 1 || repo.trashed[child.ID] == nil {
+		t.Fatalf("Subtarefa deveria ir para a lixeira com a tarefa, resultado: %+v", result)
+	}
+	if len(repo.queued) != 1 || repo.queued[0].Operation != task.CalendarSyncDelete || repo.queued[0].EventID != "child-event" {
+		t.Errorf("Apenas o evento da subtarefa deveria ser removido, fila: %+v", repo.queued)
+	}
+}
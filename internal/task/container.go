@@ -9,7 +9,11 @@ import (
 )
 
 type TaskContainer struct {
-	Handler *Handler
+	Handler        *Handler
+	Service        TaskService
+	Repo           TaskRepository
+	CalendarSync   CalendarSyncService
+	CalendarOutbox CalendarOutboxWorker
 }
 
 func NewTaskContainer(
@@ -17,13 +21,22 @@ func NewTaskContainer(
 	projectService project.ProjectService,
 	studyTopicRepo studytopic.StudyTopicRepository,
 	userRepository user.UserRepository,
+	calendarService googlecalendar.CalendarService,
 	calendarManager googlecalendar.CalendarManager,
+	syncStateRepo googlecalendar.SyncStateRepository,
 ) *TaskContainer {
 	repo := NewRepository(db)
-	service := NewService(repo, projectService, userRepository, studyTopicRepo, calendarManager)
-	handler := NewHandler(service)
+	service := NewService(repo, projectService, userRepository, studyTopicRepo)
+	recurrenceService := NewRecurrenceService(repo, userRepository)
+	calendarSync := NewCalendarSyncService(repo, calendarService, syncStateRepo)
+	calendarOutbox := NewCalendarOutboxWorker(repo, calendarManager)
+	handler := NewHandler(service, recurrenceService, calendarSync)
 
 	return &TaskContainer{
-		Handler: handler,
+		Handler:        handler,
+		Service:        service,
+		Repo:           repo,
+		CalendarSync:   calendarSync,
+		CalendarOutbox: calendarOutbox,
 	}
 }
@@ -0,0 +1,268 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+)
+
+var (
+	ErrDependencyCycle    = errors.New("dependency would create a cycle")
+	ErrDependencyExists   = errors.New("dependency already exists")
+	ErrDependencyNotFound = errors.New("dependency not found")
+	ErrTaskBlocked        = errors.New("task is blocked by unfinished tasks")
+)
+
+func (s *taskService) ListDependencies(ctx context.Context, taskID string) (*TaskDependencies, error) {
+	t, err := s.findOwnedTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	blockedBy, err := s.repo.ListBlockers(t.ID, t.UserID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list blocking tasks")
+		return nil, err
+	}
+	blocks, err := s.repo.ListBlockedTasks(t.ID, t.UserID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list blocked tasks")
+		return nil, err
+	}
+
+	loc := userLocation(ctx, s.userRepo, t.UserID)
+	localizeTasks(blockedBy, loc)
+	localizeTasks(blocks, loc)
+	s.annotateTasks(ctx, blockedBy)
+	s.annotateTasks(ctx, blocks)
+	return &TaskDependencies{BlockedBy: blockedBy, Blocks: blocks}, nil
+}
+
+// AddDependency records that blockerID has to be done before taskID.
+func (s *taskService) AddDependency(ctx context.Context, taskID string, blockerID uuid.UUID) (*TaskDependency, error) {
+	t, err := s.findOwnedTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.getTaskByID(ctx, blockerID, t.UserID); err != nil {
+		return nil, err
+	}
+
+	dep := &TaskDependency{BlockerID: blockerID, BlockedID: t.ID, UserID: t.UserID, CreatedAt: time.Now()}
+	err = s.repo.Transaction(func(tx TaskRepository) error {
+		// Two requests checking for cycles at once could each add half of one.
+		if err := tx.LockDependencies(t.UserID); err != nil {
+			return err
+		}
+		edges, err := tx.ListDependencies(t.UserID)
+		if err != nil {
+			return err
+		}
+		for _, e := range edges {
+			if e.BlockerID == blockerID && e.BlockedID == t.ID {
+				return ErrDependencyExists
+			}
+		}
+		if createsCycle(edges, blockerID, t.ID) {
+			return ErrDependencyCycle
+		}
+		return tx.CreateDependency(dep)
+	})
+	if errors.Is(err, ErrDependencyExists) || errors.Is(err, ErrDependencyCycle) {
+		return nil, err
+	}
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to create dependency")
+		return nil, err
+	}
+
+	config.WithContext(ctx).WithField("task_id", t.ID).Info("Task dependency added")
+	return dep, nil
+}
+
+func (s *taskService) RemoveDependency(ctx context.Context, taskID, blockerID string) error {
+	t, err := s.findOwnedTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	bid, err := s.parseUUID(ctx, blockerID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteDependency(bid, t.ID, t.UserID); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return ErrDependencyNotFound
+		}
+		config.WithContext(ctx).WithError(err).Error("Failed to delete dependency")
+		return err
+	}
+	return nil
+}
+
+// GetProjectGraph returns the project's tasks in an order where every task
+// comes after the tasks blocking it, and the links between them.
+func (s *taskService) GetProjectGraph(ctx context.Context, projectID string) (*DependencyGraph, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pid, err := s.parseUUID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateProjectExists(ctx, pid); err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.repo.ListByProjectAndUser(pid, userID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list tasks by project")
+		return nil, err
+	}
+	edges, err := s.repo.ListDependencies(userID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list dependencies")
+		return nil, err
+	}
+
+	inProject := make(map[uuid.UUID]bool, len(tasks))
+	for _, t := range tasks {
+		inProject[t.ID] = true
+	}
+	projectEdges := make([]*TaskDependency, 0)
+	for _, e := range edges {
+		if inProject[e.BlockerID] && inProject[e.BlockedID] {
+			projectEdges = append(projectEdges, e)
+		}
+	}
+
+	ordered, err := topologicalOrder(tasks, projectEdges)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).WithField("project_id", pid).Error("Dependency cycle in project")
+		return nil, err
+	}
+
+	localizeTasks(ordered, userLocation(ctx, s.userRepo, userID))
+	s.annotateTasks(ctx, ordered)
+	return &DependencyGraph{Tasks: ordered, Edges: projectEdges}, nil
+}
+
+// ============= Helper Methods =============
+
+// checkNotBlocked refuses to start or finish a task while any of its blockers
+// is still open.
+func (s *taskService) checkNotBlocked(ctx context.Context, t *Task) error {
+	blocked, err := isBlocked(s.repo, t.ID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to check blocking tasks")
+		return err
+	}
+	if blocked {
+		return ErrTaskBlocked
+	}
+	return nil
+}
+
+// isBlocked reports whether any blocker of the task is still open. It takes
+// the repository so status changes made inside a transaction, like automatic
+// completion, can apply the same rule.
+func isBlocked(repo TaskRepository, id uuid.UUID) (bool, error) {
+	open, err := repo.ListOpenBlockers([]uuid.UUID{id})
+	if err != nil {
+		return false, err
+	}
+	return len(open[id]) > 0, nil
+}
+
+// createsCycle reports whether adding "blocker blocks blocked" closes a loop,
+// that is whether blocker already depends, directly or not, on blocked.
+func createsCycle(edges []*TaskDependency, blocker, blocked uuid.UUID) bool {
+	if blocker == blocked {
+		return true
+	}
+
+	next := make(map[uuid.UUID][]uuid.UUID)
+	for _, e := range edges {
+		next[e.BlockerID] = append(next[e.BlockerID], e.BlockedID)
+	}
+
+	visited := map[uuid.UUID]bool{blocked: true}
+	stack := []uuid.UUID{blocked}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, n := range next[id] {
+			if n == blocker {
+				return true
+			}
+			if !visited[n] {
+				visited[n] = true
+				stack = append(stack, n)
+			}
+		}
+	}
+	return false
+}
+
+// topologicalOrder sorts tasks so blockers come first (Kahn's algorithm).
+// Among tasks that are ready at the same time the earliest due date wins, so
+// the order doubles as a suggested plan.
+func topologicalOrder(tasks []*Task, edges []*TaskDependency) ([]*Task, error) {
+	indegree := make(map[uuid.UUID]int, len(tasks))
+	next := make(map[uuid.UUID][]uuid.UUID)
+	byID := make(map[uuid.UUID]*Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+		indegree[t.ID] = 0
+	}
+	for _, e := range edges {
+		next[e.BlockerID] = append(next[e.BlockerID], e.BlockedID)
+		indegree[e.BlockedID]++
+	}
+
+	var ready []*Task
+	for _, t := range tasks {
+		if indegree[t.ID] == 0 {
+			ready = append(ready, t)
+		}
+	}
+
+	ordered := make([]*Task, 0, len(tasks))
+	for len(ready) > 0 {
+		sort.SliceStable(ready, func(i, j int) bool { return plansBefore(ready[i], ready[j]) })
+		t := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, t)
+
+		for _, id := range next[t.ID] {
+			indegree[id]--
+			if indegree[id] == 0 {
+				ready = append(ready, byID[id])
+			}
+		}
+	}
+
+	if len(ordered) != len(tasks) {
+		return nil, ErrDependencyCycle
+	}
+	return ordered, nil
+}
+
+func plansBefore(a, b *Task) bool {
+	aDue, bDue := hasDate(a.DueDate), hasDate(b.DueDate)
+	switch {
+	case aDue && bDue && !a.DueDate.Time.Equal(b.DueDate.Time):
+		return a.DueDate.Time.Before(b.DueDate.Time)
+	case aDue != bDue:
+		return aDue
+	}
+	return a.CreatedAt.Before(b.CreatedAt)
+}
@@ -0,0 +1,137 @@
+package task_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/project"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+)
+
+func (f *fakeTreeRepo) ListDependencies(userId uuid.UUID) ([]*task.TaskDependency, error) {
+	return f.deps, nil
+}
+
+func (f *fakeTreeRepo) LockDependencies(userId uuid.UUID) error {
+	return nil
+}
+
+func (f *fakeTreeRepo) CreateDependency(d *task.TaskDependency) error {
+	f.deps = append(f.deps, d)
+	return nil
+}
+
+func (f *fakeTreeRepo) ListByProjectAndUser(projectId, userId uuid.UUID) ([]*task.Task, error) {
+	var tasks []*task.Task
+	for _, t := range f.tasks {
+		if t.ProjectId != nil && *t.ProjectId == projectId {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks, nil
+}
+
+type fakeProjectService struct {
+	project.ProjectService
+}
+
+func (f *fakeProjectService) GetProjectByID(ctx context.Context, id string) (*project.Project, error) {
+	return &project.Project{ID: uuid.MustParse(id)}, nil
+}
+
+func TestDependencies(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.UserDataKeyID, userID.String())
+	ctx = context.WithValue(ctx, auth.UserDataKeyRole, "user")
+
+	repo := &fakeTreeRepo{tasks: map[uuid.UUID]*task.Task{}}
+	svc := task.NewService(repo, &fakeProjectService{}, &fakeLocationUserRepo{}, nil)
+
+	projectID := uuid.New()
+	now := time.Now()
+	newTask := func(name string, due *util.LocalDateTime, created time.Time) *task.Task {
+		return repo.add(&task.Task{ID: uuid.New(), UserID: userID, Name: name, Status: task.TODO, ProjectId: &projectID, DueDate: due, CreatedAt: created})
+	}
+	design := newTask("Design", nil, now)
+	build := newTask("Build", &util.LocalDateTime{Time: now.AddDate(0, 0, 10)}, now)
+	docs := newTask("Docs", &util.LocalDateTime{Time: now.AddDate(0, 0, 1)}, now)
+	release := newTask("Release", nil, now)
+
+	for _, link := range [][2]*task.Task{{design, build}, {build, release}, {docs, release}} {
+		if _, err := svc.AddDependency(ctx, link[1].ID.String(), link[0].ID); err != nil {
+			t.Fatalf("Erro inesperado ao ligar %s -> %s: %v", link[0].Name, link[1].Name, err)
+		}
+	}
+
+	t.Run("RejectsCycles", func(t *testing.T) {
+		if _, err := svc.AddDependency(ctx, design.ID.String(), release.ID); !errors.Is(err, task.ErrDependencyCycle) {
+			t.Errorf("Ciclo indireto deveria ser rejeitado, erro: %v", err)
+		}
+		if _, err := svc.AddDependency(ctx, design.ID.String(), design.ID); !errors.Is(err, task.ErrDependencyCycle) {
+			t.Errorf("Tarefa não pode bloquear a si mesma, erro: %v", err)
+		}
+		if _, err := svc.AddDependency(ctx, build.ID.String(), design.ID); !errors.Is(err, task.ErrDependencyExists) {
+			t.Errorf("Dependência duplicada deveria ser rejeitada, erro: %v", err)
+		}
+	})
+
+	t.Run("ReportsBlockedState", func(t *testing.T) {
+		found, err := svc.FindByID(ctx, release.ID.String())
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if !found.Blocked || len(found.BlockedBy) != 2 {
+			t.Errorf("Release deveria estar bloqueada por 2 tarefas: %v", found.BlockedBy)
+		}
+	})
+
+	t.Run("RefusesToStartBlockedTaskUnlessForced", func(t *testing.T) {
+		_, err := svc.UpdateTask(ctx, &task.TaskUpdateDTO{ID: build.ID, Status: task.IN_PROGRESS})
+		if !errors.Is(err, task.ErrTaskBlocked) {
+			t.Errorf("Tarefa bloqueada não deveria iniciar, erro: %v", err)
+		}
+		if _, err := svc.UpdateTask(ctx, &task.TaskUpdateDTO{ID: build.ID, Status: task.IN_PROGRESS, Force: true}); err != nil {
+			t.Errorf("Forçar deveria permitir iniciar, erro: %v", err)
+		}
+	})
+
+	t.Run("AutoCompleteSkipsBlockedParent", func(t *testing.T) {
+		parent := repo.add(&task.Task{ID: uuid.New(), UserID: userID, Name: "Publicação", Status: task.TODO, AutoComplete: true})
+		child := repo.add(&task.Task{ID: uuid.New(), UserID: userID, Name: "Revisar texto", Status: task.TODO, ParentID: &parent.ID})
+		if _, err := svc.AddDependency(ctx, parent.ID.String(), docs.ID); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+
+		if _, err := svc.UpdateTask(ctx, &task.TaskUpdateDTO{ID: child.ID, Status: task.DONE}); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if repo.tasks[parent.ID].Status != task.TODO {
+			t.Error("Tarefa pai bloqueada não deveria ser concluída automaticamente")
+		}
+	})
+
+	t.Run("OrdersProjectTopologically", func(t *testing.T) {
+		graph, err := svc.GetProjectGraph(ctx, projectID.String())
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		var names []string
+		for _, gt := range graph.Tasks {
+			names = append(names, gt.Name)
+		}
+		expected := []string{"Docs", "Design", "Build", "Release"}
+		for i := range expected {
+			if i >= len(names) || names[i] != expected[i] {
+				t.Fatalf("Ordem esperada %v, recebida %v", expected, names)
+			}
+		}
+		if len(graph.Edges) != 3 {
+			t.Errorf("Esperado 3 arestas, recebido %d", len(graph.Edges))
+		}
+	})
+}
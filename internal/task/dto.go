@@ -1,6 +1,8 @@
 package task
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
 )
@@ -27,13 +29,85 @@ type DashboardStatsResponse struct {
 }
 
 type TaskUpdateDTO struct {
-	ID            uuid.UUID          `json:"id"`
-	Name          string             `json:"name"`
-	Description   string             `json:"description"`
-	Status        TaskStatus         `json:"status"`
-	Priority      TaskPriority       `json:"priority"`
-	StartDate     util.LocalDateTime `json:"startDate"`
-	DueDate       util.LocalDateTime `json:"dueDate"`
-	RemoveDueDate bool               `json:"removeDueDate"`
-	DoneAt        util.LocalDateTime `json:"doneAt"`
+	ID               uuid.UUID          `json:"id"`
+	Name             string             `json:"name"`
+	Description      string             `json:"description"`
+	Status           TaskStatus         `json:"status"`
+	Priority         TaskPriority       `json:"priority"`
+	StartDate        util.LocalDateTime `json:"startDate"`
+	DueDate          util.LocalDateTime `json:"dueDate"`
+	RemoveDueDate    bool               `json:"removeDueDate"`
+	AllDay           *bool              `json:"allDay"`
+	DoneAt           util.LocalDateTime `json:"doneAt"`
+	Recurrence       *RecurrenceRule    `json:"recurrence"`
+	RemoveRecurrence bool               `json:"removeRecurrence"`
+	ParentID         *uuid.UUID         `json:"parentId"`
+	RemoveParent     bool               `json:"removeParent"`
+	AutoComplete     *bool              `json:"autoComplete"`
+	// EstimatedMinutes set to 0 clears the estimate.
+	EstimatedMinutes *int `json:"estimatedMinutes"`
+	// Force starts or finishes a task even though it is blocked.
+	Force bool `json:"force"`
+}
+
+type OccurrenceDTO struct {
+	TaskID      uuid.UUID           `json:"taskId"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Type        TaskType            `json:"type"`
+	Priority    TaskPriority        `json:"priority"`
+	Status      TaskStatus          `json:"status"`
+	StartDate   *util.LocalDateTime `json:"startDate"`
+	DueDate     *util.LocalDateTime `json:"dueDate"`
+	AllDay      bool                `json:"allDay"`
+	DoneAt      *util.LocalDateTime `json:"doneAt"`
+	Recurring   bool                `json:"recurring"`
+}
+
+type OccurrenceStatusDTO struct {
+	OccurrenceDate util.LocalDateTime `json:"occurrenceDate"`
+}
+
+// TaskPage is one page of a task query. NextCursor is empty on the last page.
+type TaskPage struct {
+	Items      []*Task `json:"items"`
+	NextCursor string  `json:"nextCursor,omitempty"`
+	Total      int64   `json:"total"`
+}
+
+type ChecklistItemDTO struct {
+	Title string `json:"title"`
+	Done  *bool  `json:"done"`
+}
+
+type ChecklistOrderDTO struct {
+	ItemIDs []uuid.UUID `json:"itemIds"`
+}
+
+type DependencyDTO struct {
+	BlockerID uuid.UUID `json:"blockerId"`
+}
+
+type TaskDependencies struct {
+	BlockedBy []*Task `json:"blockedBy"`
+	Blocks    []*Task `json:"blocks"`
+}
+
+// DependencyGraph lists a project's tasks in topological order, blockers
+// first, with the links between them.
+type DependencyGraph struct {
+	Tasks []*Task           `json:"tasks"`
+	Edges []*TaskDependency `json:"edges"`
+}
+
+// StatusHistoryResponse lists a task's status transitions, oldest first.
+// Cycle and lead times are only set once the task is done.
+type StatusHistoryResponse struct {
+	TaskID           uuid.UUID           `json:"taskId"`
+	Status           TaskStatus          `json:"status"`
+	Transitions      []*TaskStatusChange `json:"transitions"`
+	StartedAt        *time.Time          `json:"startedAt,omitempty"`
+	CompletedAt      *time.Time          `json:"completedAt,omitempty"`
+	CycleTimeSeconds *int64              `json:"cycleTimeSeconds,omitempty"`
+	LeadTimeSeconds  *int64              `json:"leadTimeSeconds,omitempty"`
 }
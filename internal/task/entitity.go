@@ -4,15 +4,20 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	googlecalendar "github.com/saulo-duarte/chronos-lambda/internal/google_calendar"
 	"github.com/saulo-duarte/chronos-lambda/internal/project"
 	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"github.com/saulo-duarte/chronos-lambda/internal/tag"
 	"github.com/saulo-duarte/chronos-lambda/internal/user"
 	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+	"gorm.io/gorm"
 )
 
 type Task struct {
 	ID                    uuid.UUID             `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
 	GoogleCalendarEventID string                `json:"googleCalendarEventId"`
+	GoogleCalendarID      string                `json:"googleCalendarId"`
+	ICalUID               string                `gorm:"column:ical_uid" json:"icalUid,omitempty"`
 	Name                  string                `json:"name"`
 	Description           string                `json:"description"`
 	Status                TaskStatus            `json:"status"`
@@ -20,13 +25,110 @@ type Task struct {
 	Priority              TaskPriority          `json:"priority"`
 	StartDate             *util.LocalDateTime   `json:"startDate"`
 	DueDate               *util.LocalDateTime   `json:"dueDate"`
+	AllDay                bool                  `json:"allDay"`
+	Recurrence            *RecurrenceRule       `gorm:"type:jsonb;serializer:json" json:"recurrence"`
 	ProjectId             *uuid.UUID            `json:"projectId"`
 	Project               project.Project       `gorm:"foreignKey:ProjectId" json:"project"`
 	StudyTopicId          *uuid.UUID            `json:"studyTopicId"`
 	StudyTopic            studytopic.StudyTopic `gorm:"foreignKey:StudyTopicId" json:"studyTopic"`
+	ParentID              *uuid.UUID            `gorm:"type:uuid" json:"parentId"`
+	Tags                  []tag.Tag             `gorm:"many2many:task_tags" json:"tags"`
+	AutoComplete          bool                  `json:"autoComplete"`
+	EstimatedMinutes      *int                  `json:"estimatedMinutes"`
+	Progress              int                   `gorm:"-" json:"progress"`
+	Blocked               bool                  `gorm:"-" json:"blocked"`
+	BlockedBy             []uuid.UUID           `gorm:"-" json:"blockedBy,omitempty"`
 	UserID                uuid.UUID             `gorm:"column:user_id;not null" json:"userId"`
 	User                  user.User             `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-"`
 	DoneAt                time.Time             `json:"doneAt"`
+	CalendarSyncedAt      *time.Time            `json:"calendarSyncedAt,omitempty"`
 	CreatedAt             time.Time             `json:"createdAt"`
 	UpdatedAt             time.Time             `json:"updatedAt"`
+	DeletedAt             gorm.DeletedAt        `gorm:"index" json:"-"`
+}
+
+// CalendarID is the Google calendar holding the task's event.
+func (t *Task) CalendarID() string {
+	if t.GoogleCalendarID == "" {
+		return googlecalendar.PrimaryCalendarID
+	}
+	return t.GoogleCalendarID
+}
+
+// TaskOccurrence stores the state of a single occurrence of a recurring task,
+// so completing one occurrence does not complete the whole series.
+type TaskOccurrence struct {
+	ID             uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
+	TaskID         uuid.UUID  `gorm:"type:uuid;not null" json:"taskId"`
+	UserID         uuid.UUID  `gorm:"column:user_id;not null" json:"userId"`
+	OccurrenceDate time.Time  `json:"occurrenceDate"`
+	Status         TaskStatus `json:"status"`
+	DoneAt         *time.Time `json:"doneAt"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+// ChecklistItem is an ordered step inside a task. Together with subtasks the
+// items make up the task's progress.
+type ChecklistItem struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
+	TaskID    uuid.UUID  `gorm:"type:uuid;not null" json:"taskId"`
+	UserID    uuid.UUID  `gorm:"column:user_id;not null" json:"userId"`
+	Title     string     `json:"title"`
+	Done      bool       `json:"done"`
+	Position  int        `json:"position"`
+	DoneAt    *time.Time `json:"doneAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+func (ChecklistItem) TableName() string {
+	return "task_checklist_items"
+}
+
+// ChildCounts is how many subtasks and checklist items a task has and how
+// many of them are done.
+type ChildCounts struct {
+	Total int
+	Done  int
+}
+
+// Percent is the progress shown on the task: the share of done children, or
+// 0/100 from the task's own status when it has none.
+func (c ChildCounts) Percent(status TaskStatus) int {
+	if c.Total == 0 {
+		if status == DONE {
+			return 100
+		}
+		return 0
+	}
+	return c.Done * 100 / c.Total
+}
+
+// TaskDependency records that BlockerID has to be done before BlockedID can
+// start.
+type TaskDependency struct {
+	BlockerID uuid.UUID `gorm:"type:uuid;primaryKey" json:"blockerId"`
+	BlockedID uuid.UUID `gorm:"type:uuid;primaryKey" json:"blockedId"`
+	UserID    uuid.UUID `gorm:"column:user_id;not null" json:"-"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (TaskDependency) TableName() string {
+	return "task_dependencies"
+}
+
+// TaskStatusChange is one entry of a task's status history. The first entry
+// of a task has an empty FromStatus.
+type TaskStatusChange struct {
+	ID         uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
+	TaskID     uuid.UUID  `gorm:"type:uuid;not null" json:"taskId"`
+	UserID     uuid.UUID  `gorm:"column:user_id;not null" json:"-"`
+	FromStatus TaskStatus `json:"fromStatus"`
+	ToStatus   TaskStatus `json:"toStatus"`
+	ChangedAt  time.Time  `json:"changedAt"`
+}
+
+func (TaskStatusChange) TableName() string {
+	return "task_status_history"
 }
@@ -17,3 +17,11 @@ const (
 	HIGH   TaskPriority = "HIGH"
 	MEDIUM TaskPriority = "MEDIUM"
 )
+
+type RecurrenceFrequency string
+
+const (
+	DAILY   RecurrenceFrequency = "DAILY"
+	WEEKLY  RecurrenceFrequency = "WEEKLY"
+	MONTHLY RecurrenceFrequency = "MONTHLY"
+)
@@ -7,15 +7,21 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	googlecalendar "github.com/saulo-duarte/chronos-lambda/internal/google_calendar"
+	"github.com/saulo-duarte/chronos-lambda/internal/i18n"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
 )
 
 type Handler struct {
-	service TaskService
+	service           TaskService
+	recurrenceService RecurrenceService
+	syncService       CalendarSyncService
 }
 
-func NewHandler(s TaskService) *Handler {
-	return &Handler{service: s}
+func NewHandler(s TaskService, rs RecurrenceService, cs CalendarSyncService) *Handler {
+	return &Handler{service: s, recurrenceService: rs, syncService: cs}
 }
 
 func (h *Handler) GetDashboardStats(w http.ResponseWriter, r *http.Request) {
@@ -24,11 +30,11 @@ func (h *Handler) GetDashboardStats(w http.ResponseWriter, r *http.Request) {
 	stats, err := h.service.GetDashboardStats(r.Context())
 	if err != nil {
 		if errors.Is(err, ErrUnauthorized) {
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
 			return
 		}
 		log.WithError(err).Error("Erro ao buscar estatísticas do dashboard")
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal error", http.StatusInternalServerError)
 		return
 	}
 
@@ -41,14 +47,22 @@ func (h *Handler) CreateTask(w http.ResponseWriter, r *http.Request) {
 	var payload Task
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 		log.WithError(err).Error("Corpo da requisição inválido")
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	task, err := h.service.CreateTask(r.Context(), &payload)
 	if err != nil {
+		if h.writeReferenceError(w, r, err) {
+			return
+		}
+		if errors.Is(err, ErrInvalidRecurrence) || errors.Is(err, ErrRecurrenceWithoutDate) || errors.Is(err, ErrInvalidParent) ||
+			errors.Is(err, ErrInvalidStatus) || errors.Is(err, ErrInvalidPriority) || errors.Is(err, ErrInvalidEstimate) {
+			i18n.Error(w, r, err.Error(), http.StatusBadRequest)
+			return
+		}
 		log.WithError(err).Error("Falha ao criar task")
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal error", http.StatusInternalServerError)
 		return
 	}
 
@@ -63,11 +77,11 @@ func (h *Handler) GetTask(w http.ResponseWriter, r *http.Request) {
 	task, err := h.service.FindByID(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, ErrTaskNotFound) {
-			http.Error(w, "task not found", http.StatusNotFound)
+			i18n.Error(w, r, "task not found", http.StatusNotFound)
 			return
 		}
 		log.WithError(err).Error("Erro ao buscar task")
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal error", http.StatusInternalServerError)
 		return
 	}
 
@@ -77,14 +91,38 @@ func (h *Handler) GetTask(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) ListTasksByUser(w http.ResponseWriter, r *http.Request) {
 	log := config.WithContext(r.Context())
 
-	tasks, err := h.service.FindAllByUser(r.Context())
+	query, err := ParseTaskQuery(r.URL.Query())
 	if err != nil {
-		log.WithError(err).Error("Erro ao listar tasks por usuário")
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		h.writeQueryError(w, r, err)
 		return
 	}
 
-	config.JSON(w, http.StatusOK, tasks)
+	page, err := h.service.QueryTasks(r.Context(), query)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUnauthorized):
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+		case errors.Is(err, ErrInvalidCursor):
+			h.writeQueryError(w, r, err)
+		default:
+			log.WithError(err).Error("Erro ao listar tasks por usuário")
+			i18n.Error(w, r, "internal error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, page)
+}
+
+func (h *Handler) writeQueryError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidTaskSort):
+		i18n.Error(w, r, "invalid task sort", http.StatusBadRequest)
+	case errors.Is(err, ErrInvalidCursor):
+		i18n.Error(w, r, "invalid cursor", http.StatusBadRequest)
+	default:
+		i18n.Error(w, r, "invalid task filter", http.StatusBadRequest)
+	}
 }
 
 func (h *Handler) ListTasksByProject(w http.ResponseWriter, r *http.Request) {
@@ -95,11 +133,11 @@ func (h *Handler) ListTasksByProject(w http.ResponseWriter, r *http.Request) {
 	tasks, err := h.service.FindAllByProjectID(r.Context(), projectID)
 	if err != nil {
 		if errors.Is(err, ErrProjectNotFound) {
-			http.Error(w, "project not found", http.StatusNotFound)
+			i18n.Error(w, r, "project not found", http.StatusNotFound)
 			return
 		}
 		log.WithError(err).Error("Erro ao listar tasks por projeto")
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal error", http.StatusInternalServerError)
 		return
 	}
 
@@ -114,19 +152,37 @@ func (h *Handler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 	var payload TaskUpdateDTO
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 		log.WithError(err).Error("Corpo da requisição inválido")
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	taskID, err := uuid.Parse(id)
+	if err != nil {
+		log.WithError(err).Warn("ID da task inválido")
+		i18n.Error(w, r, "invalid task id", http.StatusBadRequest)
 		return
 	}
-	payload.ID, _ = uuid.Parse(id)
+	payload.ID = taskID
 
 	task, err := h.service.UpdateTask(r.Context(), &payload)
 	if err != nil {
 		if errors.Is(err, ErrTaskNotFound) {
-			http.Error(w, "task not found", http.StatusNotFound)
+			i18n.Error(w, r, "task not found", http.StatusNotFound)
+			return
+		}
+		if h.writeReferenceError(w, r, err) {
+			return
+		}
+		if errors.Is(err, ErrInvalidRecurrence) || errors.Is(err, ErrRecurrenceWithoutDate) || errors.Is(err, ErrInvalidParent) ||
+			errors.Is(err, ErrInvalidStatus) || errors.Is(err, ErrInvalidPriority) || errors.Is(err, ErrInvalidEstimate) {
+			i18n.Error(w, r, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, ErrTaskBlocked) || errors.Is(err, ErrInvalidStatusTransition) {
+			i18n.Error(w, r, err.Error(), http.StatusConflict)
 			return
 		}
 		log.WithError(err).Error("Erro ao atualizar task")
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal error", http.StatusInternalServerError)
 		return
 	}
 
@@ -140,11 +196,11 @@ func (h *Handler) DeleteTask(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.service.DeleteByID(r.Context(), id); err != nil {
 		if errors.Is(err, ErrTaskNotFound) {
-			http.Error(w, "task not found", http.StatusNotFound)
+			i18n.Error(w, r, "task not found", http.StatusNotFound)
 			return
 		}
 		log.WithError(err).Error("Erro ao excluir task")
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal error", http.StatusInternalServerError)
 		return
 	}
 
@@ -161,13 +217,401 @@ func (h *Handler) ListTasksByStudyTopic(w http.ResponseWriter, r *http.Request)
 	tasks, err := h.service.FindAllByTopicID(r.Context(), studyTopicID)
 	if err != nil {
 		if errors.Is(err, ErrStudyTopicNotFound) {
-			http.Error(w, "study topic not found", http.StatusNotFound)
+			i18n.Error(w, r, "study topic not found", http.StatusNotFound)
 			return
 		}
 		log.WithError(err).Error("Erro ao listar tasks por tópico de estudo")
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal error", http.StatusInternalServerError)
 		return
 	}
 
 	config.JSON(w, http.StatusOK, tasks)
 }
+
+func (h *Handler) ListOccurrences(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	from, err := util.ParseLocalDateTime(r.URL.Query().Get("from"))
+	if err != nil {
+		i18n.Error(w, r, "invalid from date", http.StatusBadRequest)
+		return
+	}
+
+	to, err := util.ParseLocalDateTime(r.URL.Query().Get("to"))
+	if err != nil {
+		i18n.Error(w, r, "invalid to date", http.StatusBadRequest)
+		return
+	}
+
+	occurrences, err := h.recurrenceService.ExpandOccurrences(r.Context(), from, to)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUnauthorized):
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+		case errors.Is(err, ErrInvalidOccurrenceRange):
+			i18n.Error(w, r, "invalid occurrence range", http.StatusBadRequest)
+		default:
+			log.WithError(err).Error("Erro ao expandir ocorrências")
+			i18n.Error(w, r, "internal error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, occurrences)
+}
+
+func (h *Handler) CompleteOccurrence(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	id := chi.URLParam(r, "taskID")
+
+	var payload OccurrenceStatusDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.OccurrenceDate.IsZero() {
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	occurrence, err := h.recurrenceService.CompleteOccurrence(r.Context(), id, payload.OccurrenceDate)
+	if err != nil {
+		if !h.writeOccurrenceError(w, r, err) {
+			log.WithError(err).Error("Erro ao concluir ocorrência")
+			i18n.Error(w, r, "internal error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, occurrence)
+}
+
+func (h *Handler) ReopenOccurrence(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	id := chi.URLParam(r, "taskID")
+
+	var payload OccurrenceStatusDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.OccurrenceDate.IsZero() {
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.recurrenceService.ReopenOccurrence(r.Context(), id, payload.OccurrenceDate); err != nil {
+		if !h.writeOccurrenceError(w, r, err) {
+			log.WithError(err).Error("Erro ao reabrir ocorrência")
+			i18n.Error(w, r, "internal error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]string{
+		"message": "occurrence reopened successfully",
+	})
+}
+
+// writeReferenceError maps problems with the project or study topic a task
+// points at. It reports whether a response was written.
+func (h *Handler) writeReferenceError(w http.ResponseWriter, r *http.Request, err error) bool {
+	switch {
+	case errors.Is(err, ErrProjectNotFound):
+		i18n.Error(w, r, "project not found", http.StatusNotFound)
+	case errors.Is(err, ErrStudyTopicNotFound):
+		i18n.Error(w, r, "study topic not found", http.StatusNotFound)
+	case errors.Is(err, ErrProjectRequired):
+		i18n.Error(w, r, err.Error(), http.StatusBadRequest)
+	default:
+		return false
+	}
+	config.WithContext(r.Context()).WithError(err).Warn("Task references an invalid project or study topic")
+	return true
+}
+
+func (h *Handler) writeOccurrenceError(w http.ResponseWriter, r *http.Request, err error) bool {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrInvalidID):
+		i18n.Error(w, r, "invalid task id", http.StatusBadRequest)
+	case errors.Is(err, ErrTaskNotFound):
+		i18n.Error(w, r, "task not found", http.StatusNotFound)
+	case errors.Is(err, ErrOccurrenceNotFound):
+		i18n.Error(w, r, "occurrence not found", http.StatusNotFound)
+	case errors.Is(err, ErrTaskNotRecurring), errors.Is(err, ErrInvalidOccurrence):
+		i18n.Error(w, r, err.Error(), http.StatusBadRequest)
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *Handler) PullCalendarChanges(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	claims, err := auth.GetUserClaimsFromContext(r.Context())
+	if err != nil {
+		i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	result, err := h.syncService.PullChanges(r.Context(), uuid.MustParse(claims.UserID))
+	if err != nil {
+		if errors.Is(err, googlecalendar.ErrMissingCalendarTokens) || errors.Is(err, googlecalendar.ErrCalendarDisconnected) {
+			i18n.Error(w, r, "google calendar not connected", http.StatusConflict)
+			return
+		}
+		log.WithError(err).Error("Failed to pull calendar changes")
+		i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	config.JSON(w, http.StatusOK, result)
+}
+
+func (h *Handler) ListUnsyncedTasks(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	entries, err := h.service.ListUnsyncedTasks(r.Context())
+	if err != nil {
+		if errors.Is(err, ErrUnauthorized) {
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		log.WithError(err).Error("Failed to list unsynced tasks")
+		i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	config.JSON(w, http.StatusOK, entries)
+}
+
+func (h *Handler) ListSubtasks(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	tasks, err := h.service.ListSubtasks(r.Context(), chi.URLParam(r, "taskID"))
+	if err != nil {
+		if !h.writeChecklistError(w, r, err) {
+			log.WithError(err).Error("Erro ao listar subtarefas")
+			i18n.Error(w, r, "internal error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, tasks)
+}
+
+func (h *Handler) ListChecklist(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	items, err := h.service.ListChecklist(r.Context(), chi.URLParam(r, "taskID"))
+	if err != nil {
+		if !h.writeChecklistError(w, r, err) {
+			log.WithError(err).Error("Erro ao listar checklist")
+			i18n.Error(w, r, "internal error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, items)
+}
+
+func (h *Handler) AddChecklistItem(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload ChecklistItemDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.service.AddChecklistItem(r.Context(), chi.URLParam(r, "taskID"), &payload)
+	if err != nil {
+		if !h.writeChecklistError(w, r, err) {
+			log.WithError(err).Error("Erro ao criar item do checklist")
+			i18n.Error(w, r, "internal error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, item)
+}
+
+func (h *Handler) UpdateChecklistItem(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload ChecklistItemDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.service.UpdateChecklistItem(r.Context(), chi.URLParam(r, "taskID"), chi.URLParam(r, "itemID"), &payload)
+	if err != nil {
+		if !h.writeChecklistError(w, r, err) {
+			log.WithError(err).Error("Erro ao atualizar item do checklist")
+			i18n.Error(w, r, "internal error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, item)
+}
+
+func (h *Handler) DeleteChecklistItem(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	if err := h.service.DeleteChecklistItem(r.Context(), chi.URLParam(r, "taskID"), chi.URLParam(r, "itemID")); err != nil {
+		if !h.writeChecklistError(w, r, err) {
+			log.WithError(err).Error("Erro ao excluir item do checklist")
+			i18n.Error(w, r, "internal error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) ReorderChecklist(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload ChecklistOrderDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	items, err := h.service.ReorderChecklist(r.Context(), chi.URLParam(r, "taskID"), payload.ItemIDs)
+	if err != nil {
+		if !h.writeChecklistError(w, r, err) {
+			log.WithError(err).Error("Erro ao reordenar checklist")
+			i18n.Error(w, r, "internal error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, items)
+}
+
+func (h *Handler) writeChecklistError(w http.ResponseWriter, r *http.Request, err error) bool {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrInvalidID):
+		i18n.Error(w, r, "invalid id", http.StatusBadRequest)
+	case errors.Is(err, ErrTaskNotFound):
+		i18n.Error(w, r, "task not found", http.StatusNotFound)
+	case errors.Is(err, ErrChecklistItemNotFound):
+		i18n.Error(w, r, "checklist item not found", http.StatusNotFound)
+	case errors.Is(err, ErrEmptyChecklistItem), errors.Is(err, ErrInvalidChecklistOrder):
+		i18n.Error(w, r, err.Error(), http.StatusBadRequest)
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *Handler) ListDependencies(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	deps, err := h.service.ListDependencies(r.Context(), chi.URLParam(r, "taskID"))
+	if err != nil {
+		if !h.writeDependencyError(w, r, err) {
+			log.WithError(err).Error("Erro ao listar dependências")
+			i18n.Error(w, r, "internal error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, deps)
+}
+
+func (h *Handler) AddDependency(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload DependencyDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.BlockerID == uuid.Nil {
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	dep, err := h.service.AddDependency(r.Context(), chi.URLParam(r, "taskID"), payload.BlockerID)
+	if err != nil {
+		if !h.writeDependencyError(w, r, err) {
+			log.WithError(err).Error("Erro ao criar dependência")
+			i18n.Error(w, r, "internal error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, dep)
+}
+
+func (h *Handler) RemoveDependency(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	if err := h.service.RemoveDependency(r.Context(), chi.URLParam(r, "taskID"), chi.URLParam(r, "blockerID")); err != nil {
+		if !h.writeDependencyError(w, r, err) {
+			log.WithError(err).Error("Erro ao remover dependência")
+			i18n.Error(w, r, "internal error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) GetProjectGraph(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	graph, err := h.service.GetProjectGraph(r.Context(), chi.URLParam(r, "projectID"))
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			i18n.Error(w, r, "project not found", http.StatusNotFound)
+			return
+		}
+		if !h.writeDependencyError(w, r, err) {
+			log.WithError(err).Error("Erro ao montar grafo de dependências")
+			i18n.Error(w, r, "internal error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, graph)
+}
+
+func (h *Handler) writeDependencyError(w http.ResponseWriter, r *http.Request, err error) bool {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrInvalidID):
+		i18n.Error(w, r, "invalid id", http.StatusBadRequest)
+	case errors.Is(err, ErrTaskNotFound):
+		i18n.Error(w, r, "task not found", http.StatusNotFound)
+	case errors.Is(err, ErrDependencyNotFound):
+		i18n.Error(w, r, "dependency not found", http.StatusNotFound)
+	case errors.Is(err, ErrDependencyExists), errors.Is(err, ErrDependencyCycle):
+		i18n.Error(w, r, err.Error(), http.StatusConflict)
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *Handler) GetStatusHistory(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	history, err := h.service.GetStatusHistory(r.Context(), chi.URLParam(r, "taskID"))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUnauthorized):
+			i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+		case errors.Is(err, ErrInvalidID):
+			i18n.Error(w, r, "invalid id", http.StatusBadRequest)
+		case errors.Is(err, ErrTaskNotFound):
+			i18n.Error(w, r, "task not found", http.StatusNotFound)
+		default:
+			log.WithError(err).Error("Erro ao buscar histórico de status")
+			i18n.Error(w, r, "internal error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, history)
+}
@@ -0,0 +1,63 @@
+package task_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+)
+
+func TestTaskHandlerErrors(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.UserDataKeyID, userID.String())
+	ctx = context.WithValue(ctx, auth.UserDataKeyRole, "user")
+
+	foreign := &studytopic.StudyTopic{ID: uuid.New(), UserID: uuid.New()}
+	topics := &fakeStudyTopicRepo{topics: map[uuid.UUID]*studytopic.StudyTopic{foreign.ID: foreign}}
+	repo := &fakeTreeRepo{tasks: map[uuid.UUID]*task.Task{}}
+	h := task.NewHandler(task.NewService(repo, nil, &fakeLocationUserRepo{}, topics), nil, nil)
+
+	t.Run("CreateWithForeignTopic", func(t *testing.T) {
+		body := `{"name": "Revisão", "type": "STUDY", "studyTopicId": "` + foreign.ID.String() + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(body)).WithContext(ctx)
+		rec := httptest.NewRecorder()
+
+		h.CreateTask(rec, req)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Tópico de outro usuário deveria dar 404, recebido %d", rec.Code)
+		}
+	})
+
+	t.Run("CreateProjectTaskWithoutProject", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(`{"name": "Deploy", "type": "PROJECT"}`)).WithContext(ctx)
+		rec := httptest.NewRecorder()
+
+		h.CreateTask(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Tarefa de projeto sem projeto deveria dar 400, recebido %d", rec.Code)
+		}
+	})
+
+	t.Run("UpdateWithMalformedID", func(t *testing.T) {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("taskID", "nao-e-uuid")
+		req := httptest.NewRequest(http.MethodPut, "/tasks/nao-e-uuid", strings.NewReader(`{}`)).
+			WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+		rec := httptest.NewRecorder()
+
+		h.UpdateTask(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("ID inválido deveria dar 400, recebido %d", rec.Code)
+		}
+	})
+}
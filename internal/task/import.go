@@ -0,0 +1,306 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+)
+
+var ErrInvalidTaskType = errors.New("invalid task type")
+
+type ImportAction string
+
+const (
+	ImportCreate ImportAction = "CREATE"
+	ImportUpdate ImportAction = "UPDATE"
+	ImportSkip   ImportAction = "SKIP"
+)
+
+// ImportRequest is a batch of tasks parsed from an external calendar. Every
+// item gets the same type, project and study topic.
+type ImportRequest struct {
+	Type         TaskType
+	ProjectId    *uuid.UUID
+	StudyTopicId *uuid.UUID
+	Items        []*ImportItem
+}
+
+// ImportItem is one calendar entry. UID identifies it across imports; a
+// non-empty SkipReason means the parser could not map it to a task.
+type ImportItem struct {
+	UID         string
+	Name        string
+	Description string
+	Status      TaskStatus
+	Priority    TaskPriority
+	StartDate   *util.LocalDateTime
+	DueDate     *util.LocalDateTime
+	AllDay      bool
+	Recurrence  *RecurrenceRule
+	SkipReason  string
+}
+
+type ImportItemResult struct {
+	UID       string              `json:"uid"`
+	Name      string              `json:"name"`
+	StartDate *util.LocalDateTime `json:"startDate,omitempty"`
+	DueDate   *util.LocalDateTime `json:"dueDate,omitempty"`
+	AllDay    bool                `json:"allDay"`
+	Action    ImportAction        `json:"action"`
+	Reason    string              `json:"reason,omitempty"`
+	TaskID    *uuid.UUID          `json:"taskId,omitempty"`
+}
+
+type ImportResult struct {
+	Preview bool                `json:"preview"`
+	Created int                 `json:"created"`
+	Updated int                 `json:"updated"`
+	Skipped int                 `json:"skipped"`
+	Items   []*ImportItemResult `json:"items"`
+}
+
+// ImportTasks creates a task per new UID and updates tasks imported earlier
+// with the same UID. With preview set nothing is written and the result
+// shows what an import would do.
+func (s *taskService) ImportTasks(ctx context.Context, req *ImportRequest, preview bool) (*ImportResult, error) {
+	log := config.WithContext(ctx)
+
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.Type {
+	case EVENT, PROJECT, STUDY:
+	default:
+		return nil, ErrInvalidTaskType
+	}
+
+	// Imported tasks go through the same checks as tasks created one by one.
+	template := &Task{Type: req.Type, ProjectId: req.ProjectId, StudyTopicId: req.StudyTopicId}
+	if err := s.validateTaskDependencies(ctx, template); err != nil {
+		return nil, err
+	}
+
+	uids := make([]string, 0, len(req.Items))
+	for _, item := range req.Items {
+		if item.UID != "" {
+			uids = append(uids, item.UID)
+		}
+	}
+	existing, err := s.repo.FindByICalUIDs(userID, uids)
+	if err != nil {
+		log.WithError(err).Error("Failed to look up imported tasks")
+		return nil, err
+	}
+
+	loc := userLocation(ctx, s.userRepo, userID)
+	now := time.Now()
+	result := &ImportResult{Preview: preview, Items: make([]*ImportItemResult, 0, len(req.Items))}
+	var creates, updates []*Task
+	var statusChanges []*TaskStatusChange
+	seen := make(map[string]bool, len(req.Items))
+
+	for _, item := range req.Items {
+		item.StartDate = util.LocalizePtr(item.StartDate, loc)
+		item.DueDate = util.LocalizePtr(item.DueDate, loc)
+		res := &ImportItemResult{UID: item.UID, Name: item.Name, StartDate: item.StartDate, DueDate: item.DueDate, AllDay: item.AllDay}
+		result.Items = append(result.Items, res)
+
+		if reason := s.importSkipReason(item, seen); reason != "" {
+			res.Action, res.Reason = ImportSkip, reason
+			result.Skipped++
+			continue
+		}
+		seen[item.UID] = true
+
+		if t, ok := existing[item.UID]; ok {
+			if t.DeletedAt.Valid {
+				res.TaskID = &t.ID
+				res.Action, res.Reason = ImportSkip, "task in trash"
+				result.Skipped++
+				continue
+			}
+			localizeTasks([]*Task{t}, loc)
+			res.TaskID = &t.ID
+			changed := applyImportItem(t, item)
+			// A status the task cannot move to from its current one, or
+			// cannot start while blocked, is ignored; the other fields are
+			// still imported.
+			if item.Status != "" {
+				blocked, err := s.importBlocked(t, item.Status)
+				if err != nil {
+					log.WithError(err).Error("Failed to check blocking tasks")
+					return nil, err
+				}
+				if !blocked {
+					if change, err := changeStatus(t, item.Status, now); err == nil && change != nil {
+						statusChanges = append(statusChanges, change)
+						changed = true
+					}
+				}
+			}
+			if !changed {
+				res.Action, res.Reason = ImportSkip, "unchanged"
+				result.Skipped++
+				continue
+			}
+			t.UpdatedAt = now
+			updates = append(updates, t)
+			res.Action = ImportUpdate
+			result.Updated++
+			continue
+		}
+
+		t := &Task{
+			ID:           uuid.New(),
+			UserID:       userID,
+			Type:         req.Type,
+			ProjectId:    req.ProjectId,
+			StudyTopicId: req.StudyTopicId,
+			ICalUID:      item.UID,
+			Status:       item.Status,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+		applyImportItem(t, item)
+		change, err := initialStatus(t, now)
+		if err != nil {
+			res.Action, res.Reason = ImportSkip, err.Error()
+			result.Skipped++
+			continue
+		}
+		statusChanges = append(statusChanges, change)
+		creates = append(creates, t)
+		res.TaskID = &t.ID
+		res.Action = ImportCreate
+		result.Created++
+	}
+
+	if preview || len(creates)+len(updates) == 0 {
+		return result, nil
+	}
+
+	syncCalendar := s.calendarEnabled(ctx, userID)
+	err = s.repo.Transaction(func(tx TaskRepository) error {
+		for _, t := range creates {
+			if err := tx.Create(t); err != nil {
+				return err
+			}
+		}
+		for _, t := range updates {
+			if err := tx.Update(t); err != nil {
+				return err
+			}
+		}
+		for _, c := range statusChanges {
+			if err := tx.RecordStatusChange(c); err != nil {
+				return err
+			}
+		}
+		if !syncCalendar {
+			return nil
+		}
+		for _, t := range append(creates, updates...) {
+			if err := tx.EnqueueCalendarSync(newCalendarOutboxEntry(t, CalendarSyncUpsert)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.WithError(err).Error("Failed to import tasks")
+		return nil, err
+	}
+
+	log.WithField("user_id", userID).Infof("Imported tasks: %d created, %d updated, %d skipped", result.Created, result.Updated, result.Skipped)
+	return result, nil
+}
+
+func (s *taskService) importSkipReason(item *ImportItem, seen map[string]bool) string {
+	switch {
+	case item.SkipReason != "":
+		return item.SkipReason
+	case item.UID == "":
+		return "missing UID"
+	case seen[item.UID]:
+		return "duplicate UID in file"
+	case item.Name == "":
+		return "missing summary"
+	case !hasDate(item.StartDate) && !hasDate(item.DueDate):
+		return "missing dates"
+	}
+	if err := s.validateRecurrence(item.Recurrence, item.StartDate, item.DueDate); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// importBlocked reports whether moving t to status would start or finish it
+// while one of its blockers is still open.
+func (s *taskService) importBlocked(t *Task, status TaskStatus) (bool, error) {
+	if status == t.Status || (status != IN_PROGRESS && status != DONE) {
+		return false, nil
+	}
+	return isBlocked(s.repo, t.ID)
+}
+
+// applyImportItem copies the calendar fields other than the status onto t and
+// reports whether anything changed. Type, project and study topic are kept, so a re-import
+// does not move tasks the user has since reorganised.
+func applyImportItem(t *Task, item *ImportItem) bool {
+	changed := false
+
+	if t.Name != item.Name {
+		t.Name = item.Name
+		changed = true
+	}
+	if t.Description != item.Description {
+		t.Description = item.Description
+		changed = true
+	}
+	if item.Priority != "" && t.Priority != item.Priority {
+		t.Priority = item.Priority
+		changed = true
+	}
+	if !sameDate(t.StartDate, item.StartDate) {
+		t.StartDate = item.StartDate
+		changed = true
+	}
+	if !sameDate(t.DueDate, item.DueDate) {
+		t.DueDate = item.DueDate
+		changed = true
+	}
+	if t.AllDay != item.AllDay {
+		t.AllDay = item.AllDay
+		changed = true
+	}
+	if !sameRecurrence(t.Recurrence, item.Recurrence) {
+		t.Recurrence = item.Recurrence
+		changed = true
+	}
+
+	return changed
+}
+
+func hasDate(ldt *util.LocalDateTime) bool {
+	return ldt != nil && !ldt.IsZero()
+}
+
+func sameDate(a, b *util.LocalDateTime) bool {
+	if !hasDate(a) || !hasDate(b) {
+		return hasDate(a) == hasDate(b)
+	}
+	return a.Equal(*b)
+}
+
+func sameRecurrence(a, b *RecurrenceRule) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.RRULE() == b.RRULE()
+}
@@ -0,0 +1,93 @@
+package task_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+)
+
+type fakeStudyTopicRepo struct {
+	studytopic.StudyTopicRepository
+	topics map[uuid.UUID]*studytopic.StudyTopic
+}
+
+func (f *fakeStudyTopicRepo) GetByID(id string) (*studytopic.StudyTopic, error) {
+	return f.topics[uuid.MustParse(id)], nil
+}
+
+func (f *fakeTreeRepo) FindByICalUIDs(userId uuid.UUID, uids []string) (map[string]*task.Task, error) {
+	found := map[string]*task.Task{}
+	for _, t := range f.tasks {
+		for _, uid := range uids {
+			if t.UserID == userId && t.ICalUID == uid {
+				copied := *t
+				found[uid] = &copied
+			}
+		}
+	}
+	return found, nil
+}
+
+func TestImportTasksStudyTopic(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.UserDataKeyID, userID.String())
+	ctx = context.WithValue(ctx, auth.UserDataKeyRole, "user")
+
+	foreign := &studytopic.StudyTopic{ID: uuid.New(), UserID: uuid.New()}
+	topics := &fakeStudyTopicRepo{topics: map[uuid.UUID]*studytopic.StudyTopic{foreign.ID: foreign}}
+	repo := &fakeTreeRepo{tasks: map[uuid.UUID]*task.Task{}}
+	svc := task.NewService(repo, nil, &fakeLocationUserRepo{}, topics)
+
+	cases := map[string]uuid.UUID{
+		"TopicoDeOutroUsuario": foreign.ID,
+		"TopicoInexistente":    uuid.New(),
+	}
+	for name, topicID := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := &task.ImportRequest{
+				Type:         task.STUDY,
+				StudyTopicId: &topicID,
+				Items:        []*task.ImportItem{{UID: "aula-1@calendario", Name: "Aula"}},
+			}
+			if _, err := svc.ImportTasks(ctx, req, true); !errors.Is(err, task.ErrStudyTopicNotFound) {
+				t.Errorf("Importação deveria falhar com tópico não encontrado, erro: %v", err)
+			}
+		})
+	}
+}
+
+func TestImportSkipsStatusOfBlockedTask(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.UserDataKeyID, userID.String())
+	ctx = context.WithValue(ctx, auth.UserDataKeyRole, "user")
+
+	repo := &fakeTreeRepo{tasks: map[uuid.UUID]*task.Task{}}
+	svc := task.NewService(repo, nil, &fakeLocationUserRepo{}, nil)
+
+	due := &util.LocalDateTime{Time: time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)}
+	blocker := repo.add(&task.Task{ID: uuid.New(), UserID: userID, Name: "Revisão", Status: task.TODO})
+	imported := repo.add(&task.Task{ID: uuid.New(), UserID: userID, Name: "Entrega", Status: task.TODO, Type: task.EVENT, ICalUID: "entrega@calendario", DueDate: due})
+	repo.deps = []*task.TaskDependency{{BlockerID: blocker.ID, BlockedID: imported.ID, UserID: userID}}
+
+	req := &task.ImportRequest{
+		Type:  task.EVENT,
+		Items: []*task.ImportItem{{UID: imported.ICalUID, Name: imported.Name, Status: task.DONE, DueDate: due}},
+	}
+	result, err := svc.ImportTasks(ctx, req, false)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if result.Skipped != 1 || result.Items[0].Reason != "unchanged" {
+		t.Errorf("Status de tarefa bloqueada deveria ser ignorado, resultado: %+v", result.Items[0])
+	}
+	if repo.tasks[imported.ID].Status != task.TODO || len(repo.history) != 0 {
+		t.Errorf("Tarefa bloqueada não deveria ser concluída pela importação, status: %s", repo.tasks[imported.ID].Status)
+	}
+}
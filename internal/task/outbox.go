@@ -0,0 +1,100 @@
+package task
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	googlecalendar "github.com/saulo-duarte/chronos-lambda/internal/google_calendar"
+)
+
+type CalendarSyncOperation string
+
+const (
+	CalendarSyncUpsert CalendarSyncOperation = "UPSERT"
+	CalendarSyncDelete CalendarSyncOperation = "DELETE"
+)
+
+type CalendarSyncStatus string
+
+const (
+	CalendarSyncPending CalendarSyncStatus = "PENDING"
+	CalendarSyncDone    CalendarSyncStatus = "DONE"
+	CalendarSyncDead    CalendarSyncStatus = "DEAD"
+)
+
+// CalendarOutboxEntry is a pending push of a task to Google Calendar. It is
+// written in the same transaction as the task and drained by the outbox
+// worker. A task has at most one PENDING entry: new writes replace it and
+// bump Version, so a worker holding an older version never marks the newer
+// change as done.
+type CalendarOutboxEntry struct {
+	ID            uuid.UUID             `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
+	TaskID        uuid.UUID             `gorm:"type:uuid;not null" json:"taskId"`
+	UserID        uuid.UUID             `gorm:"column:user_id;not null" json:"userId"`
+	Operation     CalendarSyncOperation `json:"operation"`
+	CalendarID    string                `json:"calendarId,omitempty"`
+	EventID       string                `json:"eventId,omitempty"`
+	Status        CalendarSyncStatus    `json:"status"`
+	Attempts      int                   `json:"attempts"`
+	Version       int                   `json:"-"`
+	NextAttemptAt time.Time             `json:"nextAttemptAt"`
+	LastError     string                `json:"lastError,omitempty"`
+	ProcessedAt   *time.Time            `json:"processedAt,omitempty"`
+	CreatedAt     time.Time             `json:"createdAt"`
+	UpdatedAt     time.Time             `json:"updatedAt"`
+	Task          *Task                 `gorm:"foreignKey:TaskID" json:"task,omitempty"`
+}
+
+func (CalendarOutboxEntry) TableName() string {
+	return "calendar_sync_outbox"
+}
+
+func newCalendarOutboxEntry(t *Task, op CalendarSyncOperation) *CalendarOutboxEntry {
+	now := time.Now()
+	entry := &CalendarOutboxEntry{
+		ID:            uuid.New(),
+		TaskID:        t.ID,
+		UserID:        t.UserID,
+		Operation:     op,
+		Status:        CalendarSyncPending,
+		Version:       1,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if op == CalendarSyncDelete {
+		// The task row is gone by the time the worker runs, so keep the
+		// event ID. Tasks never pushed may still have an in-flight insert
+		// under the derived ID.
+		entry.CalendarID = t.GoogleCalendarID
+		entry.EventID = t.GoogleCalendarEventID
+		if entry.EventID == "" {
+			entry.EventID = googlecalendar.EventIDForTask(t.ID)
+		}
+	}
+
+	return entry
+}
+
+const (
+	outboxBaseBackoff = 30 * time.Second
+	outboxMaxBackoff  = 6 * time.Hour
+)
+
+// OutboxBackoff is the delay before retry number attempts (1-based):
+// 30s, 1m, 2m, ... capped at 6h.
+func OutboxBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+
+	delay := outboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return delay
+}
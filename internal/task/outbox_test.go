@@ -0,0 +1,212 @@
+package task_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/activity"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	googlecalendar "github.com/saulo-duarte/chronos-lambda/internal/google_calendar"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+)
+
+func TestMain(m *testing.M) {
+	config.Init()
+	os.Exit(m.Run())
+}
+
+type fakeOutboxRepo struct {
+	task.TaskRepository
+	pending   []*task.CalendarOutboxEntry
+	tasks     map[uuid.UUID]*task.Task
+	completed []*task.CalendarOutboxEntry
+	failed    []*task.CalendarOutboxEntry
+	synced    map[uuid.UUID]string
+	activity  []*activity.Entry
+}
+
+func (f *fakeOutboxRepo) ClaimCalendarSyncs(now time.Time, lease time.Duration, limit int) ([]*task.CalendarOutboxEntry, error) {
+	claimed := f.pending
+	f.pending = nil
+	return claimed, nil
+}
+
+func (f *fakeOutboxRepo) CompleteCalendarSync(e *task.CalendarOutboxEntry, processedAt time.Time) error {
+	f.completed = append(f.completed, e)
+	return nil
+}
+
+func (f *fakeOutboxRepo) FailCalendarSync(e *task.CalendarOutboxEntry) error {
+	f.failed = append(f.failed, e)
+	return nil
+}
+
+func (f *fakeOutboxRepo) PurgeCalendarSyncs(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeOutboxRepo) FindByIdAndUserId(id, userId uuid.UUID) (*task.Task, error) {
+	if t, ok := f.tasks[id]; ok {
+		return t, nil
+	}
+	return nil, task.ErrNotFound
+}
+
+func (f *fakeOutboxRepo) MarkCalendarSynced(id uuid.UUID, calendarID, eventID string, syncedAt time.Time) error {
+	f.synced[id] = eventID
+	return nil
+}
+
+func (f *fakeOutboxRepo) RecordActivity(e *activity.Entry) error {
+	f.activity = append(f.activity, e)
+	return nil
+}
+
+type fakeCalendarManager struct {
+	syncErr error
+	removed []string
+}
+
+func (f *fakeCalendarManager) SyncTask(ctx context.Context, userID uuid.UUID, t *googlecalendar.CalendarTask) (string, error) {
+	if f.syncErr != nil {
+		return "", f.syncErr
+	}
+	return googlecalendar.EventIDForTask(t.ID), nil
+}
+
+func (f *fakeCalendarManager) RemoveTask(ctx context.Context, userID uuid.UUID, calendarID, eventID string) error {
+	f.removed = append(f.removed, eventID)
+	return nil
+}
+
+func newOutboxFixture(op task.CalendarSyncOperation, attempts int) (*fakeOutboxRepo, *task.CalendarOutboxEntry) {
+	t := &task.Task{ID: uuid.New(), UserID: uuid.New(), Name: "Estudar"}
+	entry := &task.CalendarOutboxEntry{
+		ID:        uuid.New(),
+		TaskID:    t.ID,
+		UserID:    t.UserID,
+		Operation: op,
+		EventID:   "evt-1",
+		Status:    task.CalendarSyncPending,
+		Attempts:  attempts,
+		Version:   1,
+	}
+	repo := &fakeOutboxRepo{
+		pending: []*task.CalendarOutboxEntry{entry},
+		tasks:   map[uuid.UUID]*task.Task{t.ID: t},
+		synced:  map[uuid.UUID]string{},
+	}
+	return repo, entry
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	cases := map[int]time.Duration{
+		0:  0,
+		1:  30 * time.Second,
+		2:  time.Minute,
+		4:  4 * time.Minute,
+		20: 6 * time.Hour,
+	}
+	for attempts, expected := range cases {
+		if got := task.OutboxBackoff(attempts); got != expected {
+			t.Errorf("Backoff incorreto para %d tentativas. Esperado: %v, Recebido: %v", attempts, expected, got)
+		}
+	}
+}
+
+func TestCalendarOutboxWorker(t *testing.T) {
+	t.Run("PushesTaskAndCompletesEntry", func(t *testing.T) {
+		repo, entry := newOutboxFixture(task.CalendarSyncUpsert, 0)
+		worker := task.NewCalendarOutboxWorker(repo, &fakeCalendarManager{})
+
+		result, err := worker.Drain(context.Background())
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+
+		if result.Processed != 1 || len(repo.completed) != 1 {
+			t.Fatalf("Entrada deveria ser concluída, resultado: %+v", result)
+		}
+		if repo.synced[entry.TaskID] != googlecalendar.EventIDForTask(entry.TaskID) {
+			t.Errorf("Event ID não foi salvo na tarefa: %q", repo.synced[entry.TaskID])
+		}
+		if len(repo.activity) != 1 || repo.activity[0].Action != activity.CalendarSynced || repo.activity[0].EntityID != entry.TaskID {
+			t.Errorf("Sincronização deveria aparecer na atividade da tarefa: %+v", repo.activity)
+		}
+	})
+
+	t.Run("DeleteUsesStoredEventID", func(t *testing.T) {
+		repo, _ := newOutboxFixture(task.CalendarSyncDelete, 0)
+		manager := &fakeCalendarManager{}
+		worker := task.NewCalendarOutboxWorker(repo, manager)
+
+		if _, err := worker.Drain(context.Background()); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if len(manager.removed) != 1 || manager.removed[0] != "evt-1" {
+			t.Errorf("Evento esperado evt-1 removido, recebido: %v", manager.removed)
+		}
+	})
+
+	t.Run("TransientErrorIsRetriedWithBackoff", func(t *testing.T) {
+		repo, entry := newOutboxFixture(task.CalendarSyncUpsert, 1)
+		worker := task.NewCalendarOutboxWorker(repo, &fakeCalendarManager{syncErr: errors.New("google indisponível")})
+
+		before := time.Now()
+		result, _ := worker.Drain(context.Background())
+
+		if result.Retried != 1 || entry.Status != task.CalendarSyncPending {
+			t.Fatalf("Entrada deveria ser reagendada, resultado: %+v status: %s", result, entry.Status)
+		}
+		if entry.Attempts != 2 || entry.LastError == "" {
+			t.Errorf("Tentativas/erro não registrados: %d %q", entry.Attempts, entry.LastError)
+		}
+		if len(repo.activity) != 0 {
+			t.Errorf("Falha com nova tentativa não deveria aparecer na atividade: %+v", repo.activity)
+		}
+		if entry.NextAttemptAt.Before(before.Add(task.OutboxBackoff(2))) {
+			t.Errorf("Próxima tentativa cedo demais: %v", entry.NextAttemptAt)
+		}
+	})
+
+	t.Run("ExhaustedRetriesGoToDeadLetter", func(t *testing.T) {
+		repo, entry := newOutboxFixture(task.CalendarSyncUpsert, 7)
+		worker := task.NewCalendarOutboxWorker(repo, &fakeCalendarManager{syncErr: errors.New("google indisponível")})
+
+		result, _ := worker.Drain(context.Background())
+
+		if result.Dead != 1 || entry.Status != task.CalendarSyncDead {
+			t.Errorf("Entrada deveria ir para dead-letter, status: %s", entry.Status)
+		}
+		if len(repo.activity) != 1 || repo.activity[0].Action != activity.CalendarSyncFailed || repo.activity[0].Detail != entry.LastError {
+			t.Errorf("Falha definitiva deveria aparecer na atividade com o erro: %+v", repo.activity)
+		}
+	})
+
+	t.Run("DisconnectedCalendarIsNotRetried", func(t *testing.T) {
+		repo, entry := newOutboxFixture(task.CalendarSyncUpsert, 0)
+		worker := task.NewCalendarOutboxWorker(repo, &fakeCalendarManager{syncErr: googlecalendar.ErrCalendarDisconnected})
+
+		worker.Drain(context.Background())
+
+		if entry.Status != task.CalendarSyncDead || entry.Attempts != 1 {
+			t.Errorf("Erro permanente deveria ir direto para dead-letter, status: %s tentativas: %d", entry.Status, entry.Attempts)
+		}
+	})
+
+	t.Run("DeletedTaskCompletesEntry", func(t *testing.T) {
+		repo, _ := newOutboxFixture(task.CalendarSyncUpsert, 0)
+		repo.tasks = map[uuid.UUID]*task.Task{}
+		worker := task.NewCalendarOutboxWorker(repo, &fakeCalendarManager{})
+
+		worker.Drain(context.Background())
+
+		if len(repo.completed) != 1 {
+			t.Error("Entrada de tarefa removida deveria ser concluída sem chamar o Google")
+		}
+	})
+}
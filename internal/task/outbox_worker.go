@@ -0,0 +1,159 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/activity"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	googlecalendar "github.com/saulo-duarte/chronos-lambda/internal/google_calendar"
+)
+
+const (
+	outboxBatchSize   = 50
+	outboxLease       = 5 * time.Minute
+	outboxMaxAttempts = 8
+	outboxRetention   = 7 * 24 * time.Hour
+)
+
+// DrainResult summarises one pass of the outbox worker.
+type DrainResult struct {
+	Processed int `json:"processed"`
+	Retried   int `json:"retried"`
+	Dead      int `json:"dead"`
+}
+
+type CalendarOutboxWorker interface {
+	// Drain processes every entry that is due and returns.
+	Drain(ctx context.Context) (*DrainResult, error)
+	// Run drains the outbox every interval until ctx is cancelled.
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type calendarOutboxWorker struct {
+	repo            TaskRepository
+	calendarManager googlecalendar.CalendarManager
+	now             func() time.Time
+}
+
+func NewCalendarOutboxWorker(repo TaskRepository, calendarManager googlecalendar.CalendarManager) CalendarOutboxWorker {
+	return &calendarOutboxWorker{
+		repo:            repo,
+		calendarManager: calendarManager,
+		now:             time.Now,
+	}
+}
+
+func (w *calendarOutboxWorker) Drain(ctx context.Context) (*DrainResult, error) {
+	log := config.WithContext(ctx)
+	result := &DrainResult{}
+
+	for ctx.Err() == nil {
+		entries, err := w.repo.ClaimCalendarSyncs(w.now(), outboxLease, outboxBatchSize)
+		if err != nil {
+			log.WithError(err).Error("Failed to claim calendar outbox entries")
+			return result, err
+		}
+		if len(entries) == 0 {
+			break
+		}
+
+		for _, entry := range entries {
+			w.process(ctx, entry, result)
+		}
+	}
+
+	if purged, err := w.repo.PurgeCalendarSyncs(w.now().Add(-outboxRetention)); err != nil {
+		log.WithError(err).Warn("Failed to purge processed calendar outbox entries")
+	} else if purged > 0 {
+		log.Infof("Purged %d processed calendar outbox entries", purged)
+	}
+
+	log.Infof("Calendar outbox drained: %d processed, %d retried, %d dead", result.Processed, result.Retried, result.Dead)
+	return result, nil
+}
+
+func (w *calendarOutboxWorker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Drain(ctx); err != nil {
+			config.WithContext(ctx).WithError(err).Warn("Calendar outbox drain failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *calendarOutboxWorker) process(ctx context.Context, entry *CalendarOutboxEntry, result *DrainResult) {
+	log := config.WithContext(ctx).WithField("task_id", entry.TaskID)
+
+	err := w.apply(ctx, entry)
+	if err == nil {
+		if err := w.repo.CompleteCalendarSync(entry, w.now()); err != nil {
+			log.WithError(err).Error("Failed to complete calendar outbox entry")
+		}
+		w.recordActivity(ctx, entry, activity.CalendarSynced, string(entry.Operation))
+		result.Processed++
+		return
+	}
+
+	entry.Attempts++
+	entry.LastError = err.Error()
+	if isPermanentCalendarError(err) || entry.Attempts >= outboxMaxAttempts {
+		entry.Status = CalendarSyncDead
+		result.Dead++
+		log.WithError(err).Warnf("Calendar sync gave up after %d attempts", entry.Attempts)
+		w.recordActivity(ctx, entry, activity.CalendarSyncFailed, entry.LastError)
+	} else {
+		entry.NextAttemptAt = w.now().Add(OutboxBackoff(entry.Attempts))
+		result.Retried++
+		log.WithError(err).Infof("Calendar sync failed, retrying at %s", entry.NextAttemptAt.Format(time.RFC3339))
+	}
+
+	if err := w.repo.FailCalendarSync(entry); err != nil {
+		log.WithError(err).Error("Failed to reschedule calendar outbox entry")
+	}
+}
+
+// recordActivity adds the sync result to the task's timeline. The timeline
+// is informational, so a failure here does not affect the outbox entry.
+func (w *calendarOutboxWorker) recordActivity(ctx context.Context, entry *CalendarOutboxEntry, action activity.Action, detail string) {
+	e := activity.NewEntry(entry.UserID, activity.TaskEntity, entry.TaskID, action, nil)
+	e.Detail = detail
+	if err := w.repo.RecordActivity(e); err != nil {
+		config.WithContext(ctx).WithError(err).WithField("task_id", entry.TaskID).Warn("Failed to record calendar sync activity")
+	}
+}
+
+func (w *calendarOutboxWorker) apply(ctx context.Context, entry *CalendarOutboxEntry) error {
+	if entry.Operation == CalendarSyncDelete {
+		return w.calendarManager.RemoveTask(ctx, entry.UserID, entry.CalendarID, entry.EventID)
+	}
+
+	// Always push the task as it is now: older writes were folded into this
+	// entry when it was re-enqueued.
+	t, err := w.repo.FindByIdAndUserId(entry.TaskID, entry.UserID)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return pushToCalendar(ctx, w.repo, w.calendarManager, entry.UserID, t)
+}
+
+// isPermanentCalendarError reports failures that retrying cannot fix until
+// the user reconnects their Google account.
+func isPermanentCalendarError(err error) bool {
+	return errors.Is(err, googlecalendar.ErrMissingCalendarTokens) ||
+		errors.Is(err, googlecalendar.ErrCalendarDisconnected) ||
+		errors.Is(err, googlecalendar.ErrUserNotFound)
+}
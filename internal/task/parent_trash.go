@@ -0,0 +1,111 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/user"
+)
+
+// TrashParent is a record whose tasks go to the trash with it.
+type TrashParent string
+
+const (
+	ProjectParent      TrashParent = "project"
+	StudySubjectParent TrashParent = "study-subject"
+)
+
+// ParentTrashService moves projects and study subjects to the trash together
+// with their tasks, and brings them back together, keeping the tasks'
+// calendar events in step. It does not check ownership: callers pass the
+// owner they already verified.
+type ParentTrashService interface {
+	TrashProject(ctx context.Context, projectID, userID uuid.UUID) error
+	RestoreProject(ctx context.Context, projectID, userID uuid.UUID) error
+	TrashStudySubject(ctx context.Context, subjectID, userID uuid.UUID) error
+	RestoreStudySubject(ctx context.Context, subjectID, userID uuid.UUID) error
+}
+
+type parentTrashService struct {
+	repo     TaskRepository
+	userRepo user.UserRepository
+}
+
+func NewParentTrashService(repo TaskRepository, userRepo user.UserRepository) ParentTrashService {
+	return &parentTrashService{repo: repo, userRepo: userRepo}
+}
+
+func (s *parentTrashService) TrashProject(ctx context.Context, projectID, userID uuid.UUID) error {
+	return s.trash(ctx, ProjectParent, projectID, userID)
+}
+
+func (s *parentTrashService) RestoreProject(ctx context.Context, projectID, userID uuid.UUID) error {
+	return s.restore(ctx, ProjectParent, projectID, userID)
+}
+
+func (s *parentTrashService) TrashStudySubject(ctx context.Context, subjectID, userID uuid.UUID) error {
+	return s.trash(ctx, StudySubjectParent, subjectID, userID)
+}
+
+func (s *parentTrashService) RestoreStudySubject(ctx context.Context, subjectID, userID uuid.UUID) error {
+	return s.restore(ctx, StudySubjectParent, subjectID, userID)
+}
+
+// trash stamps the parent and its tasks with one deleted_at, so restore and
+// purge can tell them from tasks trashed on their own, and removes the
+// tasks' events.
+func (s *parentTrashService) trash(ctx context.Context, parent TrashParent, id, userID uuid.UUID) error {
+	syncCalendar := calendarConnected(ctx, s.userRepo, userID)
+	var trashed []*Task
+	err := s.repo.Transaction(func(tx TaskRepository) error {
+		tasks, err := tx.TrashParent(parent, id, userID, time.Now())
+		if err != nil {
+			return err
+		}
+		trashed = tasks
+		if !syncCalendar {
+			return nil
+		}
+		for _, t := range tasks {
+			if err := tx.EnqueueCalendarSync(newCalendarOutboxEntry(t, CalendarSyncDelete)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	config.WithContext(ctx).WithField("parent_id", id).Infof("Moved %s to trash with %d tasks", parent, len(trashed))
+	return nil
+}
+
+func (s *parentTrashService) restore(ctx context.Context, parent TrashParent, id, userID uuid.UUID) error {
+	syncCalendar := calendarConnected(ctx, s.userRepo, userID)
+	var restored []*Task
+	err := s.repo.Transaction(func(tx TaskRepository) error {
+		tasks, err := tx.RestoreParent(parent, id, userID)
+		if err != nil {
+			return err
+		}
+		restored = tasks
+		if !syncCalendar {
+			return nil
+		}
+		for _, t := range tasks {
+			if err := tx.EnqueueCalendarSync(newCalendarOutboxEntry(t, CalendarSyncUpsert)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	config.WithContext(ctx).WithField("parent_id", id).Infof("Restored %s with %d tasks", parent, len(restored))
+	return nil
+}
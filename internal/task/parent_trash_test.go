@@ -0,0 +1,77 @@
+package task_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+)
+
+func (f *fakeTreeRepo) TrashParent(parent task.TrashParent, id, userId uuid.UUID, at time.Time) ([]*task.Task, error) {
+	var trashed []*task.Task
+	for _, t := range f.tasks {
+		if t.UserID == userId && t.ProjectId != nil && *t.ProjectId == id {
+			below, _ := f.ListDescendants(t.ID, userId)
+			trashed = append(trashed, append([]*task.Task{t}, below...)...)
+		}
+	}
+	if f.trashed == nil {
+		f.trashed = map[uuid.UUID]*task.Task{}
+	}
+	for _, t := range trashed {
+		delete(f.tasks, t.ID)
+		f.trashed[t.ID] = t
+	}
+	return trashed, nil
+}
+
+func (f *fakeTreeRepo) RestoreParent(parent task.TrashParent, id, userId uuid.UUID) ([]*task.Task, error) {
+	var restored []*task.Task
+	for _, t := range f.trashed {
+		restored = append(restored, t)
+		f.tasks[t.ID] = t
+	}
+	f.trashed = nil
+	return restored, nil
+}
+
+func TestParentTrash(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.Background()
+
+	repo := &fakeTreeRepo{tasks: map[uuid.UUID]*task.Task{}}
+	svc := task.NewParentTrashService(repo, &fakeConnectedUserRepo{})
+
+	projectID := uuid.New()
+	other := uuid.New()
+	plan := repo.add(&task.Task{ID: uuid.New(), UserID: userID, Name: "Planejar", ProjectId: &projectID})
+	step := repo.add(&task.Task{ID: uuid.New(), UserID: userID, Name: "Detalhar", ParentID: &plan.ID})
+	repo.add(&task.Task{ID: uuid.New(), UserID: userID, Name: "Outro projeto", ProjectId: &other})
+
+	t.Run("TrashProjectTakesItsTasks", func(t *testing.T) {
+		if err := svc.TrashProject(ctx, projectID, userID); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if repo.trashed[plan.ID] == nil || repo.trashed[step.ID] == nil || len(repo.tasks) != 1 {
+			t.Errorf("Tarefas e subtarefas do projeto deveriam ir para a lixeira, restantes: %d", len(repo.tasks))
+		}
+		if len(repo.queued) != 2 || repo.queued[0].Operation != task.CalendarSyncDelete || repo.queued[1].Operation != task.CalendarSyncDelete {
+			t.Errorf("Eventos das tarefas do projeto deveriam ser removidos, fila: %+v", repo.queued)
+		}
+	})
+
+	t.Run("RestoreProjectBringsTasksBack", func(t *testing.T) {
+		repo.queued = nil
+		if err := svc.RestoreProject(ctx, projectID, userID); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if repo.tasks[plan.ID] == nil || repo.tasks[step.ID] == nil {
+			t.Error("Tarefas deveriam voltar com o projeto")
+		}
+		if len(repo.queued) != 2 || repo.queued[0].Operation != task.CalendarSyncUpsert {
+			t.Errorf("Eventos das tarefas restauradas deveriam ser recriados, fila: %+v", repo.queued)
+		}
+	})
+}
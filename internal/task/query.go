@@ -0,0 +1,451 @@
+package task
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/tag"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidTaskFilter = errors.New("invalid task filter")
+	ErrInvalidTaskSort   = errors.New("invalid task sort")
+	ErrInvalidCursor     = errors.New("invalid cursor")
+)
+
+const (
+	DefaultTaskPageSize = 50
+	MaxTaskPageSize     = 200
+	defaultTaskSort     = "dueDate,-createdAt"
+)
+
+// TaskQuery filters, sorts and pages a user's tasks. Every filter is
+// optional; list filters match any of their values.
+type TaskQuery struct {
+	Statuses     []TaskStatus
+	Types        []TaskType
+	Priorities   []TaskPriority
+	DueFrom      *util.LocalDateTime
+	DueTo        *util.LocalDateTime
+	Overdue      *bool
+	ProjectID    *uuid.UUID
+	StudyTopicID *uuid.UUID
+	TagIDs       []uuid.UUID
+	Search       string
+	Sort         []TaskSort
+	Limit        int
+	Cursor       *TaskCursor
+
+	// Now is the reference time for the overdue filter.
+	Now time.Time
+	// dueToWholeDay is set when dueTo was a bare date, which includes the
+	// whole day once the date is placed in the user's time zone.
+	dueToWholeDay bool
+}
+
+type TaskSort struct {
+	Field string
+	Desc  bool
+}
+
+// TaskCursor is the position after the last task of a page: its sort key
+// values and ID. Sort records the ordering it was issued for, so a cursor
+// cannot be replayed against a different one.
+type TaskCursor struct {
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+	ID     uuid.UUID         `json:"id"`
+}
+
+// sortField describes a sortable column. Nullable dates sort last in both
+// directions by standing in for NULL with a sentinel.
+type sortField struct {
+	column   string
+	nullable bool
+	value    func(t *Task) interface{}
+}
+
+var (
+	nullsLastAsc  = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+	nullsLastDesc = time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+const (
+	priorityRankSQL = "CASE tasks.priority WHEN 'HIGH' THEN 3 WHEN 'MEDIUM' THEN 2 WHEN 'LOW' THEN 1 ELSE 0 END"
+	statusRankSQL   = "CASE tasks.status WHEN 'TODO' THEN 1 WHEN 'IN_PROGRESS' THEN 2 WHEN 'DONE' THEN 3 ELSE 0 END"
+)
+
+var taskSortFields = map[string]sortField{
+	"dueDate":   {column: "tasks.due_date", nullable: true, value: func(t *Task) interface{} { return util.ToTimePtr(t.DueDate) }},
+	"startDate": {column: "tasks.start_date", nullable: true, value: func(t *Task) interface{} { return util.ToTimePtr(t.StartDate) }},
+	"createdAt": {column: "tasks.created_at", value: func(t *Task) interface{} { return t.CreatedAt }},
+	"updatedAt": {column: "tasks.updated_at", value: func(t *Task) interface{} { return t.UpdatedAt }},
+	"name":      {column: "tasks.name", value: func(t *Task) interface{} { return t.Name }},
+	"priority":  {column: priorityRankSQL, value: func(t *Task) interface{} { return priorityRank(t.Priority) }},
+	"status":    {column: statusRankSQL, value: func(t *Task) interface{} { return statusRank(t.Status) }},
+}
+
+// ParseTaskQuery reads the GET /tasks query string. List filters accept
+// repeated parameters or comma-separated values; sort is a comma-separated
+// list of fields, each prefixed with "-" for descending order.
+func ParseTaskQuery(values url.Values) (*TaskQuery, error) {
+	q := &TaskQuery{
+		Search: strings.TrimSpace(values.Get("search")),
+		Limit:  DefaultTaskPageSize,
+	}
+
+	for _, v := range listParam(values, "status") {
+		status := TaskStatus(strings.ToUpper(v))
+		switch status {
+		case TODO, IN_PROGRESS, DONE:
+		default:
+			return nil, ErrInvalidTaskFilter
+		}
+		q.Statuses = append(q.Statuses, status)
+	}
+	for _, v := range listParam(values, "type") {
+		taskType := TaskType(strings.ToUpper(v))
+		switch taskType {
+		case EVENT, STUDY, PROJECT:
+		default:
+			return nil, ErrInvalidTaskFilter
+		}
+		q.Types = append(q.Types, taskType)
+	}
+	for _, v := range listParam(values, "priority") {
+		priority := TaskPriority(strings.ToUpper(v))
+		if priorityRank(priority) == 0 {
+			return nil, ErrInvalidTaskFilter
+		}
+		q.Priorities = append(q.Priorities, priority)
+	}
+
+	var err error
+	if q.DueFrom, err = dateParam(values, "dueFrom"); err != nil {
+		return nil, err
+	}
+	if q.DueTo, err = dateParam(values, "dueTo"); err != nil {
+		return nil, err
+	}
+	q.dueToWholeDay = len(values.Get("dueTo")) == len("2006-01-02")
+	if q.ProjectID, err = uuidParam(values, "projectId"); err != nil {
+		return nil, err
+	}
+	if q.StudyTopicID, err = uuidParam(values, "studyTopicId"); err != nil {
+		return nil, err
+	}
+	if q.TagIDs, err = tag.ParseIDs(values); err != nil {
+		return nil, ErrInvalidTaskFilter
+	}
+	if v := values.Get("overdue"); v != "" {
+		overdue, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, ErrInvalidTaskFilter
+		}
+		q.Overdue = &overdue
+	}
+	if v := values.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return nil, ErrInvalidTaskFilter
+		}
+		q.Limit = min(limit, MaxTaskPageSize)
+	}
+
+	sortSpec := values.Get("sort")
+	if sortSpec == "" {
+		sortSpec = defaultTaskSort
+	}
+	if q.Sort, err = parseTaskSort(sortSpec); err != nil {
+		return nil, err
+	}
+
+	if v := values.Get("cursor"); v != "" {
+		if q.Cursor, err = decodeCursor(v, q.Sort); err != nil {
+			return nil, err
+		}
+	}
+
+	return q, nil
+}
+
+func parseTaskSort(spec string) ([]TaskSort, error) {
+	var sorts []TaskSort
+	seen := map[string]bool{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		s := TaskSort{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if _, ok := taskSortFields[s.Field]; !ok || seen[s.Field] {
+			return nil, ErrInvalidTaskSort
+		}
+		seen[s.Field] = true
+		sorts = append(sorts, s)
+	}
+	return sorts, nil
+}
+
+func sortSignature(sorts []TaskSort) string {
+	parts := make([]string, len(sorts))
+	for i, s := range sorts {
+		parts[i] = s.Field
+		if s.Desc {
+			parts[i] = "-" + s.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// encodeCursor returns the opaque cursor pointing after t.
+func encodeCursor(t *Task, sorts []TaskSort) string {
+	c := TaskCursor{Sort: sortSignature(sorts), ID: t.ID}
+	for _, s := range sorts {
+		raw, _ := json.Marshal(cursorValue(taskSortFields[s.Field], s, t))
+		c.Values = append(c.Values, raw)
+	}
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string, sorts []TaskSort) (*TaskCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c TaskCursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.Sort != sortSignature(sorts) || len(c.Values) != len(sorts) || c.ID == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// cursorValue is the value t sorts by, with the NULL sentinel applied.
+func cursorValue(f sortField, s TaskSort, t *Task) interface{} {
+	v := f.value(t)
+	if !f.nullable {
+		return v
+	}
+	if ts := v.(*time.Time); ts != nil && !ts.IsZero() {
+		return *ts
+	}
+	if s.Desc {
+		return nullsLastDesc
+	}
+	return nullsLastAsc
+}
+
+// decodeValue turns a cursor value back into the type its column compares
+// against.
+func decodeValue(f sortField, raw json.RawMessage) (interface{}, error) {
+	switch f.value(&Task{}).(type) {
+	case *time.Time, time.Time:
+		var t time.Time
+		err := json.Unmarshal(raw, &t)
+		return t, err
+	case int:
+		var n int
+		err := json.Unmarshal(raw, &n)
+		return n, err
+	default:
+		var s string
+		err := json.Unmarshal(raw, &s)
+		return s, err
+	}
+}
+
+func sortExpr(f sortField, s TaskSort) string {
+	if !f.nullable {
+		return f.column
+	}
+	sentinel := nullsLastAsc
+	if s.Desc {
+		sentinel = nullsLastDesc
+	}
+	return fmt.Sprintf("COALESCE(%s, '%s'::timestamptz)", f.column, sentinel.Format(time.RFC3339))
+}
+
+// filterScope applies the query filters; the cursor and ordering are left
+// out so the same scope also counts the total.
+func (q *TaskQuery) filterScope(userId uuid.UUID) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("tasks.user_id = ?", userId)
+
+		if len(q.Statuses) > 0 {
+			db = db.Where("tasks.status IN ?", q.Statuses)
+		}
+		if len(q.Types) > 0 {
+			db = db.Where("tasks.type IN ?", q.Types)
+		}
+		if len(q.Priorities) > 0 {
+			db = db.Where("tasks.priority IN ?", q.Priorities)
+		}
+		if q.DueFrom != nil {
+			db = db.Where("tasks.due_date >= ?", q.DueFrom.Time)
+		}
+		if q.DueTo != nil {
+			db = db.Where("tasks.due_date <= ?", q.DueTo.Time)
+		}
+		if q.ProjectID != nil {
+			db = db.Where("tasks.project_id = ?", *q.ProjectID)
+		}
+		if q.StudyTopicID != nil {
+			db = db.Where("tasks.study_topic_id = ?", *q.StudyTopicID)
+		}
+		if len(q.TagIDs) > 0 {
+			db = db.Where("tasks.id IN (SELECT task_id FROM task_tags WHERE tag_id IN ?)", q.TagIDs)
+		}
+		if q.Search != "" {
+			pattern := "%" + escapeLike(q.Search) + "%"
+			db = db.Where("(tasks.name ILIKE ? OR tasks.description ILIKE ?)", pattern, pattern)
+		}
+		if q.Overdue != nil {
+			// Mirrors dueCutoff: all-day tasks are due until the end of the day.
+			overdue := "tasks.status <> 'DONE' AND tasks.due_date IS NOT NULL AND " +
+				"(CASE WHEN tasks.all_day THEN tasks.due_date + INTERVAL '1 day' ELSE tasks.due_date END) < ?"
+			if *q.Overdue {
+				db = db.Where(overdue, q.Now)
+			} else {
+				db = db.Not(overdue, q.Now)
+			}
+		}
+
+		return db
+	}
+}
+
+// pageScope orders by the requested fields with the ID as tiebreaker and,
+// given a cursor, keeps only the rows after it (keyset pagination).
+func (q *TaskQuery) pageScope() (func(db *gorm.DB) *gorm.DB, error) {
+	var (
+		orders, exprs, ops []string
+		args               []interface{}
+	)
+	for i, s := range q.Sort {
+		f := taskSortFields[s.Field]
+		expr := sortExpr(f, s)
+		order, op := expr, ">"
+		if s.Desc {
+			order, op = expr+" DESC", "<"
+		}
+		orders = append(orders, order)
+		if q.Cursor != nil {
+			exprs = append(exprs, expr)
+			ops = append(ops, op)
+			v, err := decodeValue(f, q.Cursor.Values[i])
+			if err != nil {
+				return nil, ErrInvalidCursor
+			}
+			args = append(args, v)
+		}
+	}
+	orders = append(orders, "tasks.id")
+
+	var where string
+	if q.Cursor != nil {
+		exprs = append(exprs, "tasks.id")
+		ops = append(ops, ">")
+		where, args = keysetCondition(exprs, ops, append(args, q.Cursor.ID))
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		if where != "" {
+			db = db.Where(where, args...)
+		}
+		return db.Order(strings.Join(orders, ", ")).Limit(q.Limit + 1)
+	}, nil
+}
+
+// keysetCondition expands (a, b, c) > (x, y, z) for mixed directions:
+// a > x OR (a = x AND b > y) OR (a = x AND b = y AND c > z).
+func keysetCondition(exprs, ops []string, values []interface{}) (string, []interface{}) {
+	var (
+		terms []string
+		args  []interface{}
+	)
+	for i := range exprs {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, exprs[j]+" = ?")
+			args = append(args, values[j])
+		}
+		parts = append(parts, exprs[i]+" "+ops[i]+" ?")
+		args = append(args, values[i])
+		terms = append(terms, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(terms, " OR ") + ")", args
+}
+
+func priorityRank(p TaskPriority) int {
+	switch p {
+	case HIGH:
+		return 3
+	case MEDIUM:
+		return 2
+	case LOW:
+		return 1
+	}
+	return 0
+}
+
+func statusRank(s TaskStatus) int {
+	switch s {
+	case TODO:
+		return 1
+	case IN_PROGRESS:
+		return 2
+	case DONE:
+		return 3
+	}
+	return 0
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func listParam(values url.Values, key string) []string {
+	var result []string
+	for _, v := range values[key] {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+	return result
+}
+
+func dateParam(values url.Values, key string) (*util.LocalDateTime, error) {
+	v := values.Get(key)
+	if v == "" {
+		return nil, nil
+	}
+	ldt, err := util.ParseLocalDateTime(v)
+	if err != nil {
+		return nil, ErrInvalidTaskFilter
+	}
+	return &ldt, nil
+}
+
+func uuidParam(values url.Values, key string) (*uuid.UUID, error) {
+	v := values.Get(key)
+	if v == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(v)
+	if err != nil {
+		return nil, ErrInvalidTaskFilter
+	}
+	return &id, nil
+}
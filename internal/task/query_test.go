@@ -0,0 +1,193 @@
+package task_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	"github.com/saulo-duarte/chronos-lambda/internal/user"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+)
+
+func TestParseTaskQuery(t *testing.T) {
+	t.Run("ParsesFiltersAndSort", func(t *testing.T) {
+		projectID := uuid.New()
+		tagIDs := []uuid.UUID{uuid.New(), uuid.New()}
+		values := url.Values{
+			"tag":       {tagIDs[0].String() + "," + tagIDs[1].String()},
+			"status":    {"todo,IN_PROGRESS"},
+			"type":      {"STUDY", "event"},
+			"priority":  {"HIGH"},
+			"dueFrom":   {"2025-03-01"},
+			"dueTo":     {"2025-03-31T18:00:00"},
+			"overdue":   {"false"},
+			"projectId": {projectID.String()},
+			"search":    {"  prova  "},
+			"sort":      {"-priority,dueDate"},
+			"limit":     {"1000"},
+		}
+
+		q, err := task.ParseTaskQuery(values)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if len(q.Statuses) != 2 || q.Statuses[0] != task.TODO || q.Statuses[1] != task.IN_PROGRESS {
+			t.Errorf("Status incorretos: %v", q.Statuses)
+		}
+		if len(q.Types) != 2 || q.Types[1] != task.EVENT {
+			t.Errorf("Tipos incorretos: %v", q.Types)
+		}
+		if q.DueFrom == nil || q.DueTo == nil || q.DueTo.Hour() != 18 {
+			t.Errorf("Intervalo de datas incorreto: %v - %v", q.DueFrom, q.DueTo)
+		}
+		if q.Overdue == nil || *q.Overdue {
+			t.Error("Filtro de atraso deveria ser falso")
+		}
+		if q.ProjectID == nil || *q.ProjectID != projectID {
+			t.Error("Projeto incorreto")
+		}
+		if len(q.TagIDs) != 2 || q.TagIDs[1] != tagIDs[1] {
+			t.Errorf("Etiquetas incorretas: %v", q.TagIDs)
+		}
+		if q.Search != "prova" {
+			t.Errorf("Busca incorreta: %q", q.Search)
+		}
+		expectedSort := []task.TaskSort{{Field: "priority", Desc: true}, {Field: "dueDate"}}
+		if len(q.Sort) != 2 || q.Sort[0] != expectedSort[0] || q.Sort[1] != expectedSort[1] {
+			t.Errorf("Ordenação incorreta: %v", q.Sort)
+		}
+		if q.Limit != task.MaxTaskPageSize {
+			t.Errorf("Limite deveria ser %d, recebido %d", task.MaxTaskPageSize, q.Limit)
+		}
+	})
+
+	t.Run("UsesDefaults", func(t *testing.T) {
+		q, err := task.ParseTaskQuery(url.Values{})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if q.Limit != task.DefaultTaskPageSize || len(q.Sort) == 0 || q.Cursor != nil {
+			t.Errorf("Padrões incorretos: %+v", q)
+		}
+	})
+
+	invalid := map[string]struct {
+		values url.Values
+		err    error
+	}{
+		"UnknownStatus":  {url.Values{"status": {"ARCHIVED"}}, task.ErrInvalidTaskFilter},
+		"InvalidDate":    {url.Values{"dueFrom": {"amanhã"}}, task.ErrInvalidTaskFilter},
+		"InvalidProject": {url.Values{"projectId": {"123"}}, task.ErrInvalidTaskFilter},
+		"InvalidTag":     {url.Values{"tag": {"urgente"}}, task.ErrInvalidTaskFilter},
+		"ZeroLimit":      {url.Values{"limit": {"0"}}, task.ErrInvalidTaskFilter},
+		"UnknownSort":    {url.Values{"sort": {"color"}}, task.ErrInvalidTaskSort},
+		"RepeatedSort":   {url.Values{"sort": {"name,-name"}}, task.ErrInvalidTaskSort},
+		"GarbageCursor":  {url.Values{"cursor": {"%%%"}}, task.ErrInvalidCursor},
+	}
+	for name, tc := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if _, err := task.ParseTaskQuery(tc.values); !errors.Is(err, tc.err) {
+				t.Errorf("Esperado erro %v, recebido %v", tc.err, err)
+			}
+		})
+	}
+}
+
+type fakeQueryRepo struct {
+	task.TaskRepository
+	tasks []*task.Task
+	query *task.TaskQuery
+}
+
+func (f *fakeQueryRepo) Query(userId uuid.UUID, q *task.TaskQuery) ([]*task.Task, int64, error) {
+	f.query = q
+	if len(f.tasks) > q.Limit+1 {
+		return f.tasks[:q.Limit+1], int64(len(f.tasks)), nil
+	}
+	return f.tasks, int64(len(f.tasks)), nil
+}
+
+func (f *fakeQueryRepo) CountChildren(ids []uuid.UUID) (map[uuid.UUID]task.ChildCounts, error) {
+	return map[uuid.UUID]task.ChildCounts{}, nil
+}
+
+func (f *fakeQueryRepo) ListOpenBlockers(ids []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	return map[uuid.UUID][]uuid.UUID{}, nil
+}
+
+type fakeLocationUserRepo struct {
+	user.UserRepository
+}
+
+func (f *fakeLocationUserRepo) GetByID(id string) (*user.User, error) {
+	return &user.User{ID: uuid.MustParse(id), Timezone: "Europe/Lisbon"}, nil
+}
+
+func TestQueryTasks(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.UserDataKeyID, userID.String())
+	ctx = context.WithValue(ctx, auth.UserDataKeyRole, "user")
+
+	due := util.LocalDateTime{Time: time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)}
+	repo := &fakeQueryRepo{tasks: []*task.Task{
+		{ID: uuid.New(), Name: "A", DueDate: &due, CreatedAt: time.Now()},
+		{ID: uuid.New(), Name: "B", CreatedAt: time.Now()},
+		{ID: uuid.New(), Name: "C", CreatedAt: time.Now()},
+	}}
+	svc := task.NewService(repo, nil, &fakeLocationUserRepo{}, nil)
+
+	t.Run("ReturnsCursorWhenMoreTasksExist", func(t *testing.T) {
+		q, _ := task.ParseTaskQuery(url.Values{"limit": {"2"}, "dueTo": {"2025-03-10"}})
+		page, err := svc.QueryTasks(ctx, q)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if len(page.Items) != 2 || page.Total != 3 || page.NextCursor == "" {
+			t.Fatalf("Página incorreta: %d itens, total %d, cursor %q", len(page.Items), page.Total, page.NextCursor)
+		}
+		if page.Items[0].DueDate.Location().String() != "Europe/Lisbon" {
+			t.Error("Datas deveriam estar no fuso do usuário")
+		}
+
+		dueTo := repo.query.DueTo.Time
+		if dueTo.Location().String() != "Europe/Lisbon" || dueTo.Day() != 10 || dueTo.Hour() != 23 {
+			t.Errorf("Data final deveria cobrir o dia inteiro no fuso do usuário: %v", dueTo)
+		}
+
+		next, err := task.ParseTaskQuery(url.Values{"limit": {"2"}, "cursor": {page.NextCursor}})
+		if err != nil {
+			t.Fatalf("Cursor deveria ser aceito: %v", err)
+		}
+		if next.Cursor.ID != page.Items[1].ID {
+			t.Error("Cursor deveria apontar para o último item da página")
+		}
+	})
+
+	t.Run("RejectsCursorFromAnotherSort", func(t *testing.T) {
+		q, _ := task.ParseTaskQuery(url.Values{"limit": {"1"}})
+		page, err := svc.QueryTasks(ctx, q)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		_, err = task.ParseTaskQuery(url.Values{"sort": {"name"}, "cursor": {page.NextCursor}})
+		if !errors.Is(err, task.ErrInvalidCursor) {
+			t.Errorf("Cursor de outra ordenação deveria ser rejeitado, erro: %v", err)
+		}
+	})
+
+	t.Run("LastPageHasNoCursor", func(t *testing.T) {
+		q, _ := task.ParseTaskQuery(url.Values{})
+		page, err := svc.QueryTasks(ctx, q)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if len(page.Items) != 3 || page.NextCursor != "" {
+			t.Errorf("Última página não deveria ter cursor: %q", page.NextCursor)
+		}
+	})
+}
@@ -0,0 +1,302 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+)
+
+var (
+	ErrInvalidRecurrence     = errors.New("invalid recurrence rule")
+	ErrRecurrenceWithoutDate = errors.New("recurring tasks require a startDate or dueDate")
+)
+
+// maxRecurrenceIterations bounds the expansion loop so a rule without
+// COUNT/UNTIL can never spin forever.
+const maxRecurrenceIterations = 100000
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// RecurrenceRule is a subset of the RFC 5545 RRULE: FREQ, INTERVAL, BYDAY,
+// COUNT and UNTIL.
+type RecurrenceRule struct {
+	Frequency RecurrenceFrequency `json:"frequency"`
+	Interval  int                 `json:"interval,omitempty"`
+	ByWeekday []string            `json:"byWeekday,omitempty"`
+	Count     *int                `json:"count,omitempty"`
+	Until     *util.LocalDateTime `json:"until,omitempty"`
+}
+
+func (r *RecurrenceRule) Validate() error {
+	switch r.Frequency {
+	case DAILY, WEEKLY, MONTHLY:
+	default:
+		return fmt.Errorf("%w: unknown frequency %q", ErrInvalidRecurrence, r.Frequency)
+	}
+
+	if r.Interval < 0 {
+		return fmt.Errorf("%w: interval must be positive", ErrInvalidRecurrence)
+	}
+
+	for _, day := range r.ByWeekday {
+		if _, ok := weekdayCodes[strings.ToUpper(day)]; !ok {
+			return fmt.Errorf("%w: unknown weekday %q", ErrInvalidRecurrence, day)
+		}
+	}
+
+	if r.Frequency == MONTHLY && len(r.ByWeekday) > 0 {
+		return fmt.Errorf("%w: byWeekday is not supported for MONTHLY rules", ErrInvalidRecurrence)
+	}
+
+	if r.Count != nil && r.Until != nil {
+		return fmt.Errorf("%w: count and until are mutually exclusive", ErrInvalidRecurrence)
+	}
+
+	if r.Count != nil && *r.Count <= 0 {
+		return fmt.Errorf("%w: count must be positive", ErrInvalidRecurrence)
+	}
+
+	return nil
+}
+
+// RRULE renders the rule in the format expected by Google Calendar's
+// Event.Recurrence field, for a series anchored at a date-time.
+func (r *RecurrenceRule) RRULE() string {
+	return r.RRULEFor(false)
+}
+
+// RRULEFor renders the rule for a series that is all-day or not. RFC 5545
+// requires UNTIL to share the value type of DTSTART, so an all-day series
+// gets a DATE and a timed one a UTC date-time.
+func (r *RecurrenceRule) RRULEFor(allDay bool) string {
+	parts := []string{"FREQ=" + string(r.Frequency)}
+
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	if days := r.weekdays(); len(days) > 0 {
+		codes := make([]string, 0, len(days))
+		for _, d := range days {
+			codes = append(codes, weekdayCode(d))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+
+	if r.Count != nil {
+		parts = append(parts, "COUNT="+strconv.Itoa(*r.Count))
+	}
+
+	if r.Until != nil && !r.Until.IsZero() {
+		if allDay {
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+		} else {
+			parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+		}
+	}
+
+	return "RRULE:" + strings.Join(parts, ";")
+}
+
+// ParseRRULE reads a rule in the format RRULE produces. Parts outside the
+// supported subset are rejected rather than silently dropped.
+func ParseRRULE(s string) (*RecurrenceRule, error) {
+	rule := &RecurrenceRule{}
+
+	for _, part := range strings.Split(strings.TrimPrefix(strings.TrimSpace(s), "RRULE:"), ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRecurrence, part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Frequency = RecurrenceFrequency(strings.ToUpper(value))
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: invalid interval %q", ErrInvalidRecurrence, value)
+			}
+			rule.Interval = n
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				rule.ByWeekday = append(rule.ByWeekday, strings.ToUpper(day))
+			}
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid count %q", ErrInvalidRecurrence, value)
+			}
+			rule.Count = &n
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid until %q", ErrInvalidRecurrence, value)
+			}
+			rule.Until = &util.LocalDateTime{Time: until}
+		case "WKST":
+			// Only changes results for weekly rules with BYDAY and INTERVAL > 1
+			// starting on a day other than Monday; the default is kept.
+		default:
+			return nil, fmt.Errorf("%w: %s is not supported", ErrInvalidRecurrence, key)
+		}
+	}
+
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// parseUntil accepts UTC, floating and date-only UNTIL values. A date covers
+// the whole day.
+func parseUntil(value string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102T150405", value, util.DefaultLocation()); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("20060102", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.Add(24*time.Hour - time.Second), nil
+}
+
+// Occurrences expands the rule anchored at anchor (the series' first start)
+// and returns the occurrence start times that fall within [from, to].
+// COUNT is always counted from the anchor, not from the window.
+func (r *RecurrenceRule) Occurrences(anchor, from, to time.Time) []time.Time {
+	var result []time.Time
+
+	interval := r.Interval
+	if interval <= 0 {
+		interval = 1
+	}
+
+	emitted := 0
+	emit := func(t time.Time) bool {
+		if t.Before(anchor) {
+			return true
+		}
+		if r.Until != nil && !r.Until.IsZero() && t.After(r.Until.Time) {
+			return false
+		}
+		if r.Count != nil && emitted >= *r.Count {
+			return false
+		}
+		if t.After(to) {
+			return false
+		}
+		emitted++
+		if !t.Before(from) {
+			result = append(result, t)
+		}
+		return true
+	}
+
+	switch r.Frequency {
+	case DAILY:
+		allowed := map[time.Weekday]bool{}
+		for _, d := range r.weekdays() {
+			allowed[d] = true
+		}
+		for i := 0; i < maxRecurrenceIterations; i++ {
+			t := anchor.AddDate(0, 0, i*interval)
+			// Checked before the weekday filter: an interval that never
+			// lands on an allowed day would otherwise run every iteration.
+			if t.After(to) || (r.Until != nil && !r.Until.IsZero() && t.After(r.Until.Time)) {
+				break
+			}
+			if len(allowed) > 0 && !allowed[t.Weekday()] {
+				continue
+			}
+			if !emit(t) {
+				break
+			}
+		}
+
+	case WEEKLY:
+		days := r.weekdays()
+		if len(days) == 0 {
+			days = []time.Weekday{anchor.Weekday()}
+		}
+		weekStart := anchor.AddDate(0, 0, -mondayOffset(anchor.Weekday()))
+		for w := 0; w < maxRecurrenceIterations; w++ {
+			base := weekStart.AddDate(0, 0, 7*w*interval)
+			for _, d := range days {
+				if !emit(base.AddDate(0, 0, mondayOffset(d))) {
+					return result
+				}
+			}
+		}
+
+	case MONTHLY:
+		for m := 0; m < maxRecurrenceIterations; m++ {
+			t := time.Date(
+				anchor.Year(), anchor.Month()+time.Month(m*interval), anchor.Day(),
+				anchor.Hour(), anchor.Minute(), anchor.Second(), anchor.Nanosecond(),
+				anchor.Location(),
+			)
+			// Months without this day (e.g. the 31st) are skipped, as in RFC 5545.
+			if t.Day() != anchor.Day() {
+				continue
+			}
+			if !emit(t) {
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+// IsOccurrence reports whether t is exactly one of the rule's occurrences.
+func (r *RecurrenceRule) IsOccurrence(anchor, t time.Time) bool {
+	occurrences := r.Occurrences(anchor, t, t)
+	return len(occurrences) == 1 && occurrences[0].Equal(t)
+}
+
+func (r *RecurrenceRule) weekdays() []time.Weekday {
+	seen := map[time.Weekday]bool{}
+	var days []time.Weekday
+	for _, code := range r.ByWeekday {
+		d, ok := weekdayCodes[strings.ToUpper(code)]
+		if !ok || seen[d] {
+			continue
+		}
+		seen[d] = true
+		days = append(days, d)
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return mondayOffset(days[i]) < mondayOffset(days[j])
+	})
+	return days
+}
+
+func mondayOffset(d time.Weekday) int {
+	return (int(d) + 6) % 7
+}
+
+func weekdayCode(d time.Weekday) string {
+	for code, wd := range weekdayCodes {
+		if wd == d {
+			return code
+		}
+	}
+	return ""
+}
@@ -0,0 +1,273 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/user"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+)
+
+var (
+	ErrTaskNotRecurring       = errors.New("task is not recurring")
+	ErrInvalidOccurrence      = errors.New("date is not an occurrence of this task")
+	ErrOccurrenceNotFound     = errors.New("occurrence not found")
+	ErrInvalidOccurrenceRange = errors.New("invalid occurrence range")
+)
+
+const maxOccurrenceRange = 366 * 24 * time.Hour
+
+type RecurrenceService interface {
+	ExpandOccurrences(ctx context.Context, from, to util.LocalDateTime) ([]*OccurrenceDTO, error)
+	CompleteOccurrence(ctx context.Context, taskID string, occurrenceDate util.LocalDateTime) (*TaskOccurrence, error)
+	ReopenOccurrence(ctx context.Context, taskID string, occurrenceDate util.LocalDateTime) error
+}
+
+type recurrenceService struct {
+	repo     TaskRepository
+	userRepo user.UserRepository
+}
+
+func NewRecurrenceService(repo TaskRepository, userRepo user.UserRepository) RecurrenceService {
+	return &recurrenceService{repo: repo, userRepo: userRepo}
+}
+
+// ExpandOccurrences lists occurrences between from and to, read as wall
+// clock in the user's time zone. Series are expanded in that zone so a
+// weekly 09:00 task stays at 09:00 across DST changes.
+func (s *recurrenceService) ExpandOccurrences(ctx context.Context, fromDate, toDate util.LocalDateTime) ([]*OccurrenceDTO, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := userLocation(ctx, s.userRepo, userID)
+	from, to := fromDate.Localize(loc).Time, toDate.Localize(loc).Time
+
+	if to.Before(from) || to.Sub(from) > maxOccurrenceRange {
+		return nil, ErrInvalidOccurrenceRange
+	}
+
+	tasks, err := s.repo.ListByUser(userID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list tasks for occurrence expansion")
+		return nil, err
+	}
+	localizeTasks(tasks, loc)
+
+	completions, err := s.repo.ListOccurrencesByUser(userID, from, to)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list task occurrences")
+		return nil, err
+	}
+
+	completed := make(map[uuid.UUID]map[int64]*TaskOccurrence)
+	for _, o := range completions {
+		if completed[o.TaskID] == nil {
+			completed[o.TaskID] = make(map[int64]*TaskOccurrence)
+		}
+		completed[o.TaskID][o.OccurrenceDate.Unix()] = o
+	}
+
+	occurrences := []*OccurrenceDTO{}
+	for _, t := range tasks {
+		anchor := occurrenceAnchor(t)
+		if anchor == nil {
+			continue
+		}
+
+		if t.Recurrence == nil {
+			if !anchor.Before(from) && !anchor.After(to) {
+				occurrences = append(occurrences, singleOccurrence(t, loc))
+			}
+			continue
+		}
+
+		for _, start := range t.Recurrence.Occurrences(*anchor, from, to) {
+			occurrences = append(occurrences, recurringOccurrence(t, *anchor, start, completed[t.ID][start.Unix()], loc))
+		}
+	}
+
+	sort.Slice(occurrences, func(i, j int) bool {
+		return occurrenceTime(occurrences[i]).Before(occurrenceTime(occurrences[j]))
+	})
+
+	return occurrences, nil
+}
+
+func (s *recurrenceService) CompleteOccurrence(ctx context.Context, taskID string, date util.LocalDateTime) (*TaskOccurrence, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := userLocation(ctx, s.userRepo, userID)
+	occurrenceDate := date.Localize(loc).Time
+
+	t, err := s.getRecurringTask(ctx, taskID, userID, occurrenceDate, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	occurrence := &TaskOccurrence{
+		ID:             uuid.New(),
+		TaskID:         t.ID,
+		UserID:         userID,
+		OccurrenceDate: occurrenceDate,
+		Status:         DONE,
+		DoneAt:         &now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := s.repo.UpsertOccurrence(occurrence); err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to complete task occurrence")
+		return nil, err
+	}
+
+	config.WithContext(ctx).WithField("task_id", t.ID).Infof("Occurrence %s completed", occurrenceDate.Format(time.RFC3339))
+	return occurrence, nil
+}
+
+func (s *recurrenceService) ReopenOccurrence(ctx context.Context, taskID string, date util.LocalDateTime) error {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	loc := userLocation(ctx, s.userRepo, userID)
+	occurrenceDate := date.Localize(loc).Time
+
+	t, err := s.getRecurringTask(ctx, taskID, userID, occurrenceDate, loc)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteOccurrence(t.ID, userID, occurrenceDate); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return ErrOccurrenceNotFound
+		}
+		config.WithContext(ctx).WithError(err).Error("Failed to reopen task occurrence")
+		return err
+	}
+
+	config.WithContext(ctx).WithField("task_id", t.ID).Infof("Occurrence %s reopened", occurrenceDate.Format(time.RFC3339))
+	return nil
+}
+
+// ============= Helper Methods =============
+
+func (s *recurrenceService) getUserID(ctx context.Context) (uuid.UUID, error) {
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Warn("Unauthorized access attempt")
+		return uuid.Nil, ErrUnauthorized
+	}
+	return uuid.MustParse(claims.UserID), nil
+}
+
+func (s *recurrenceService) getRecurringTask(ctx context.Context, taskID string, userID uuid.UUID, occurrenceDate time.Time, loc *time.Location) (*Task, error) {
+	id, err := uuid.Parse(taskID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Warnf("Invalid ID: %s", taskID)
+		return nil, ErrInvalidID
+	}
+
+	t, err := s.repo.FindByIdAndUserId(id, userID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrTaskNotFound
+		}
+		config.WithContext(ctx).WithError(err).Error("Error finding task")
+		return nil, err
+	}
+
+	if t.Recurrence == nil {
+		return nil, ErrTaskNotRecurring
+	}
+	localizeTasks([]*Task{t}, loc)
+
+	anchor := occurrenceAnchor(t)
+	if anchor == nil || !t.Recurrence.IsOccurrence(*anchor, occurrenceDate) {
+		return nil, ErrInvalidOccurrence
+	}
+
+	return t, nil
+}
+
+// occurrenceAnchor is the series start: StartDate when present, DueDate otherwise.
+func occurrenceAnchor(t *Task) *time.Time {
+	if t.StartDate != nil && !t.StartDate.IsZero() {
+		return util.ToTimePtr(t.StartDate)
+	}
+	if t.DueDate != nil && !t.DueDate.IsZero() {
+		return util.ToTimePtr(t.DueDate)
+	}
+	return nil
+}
+
+func singleOccurrence(t *Task, loc *time.Location) *OccurrenceDTO {
+	o := &OccurrenceDTO{
+		TaskID:      t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		Type:        t.Type,
+		Priority:    t.Priority,
+		Status:      t.Status,
+		StartDate:   t.StartDate,
+		DueDate:     t.DueDate,
+		AllDay:      t.AllDay,
+	}
+	if t.Status == DONE && !t.DoneAt.IsZero() {
+		o.DoneAt = &util.LocalDateTime{Time: t.DoneAt.In(loc)}
+	}
+	return o
+}
+
+func recurringOccurrence(t *Task, anchor, start time.Time, completion *TaskOccurrence, loc *time.Location) *OccurrenceDTO {
+	o := &OccurrenceDTO{
+		TaskID:      t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		Type:        t.Type,
+		Priority:    t.Priority,
+		Status:      TODO,
+		AllDay:      t.AllDay,
+		Recurring:   true,
+	}
+
+	switch {
+	case t.StartDate != nil && !t.StartDate.IsZero():
+		o.StartDate = &util.LocalDateTime{Time: start}
+		if t.DueDate != nil && !t.DueDate.IsZero() {
+			o.DueDate = &util.LocalDateTime{Time: start.Add(t.DueDate.Sub(anchor))}
+		}
+	default:
+		o.DueDate = &util.LocalDateTime{Time: start}
+	}
+
+	if completion != nil {
+		o.Status = completion.Status
+		if completion.DoneAt != nil {
+			o.DoneAt = &util.LocalDateTime{Time: completion.DoneAt.In(loc)}
+		}
+	}
+
+	return o
+}
+
+func occurrenceTime(o *OccurrenceDTO) time.Time {
+	if o.StartDate != nil {
+		return o.StartDate.Time
+	}
+	if o.DueDate != nil {
+		return o.DueDate.Time
+	}
+	return time.Time{}
+}
@@ -0,0 +1,180 @@
+package task_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestRecurrenceOccurrences(t *testing.T) {
+	loc := util.DefaultLocation()
+	// Segunda-feira, 6 de janeiro de 2025, 09:00
+	anchor := time.Date(2025, 1, 6, 9, 0, 0, 0, loc)
+	windowEnd := anchor.AddDate(1, 0, 0)
+
+	t.Run("WeeklyByWeekdayWithCount", func(t *testing.T) {
+		rule := &task.RecurrenceRule{Frequency: task.WEEKLY, ByWeekday: []string{"WE", "MO"}, Count: intPtr(4)}
+
+		got := rule.Occurrences(anchor, anchor, windowEnd)
+		expected := []time.Time{
+			anchor,
+			anchor.AddDate(0, 0, 2),
+			anchor.AddDate(0, 0, 7),
+			anchor.AddDate(0, 0, 9),
+		}
+
+		if len(got) != len(expected) {
+			t.Fatalf("Quantidade de ocorrências incorreta. Esperado: %d, Recebido: %d", len(expected), len(got))
+		}
+		for i := range expected {
+			if !got[i].Equal(expected[i]) {
+				t.Errorf("Ocorrência %d incorreta. Esperado: %v, Recebido: %v", i, expected[i], got[i])
+			}
+		}
+	})
+
+	t.Run("CountIsAnchoredAtSeriesStart", func(t *testing.T) {
+		rule := &task.RecurrenceRule{Frequency: task.DAILY, Count: intPtr(5)}
+
+		got := rule.Occurrences(anchor, anchor.AddDate(0, 0, 3), windowEnd)
+		if len(got) != 2 {
+			t.Fatalf("Esperado 2 ocorrências restantes na janela, Recebido: %d", len(got))
+		}
+	})
+
+	t.Run("DailyRestrictedToWeekdays", func(t *testing.T) {
+		rule := &task.RecurrenceRule{Frequency: task.DAILY, ByWeekday: []string{"MO", "TU", "WE", "TH", "FR"}}
+
+		got := rule.Occurrences(anchor, anchor, anchor.AddDate(0, 0, 13))
+		if len(got) != 10 {
+			t.Fatalf("Esperado 10 dias úteis em duas semanas, Recebido: %d", len(got))
+		}
+		for _, o := range got {
+			if o.Weekday() == time.Saturday || o.Weekday() == time.Sunday {
+				t.Errorf("Ocorrência em fim de semana: %v", o)
+			}
+		}
+	})
+
+	t.Run("DailyIntervalNeverOnWeekdayStopsAtWindow", func(t *testing.T) {
+		// Every 7 days from a Monday never lands on a Tuesday.
+		rule := &task.RecurrenceRule{Frequency: task.DAILY, Interval: 7, ByWeekday: []string{"TU"}}
+
+		if got := rule.Occurrences(anchor, anchor, anchor.AddDate(0, 1, 0)); len(got) != 0 {
+			t.Errorf("Nenhuma ocorrência esperada, Recebido: %v", got)
+		}
+	})
+
+	t.Run("MonthlySkipsMissingDays", func(t *testing.T) {
+		start := time.Date(2025, 1, 31, 9, 0, 0, 0, loc)
+		rule := &task.RecurrenceRule{Frequency: task.MONTHLY}
+
+		got := rule.Occurrences(start, start, time.Date(2025, 6, 1, 0, 0, 0, 0, loc))
+		var months []time.Month
+		for _, o := range got {
+			months = append(months, o.Month())
+		}
+		expected := []time.Month{time.January, time.March, time.May}
+		if len(months) != len(expected) {
+			t.Fatalf("Meses incorretos. Esperado: %v, Recebido: %v", expected, months)
+		}
+		for i := range expected {
+			if months[i] != expected[i] {
+				t.Errorf("Meses incorretos. Esperado: %v, Recebido: %v", expected, months)
+			}
+		}
+	})
+
+	t.Run("UntilIsInclusive", func(t *testing.T) {
+		until := util.LocalDateTime{Time: anchor.AddDate(0, 0, 14)}
+		rule := &task.RecurrenceRule{Frequency: task.WEEKLY, Interval: 2, Until: &until}
+
+		got := rule.Occurrences(anchor, anchor, windowEnd)
+		if len(got) != 2 {
+			t.Fatalf("Esperado 2 ocorrências até a data limite, Recebido: %d", len(got))
+		}
+	})
+}
+
+func TestRecurrenceRRULE(t *testing.T) {
+	rule := &task.RecurrenceRule{Frequency: task.WEEKLY, Interval: 2, ByWeekday: []string{"fr", "MO"}, Count: intPtr(6)}
+
+	expected := "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=6"
+	if got := rule.RRULE(); got != expected {
+		t.Errorf("RRULE incorreta. Esperado: %s, Recebido: %s", expected, got)
+	}
+
+	t.Run("UntilMatchesStartValueType", func(t *testing.T) {
+		until := util.LocalDateTime{Time: time.Date(2025, 3, 10, 23, 0, 0, 0, util.DefaultLocation())}
+		rule := &task.RecurrenceRule{Frequency: task.DAILY, Until: &until}
+
+		if got := rule.RRULEFor(true); got != "RRULE:FREQ=DAILY;UNTIL=20250310" {
+			t.Errorf("UNTIL de série de dia inteiro deveria ser uma data: %s", got)
+		}
+		expected := "RRULE:FREQ=DAILY;UNTIL=" + until.UTC().Format("20060102T150405Z")
+		if got := rule.RRULEFor(false); got != expected {
+			t.Errorf("UNTIL de série com horário incorreto. Esperado: %s, Recebido: %s", expected, got)
+		}
+	})
+}
+
+func TestRecurrenceValidate(t *testing.T) {
+	until := util.LocalDateTime{Time: time.Now()}
+
+	invalid := map[string]*task.RecurrenceRule{
+		"UnknownFrequency": {Frequency: "YEARLY"},
+		"UnknownWeekday":   {Frequency: task.WEEKLY, ByWeekday: []string{"XX"}},
+		"MonthlyByWeekday": {Frequency: task.MONTHLY, ByWeekday: []string{"MO"}},
+		"CountAndUntil":    {Frequency: task.DAILY, Count: intPtr(3), Until: &until},
+		"ZeroCount":        {Frequency: task.DAILY, Count: intPtr(0)},
+	}
+
+	for name, rule := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if err := rule.Validate(); err == nil {
+				t.Errorf("Validate deveria ter falhado para %s", name)
+			}
+		})
+	}
+}
+
+func TestParseRRULE(t *testing.T) {
+	t.Run("RoundTripsRRULE", func(t *testing.T) {
+		rule, err := task.ParseRRULE("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=6")
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if got := rule.RRULE(); got != "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=6" {
+			t.Errorf("RRULE incorreta após leitura: %s", got)
+		}
+	})
+
+	t.Run("DateUntilCoversWholeDay", func(t *testing.T) {
+		rule, err := task.ParseRRULE("FREQ=DAILY;UNTIL=20250310;WKST=SU")
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		expected := time.Date(2025, 3, 10, 23, 59, 59, 0, time.UTC)
+		if !rule.Until.Time.Equal(expected) {
+			t.Errorf("UNTIL incorreto. Esperado: %v, Recebido: %v", expected, rule.Until.Time)
+		}
+	})
+
+	unsupported := map[string]string{
+		"Yearly":     "FREQ=YEARLY",
+		"ByMonthDay": "FREQ=MONTHLY;BYMONTHDAY=15",
+		"Malformed":  "FREQ",
+		"BadCount":   "FREQ=DAILY;COUNT=x",
+	}
+	for name, value := range unsupported {
+		t.Run(name, func(t *testing.T) {
+			if _, err := task.ParseRRULE(value); err == nil {
+				t.Errorf("ParseRRULE deveria ter falhado para %s", value)
+			}
+		})
+	}
+}
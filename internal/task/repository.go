@@ -2,9 +2,13 @@ package task
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/activity"
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
@@ -15,10 +19,72 @@ type TaskRepository interface {
 	Create(t *Task) error
 	FindByIdAndUserId(id, userId uuid.UUID) (*Task, error)
 	ListByUser(userId uuid.UUID) ([]*Task, error)
+	// Query returns up to q.Limit+1 tasks, the extra one signalling another
+	// page, and the number of tasks matching the filters.
+	Query(userId uuid.UUID, q *TaskQuery) ([]*Task, int64, error)
 	ListByProjectAndUser(projectId, userId uuid.UUID) ([]*Task, error)
 	ListByStudyTopicAndUser(topicId, userId uuid.UUID) ([]*Task, error)
+	ListByStudySubjectAndUser(subjectId, userId uuid.UUID) ([]*Task, error)
 	Update(t *Task) error
+	// Delete moves the task and its subtasks to the trash.
 	Delete(id, userId uuid.UUID) error
+	// Restore takes the task out of the trash together with the subtasks
+	// trashed with it and returns them all, the task first. Their calendar
+	// event IDs are cleared, since the events were removed on delete.
+	Restore(id, userId uuid.UUID) ([]*Task, error)
+	// TrashParent moves a project or study subject to the trash together
+	// with its tasks and their subtasks, stamping them all with at, and
+	// returns the tasks. A subject's quizzes go with it as well.
+	TrashParent(parent TrashParent, id, userId uuid.UUID, at time.Time) ([]*Task, error)
+	// RestoreParent undoes TrashParent, leaving alone the children trashed
+	// on their own, and returns the restored tasks with their calendar
+	// event IDs cleared.
+	RestoreParent(parent TrashParent, id, userId uuid.UUID) ([]*Task, error)
+
+	ListSubtasks(parentId, userId uuid.UUID) ([]*Task, error)
+	// ListDescendants returns every task below id, at any depth.
+	ListDescendants(id, userId uuid.UUID) ([]*Task, error)
+	CountChildren(taskIds []uuid.UUID) (map[uuid.UUID]ChildCounts, error)
+
+	ListChecklistItems(taskId uuid.UUID) ([]*ChecklistItem, error)
+	FindChecklistItem(id, taskId uuid.UUID) (*ChecklistItem, error)
+	CreateChecklistItem(item *ChecklistItem) error
+	UpdateChecklistItem(item *ChecklistItem) error
+	DeleteChecklistItem(id, taskId uuid.UUID) error
+	SetChecklistPositions(taskId uuid.UUID, itemIds []uuid.UUID) error
+
+	CreateDependency(d *TaskDependency) error
+	DeleteDependency(blockerId, blockedId, userId uuid.UUID) error
+	// LockDependencies serializes the user's dependency changes until the
+	// surrounding transaction ends, so concurrent cycle checks see each
+	// other's edges.
+	LockDependencies(userId uuid.UUID) error
+	ListDependencies(userId uuid.UUID) ([]*TaskDependency, error)
+	ListBlockers(taskId, userId uuid.UUID) ([]*Task, error)
+	ListBlockedTasks(taskId, userId uuid.UUID) ([]*Task, error)
+	// ListOpenBlockers maps each task to its blockers that are not done yet.
+	ListOpenBlockers(taskIds []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error)
+
+	RecordStatusChange(c *TaskStatusChange) error
+	ListStatusHistory(taskId uuid.UUID) ([]*TaskStatusChange, error)
+	RecordActivity(e *activity.Entry) error
+
+	FindByCalendarEventID(userId uuid.UUID, eventID string) (*Task, error)
+	FindByICalUIDs(userId uuid.UUID, uids []string) (map[string]*Task, error)
+	MarkCalendarSynced(id uuid.UUID, calendarID, eventID string, syncedAt time.Time) error
+
+	// Transaction runs fn with a repository bound to a single DB transaction.
+	Transaction(fn func(tx TaskRepository) error) error
+	EnqueueCalendarSync(e *CalendarOutboxEntry) error
+	ClaimCalendarSyncs(now time.Time, lease time.Duration, limit int) ([]*CalendarOutboxEntry, error)
+	CompleteCalendarSync(e *CalendarOutboxEntry, processedAt time.Time) error
+	FailCalendarSync(e *CalendarOutboxEntry) error
+	ListUnsyncedCalendarSyncs(userId uuid.UUID) ([]*CalendarOutboxEntry, error)
+	PurgeCalendarSyncs(before time.Time) (int64, error)
+
+	UpsertOccurrence(o *TaskOccurrence) error
+	DeleteOccurrence(taskId, userId uuid.UUID, occurrenceDate time.Time) error
+	ListOccurrencesByUser(userId uuid.UUID, from, to time.Time) ([]*TaskOccurrence, error)
 }
 
 type taskRepository struct {
@@ -29,13 +95,15 @@ func NewRepository(db *gorm.DB) TaskRepository {
 	return &taskRepository{db: db}
 }
 
+// Create and Update leave the tags alone; they are set through the tag
+// package.
 func (r *taskRepository) Create(t *Task) error {
-	return r.db.Create(t).Error
+	return r.db.Omit("Tags").Create(t).Error
 }
 
 func (r *taskRepository) FindByIdAndUserId(id, userId uuid.UUID) (*Task, error) {
 	var t Task
-	if err := r.db.Where("id = ? AND user_id = ?", id, userId).First(&t).Error; err != nil {
+	if err := r.db.Preload("Tags").Where("id = ? AND user_id = ?", id, userId).First(&t).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrNotFound
 		}
@@ -46,15 +114,37 @@ func (r *taskRepository) FindByIdAndUserId(id, userId uuid.UUID) (*Task, error)
 
 func (r *taskRepository) ListByUser(userId uuid.UUID) ([]*Task, error) {
 	var tasks []*Task
-	if err := r.db.Preload("Project").Preload("StudyTopic").Where("user_id = ?", userId).Find(&tasks).Error; err != nil {
+	if err := r.db.Preload("Project").Preload("StudyTopic").Preload("Tags").Where("user_id = ?", userId).Find(&tasks).Error; err != nil {
 		return nil, err
 	}
 	return tasks, nil
 }
 
+// Query loads the page and its project and study topic in one joined query;
+// the total is counted with the same filters but without the cursor.
+func (r *taskRepository) Query(userId uuid.UUID, q *TaskQuery) ([]*Task, int64, error) {
+	page, err := q.pageScope()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := r.db.Model(&Task{}).Scopes(q.filterScope(userId)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var tasks []*Task
+	if err := r.db.Joins("Project").Joins("StudyTopic").Preload("Tags").
+		Scopes(q.filterScope(userId), page).
+		Find(&tasks).Error; err != nil {
+		return nil, 0, err
+	}
+	return tasks, total, nil
+}
+
 func (r *taskRepository) ListByProjectAndUser(projectId, userId uuid.UUID) ([]*Task, error) {
 	var tasks []*Task
-	if err := r.db.Preload("Project").Preload("StudyTopic").Where("project_id = ? AND user_id = ?", projectId, userId).Find(&tasks).Error; err != nil {
+	if err := r.db.Preload("Project").Preload("StudyTopic").Preload("Tags").Where("project_id = ? AND user_id = ?", projectId, userId).Find(&tasks).Error; err != nil {
 		return nil, err
 	}
 	return tasks, nil
@@ -62,20 +152,524 @@ func (r *taskRepository) ListByProjectAndUser(projectId, userId uuid.UUID) ([]*T
 
 func (r *taskRepository) ListByStudyTopicAndUser(topicId, userId uuid.UUID) ([]*Task, error) {
 	var tasks []*Task
-	if err := r.db.Preload("Project").Preload("StudyTopic").Where("study_topic_id = ? AND user_id = ?", topicId, userId).Find(&tasks).Error; err != nil {
+	if err := r.db.Preload("Project").Preload("StudyTopic").Preload("Tags").Where("study_topic_id = ? AND user_id = ?", topicId, userId).Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (r *taskRepository) ListByStudySubjectAndUser(subjectId, userId uuid.UUID) ([]*Task, error) {
+	var tasks []*Task
+	if err := r.db.Preload("Project").Preload("StudyTopic").Preload("Tags").
+		Where("study_topic_id IN (?) AND user_id = ?",
+			r.db.Model(&studytopic.StudyTopic{}).Select("id").Where("subject_id = ?", subjectId), userId).
+		Find(&tasks).Error; err != nil {
 		return nil, err
 	}
 	return tasks, nil
 }
 
 func (r *taskRepository) Update(t *Task) error {
-	return r.db.Save(t).Error
+	return r.db.Omit("Tags").Save(t).Error
 }
 
+// Delete stamps the whole subtree with the same time, which is how Restore
+// tells the subtasks trashed with their parent from those trashed earlier.
 func (r *taskRepository) Delete(id, userId uuid.UUID) error {
-	result := r.db.Where("id = ? AND user_id = ?", id, userId).Delete(&Task{})
+	result := r.db.Exec(`
+		WITH RECURSIVE subtree(id) AS (
+			SELECT id FROM tasks WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+			UNION
+			SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id WHERE t.deleted_at IS NULL
+		)
+		UPDATE tasks SET deleted_at = ? WHERE id IN (SELECT id FROM subtree)`,
+		id, userId, time.Now(),
+	)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *taskRepository) Restore(id, userId uuid.UUID) ([]*Task, error) {
+	var tasks []*Task
+	err := r.db.Raw(`
+		WITH RECURSIVE root AS (
+			SELECT id, deleted_at FROM tasks WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL
+		), subtree(id) AS (
+			SELECT id FROM root
+			UNION
+			SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id
+			WHERE t.deleted_at = (SELECT deleted_at FROM root)
+		)
+		UPDATE tasks SET deleted_at = NULL, google_calendar_event_id = '', google_calendar_id = '', calendar_synced_at = NULL
+		WHERE id IN (SELECT id FROM subtree)
+		RETURNING *`,
+		id, userId,
+	).Scan(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, ErrNotFound
+	}
+	for i, t := range tasks {
+		if t.ID == id {
+			tasks[0], tasks[i] = tasks[i], tasks[0]
+		}
+	}
+	return tasks, nil
+}
+
+// parentTable is where a TrashParent is stored and how its children are
+// found.
+type parentTable struct {
+	name string
+	// tasks selects the parent's own tasks by its id; their subtasks follow
+	// them whatever they belong to.
+	tasks string
+	// children maps other trashable tables to their column referencing the
+	// parent.
+	children map[string]string
+}
+
+var parentTables = map[TrashParent]parentTable{
+	ProjectParent: {name: "projects", tasks: "project_id = ?"},
+	StudySubjectParent: {
+		name:     "study_subjects",
+		tasks:    "study_topic_id IN (SELECT id FROM study_topics WHERE subject_id = ?)",
+		children: map[string]string{"quizzes": "subject_id"},
+	},
+}
+
+func (r *taskRepository) TrashParent(parent TrashParent, id, userId uuid.UUID, at time.Time) ([]*Task, error) {
+	p := parentTables[parent]
+	result := r.db.Exec("UPDATE "+p.name+" SET deleted_at = ? WHERE id = ? AND user_id = ? AND deleted_at IS NULL", at, id, userId)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrNotFound
+	}
+	for table, column := range p.children {
+		if err := r.db.Exec("UPDATE "+table+" SET deleted_at = ? WHERE "+column+" = ? AND deleted_at IS NULL", at, id).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	var tasks []*Task
+	err := r.db.Raw(`
+		WITH RECURSIVE subtree(id) AS (
+			SELECT id FROM tasks WHERE `+p.tasks+` AND user_id = ? AND deleted_at IS NULL
+			UNION
+			SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id WHERE t.deleted_at IS NULL
+		)
+		UPDATE tasks SET deleted_at = ? WHERE id IN (SELECT id FROM subtree)
+		RETURNING *`,
+		id, userId, at,
+	).Scan(&tasks).Error
+	return tasks, err
+}
+
+func (r *taskRepository) RestoreParent(parent TrashParent, id, userId uuid.UUID) ([]*Task, error) {
+	p := parentTables[parent]
+	var trashed struct{ DeletedAt *time.Time }
+	err := r.db.Raw("SELECT deleted_at FROM "+p.name+" WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userId).
+		Scan(&trashed).Error
+	if err != nil {
+		return nil, err
+	}
+	if trashed.DeletedAt == nil {
+		return nil, ErrNotFound
+	}
+	at := *trashed.DeletedAt
+
+	if err := r.db.Exec("UPDATE "+p.name+" SET deleted_at = NULL WHERE id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	for table, column := range p.children {
+		if err := r.db.Exec("UPDATE "+table+" SET deleted_at = NULL WHERE "+column+" = ? AND deleted_at = ?", id, at).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	var tasks []*Task
+	err = r.db.Raw(`
+		WITH RECURSIVE subtree(id) AS (
+			SELECT id FROM tasks WHERE `+p.tasks+` AND user_id = ? AND deleted_at = ?
+			UNION
+			SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id WHERE t.deleted_at = ?
+		)
+		UPDATE tasks SET deleted_at = NULL, google_calendar_event_id = '', google_calendar_id = '', calendar_synced_at = NULL
+		WHERE id IN (SELECT id FROM subtree)
+		RETURNING *`,
+		id, userId, at, at,
+	).Scan(&tasks).Error
+	return tasks, err
+}
+
+func (r *taskRepository) ListSubtasks(parentId, userId uuid.UUID) ([]*Task, error) {
+	var tasks []*Task
+	if err := r.db.Preload("Project").Preload("StudyTopic").Preload("Tags").
+		Where("parent_id = ? AND user_id = ?", parentId, userId).
+		Order("created_at, id").
+		Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (r *taskRepository) ListDescendants(id, userId uuid.UUID) ([]*Task, error) {
+	var tasks []*Task
+	err := r.db.Raw(`
+		WITH RECURSIVE subtree(id) AS (
+			SELECT id FROM tasks WHERE parent_id = ? AND user_id = ? AND deleted_at IS NULL
+			UNION
+			SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id WHERE t.deleted_at IS NULL
+		)
+		SELECT * FROM tasks WHERE id IN (SELECT id FROM subtree)`,
+		id, userId,
+	).Scan(&tasks).Error
+	return tasks, err
+}
+
+// CountChildren counts the subtasks and checklist items of each task in a
+// single query. Tasks without children are missing from the map.
+func (r *taskRepository) CountChildren(taskIds []uuid.UUID) (map[uuid.UUID]ChildCounts, error) {
+	counts := make(map[uuid.UUID]ChildCounts, len(taskIds))
+	if len(taskIds) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		TaskID uuid.UUID
+		Total  int
+		Done   int
+	}
+	err := r.db.Raw(`
+		SELECT parent_id AS task_id, COUNT(*) AS total, COUNT(*) FILTER (WHERE status = 'DONE') AS done
+		FROM tasks WHERE parent_id IN ? AND deleted_at IS NULL GROUP BY parent_id
+		UNION ALL
+		SELECT task_id, COUNT(*), COUNT(*) FILTER (WHERE done)
+		FROM task_checklist_items WHERE task_id IN ? GROUP BY task_id`,
+		taskIds, taskIds,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		c := counts[row.TaskID]
+		c.Total += row.Total
+		c.Done += row.Done
+		counts[row.TaskID] = c
+	}
+	return counts, nil
+}
+
+func (r *taskRepository) ListChecklistItems(taskId uuid.UUID) ([]*ChecklistItem, error) {
+	var items []*ChecklistItem
+	if err := r.db.Where("task_id = ?", taskId).Order("position, created_at").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *taskRepository) FindChecklistItem(id, taskId uuid.UUID) (*ChecklistItem, error) {
+	var item ChecklistItem
+	if err := r.db.Where("id = ? AND task_id = ?", id, taskId).First(&item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+// CreateChecklistItem appends the item after the task's last one.
+func (r *taskRepository) CreateChecklistItem(item *ChecklistItem) error {
+	if err := r.db.Model(&ChecklistItem{}).
+		Where("task_id = ?", item.TaskID).
+		Select("COALESCE(MAX(position) + 1, 0)").
+		Scan(&item.Position).Error; err != nil {
+		return err
+	}
+	return r.db.Create(item).Error
+}
+
+func (r *taskRepository) UpdateChecklistItem(item *ChecklistItem) error {
+	return r.db.Save(item).Error
+}
+
+func (r *taskRepository) DeleteChecklistItem(id, taskId uuid.UUID) error {
+	result := r.db.Where("id = ? AND task_id = ?", id, taskId).Delete(&ChecklistItem{})
 	if result.RowsAffected == 0 {
 		return ErrNotFound
 	}
 	return result.Error
 }
+
+// SetChecklistPositions numbers the items in the order given.
+func (r *taskRepository) SetChecklistPositions(taskId uuid.UUID, itemIds []uuid.UUID) error {
+	now := time.Now()
+	for i, id := range itemIds {
+		if err := r.db.Model(&ChecklistItem{}).
+			Where("id = ? AND task_id = ?", id, taskId).
+			UpdateColumns(map[string]interface{}{"position": i, "updated_at": now}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *taskRepository) CreateDependency(d *TaskDependency) error {
+	err := r.db.Create(d).Error
+	if translator, ok := r.db.Dialector.(gorm.ErrorTranslator); ok && err != nil {
+		if errors.Is(translator.Translate(err), gorm.ErrDuplicatedKey) {
+			return ErrDependencyExists
+		}
+	}
+	return err
+}
+
+func (r *taskRepository) DeleteDependency(blockerId, blockedId, userId uuid.UUID) error {
+	result := r.db.Where("blocker_id = ? AND blocked_id = ? AND user_id = ?", blockerId, blockedId, userId).Delete(&TaskDependency{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *taskRepository) LockDependencies(userId uuid.UUID) error {
+	return r.db.Exec("SELECT 1 FROM users WHERE id = ? FOR UPDATE", userId).Error
+}
+
+func (r *taskRepository) ListDependencies(userId uuid.UUID) ([]*TaskDependency, error) {
+	var deps []*TaskDependency
+	if err := r.db.Where("user_id = ?", userId).Order("created_at").Find(&deps).Error; err != nil {
+		return nil, err
+	}
+	return deps, nil
+}
+
+func (r *taskRepository) ListBlockers(taskId, userId uuid.UUID) ([]*Task, error) {
+	var tasks []*Task
+	if err := r.db.Preload("Project").Preload("StudyTopic").Preload("Tags").
+		Where("user_id = ? AND id IN (?)", userId,
+			r.db.Model(&TaskDependency{}).Select("blocker_id").Where("blocked_id = ?", taskId)).
+		Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (r *taskRepository) ListBlockedTasks(taskId, userId uuid.UUID) ([]*Task, error) {
+	var tasks []*Task
+	if err := r.db.Preload("Project").Preload("StudyTopic").Preload("Tags").
+		Where("user_id = ? AND id IN (?)", userId,
+			r.db.Model(&TaskDependency{}).Select("blocked_id").Where("blocker_id = ?", taskId)).
+		Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (r *taskRepository) ListOpenBlockers(taskIds []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	open := make(map[uuid.UUID][]uuid.UUID)
+	if len(taskIds) == 0 {
+		return open, nil
+	}
+
+	var rows []struct {
+		BlockedID uuid.UUID
+		BlockerID uuid.UUID
+	}
+	err := r.db.Table("task_dependencies d").
+		Select("d.blocked_id, d.blocker_id").
+		Joins("JOIN tasks t ON t.id = d.blocker_id").
+		Where("d.blocked_id IN ? AND t.status <> ? AND t.deleted_at IS NULL", taskIds, DONE).
+		Order("d.created_at").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		open[row.BlockedID] = append(open[row.BlockedID], row.BlockerID)
+	}
+	return open, nil
+}
+
+func (r *taskRepository) RecordStatusChange(c *TaskStatusChange) error {
+	return r.db.Create(c).Error
+}
+
+func (r *taskRepository) RecordActivity(e *activity.Entry) error {
+	return r.db.Create(e).Error
+}
+
+func (r *taskRepository) ListStatusHistory(taskId uuid.UUID) ([]*TaskStatusChange, error) {
+	var changes []*TaskStatusChange
+	err := r.db.Where("task_id = ?", taskId).Order("changed_at, id").Find(&changes).Error
+	return changes, err
+}
+
+func (r *taskRepository) UpsertOccurrence(o *TaskOccurrence) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "task_id"}, {Name: "occurrence_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "done_at", "updated_at"}),
+	}).Create(o).Error
+}
+
+func (r *taskRepository) DeleteOccurrence(taskId, userId uuid.UUID, occurrenceDate time.Time) error {
+	result := r.db.Where("task_id = ? AND user_id = ? AND occurrence_date = ?", taskId, userId, occurrenceDate).Delete(&TaskOccurrence{})
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return result.Error
+}
+
+func (r *taskRepository) ListOccurrencesByUser(userId uuid.UUID, from, to time.Time) ([]*TaskOccurrence, error) {
+	var occurrences []*TaskOccurrence
+	if err := r.db.Where("user_id = ? AND occurrence_date BETWEEN ? AND ?", userId, from, to).Find(&occurrences).Error; err != nil {
+		return nil, err
+	}
+	return occurrences, nil
+}
+
+func (r *taskRepository) FindByCalendarEventID(userId uuid.UUID, eventID string) (*Task, error) {
+	var t Task
+	if err := r.db.Where("user_id = ? AND google_calendar_event_id = ?", userId, eventID).First(&t).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// FindByICalUIDs returns the user's imported tasks keyed by their UID,
+// including the ones in the trash: the UID stays taken until they are purged.
+func (r *taskRepository) FindByICalUIDs(userId uuid.UUID, uids []string) (map[string]*Task, error) {
+	found := make(map[string]*Task, len(uids))
+	if len(uids) == 0 {
+		return found, nil
+	}
+
+	var tasks []*Task
+	if err := r.db.Unscoped().Where("user_id = ? AND ical_uid IN ?", userId, uids).Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	for _, t := range tasks {
+		found[t.ICalUID] = t
+	}
+	return found, nil
+}
+
+// MarkCalendarSynced records the event ID and sync time without touching
+// updated_at, so the sync itself is not mistaken for a local edit.
+func (r *taskRepository) MarkCalendarSynced(id uuid.UUID, calendarID, eventID string, syncedAt time.Time) error {
+	return r.db.Model(&Task{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"google_calendar_id":       calendarID,
+		"google_calendar_event_id": eventID,
+		"calendar_synced_at":       syncedAt,
+	}).Error
+}
+
+func (r *taskRepository) Transaction(fn func(tx TaskRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&taskRepository{db: tx})
+	})
+}
+
+// EnqueueCalendarSync replaces the task's pending entry, if any, so the worker
+// only ever pushes the latest state. Dead entries for the task are dropped:
+// the new write supersedes them.
+func (r *taskRepository) EnqueueCalendarSync(e *CalendarOutboxEntry) error {
+	if err := r.db.Where("task_id = ? AND status = ?", e.TaskID, CalendarSyncDead).
+		Delete(&CalendarOutboxEntry{}).Error; err != nil {
+		return err
+	}
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "task_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "status = 'PENDING'"}}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"operation":       e.Operation,
+			"calendar_id":     e.CalendarID,
+			"event_id":        e.EventID,
+			"attempts":        0,
+			"last_error":      "",
+			"next_attempt_at": e.NextAttemptAt,
+			"version":         gorm.Expr("calendar_sync_outbox.version + 1"),
+			"updated_at":      e.UpdatedAt,
+		}),
+	}).Create(e).Error
+}
+
+// ClaimCalendarSyncs leases due entries by pushing next_attempt_at past the
+// lease, so concurrent workers skip them. An entry whose worker dies becomes
+// due again once the lease expires.
+func (r *taskRepository) ClaimCalendarSyncs(now time.Time, lease time.Duration, limit int) ([]*CalendarOutboxEntry, error) {
+	var entries []*CalendarOutboxEntry
+	err := r.db.Raw(`
+		UPDATE calendar_sync_outbox SET next_attempt_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM calendar_sync_outbox
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		now.Add(lease), now, CalendarSyncPending, now, limit,
+	).Scan(&entries).Error
+	return entries, err
+}
+
+// CompleteCalendarSync is a no-op when the entry was re-enqueued while being
+// processed; the newer version stays pending.
+func (r *taskRepository) CompleteCalendarSync(e *CalendarOutboxEntry, processedAt time.Time) error {
+	return r.db.Model(&CalendarOutboxEntry{}).
+		Where("id = ? AND version = ?", e.ID, e.Version).
+		UpdateColumns(map[string]interface{}{
+			"status":       CalendarSyncDone,
+			"processed_at": processedAt,
+			"last_error":   "",
+			"updated_at":   processedAt,
+		}).Error
+}
+
+func (r *taskRepository) FailCalendarSync(e *CalendarOutboxEntry) error {
+	return r.db.Model(&CalendarOutboxEntry{}).
+		Where("id = ? AND version = ?", e.ID, e.Version).
+		UpdateColumns(map[string]interface{}{
+			"status":          e.Status,
+			"attempts":        e.Attempts,
+			"next_attempt_at": e.NextAttemptAt,
+			"last_error":      e.LastError,
+			"updated_at":      time.Now(),
+		}).Error
+}
+
+func (r *taskRepository) ListUnsyncedCalendarSyncs(userId uuid.UUID) ([]*CalendarOutboxEntry, error) {
+	var entries []*CalendarOutboxEntry
+	if err := r.db.Preload("Task").
+		Where("user_id = ? AND status IN ?", userId, []CalendarSyncStatus{CalendarSyncPending, CalendarSyncDead}).
+		Order("created_at").
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *taskRepository) PurgeCalendarSyncs(before time.Time) (int64, error) {
+	result := r.db.Where("status = ? AND processed_at < ?", CalendarSyncDone, before).Delete(&CalendarOutboxEntry{})
+	return result.RowsAffected, result.Error
+}
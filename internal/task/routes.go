@@ -10,10 +10,26 @@ func Routes(h *Handler) chi.Router {
 	r.Post("/", h.CreateTask)
 	r.Get("/{taskID}", h.GetTask)
 	r.Get("/dashboard/stats", h.GetDashboardStats)
+	r.Get("/occurrences", h.ListOccurrences)
+	r.Post("/calendar/pull", h.PullCalendarChanges)
+	r.Get("/calendar/unsynced", h.ListUnsyncedTasks)
+	r.Post("/{taskID}/occurrences/complete", h.CompleteOccurrence)
+	r.Post("/{taskID}/occurrences/reopen", h.ReopenOccurrence)
 	r.Get("/", h.ListTasksByUser)
 	r.Get("/project/{projectID}", h.ListTasksByProject)
+	r.Get("/project/{projectID}/graph", h.GetProjectGraph)
 	r.Put("/{taskID}", h.UpdateTask)
 	r.Delete("/{taskID}", h.DeleteTask)
+	r.Get("/{taskID}/subtasks", h.ListSubtasks)
+	r.Get("/{taskID}/checklist", h.ListChecklist)
+	r.Post("/{taskID}/checklist", h.AddChecklistItem)
+	r.Put("/{taskID}/checklist/order", h.ReorderChecklist)
+	r.Put("/{taskID}/checklist/{itemID}", h.UpdateChecklistItem)
+	r.Delete("/{taskID}/checklist/{itemID}", h.DeleteChecklistItem)
+	r.Get("/{taskID}/dependencies", h.ListDependencies)
+	r.Post("/{taskID}/dependencies", h.AddDependency)
+	r.Delete("/{taskID}/dependencies/{blockerID}", h.RemoveDependency)
+	r.Get("/{taskID}/history", h.GetStatusHistory)
 
 	return r
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/activity"
 	"github.com/saulo-duarte/chronos-lambda/internal/auth"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
 	googlecalendar "github.com/saulo-duarte/chronos-lambda/internal/google_calendar"
@@ -23,6 +24,7 @@ var (
 	ErrStudyTopicNotFound = studytopic.ErrStudyTopicNotFound
 	ErrInvalidID          = errors.New("invalid id format")
 	ErrProjectRequired    = errors.New("projectId is required for PROJECT tasks")
+	ErrInvalidEstimate    = errors.New("estimated minutes cannot be negative")
 )
 
 const dashboardTaskLimit = 5
@@ -30,20 +32,37 @@ const dashboardTaskLimit = 5
 type TaskService interface {
 	CreateTask(ctx context.Context, t *Task) (*Task, error)
 	FindAllByUser(ctx context.Context) ([]*Task, error)
+	QueryTasks(ctx context.Context, q *TaskQuery) (*TaskPage, error)
 	FindByID(ctx context.Context, id string) (*Task, error)
 	DeleteByID(ctx context.Context, id string) error
+	RestoreTask(ctx context.Context, id string) (*Task, error)
 	FindAllByProjectID(ctx context.Context, projectID string) ([]*Task, error)
 	FindAllByTopicID(ctx context.Context, topicID string) ([]*Task, error)
 	UpdateTask(ctx context.Context, dto *TaskUpdateDTO) (*Task, error)
 	GetDashboardStats(ctx context.Context) (*DashboardStatsResponse, error)
+	ListUnsyncedTasks(ctx context.Context) ([]*CalendarOutboxEntry, error)
+	ImportTasks(ctx context.Context, req *ImportRequest, preview bool) (*ImportResult, error)
+
+	ListSubtasks(ctx context.Context, taskID string) ([]*Task, error)
+	ListChecklist(ctx context.Context, taskID string) ([]*ChecklistItem, error)
+	AddChecklistItem(ctx context.Context, taskID string, dto *ChecklistItemDTO) (*ChecklistItem, error)
+	UpdateChecklistItem(ctx context.Context, taskID, itemID string, dto *ChecklistItemDTO) (*ChecklistItem, error)
+	DeleteChecklistItem(ctx context.Context, taskID, itemID string) error
+	ReorderChecklist(ctx context.Context, taskID string, itemIDs []uuid.UUID) ([]*ChecklistItem, error)
+
+	ListDependencies(ctx context.Context, taskID string) (*TaskDependencies, error)
+	AddDependency(ctx context.Context, taskID string, blockerID uuid.UUID) (*TaskDependency, error)
+	RemoveDependency(ctx context.Context, taskID, blockerID string) error
+	GetProjectGraph(ctx context.Context, projectID string) (*DependencyGraph, error)
+
+	GetStatusHistory(ctx context.Context, taskID string) (*StatusHistoryResponse, error)
 }
 
 type taskService struct {
-	repo            TaskRepository
-	projectService  project.ProjectService
-	userRepo        user.UserRepository
-	studyTopicRepo  studytopic.StudyTopicRepository
-	calendarManager googlecalendar.CalendarManager
+	repo           TaskRepository
+	projectService project.ProjectService
+	userRepo       user.UserRepository
+	studyTopicRepo studytopic.StudyTopicRepository
 }
 
 func NewService(
@@ -51,14 +70,12 @@ func NewService(
 	projectService project.ProjectService,
 	userRepo user.UserRepository,
 	studyTopicRepo studytopic.StudyTopicRepository,
-	calendarManager googlecalendar.CalendarManager,
 ) TaskService {
 	return &taskService{
-		repo:            repo,
-		projectService:  projectService,
-		userRepo:        userRepo,
-		studyTopicRepo:  studyTopicRepo,
-		calendarManager: calendarManager,
+		repo:           repo,
+		projectService: projectService,
+		userRepo:       userRepo,
+		studyTopicRepo: studyTopicRepo,
 	}
 }
 
@@ -72,17 +89,61 @@ func (s *taskService) CreateTask(ctx context.Context, t *Task) (*Task, error) {
 	t.CreatedAt = time.Now()
 	t.UpdatedAt = time.Now()
 	t.UserID = userID
+	normalizeTaskDates(t, userLocation(ctx, s.userRepo, userID))
+
+	if t.Priority != "" && !IsValidPriority(t.Priority) {
+		return nil, ErrInvalidPriority
+	}
+	if t.EstimatedMinutes != nil {
+		if *t.EstimatedMinutes < 0 {
+			return nil, ErrInvalidEstimate
+		}
+		if *t.EstimatedMinutes == 0 {
+			t.EstimatedMinutes = nil
+		}
+	}
+	statusChange, err := initialStatus(t, t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.ParentID != nil {
+		parent, err := s.validateParent(ctx, t, *t.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		inheritFromParent(t, parent)
+	}
 
 	if err := s.validateTaskDependencies(ctx, t); err != nil {
 		return nil, err
 	}
 
-	if err := s.repo.Create(t); err != nil {
+	if err := s.validateRecurrence(t.Recurrence, t.StartDate, t.DueDate); err != nil {
+		return nil, err
+	}
+
+	syncCalendar := s.calendarEnabled(ctx, userID)
+	err = s.repo.Transaction(func(tx TaskRepository) error {
+		if err := tx.Create(t); err != nil {
+			return err
+		}
+		if err := tx.RecordStatusChange(statusChange); err != nil {
+			return err
+		}
+		if err := tx.RecordActivity(newTaskActivity(t, activity.Created, activityChanges(activityFields{}, t))); err != nil {
+			return err
+		}
+		if syncCalendar {
+			return tx.EnqueueCalendarSync(newCalendarOutboxEntry(t, CalendarSyncUpsert))
+		}
+		return nil
+	})
+	if err != nil {
 		config.WithContext(ctx).WithError(err).Error("Failed to create task")
 		return nil, err
 	}
 
-	s.syncWithCalendar(ctx, userID, t)
 	config.WithContext(ctx).WithField("task_id", t.ID).Info("Task created successfully")
 	return t, nil
 }
@@ -99,9 +160,45 @@ func (s *taskService) FindAllByUser(ctx context.Context) ([]*Task, error) {
 		return nil, err
 	}
 
+	localizeTasks(tasks, userLocation(ctx, s.userRepo, userID))
+	s.annotateTasks(ctx, tasks)
 	return tasks, nil
 }
 
+func (s *taskService) QueryTasks(ctx context.Context, q *TaskQuery) (*TaskPage, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := userLocation(ctx, s.userRepo, userID)
+	q.DueFrom = util.LocalizePtr(q.DueFrom, loc)
+	q.DueTo = util.LocalizePtr(q.DueTo, loc)
+	if q.DueTo != nil && q.dueToWholeDay {
+		q.DueTo = &util.LocalDateTime{Time: q.DueTo.Time.AddDate(0, 0, 1).Add(-time.Nanosecond)}
+	}
+	if q.Now.IsZero() {
+		q.Now = time.Now()
+	}
+
+	tasks, total, err := s.repo.Query(userID, q)
+	if err != nil {
+		if !errors.Is(err, ErrInvalidCursor) {
+			config.WithContext(ctx).WithError(err).Error("Failed to query tasks")
+		}
+		return nil, err
+	}
+
+	page := &TaskPage{Items: tasks, Total: total}
+	if len(tasks) > q.Limit {
+		page.Items = tasks[:q.Limit]
+		page.NextCursor = encodeCursor(page.Items[q.Limit-1], q.Sort)
+	}
+	localizeTasks(page.Items, loc)
+	s.annotateTasks(ctx, page.Items)
+	return page, nil
+}
+
 func (s *taskService) FindByID(ctx context.Context, id string) (*Task, error) {
 	userID, err := s.getUserID(ctx)
 	if err != nil {
@@ -113,7 +210,14 @@ func (s *taskService) FindByID(ctx context.Context, id string) (*Task, error) {
 		return nil, err
 	}
 
-	return s.getTaskByID(ctx, taskID, userID)
+	task, err := s.getTaskByID(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	localizeTasks([]*Task{task}, userLocation(ctx, s.userRepo, userID))
+	s.annotateTasks(ctx, []*Task{task})
+	return task, nil
 }
 
 func (s *taskService) DeleteByID(ctx context.Context, id string) error {
@@ -132,7 +236,19 @@ func (s *taskService) DeleteByID(ctx context.Context, id string) error {
 		return err
 	}
 
-	if err := s.repo.Delete(taskID, userID); err != nil {
+	// Subtasks go to the trash with their parent, so their events have to
+	// be removed as well.
+	descendants, err := s.repo.ListDescendants(taskID, userID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list subtasks")
+		return err
+	}
+
+	var events []*Task
+	if s.calendarEnabled(ctx, userID) {
+		events = append([]*Task{task}, descendants...)
+	}
+	if err := trashTask(s.repo, task, events); err != nil {
 		if errors.Is(err, ErrNotFound) {
 			return ErrTaskNotFound
 		}
@@ -140,12 +256,83 @@ func (s *taskService) DeleteByID(ctx context.Context, id string) error {
 		return err
 	}
 
-	if task.GoogleCalendarEventID != "" {
-		s.calendarManager.RemoveTask(ctx, userID, task.GoogleCalendarEventID)
+	config.WithContext(ctx).WithField("task_id", id).Info("Task moved to trash")
+	return nil
+}
+
+// trashTask moves t and its subtasks to the trash and queues a calendar
+// delete for each task in events, in one transaction. The subtasks have to
+// be listed before the call, as the delete hides them.
+func trashTask(repo TaskRepository, t *Task, events []*Task) error {
+	return repo.Transaction(func(tx TaskRepository) error {
+		if err := tx.Delete(t.ID, t.UserID); err != nil {
+			return err
+		}
+		for _, e := range events {
+			if err := tx.EnqueueCalendarSync(newCalendarOutboxEntry(e, CalendarSyncDelete)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RestoreTask takes a task out of the trash with the subtasks trashed along
+// with it and puts their events back on the calendar. A task whose parent is
+// still in the trash is restored as a top-level task.
+func (s *taskService) RestoreTask(ctx context.Context, id string) (*Task, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	config.WithContext(ctx).WithField("task_id", id).Info("Task deleted successfully")
-	return nil
+	taskID, err := s.parseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	syncCalendar := s.calendarEnabled(ctx, userID)
+	var restored *Task
+	err = s.repo.Transaction(func(tx TaskRepository) error {
+		tasks, err := tx.Restore(taskID, userID)
+		if err != nil {
+			return err
+		}
+		restored = tasks[0]
+
+		if restored.ParentID != nil {
+			if _, err := tx.FindByIdAndUserId(*restored.ParentID, userID); errors.Is(err, ErrNotFound) {
+				restored.ParentID = nil
+				if err := tx.Update(restored); err != nil {
+					return err
+				}
+			} else if err != nil {
+				return err
+			}
+		}
+
+		if !syncCalendar {
+			return nil
+		}
+		for _, t := range tasks {
+			if err := tx.EnqueueCalendarSync(newCalendarOutboxEntry(t, CalendarSyncUpsert)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrTaskNotFound
+		}
+		config.WithContext(ctx).WithError(err).Error("Failed to restore task")
+		return nil, err
+	}
+
+	localizeTasks([]*Task{restored}, userLocation(ctx, s.userRepo, userID))
+	s.annotateTasks(ctx, []*Task{restored})
+	config.WithContext(ctx).WithField("task_id", id).Info("Task restored from trash")
+	return restored, nil
 }
 
 func (s *taskService) FindAllByProjectID(ctx context.Context, projectID string) ([]*Task, error) {
@@ -169,6 +356,8 @@ func (s *taskService) FindAllByProjectID(ctx context.Context, projectID string)
 		return nil, err
 	}
 
+	localizeTasks(tasks, userLocation(ctx, s.userRepo, userID))
+	s.annotateTasks(ctx, tasks)
 	return tasks, nil
 }
 
@@ -193,6 +382,8 @@ func (s *taskService) FindAllByTopicID(ctx context.Context, topicID string) ([]*
 		return nil, err
 	}
 
+	localizeTasks(tasks, userLocation(ctx, s.userRepo, userID))
+	s.annotateTasks(ctx, tasks)
 	return tasks, nil
 }
 
@@ -207,19 +398,86 @@ func (s *taskService) UpdateTask(ctx context.Context, dto *TaskUpdateDTO) (*Task
 		return nil, err
 	}
 
-	needsCalendarSync := s.applyTaskUpdates(task, dto)
+	loc := userLocation(ctx, s.userRepo, userID)
+	localizeTasks([]*Task{task}, loc)
+	before := snapshotActivity(task)
+	dto.StartDate = dto.StartDate.Localize(loc)
+	dto.DueDate = dto.DueDate.Localize(loc)
+
+	if dto.RemoveParent {
+		task.ParentID = nil
+	} else if dto.ParentID != nil && (task.ParentID == nil || *task.ParentID != *dto.ParentID) {
+		if _, err := s.validateParent(ctx, task, *dto.ParentID); err != nil {
+			return nil, err
+		}
+		task.ParentID = dto.ParentID
+	}
+
+	if dto.Priority != "" && !IsValidPriority(dto.Priority) {
+		return nil, ErrInvalidPriority
+	}
+	if dto.EstimatedMinutes != nil && *dto.EstimatedMinutes < 0 {
+		return nil, ErrInvalidEstimate
+	}
+
+	wasDone := task.Status == DONE
+	if starts := dto.Status == IN_PROGRESS || dto.Status == DONE; starts && dto.Status != task.Status && !dto.Force {
+		if err := s.checkNotBlocked(ctx, task); err != nil {
+			return nil, err
+		}
+	}
+
 	task.UpdatedAt = time.Now()
+	var statusChange *TaskStatusChange
+	if dto.Status != "" {
+		if statusChange, err = changeStatus(task, dto.Status, task.UpdatedAt); err != nil {
+			return nil, err
+		}
+	}
 
-	if err := s.repo.Update(task); err != nil {
-		config.WithContext(ctx).WithError(err).Error("Failed to update task")
+	needsCalendarSync := s.applyTaskUpdates(task, dto)
+	normalizeTaskDates(task, loc)
+
+	if err := s.validateRecurrence(task.Recurrence, task.StartDate, task.DueDate); err != nil {
 		return nil, err
 	}
 
-	if needsCalendarSync {
-		s.syncWithCalendar(ctx, userID, task)
+	syncCalendar := needsCalendarSync && s.calendarEnabled(ctx, userID)
+	changes := activityChanges(before, task)
+	err = s.repo.Transaction(func(tx TaskRepository) error {
+		if err := tx.Update(task); err != nil {
+			return err
+		}
+		if statusChange != nil {
+			if err := tx.RecordStatusChange(statusChange); err != nil {
+				return err
+			}
+		}
+		if len(changes) > 0 {
+			if err := tx.RecordActivity(newTaskActivity(task, activity.Updated, changes)); err != nil {
+				return err
+			}
+		}
+		if syncCalendar {
+			if err := tx.EnqueueCalendarSync(newCalendarOutboxEntry(task, CalendarSyncUpsert)); err != nil {
+				return err
+			}
+		}
+		if task.Status == DONE && !wasDone {
+			return s.autoCompleteParents(tx, task.ParentID, userID, task.UpdatedAt)
+		}
+		if dto.AutoComplete != nil && *dto.AutoComplete {
+			return s.autoCompleteParents(tx, &task.ID, userID, task.UpdatedAt)
+		}
+		return nil
+	})
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to update task")
+		return nil, err
 	}
 
 	config.WithContext(ctx).WithField("task_id", task.ID).Info("Task updated successfully")
+	s.annotateTasks(ctx, []*Task{task})
 	return task, nil
 }
 
@@ -235,7 +493,30 @@ func (s *taskService) GetDashboardStats(ctx context.Context) (*DashboardStatsRes
 		return nil, err
 	}
 
-	return s.buildDashboardStats(tasks), nil
+	loc := userLocation(ctx, s.userRepo, userID)
+	localizeTasks(tasks, loc)
+	return s.buildDashboardStats(topLevelTasks(tasks), time.Now().In(loc)), nil
+}
+
+func (s *taskService) ListUnsyncedTasks(ctx context.Context) ([]*CalendarOutboxEntry, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.repo.ListUnsyncedCalendarSyncs(userID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list unsynced tasks")
+		return nil, err
+	}
+
+	loc := userLocation(ctx, s.userRepo, userID)
+	for _, e := range entries {
+		if e.Task != nil {
+			localizeTasks([]*Task{e.Task}, loc)
+		}
+	}
+	return entries, nil
 }
 
 // ============= Helper Methods =============
@@ -278,9 +559,16 @@ func (s *taskService) validateProjectExists(ctx context.Context, projectID uuid.
 	return nil
 }
 
+// validateTopicExists checks that the study topic exists and belongs to the
+// user in ctx.
 func (s *taskService) validateTopicExists(ctx context.Context, topicID uuid.UUID) error {
-	if _, err := s.studyTopicRepo.GetByID(topicID.String()); err != nil {
-		config.WithContext(ctx).WithError(err).WithField("study_topic_id", topicID).Error("Study topic not found")
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return err
+	}
+	topic, err := s.studyTopicRepo.GetByID(topicID.String())
+	if err != nil || topic == nil || topic.UserID != userID {
+		config.WithContext(ctx).WithError(err).WithField("study_topic_id", topicID).Warn("Study topic not found")
 		return ErrStudyTopicNotFound
 	}
 	return nil
@@ -306,35 +594,37 @@ func (s *taskService) validateTaskDependencies(ctx context.Context, t *Task) err
 	return nil
 }
 
-func (s *taskService) syncWithCalendar(ctx context.Context, userID uuid.UUID, t *Task) {
-	calTask := &googlecalendar.CalendarTask{
-		ID:                    t.ID,
-		Name:                  t.Name,
-		Description:           t.Description,
-		StartDate:             util.ToTimePtr(t.StartDate),
-		DueDate:               util.ToTimePtr(t.DueDate),
-		GoogleCalendarEventID: s.getEventIDPtr(t.GoogleCalendarEventID),
+func (s *taskService) validateRecurrence(rule *RecurrenceRule, startDate, dueDate *util.LocalDateTime) error {
+	if rule == nil {
+		return nil
 	}
 
-	eventID, err := s.calendarManager.SyncTask(ctx, userID, calTask)
-	if err != nil {
-		config.WithContext(ctx).WithError(err).Warnf("Calendar sync failed for task %s", t.ID)
-		return
+	if err := rule.Validate(); err != nil {
+		return err
 	}
 
-	if eventID != t.GoogleCalendarEventID {
-		t.GoogleCalendarEventID = eventID
-		if err := s.repo.Update(t); err != nil {
-			config.WithContext(ctx).WithError(err).Error("Failed to update task with calendar event ID")
-		}
+	if (startDate == nil || startDate.IsZero()) && (dueDate == nil || dueDate.IsZero()) {
+		return ErrRecurrenceWithoutDate
 	}
+
+	return nil
 }
 
-func (s *taskService) getEventIDPtr(eventID string) *string {
-	if eventID == "" {
-		return nil
+// calendarEnabled reports whether task writes should be queued for Google
+// Calendar. Users who never connected or whose grant was revoked are skipped.
+func (s *taskService) calendarEnabled(ctx context.Context, userID uuid.UUID) bool {
+	return calendarConnected(ctx, s.userRepo, userID)
+}
+
+// calendarConnected reports whether the user's task changes are pushed to
+// Google Calendar.
+func calendarConnected(ctx context.Context, userRepo user.UserRepository, userID uuid.UUID) bool {
+	u, err := userRepo.GetByID(userID.String())
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Warn("Failed to load user for calendar sync")
+		return false
 	}
-	return &eventID
+	return u != nil && u.CalendarStatus() == user.CalendarConnected
 }
 
 func (s *taskService) applyTaskUpdates(task *Task, dto *TaskUpdateDTO) bool {
@@ -350,12 +640,10 @@ func (s *taskService) applyTaskUpdates(task *Task, dto *TaskUpdateDTO) bool {
 		needsSync = true
 	}
 
-	if dto.Status != "" && dto.Status != task.Status {
-		task.Status = dto.Status
-	}
-
 	if dto.Priority != "" && dto.Priority != task.Priority {
 		task.Priority = dto.Priority
+		// The event color follows the priority.
+		needsSync = true
 	}
 
 	if s.updateDate(&task.StartDate, &dto.StartDate) {
@@ -371,7 +659,37 @@ func (s *taskService) applyTaskUpdates(task *Task, dto *TaskUpdateDTO) bool {
 		needsSync = true
 	}
 
-	if !dto.DoneAt.IsZero() {
+	if dto.AllDay != nil && *dto.AllDay != task.AllDay {
+		task.AllDay = *dto.AllDay
+		needsSync = true
+	}
+
+	if dto.RemoveRecurrence {
+		if task.Recurrence != nil {
+			task.Recurrence = nil
+			needsSync = true
+		}
+	} else if dto.Recurrence != nil {
+		if task.Recurrence == nil || task.Recurrence.RRULE() != dto.Recurrence.RRULE() {
+			task.Recurrence = dto.Recurrence
+			needsSync = true
+		}
+	}
+
+	if dto.AutoComplete != nil {
+		task.AutoComplete = *dto.AutoComplete
+	}
+
+	if dto.EstimatedMinutes != nil {
+		task.EstimatedMinutes = nil
+		if *dto.EstimatedMinutes > 0 {
+			task.EstimatedMinutes = dto.EstimatedMinutes
+		}
+	}
+
+	// A client may backdate the completion, but DoneAt otherwise follows the
+	// status.
+	if task.Status == DONE && !dto.DoneAt.IsZero() {
 		if t := util.ToTimePtr(&dto.DoneAt); t != nil {
 			task.DoneAt = *t
 		}
@@ -394,12 +712,11 @@ func (s *taskService) updateDate(field **util.LocalDateTime, newValue *util.Loca
 	return false
 }
 
-func (s *taskService) buildDashboardStats(tasks []*Task) *DashboardStatsResponse {
+func (s *taskService) buildDashboardStats(tasks []*Task, now time.Time) *DashboardStatsResponse {
 	stats := TaskStats{Total: len(tasks)}
 	typeStats := TaskTypeStats{}
 	var tasksThisMonth []*Task
 
-	now := time.Now()
 	currentYear, currentMonth, _ := now.Date()
 
 	for _, task := range tasks {
@@ -431,7 +748,7 @@ func (s *taskService) countTaskByStatus(task *Task, stats *TaskStats, now time.T
 		stats.Todo++
 	}
 
-	if task.Status != "DONE" && task.DueDate != nil && task.DueDate.Time.Before(now) {
+	if task.Status != "DONE" && task.DueDate != nil && dueCutoff(task).Before(now) {
 		stats.Overdue++
 	}
 }
@@ -473,3 +790,113 @@ func (s *taskService) getRecentTasks(tasks []*Task) []*Task {
 
 	return sorted
 }
+
+// topLevelTasks drops subtasks, which are part of their parent's work and
+// would otherwise be counted twice.
+func topLevelTasks(tasks []*Task) []*Task {
+	result := make([]*Task, 0, len(tasks))
+	for _, t := range tasks {
+		if t.ParentID == nil {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// inheritFromParent files a new subtask under its parent's type, project and
+// study topic unless the subtask names its own.
+func inheritFromParent(t, parent *Task) {
+	if t.Type == "" {
+		t.Type = parent.Type
+	}
+	if t.ProjectId == nil && t.StudyTopicId == nil {
+		t.ProjectId = parent.ProjectId
+		t.StudyTopicId = parent.StudyTopicId
+	}
+}
+
+// userLocation is the time zone the user's dates are entered and shown in.
+func userLocation(ctx context.Context, userRepo user.UserRepository, userID uuid.UUID) *time.Location {
+	u, err := userRepo.GetByID(userID.String())
+	if err != nil || u == nil {
+		config.WithContext(ctx).WithError(err).Warn("Failed to load user time zone, using default")
+		return util.DefaultLocation()
+	}
+	return u.Location()
+}
+
+// localizeTasks shows the task dates in loc.
+func localizeTasks(tasks []*Task, loc *time.Location) {
+	for _, t := range tasks {
+		t.StartDate = util.LocalizePtr(t.StartDate, loc)
+		t.DueDate = util.LocalizePtr(t.DueDate, loc)
+	}
+}
+
+// normalizeTaskDates pins input dates to loc. All-day tasks keep only the
+// calendar day.
+func normalizeTaskDates(t *Task, loc *time.Location) {
+	localizeTasks([]*Task{t}, loc)
+	if !t.AllDay {
+		return
+	}
+	if t.StartDate != nil && !t.StartDate.IsZero() {
+		t.StartDate = &util.LocalDateTime{Time: util.DateOnly(t.StartDate.Time)}
+	}
+	if t.DueDate != nil && !t.DueDate.IsZero() {
+		t.DueDate = &util.LocalDateTime{Time: util.DateOnly(t.DueDate.Time)}
+	}
+}
+
+// dueCutoff is when the task becomes overdue: an all-day task is due until
+// the end of its day.
+func dueCutoff(t *Task) time.Time {
+	if t.AllDay {
+		return t.DueDate.Time.AddDate(0, 0, 1)
+	}
+	return t.DueDate.Time
+}
+
+// pushToCalendar sends the task to Google Calendar and records the event ID
+// and the sync time.
+func pushToCalendar(ctx context.Context, repo TaskRepository, manager googlecalendar.CalendarManager, userID uuid.UUID, t *Task) error {
+	calTask := &googlecalendar.CalendarTask{
+		ID:          t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		StartDate:   util.ToTimePtr(t.StartDate),
+		DueDate:     util.ToTimePtr(t.DueDate),
+		AllDay:      t.AllDay,
+		Type:        string(t.Type),
+		Priority:    string(t.Priority),
+		CalendarID:  t.GoogleCalendarID,
+	}
+	if t.GoogleCalendarEventID != "" {
+		eventID := t.GoogleCalendarEventID
+		calTask.GoogleCalendarEventID = &eventID
+	}
+
+	if t.Recurrence != nil {
+		calTask.Recurrence = []string{t.Recurrence.RRULEFor(t.AllDay)}
+	}
+
+	eventID, err := manager.SyncTask(ctx, userID, calTask)
+	if err != nil {
+		return err
+	}
+
+	calendarID := calTask.CalendarID
+	if eventID == "" {
+		calendarID = ""
+	}
+
+	now := time.Now()
+	t.GoogleCalendarEventID = eventID
+	t.GoogleCalendarID = calendarID
+	t.CalendarSyncedAt = &now
+	if err := repo.MarkCalendarSynced(t.ID, calendarID, eventID, now); err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to update task with calendar event ID")
+		return err
+	}
+	return nil
+}
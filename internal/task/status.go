@@ -0,0 +1,149 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+)
+
+var (
+	ErrInvalidStatus           = errors.New("invalid task status")
+	ErrInvalidPriority         = errors.New("invalid task priority")
+	ErrInvalidStatusTransition = errors.New("invalid status transition")
+)
+
+// statusTransitions lists where each status may move. A finished task has to
+// be reopened to TODO before work on it starts again.
+var statusTransitions = map[TaskStatus][]TaskStatus{
+	TODO:        {IN_PROGRESS, DONE},
+	IN_PROGRESS: {TODO, DONE},
+	DONE:        {TODO},
+}
+
+func IsValidStatus(s TaskStatus) bool {
+	_, ok := statusTransitions[s]
+	return ok
+}
+
+func IsValidPriority(p TaskPriority) bool {
+	return priorityRank(p) > 0
+}
+
+func CanTransition(from, to TaskStatus) bool {
+	for _, s := range statusTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// changeStatus moves t to status, stamping DoneAt on entering DONE and
+// clearing it on reopen. It returns the history entry to record, or nil when
+// the status does not change.
+func changeStatus(t *Task, to TaskStatus, at time.Time) (*TaskStatusChange, error) {
+	if !IsValidStatus(to) {
+		return nil, ErrInvalidStatus
+	}
+	if t.Status == to {
+		return nil, nil
+	}
+	if !CanTransition(t.Status, to) {
+		return nil, ErrInvalidStatusTransition
+	}
+
+	change := newStatusChange(t, to, at)
+	t.Status = to
+	if to == DONE {
+		t.DoneAt = at
+	} else {
+		t.DoneAt = time.Time{}
+	}
+	return change, nil
+}
+
+// initialStatus validates the status of a new task, defaulting to TODO, and
+// returns the first history entry for it.
+func initialStatus(t *Task, at time.Time) (*TaskStatusChange, error) {
+	status := t.Status
+	if status == "" {
+		status = TODO
+	}
+	if !IsValidStatus(status) {
+		return nil, ErrInvalidStatus
+	}
+
+	t.Status = ""
+	change := newStatusChange(t, status, at)
+	t.Status = status
+	if status != DONE {
+		t.DoneAt = time.Time{}
+	} else if t.DoneAt.IsZero() {
+		t.DoneAt = at
+	}
+	return change, nil
+}
+
+func newStatusChange(t *Task, to TaskStatus, at time.Time) *TaskStatusChange {
+	return &TaskStatusChange{
+		ID:         uuid.New(),
+		TaskID:     t.ID,
+		UserID:     t.UserID,
+		FromStatus: t.Status,
+		ToStatus:   to,
+		ChangedAt:  at,
+	}
+}
+
+func (s *taskService) GetStatusHistory(ctx context.Context, taskID string) (*StatusHistoryResponse, error) {
+	t, err := s.findOwnedTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := s.repo.ListStatusHistory(t.ID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list status history")
+		return nil, err
+	}
+
+	loc := userLocation(ctx, s.userRepo, t.UserID)
+	for _, c := range changes {
+		c.ChangedAt = c.ChangedAt.In(loc)
+	}
+	return buildStatusHistory(t, changes), nil
+}
+
+// buildStatusHistory derives the cycle time, from the first time work
+// started to the last completion, and the lead time, from creation to the
+// last completion. Both are only set while the task is done.
+func buildStatusHistory(t *Task, changes []*TaskStatusChange) *StatusHistoryResponse {
+	resp := &StatusHistoryResponse{TaskID: t.ID, Status: t.Status, Transitions: changes}
+
+	for _, c := range changes {
+		if c.ToStatus == IN_PROGRESS && resp.StartedAt == nil {
+			started := c.ChangedAt
+			resp.StartedAt = &started
+		}
+		if c.ToStatus == DONE {
+			completed := c.ChangedAt
+			resp.CompletedAt = &completed
+		}
+	}
+
+	if t.Status != DONE || resp.CompletedAt == nil {
+		resp.CompletedAt = nil
+		return resp
+	}
+
+	lead := int64(resp.CompletedAt.Sub(t.CreatedAt).Seconds())
+	resp.LeadTimeSeconds = &lead
+	if resp.StartedAt != nil && resp.StartedAt.Before(*resp.CompletedAt) {
+		cycle := int64(resp.CompletedAt.Sub(*resp.StartedAt).Seconds())
+		resp.CycleTimeSeconds = &cycle
+	}
+	return resp
+}
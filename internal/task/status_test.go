@@ -0,0 +1,124 @@
+package task_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+)
+
+func TestCanTransition(t *testing.T) {
+	cases := []struct {
+		from, to task.TaskStatus
+		allowed  bool
+	}{
+		{task.TODO, task.IN_PROGRESS, true},
+		{task.TODO, task.DONE, true},
+		{task.IN_PROGRESS, task.TODO, true},
+		{task.IN_PROGRESS, task.DONE, true},
+		{task.DONE, task.TODO, true},
+		{task.DONE, task.IN_PROGRESS, false},
+		{task.TODO, "ARCHIVED", false},
+	}
+	for _, c := range cases {
+		if got := task.CanTransition(c.from, c.to); got != c.allowed {
+			t.Errorf("%s -> %s: esperado %v, recebido %v", c.from, c.to, c.allowed, got)
+		}
+	}
+}
+
+func TestStatusHistory(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.UserDataKeyID, userID.String())
+	ctx = context.WithValue(ctx, auth.UserDataKeyRole, "user")
+
+	repo := &fakeTreeRepo{tasks: map[uuid.UUID]*task.Task{}, items: map[uuid.UUID][]*task.ChecklistItem{}}
+	svc := task.NewService(repo, nil, &fakeLocationUserRepo{}, nil)
+
+	created := time.Now().Add(-48 * time.Hour)
+	tk := repo.add(&task.Task{ID: uuid.New(), UserID: userID, Name: "Relatório", Status: task.TODO, CreatedAt: created})
+
+	t.Run("RejectsUnknownStatus", func(t *testing.T) {
+		_, err := svc.UpdateTask(ctx, &task.TaskUpdateDTO{ID: tk.ID, Status: "ARCHIVED"})
+		if !errors.Is(err, task.ErrInvalidStatus) {
+			t.Errorf("Status inválido deveria ser rejeitado, erro: %v", err)
+		}
+		_, err = svc.UpdateTask(ctx, &task.TaskUpdateDTO{ID: tk.ID, Priority: "URGENT"})
+		if !errors.Is(err, task.ErrInvalidPriority) {
+			t.Errorf("Prioridade inválida deveria ser rejeitada, erro: %v", err)
+		}
+	})
+
+	t.Run("StampsDoneAtOnCompletion", func(t *testing.T) {
+		if _, err := svc.UpdateTask(ctx, &task.TaskUpdateDTO{ID: tk.ID, Status: task.IN_PROGRESS}); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if !repo.tasks[tk.ID].DoneAt.IsZero() {
+			t.Error("DoneAt não deveria ser preenchido antes da conclusão")
+		}
+		if _, err := svc.UpdateTask(ctx, &task.TaskUpdateDTO{ID: tk.ID, Status: task.DONE}); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if repo.tasks[tk.ID].DoneAt.IsZero() {
+			t.Error("DoneAt deveria ser preenchido ao concluir")
+		}
+	})
+
+	t.Run("RejectsInvalidTransition", func(t *testing.T) {
+		_, err := svc.UpdateTask(ctx, &task.TaskUpdateDTO{ID: tk.ID, Status: task.IN_PROGRESS})
+		if !errors.Is(err, task.ErrInvalidStatusTransition) {
+			t.Errorf("DONE -> IN_PROGRESS deveria ser rejeitado, erro: %v", err)
+		}
+	})
+
+	t.Run("ReportsCycleTime", func(t *testing.T) {
+		history, err := svc.GetStatusHistory(ctx, tk.ID.String())
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if len(history.Transitions) != 2 {
+			t.Fatalf("Esperadas 2 transições, recebidas %d", len(history.Transitions))
+		}
+		if history.CycleTimeSeconds == nil || history.LeadTimeSeconds == nil {
+			t.Fatal("Tarefa concluída deveria ter cycle time e lead time")
+		}
+		if *history.LeadTimeSeconds < int64((48 * time.Hour).Seconds()) {
+			t.Errorf("Lead time deveria contar desde a criação, recebido %ds", *history.LeadTimeSeconds)
+		}
+	})
+
+	t.Run("ReopeningClearsDoneAt", func(t *testing.T) {
+		if _, err := svc.UpdateTask(ctx, &task.TaskUpdateDTO{ID: tk.ID, Status: task.TODO}); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if !repo.tasks[tk.ID].DoneAt.IsZero() {
+			t.Error("DoneAt deveria ser limpo ao reabrir")
+		}
+		history, err := svc.GetStatusHistory(ctx, tk.ID.String())
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		last := history.Transitions[len(history.Transitions)-1]
+		if last.FromStatus != task.DONE || last.ToStatus != task.TODO {
+			t.Errorf("Última transição esperada DONE -> TODO, recebida %s -> %s", last.FromStatus, last.ToStatus)
+		}
+		if history.CycleTimeSeconds != nil {
+			t.Error("Tarefa reaberta não deveria ter cycle time")
+		}
+	})
+
+	t.Run("IgnoresDoneAtForOpenTask", func(t *testing.T) {
+		doneAt := util.LocalDateTime{Time: time.Now().Add(-time.Hour)}
+		if _, err := svc.UpdateTask(ctx, &task.TaskUpdateDTO{ID: tk.ID, DoneAt: doneAt}); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if !repo.tasks[tk.ID].DoneAt.IsZero() {
+			t.Error("DoneAt não deveria ser aceito para tarefa aberta")
+		}
+	})
+}
@@ -0,0 +1,331 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/activity"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+)
+
+var (
+	ErrInvalidParent         = errors.New("invalid parent task")
+	ErrChecklistItemNotFound = errors.New("checklist item not found")
+	ErrEmptyChecklistItem    = errors.New("checklist item title cannot be empty")
+	ErrInvalidChecklistOrder = errors.New("checklist order must list every item once")
+)
+
+func (s *taskService) ListSubtasks(ctx context.Context, taskID string) ([]*Task, error) {
+	parent, err := s.findOwnedTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.repo.ListSubtasks(parent.ID, parent.UserID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list subtasks")
+		return nil, err
+	}
+
+	localizeTasks(tasks, userLocation(ctx, s.userRepo, parent.UserID))
+	s.annotateTasks(ctx, tasks)
+	return tasks, nil
+}
+
+func (s *taskService) ListChecklist(ctx context.Context, taskID string) ([]*ChecklistItem, error) {
+	t, err := s.findOwnedTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.ListChecklistItems(t.ID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list checklist items")
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *taskService) AddChecklistItem(ctx context.Context, taskID string, dto *ChecklistItemDTO) (*ChecklistItem, error) {
+	t, err := s.findOwnedTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	title := strings.TrimSpace(dto.Title)
+	if title == "" {
+		return nil, ErrEmptyChecklistItem
+	}
+
+	now := time.Now()
+	item := &ChecklistItem{
+		ID:        uuid.New(),
+		TaskID:    t.ID,
+		UserID:    t.UserID,
+		Title:     title,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if dto.Done != nil && *dto.Done {
+		item.Done = true
+		item.DoneAt = &now
+	}
+
+	err = s.repo.Transaction(func(tx TaskRepository) error {
+		if err := tx.CreateChecklistItem(item); err != nil {
+			return err
+		}
+		return s.autoCompleteParents(tx, &t.ID, t.UserID, now)
+	})
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to create checklist item")
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (s *taskService) UpdateChecklistItem(ctx context.Context, taskID, itemID string, dto *ChecklistItemDTO) (*ChecklistItem, error) {
+	t, item, err := s.findChecklistItem(ctx, taskID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if dto.Title != "" {
+		item.Title = strings.TrimSpace(dto.Title)
+		if item.Title == "" {
+			return nil, ErrEmptyChecklistItem
+		}
+	}
+
+	now := time.Now()
+	if dto.Done != nil && *dto.Done != item.Done {
+		item.Done = *dto.Done
+		item.DoneAt = nil
+		if item.Done {
+			item.DoneAt = &now
+		}
+	}
+	item.UpdatedAt = now
+
+	err = s.repo.Transaction(func(tx TaskRepository) error {
+		if err := tx.UpdateChecklistItem(item); err != nil {
+			return err
+		}
+		if !item.Done {
+			return nil
+		}
+		return s.autoCompleteParents(tx, &t.ID, t.UserID, now)
+	})
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to update checklist item")
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (s *taskService) DeleteChecklistItem(ctx context.Context, taskID, itemID string) error {
+	t, item, err := s.findChecklistItem(ctx, taskID, itemID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteChecklistItem(item.ID, t.ID); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return ErrChecklistItemNotFound
+		}
+		config.WithContext(ctx).WithError(err).Error("Failed to delete checklist item")
+		return err
+	}
+	return nil
+}
+
+// ReorderChecklist takes the IDs of all the task's items in their new order.
+func (s *taskService) ReorderChecklist(ctx context.Context, taskID string, itemIDs []uuid.UUID) ([]*ChecklistItem, error) {
+	t, err := s.findOwnedTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.ListChecklistItems(t.ID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list checklist items")
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*ChecklistItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	if len(itemIDs) != len(items) {
+		return nil, ErrInvalidChecklistOrder
+	}
+	ordered := make([]*ChecklistItem, 0, len(items))
+	for i, id := range itemIDs {
+		item, ok := byID[id]
+		if !ok {
+			return nil, ErrInvalidChecklistOrder
+		}
+		delete(byID, id)
+		item.Position = i
+		ordered = append(ordered, item)
+	}
+
+	err = s.repo.Transaction(func(tx TaskRepository) error {
+		return tx.SetChecklistPositions(t.ID, itemIDs)
+	})
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to reorder checklist")
+		return nil, err
+	}
+
+	return ordered, nil
+}
+
+// ============= Helper Methods =============
+
+func (s *taskService) findOwnedTask(ctx context.Context, taskID string) (*Task, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.parseUUID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getTaskByID(ctx, id, userID)
+}
+
+func (s *taskService) findChecklistItem(ctx context.Context, taskID, itemID string) (*Task, *ChecklistItem, error) {
+	t, err := s.findOwnedTask(ctx, taskID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	id, err := s.parseUUID(ctx, itemID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	item, err := s.repo.FindChecklistItem(id, t.ID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil, ErrChecklistItemNotFound
+		}
+		config.WithContext(ctx).WithError(err).Error("Failed to load checklist item")
+		return nil, nil, err
+	}
+	return t, item, nil
+}
+
+// validateParent checks that parentID is another task of the user and not
+// one of t's own subtasks, which would make a cycle.
+func (s *taskService) validateParent(ctx context.Context, t *Task, parentID uuid.UUID) (*Task, error) {
+	if parentID == t.ID {
+		return nil, ErrInvalidParent
+	}
+
+	parent, err := s.repo.FindByIdAndUserId(parentID, t.UserID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrInvalidParent
+		}
+		config.WithContext(ctx).WithError(err).Error("Failed to load parent task")
+		return nil, err
+	}
+
+	descendants, err := s.repo.ListDescendants(t.ID, t.UserID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list subtasks")
+		return nil, err
+	}
+	for _, d := range descendants {
+		if d.ID == parentID {
+			return nil, ErrInvalidParent
+		}
+	}
+
+	return parent, nil
+}
+
+// autoCompleteParents walks up from parentID marking each task DONE while it
+// opts in with AutoComplete, all of its subtasks and checklist items are
+// done and no blocker is still open.
+func (s *taskService) autoCompleteParents(tx TaskRepository, parentID *uuid.UUID, userID uuid.UUID, now time.Time) error {
+	for parentID != nil {
+		parent, err := tx.FindByIdAndUserId(*parentID, userID)
+		if err != nil {
+			return err
+		}
+		if !parent.AutoComplete || parent.Status == DONE {
+			return nil
+		}
+
+		counts, err := tx.CountChildren([]uuid.UUID{parent.ID})
+		if err != nil {
+			return err
+		}
+		if c := counts[parent.ID]; c.Total == 0 || c.Done < c.Total {
+			return nil
+		}
+		// A blocked parent stays open, and so does every task above it.
+		if blocked, err := isBlocked(tx, parent.ID); err != nil || blocked {
+			return err
+		}
+
+		before := snapshotActivity(parent)
+		change, err := changeStatus(parent, DONE, now)
+		if err != nil {
+			return err
+		}
+		parent.UpdatedAt = now
+		if err := tx.Update(parent); err != nil {
+			return err
+		}
+		if err := tx.RecordStatusChange(change); err != nil {
+			return err
+		}
+		if err := tx.RecordActivity(newTaskActivity(parent, activity.Updated, activityChanges(before, parent))); err != nil {
+			return err
+		}
+		parentID = parent.ParentID
+	}
+	return nil
+}
+
+// annotateTasks fills in the computed Progress and Blocked fields. A failure
+// only leaves them at their zero values, so the listing itself still
+// succeeds.
+func (s *taskService) annotateTasks(ctx context.Context, tasks []*Task) {
+	if len(tasks) == 0 {
+		return
+	}
+
+	ids := make([]uuid.UUID, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.ID
+	}
+
+	if counts, err := s.repo.CountChildren(ids); err != nil {
+		config.WithContext(ctx).WithError(err).Warn("Failed to count subtasks")
+	} else {
+		for _, t := range tasks {
+			t.Progress = counts[t.ID].Percent(t.Status)
+		}
+	}
+
+	if open, err := s.repo.ListOpenBlockers(ids); err != nil {
+		config.WithContext(ctx).WithError(err).Warn("Failed to list blocking tasks")
+	} else {
+		for _, t := range tasks {
+			t.BlockedBy = open[t.ID]
+			t.Blocked = len(t.BlockedBy) > 0
+		}
+	}
+}
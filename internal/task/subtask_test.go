@@ -0,0 +1,230 @@
+package task_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/activity"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+)
+
+// fakeTreeRepo keeps tasks in memory with their parent links.
+type fakeTreeRepo struct {
+	task.TaskRepository
+	tasks    map[uuid.UUID]*task.Task
+	items    map[uuid.UUID][]*task.ChecklistItem
+	deps     []*task.TaskDependency
+	history  []*task.TaskStatusChange
+	trashed  map[uuid.UUID]*task.Task
+	activity []*activity.Entry
+	queued   []*task.CalendarOutboxEntry
+}
+
+func (f *fakeTreeRepo) FindByIdAndUserId(id, userId uuid.UUID) (*task.Task, error) {
+	if t, ok := f.tasks[id]; ok && t.UserID == userId {
+		copied := *t
+		return &copied, nil
+	}
+	return nil, task.ErrNotFound
+}
+
+func (f *fakeTreeRepo) Update(t *task.Task) error {
+	f.tasks[t.ID] = t
+	return nil
+}
+
+func (f *fakeTreeRepo) Transaction(fn func(tx task.TaskRepository) error) error {
+	return fn(f)
+}
+
+func (f *fakeTreeRepo) ListByUser(userId uuid.UUID) ([]*task.Task, error) {
+	var tasks []*task.Task
+	for _, t := range f.tasks {
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+func (f *fakeTreeRepo) ListDescendants(id, userId uuid.UUID) ([]*task.Task, error) {
+	var result []*task.Task
+	for _, t := range f.tasks {
+		if t.ParentID != nil && *t.ParentID == id {
+			result = append(result, t)
+			below, _ := f.ListDescendants(t.ID, userId)
+			result = append(result, below...)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeTreeRepo) CountChildren(ids []uuid.UUID) (map[uuid.UUID]task.ChildCounts, error) {
+	counts := map[uuid.UUID]task.ChildCounts{}
+	for _, id := range ids {
+		var c task.ChildCounts
+		for _, t := range f.tasks {
+			if t.ParentID != nil && *t.ParentID == id {
+				c.Total++
+				if t.Status == task.DONE {
+					c.Done++
+				}
+			}
+		}
+		for _, item := range f.items[id] {
+			c.Total++
+			if item.Done {
+				c.Done++
+			}
+		}
+		counts[id] = c
+	}
+	return counts, nil
+}
+
+func (f *fakeTreeRepo) ListOpenBlockers(ids []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	open := map[uuid.UUID][]uuid.UUID{}
+	for _, d := range f.deps {
+		if f.tasks[d.BlockerID].Status != task.DONE {
+			open[d.BlockedID] = append(open[d.BlockedID], d.BlockerID)
+		}
+	}
+	return open, nil
+}
+
+func (f *fakeTreeRepo) RecordStatusChange(c *task.TaskStatusChange) error {
+	f.history = append(f.history, c)
+	return nil
+}
+
+func (f *fakeTreeRepo) ListStatusHistory(taskId uuid.UUID) ([]*task.TaskStatusChange, error) {
+	var changes []*task.TaskStatusChange
+	for _, c := range f.history {
+		if c.TaskID == taskId {
+			changes = append(changes, c)
+		}
+	}
+	return changes, nil
+}
+
+func (f *fakeTreeRepo) RecordActivity(e *activity.Entry) error {
+	f.activity = append(f.activity, e)
+	return nil
+}
+
+func (f *fakeTreeRepo) EnqueueCalendarSync(e *task.CalendarOutboxEntry) error {
+	f.queued = append(f.queued, e)
+	return nil
+}
+
+func (f *fakeTreeRepo) Restore(id, userId uuid.UUID) ([]*task.Task, error) {
+	t, ok := f.trashed[id]
+	if !ok || t.UserID != userId {
+		return nil, task.ErrNotFound
+	}
+	delete(f.trashed, id)
+	f.tasks[id] = t
+	return []*task.Task{t}, nil
+}
+
+func (f *fakeTreeRepo) add(t *task.Task) *task.Task {
+	f.tasks[t.ID] = t
+	return t
+}
+
+func TestSubtasks(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.UserDataKeyID, userID.String())
+	ctx = context.WithValue(ctx, auth.UserDataKeyRole, "user")
+
+	repo := &fakeTreeRepo{tasks: map[uuid.UUID]*task.Task{}, items: map[uuid.UUID][]*task.ChecklistItem{}}
+	svc := task.NewService(repo, nil, &fakeLocationUserRepo{}, nil)
+
+	root := repo.add(&task.Task{ID: uuid.New(), UserID: userID, Name: "Projeto", Status: task.TODO, AutoComplete: true})
+	parent := repo.add(&task.Task{ID: uuid.New(), UserID: userID, Name: "Fase 1", Status: task.TODO, ParentID: &root.ID, AutoComplete: true})
+	first := repo.add(&task.Task{ID: uuid.New(), UserID: userID, Name: "Passo 1", Status: task.DONE, ParentID: &parent.ID})
+	second := repo.add(&task.Task{ID: uuid.New(), UserID: userID, Name: "Passo 2", Status: task.TODO, ParentID: &parent.ID})
+	repo.items[parent.ID] = []*task.ChecklistItem{{ID: uuid.New(), TaskID: parent.ID, Done: true}}
+
+	t.Run("ProgressCountsSubtasksAndChecklist", func(t *testing.T) {
+		found, err := svc.FindByID(ctx, parent.ID.String())
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if found.Progress != 66 {
+			t.Errorf("Progresso esperado 66, recebido %d", found.Progress)
+		}
+	})
+
+	t.Run("DashboardIgnoresSubtasks", func(t *testing.T) {
+		stats, err := svc.GetDashboardStats(ctx)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if stats.Stats.Total != 1 {
+			t.Errorf("Dashboard deveria contar só tarefas de primeiro nível, contou %d", stats.Stats.Total)
+		}
+	})
+
+	t.Run("RejectsCyclicParent", func(t *testing.T) {
+		_, err := svc.UpdateTask(ctx, &task.TaskUpdateDTO{ID: root.ID, ParentID: &first.ID})
+		if !errors.Is(err, task.ErrInvalidParent) {
+			t.Errorf("Mover tarefa para baixo de um descendente deveria falhar, erro: %v", err)
+		}
+		_, err = svc.UpdateTask(ctx, &task.TaskUpdateDTO{ID: root.ID, ParentID: &root.ID})
+		if !errors.Is(err, task.ErrInvalidParent) {
+			t.Errorf("Tarefa não pode ser pai de si mesma, erro: %v", err)
+		}
+	})
+
+	t.Run("CompletingLastChildCompletesAncestors", func(t *testing.T) {
+		if _, err := svc.UpdateTask(ctx, &task.TaskUpdateDTO{ID: second.ID, Status: task.DONE}); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if repo.tasks[parent.ID].Status != task.DONE || repo.tasks[parent.ID].DoneAt.IsZero() {
+			t.Error("Tarefa pai deveria ser concluída automaticamente")
+		}
+		if repo.tasks[root.ID].Status != task.DONE {
+			t.Error("Conclusão automática deveria subir até a raiz")
+		}
+	})
+
+	t.Run("ParentWithoutAutoCompleteStaysOpen", func(t *testing.T) {
+		manual := repo.add(&task.Task{ID: uuid.New(), UserID: userID, Name: "Manual", Status: task.TODO})
+		child := repo.add(&task.Task{ID: uuid.New(), UserID: userID, Name: "Filho", Status: task.TODO, ParentID: &manual.ID})
+		if _, err := svc.UpdateTask(ctx, &task.TaskUpdateDTO{ID: child.ID, Status: task.DONE}); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if repo.tasks[manual.ID].Status != task.TODO {
+			t.Error("Tarefa pai sem conclusão automática não deveria mudar")
+		}
+	})
+
+	t.Run("RestoreDetachesFromTrashedParent", func(t *testing.T) {
+		trashedParent := uuid.New()
+		child := &task.Task{ID: uuid.New(), UserID: userID, Name: "Órfã", Status: task.TODO, ParentID: &trashedParent}
+		repo.trashed = map[uuid.UUID]*task.Task{child.ID: child}
+
+		restored, err := svc.RestoreTask(ctx, child.ID.String())
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if restored.ParentID != nil || repo.tasks[child.ID].ParentID != nil {
+			t.Error("Tarefa restaurada com pai na lixeira deveria ficar sem pai")
+		}
+
+		if _, err := svc.RestoreTask(ctx, child.ID.String()); !errors.Is(err, task.ErrTaskNotFound) {
+			t.Errorf("Tarefa fora da lixeira deveria falhar, erro: %v", err)
+		}
+	})
+}
+
+func TestChildCountsPercent(t *testing.T) {
+	if p := (task.ChildCounts{}).Percent(task.DONE); p != 100 {
+		t.Errorf("Tarefa concluída sem filhos deveria ter 100%%, recebido %d", p)
+	}
+	if p := (task.ChildCounts{Total: 4, Done: 1}).Percent(task.DONE); p != 25 {
+		t.Errorf("Progresso esperado 25, recebido %d", p)
+	}
+}
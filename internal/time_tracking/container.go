@@ -0,0 +1,29 @@
+package timetracking
+
+import (
+	"github.com/saulo-duarte/chronos-lambda/internal/project"
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	"github.com/saulo-duarte/chronos-lambda/internal/user"
+	"gorm.io/gorm"
+)
+
+type TimeTrackingContainer struct {
+	Handler *Handler
+	Service Service
+}
+
+func NewTimeTrackingContainer(
+	db *gorm.DB,
+	taskRepo task.TaskRepository,
+	projectService project.ProjectService,
+	studyTopicRepo studytopic.StudyTopicRepository,
+	userRepo user.UserRepository,
+) *TimeTrackingContainer {
+	service := NewService(NewRepository(db), taskRepo, projectService, studyTopicRepo, userRepo)
+
+	return &TimeTrackingContainer{
+		Handler: NewHandler(service),
+		Service: service,
+	}
+}
@@ -0,0 +1,57 @@
+package timetracking
+
+import (
+	"github.com/google/uuid"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+)
+
+type StartTimerDTO struct {
+	TaskID uuid.UUID `json:"task_id"`
+	Note   string    `json:"note"`
+}
+
+// PomodoroDTO starts a Pomodoro session. Zero lengths use the defaults.
+type PomodoroDTO struct {
+	TaskID       uuid.UUID `json:"task_id"`
+	WorkMinutes  int       `json:"work_minutes"`
+	BreakMinutes int       `json:"break_minutes"`
+	Note         string    `json:"note"`
+}
+
+type ManualEntryDTO struct {
+	TaskID    uuid.UUID          `json:"task_id"`
+	StartedAt util.LocalDateTime `json:"started_at"`
+	EndedAt   util.LocalDateTime `json:"ended_at"`
+	Note      string             `json:"note"`
+}
+
+// TimeTotals is the estimated and tracked effort of a task, project or study
+// topic, with the split per task.
+type TimeTotals struct {
+	EstimatedSeconds int64        `json:"estimated_seconds"`
+	ActualSeconds    int64        `json:"actual_seconds"`
+	Tasks            []*TaskTotal `json:"tasks"`
+}
+
+type ReportPeriod string
+
+const (
+	DAY  ReportPeriod = "day"
+	WEEK ReportPeriod = "week"
+)
+
+// TimeReport is the time tracked per day or per week (starting on Monday)
+// in the user's time zone. Periods without tracked time are included.
+type TimeReport struct {
+	From         string          `json:"from"`
+	To           string          `json:"to"`
+	Period       ReportPeriod    `json:"period"`
+	TotalSeconds int64           `json:"total_seconds"`
+	Buckets      []*ReportBucket `json:"buckets"`
+}
+
+type ReportBucket struct {
+	Start     string `json:"start"`
+	Seconds   int64  `json:"seconds"`
+	Pomodoros int    `json:"pomodoros"`
+}
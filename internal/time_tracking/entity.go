@@ -0,0 +1,61 @@
+package timetracking
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/user"
+)
+
+type EntryKind string
+
+const (
+	TIMER    EntryKind = "TIMER"
+	MANUAL   EntryKind = "MANUAL"
+	POMODORO EntryKind = "POMODORO"
+)
+
+// TimeEntry is time spent on a task. An entry without EndedAt is the user's
+// running timer; there is at most one per user.
+type TimeEntry struct {
+	ID              uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
+	TaskID          uuid.UUID  `gorm:"type:uuid;not null" json:"task_id"`
+	UserID          uuid.UUID  `gorm:"column:user_id;not null" json:"user_id"`
+	User            user.User  `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-"`
+	Kind            EntryKind  `json:"kind"`
+	Note            string     `json:"note,omitempty"`
+	StartedAt       time.Time  `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at"`
+	DurationSeconds int64      `json:"duration_seconds"`
+	// WorkMinutes and BreakMinutes are the lengths chosen for a Pomodoro
+	// session.
+	WorkMinutes    int        `json:"work_minutes,omitempty"`
+	BreakMinutes   int        `json:"break_minutes,omitempty"`
+	ElapsedSeconds int64      `gorm:"-" json:"elapsed_seconds,omitempty"`
+	FocusEndsAt    *time.Time `gorm:"-" json:"focus_ends_at,omitempty"`
+	BreakEndsAt    *time.Time `gorm:"-" json:"break_ends_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func (TimeEntry) TableName() string {
+	return "time_entries"
+}
+
+func (e *TimeEntry) Running() bool {
+	return e.EndedAt == nil
+}
+
+// focusEnd is when the work part of a Pomodoro session is over; the session
+// counts no time past it.
+func (e *TimeEntry) focusEnd() time.Time {
+	return e.StartedAt.Add(time.Duration(e.WorkMinutes) * time.Minute)
+}
+
+// TaskTotal compares the time tracked on a task with its estimate.
+type TaskTotal struct {
+	TaskID           uuid.UUID `json:"task_id"`
+	Name             string    `json:"name"`
+	EstimatedSeconds int64     `json:"estimated_seconds"`
+	ActualSeconds    int64     `json:"actual_seconds"`
+}
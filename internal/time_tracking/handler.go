@@ -0,0 +1,239 @@
+package timetracking
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/i18n"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+func (h *Handler) Current(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	entry, err := h.service.Current(r.Context())
+	if errors.Is(err, ErrNoTimerRunning) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err != nil {
+		if !writeTimeError(w, r, err) {
+			log.WithError(err).Error("Failed to get running timer")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, entry)
+}
+
+func (h *Handler) Start(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload StartTimerDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.TaskID == uuid.Nil {
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.service.Start(r.Context(), &payload)
+	if err != nil {
+		if !writeTimeError(w, r, err) {
+			log.WithError(err).Error("Failed to start timer")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, entry)
+}
+
+func (h *Handler) StartPomodoro(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload PomodoroDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.TaskID == uuid.Nil {
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.service.StartPomodoro(r.Context(), &payload)
+	if err != nil {
+		if !writeTimeError(w, r, err) {
+			log.WithError(err).Error("Failed to start pomodoro session")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, entry)
+}
+
+func (h *Handler) Stop(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	entry, err := h.service.Stop(r.Context())
+	if err != nil {
+		if !writeTimeError(w, r, err) {
+			log.WithError(err).Error("Failed to stop timer")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, entry)
+}
+
+func (h *Handler) AddManual(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload ManualEntryDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.TaskID == uuid.Nil {
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.service.AddManual(r.Context(), &payload)
+	if err != nil {
+		if !writeTimeError(w, r, err) {
+			log.WithError(err).Error("Failed to create time entry")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, entry)
+}
+
+func (h *Handler) ListByTask(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	entries, err := h.service.ListByTask(r.Context(), chi.URLParam(r, "taskID"))
+	if err != nil {
+		if !writeTimeError(w, r, err) {
+			log.WithError(err).Error("Failed to list time entries")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, entries)
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	if err := h.service.Delete(r.Context(), chi.URLParam(r, "entryID")); err != nil {
+		if !writeTimeError(w, r, err) {
+			log.WithError(err).Error("Failed to delete time entry")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) TaskTotals(w http.ResponseWriter, r *http.Request) {
+	h.writeTotals(w, r, h.service.TaskTotals, "taskID")
+}
+
+func (h *Handler) ProjectTotals(w http.ResponseWriter, r *http.Request) {
+	h.writeTotals(w, r, h.service.ProjectTotals, "projectID")
+}
+
+func (h *Handler) StudyTopicTotals(w http.ResponseWriter, r *http.Request) {
+	h.writeTotals(w, r, h.service.StudyTopicTotals, "studyTopicID")
+}
+
+func (h *Handler) Report(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	from, ok := queryDate(r, "from")
+	if !ok {
+		i18n.Error(w, r, "invalid from date", http.StatusBadRequest)
+		return
+	}
+	to, ok := queryDate(r, "to")
+	if !ok {
+		i18n.Error(w, r, "invalid to date", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.Report(r.Context(), from, to, ReportPeriod(r.URL.Query().Get("period")))
+	if err != nil {
+		if !writeTimeError(w, r, err) {
+			log.WithError(err).Error("Failed to build time report")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, report)
+}
+
+func (h *Handler) writeTotals(
+	w http.ResponseWriter,
+	r *http.Request,
+	totals func(ctx context.Context, id string) (*TimeTotals, error),
+	param string,
+) {
+	result, err := totals(r.Context(), chi.URLParam(r, param))
+	if err != nil {
+		if !writeTimeError(w, r, err) {
+			config.WithContext(r.Context()).WithError(err).Error("Failed to sum tracked time")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, result)
+}
+
+// queryDate reads an optional date parameter; a missing one is zero.
+func queryDate(r *http.Request, name string) (util.LocalDateTime, bool) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return util.LocalDateTime{}, true
+	}
+	date, err := util.ParseLocalDateTime(value)
+	return date, err == nil
+}
+
+func writeTimeError(w http.ResponseWriter, r *http.Request, err error) bool {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrInvalidID):
+		i18n.Error(w, r, "invalid id", http.StatusBadRequest)
+	case errors.Is(err, ErrTaskNotFound):
+		i18n.Error(w, r, "task not found", http.StatusNotFound)
+	case errors.Is(err, ErrProjectNotFound):
+		i18n.Error(w, r, "project not found", http.StatusNotFound)
+	case errors.Is(err, ErrStudyTopicNotFound):
+		i18n.Error(w, r, "study topic not found", http.StatusNotFound)
+	case errors.Is(err, ErrTimeEntryNotFound), errors.Is(err, ErrNoTimerRunning):
+		i18n.Error(w, r, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrTimerRunning):
+		i18n.Error(w, r, err.Error(), http.StatusConflict)
+	case errors.Is(err, ErrInvalidTimeEntry), errors.Is(err, ErrInvalidPomodoro),
+		errors.Is(err, ErrInvalidReportRange), errors.Is(err, ErrInvalidReportPeriod):
+		i18n.Error(w, r, err.Error(), http.StatusBadRequest)
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+package timetracking
+
+import (
+	"time"
+
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+)
+
+// BuildReport buckets the entries per day or week from the start date to
+// the end date, both inclusive midnights in the user's time zone. An entry
+// crossing midnight is split between the periods it spans; a finished
+// Pomodoro session counts in the period it started.
+func BuildReport(entries []*TimeEntry, start, end time.Time, period ReportPeriod) *TimeReport {
+	report := &TimeReport{
+		From:    util.FormatDate(start),
+		To:      util.FormatDate(end),
+		Period:  period,
+		Buckets: make([]*ReportBucket, 0),
+	}
+
+	loc := start.Location()
+	limit := end.AddDate(0, 0, 1)
+	index := make(map[string]int)
+	durations := make([]time.Duration, 0)
+	for d := periodStart(start, period); d.Before(limit); d = nextPeriod(d, period) {
+		index[util.FormatDate(d)] = len(report.Buckets)
+		report.Buckets = append(report.Buckets, &ReportBucket{Start: util.FormatDate(d)})
+		durations = append(durations, 0)
+	}
+
+	for _, e := range entries {
+		if e.EndedAt == nil {
+			continue
+		}
+		from, to := e.StartedAt.In(loc), e.EndedAt.In(loc)
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(limit) {
+			to = limit
+		}
+
+		for cur := from; cur.Before(to); {
+			bucketStart := periodStart(cur, period)
+			next := nextPeriod(bucketStart, period)
+			segmentEnd := to
+			if next.Before(segmentEnd) {
+				segmentEnd = next
+			}
+			if i, ok := index[util.FormatDate(bucketStart)]; ok {
+				durations[i] += segmentEnd.Sub(cur)
+			}
+			cur = segmentEnd
+		}
+
+		if e.Kind == POMODORO && e.DurationSeconds >= int64(e.WorkMinutes)*60 && !e.StartedAt.Before(start) {
+			if i, ok := index[util.FormatDate(periodStart(e.StartedAt.In(loc), period))]; ok {
+				report.Buckets[i].Pomodoros++
+			}
+		}
+	}
+
+	for i, d := range durations {
+		report.Buckets[i].Seconds = int64(d.Seconds())
+		report.TotalSeconds += report.Buckets[i].Seconds
+	}
+	return report
+}
+
+// periodStart is the midnight opening t's day, or the Monday of its week.
+func periodStart(t time.Time, period ReportPeriod) time.Time {
+	day := util.DateOnly(t)
+	if period == WEEK {
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	}
+	return day
+}
+
+func nextPeriod(t time.Time, period ReportPeriod) time.Time {
+	if period == WEEK {
+		return t.AddDate(0, 0, 7)
+	}
+	return t.AddDate(0, 0, 1)
+}
@@ -0,0 +1,118 @@
+package timetracking
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TotalsScope is the tasks column TaskTotals filters on.
+type TotalsScope string
+
+const (
+	ScopeTask       TotalsScope = "id"
+	ScopeProject    TotalsScope = "project_id"
+	ScopeStudyTopic TotalsScope = "study_topic_id"
+)
+
+type TimeEntryRepository interface {
+	// Create fails with ErrTimerRunning when e is a second running timer.
+	Create(e *TimeEntry) error
+	Update(e *TimeEntry) error
+	Delete(id, userID uuid.UUID) error
+	FindByIDAndUser(id, userID uuid.UUID) (*TimeEntry, error)
+	FindRunning(userID uuid.UUID) (*TimeEntry, error)
+	ListByTask(taskID, userID uuid.UUID) ([]*TimeEntry, error)
+	// ListFinishedBetween returns the finished entries overlapping [from, to).
+	ListFinishedBetween(userID uuid.UUID, from, to time.Time) ([]*TimeEntry, error)
+	// TaskTotals sums the finished entries of every task matching scope.
+	TaskTotals(userID uuid.UUID, scope TotalsScope, id uuid.UUID) ([]*TaskTotal, error)
+}
+
+type timeEntryRepository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) TimeEntryRepository {
+	return &timeEntryRepository{db: db}
+}
+
+func (r *timeEntryRepository) Create(e *TimeEntry) error {
+	err := r.db.Create(e).Error
+	// The partial unique index on running entries settles concurrent starts.
+	if translator, ok := r.db.Dialector.(gorm.ErrorTranslator); ok && err != nil {
+		if errors.Is(translator.Translate(err), gorm.ErrDuplicatedKey) {
+			return ErrTimerRunning
+		}
+	}
+	return err
+}
+
+func (r *timeEntryRepository) Update(e *TimeEntry) error {
+	return r.db.Save(e).Error
+}
+
+func (r *timeEntryRepository) Delete(id, userID uuid.UUID) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&TimeEntry{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTimeEntryNotFound
+	}
+	return nil
+}
+
+func (r *timeEntryRepository) FindByIDAndUser(id, userID uuid.UUID) (*TimeEntry, error) {
+	var e TimeEntry
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&e).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTimeEntryNotFound
+		}
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *timeEntryRepository) FindRunning(userID uuid.UUID) (*TimeEntry, error) {
+	var e TimeEntry
+	if err := r.db.Where("user_id = ? AND ended_at IS NULL", userID).First(&e).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoTimerRunning
+		}
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *timeEntryRepository) ListByTask(taskID, userID uuid.UUID) ([]*TimeEntry, error) {
+	var entries []*TimeEntry
+	err := r.db.Where("task_id = ? AND user_id = ?", taskID, userID).
+		Order("started_at DESC").
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *timeEntryRepository) ListFinishedBetween(userID uuid.UUID, from, to time.Time) ([]*TimeEntry, error) {
+	var entries []*TimeEntry
+	err := r.db.Where("user_id = ? AND ended_at IS NOT NULL AND started_at < ? AND ended_at > ?", userID, to, from).
+		Order("started_at").
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *timeEntryRepository) TaskTotals(userID uuid.UUID, scope TotalsScope, id uuid.UUID) ([]*TaskTotal, error) {
+	var totals []*TaskTotal
+	err := r.db.Table("tasks t").
+		Select(`t.id AS task_id, t.name,
+			COALESCE(t.estimated_minutes, 0) * 60 AS estimated_seconds,
+			COALESCE(SUM(e.duration_seconds), 0) AS actual_seconds`).
+		Joins("LEFT JOIN time_entries e ON e.task_id = t.id AND e.ended_at IS NOT NULL").
+		Where("t.user_id = ? AND t."+string(scope)+" = ? AND t.deleted_at IS NULL", userID, id).
+		Group("t.id, t.name, t.estimated_minutes").
+		Order("actual_seconds DESC, t.name").
+		Scan(&totals).Error
+	return totals, err
+}
@@ -0,0 +1,23 @@
+package timetracking
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+func Routes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/current", h.Current)
+	r.Post("/start", h.Start)
+	r.Post("/pomodoro", h.StartPomodoro)
+	r.Post("/stop", h.Stop)
+	r.Post("/", h.AddManual)
+	r.Delete("/{entryID}", h.Delete)
+	r.Get("/tasks/{taskID}", h.ListByTask)
+	r.Get("/totals/tasks/{taskID}", h.TaskTotals)
+	r.Get("/totals/projects/{projectID}", h.ProjectTotals)
+	r.Get("/totals/study-topics/{studyTopicID}", h.StudyTopicTotals)
+	r.Get("/report", h.Report)
+
+	return r
+}
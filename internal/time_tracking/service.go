@@ -0,0 +1,479 @@
+package timetracking
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/project"
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	"github.com/saulo-duarte/chronos-lambda/internal/user"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+)
+
+var (
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrInvalidID           = errors.New("invalid id format")
+	ErrTaskNotFound        = task.ErrTaskNotFound
+	ErrProjectNotFound     = project.ErrProjectNotFound
+	ErrStudyTopicNotFound  = studytopic.ErrStudyTopicNotFound
+	ErrTimerRunning        = errors.New("a timer is already running")
+	ErrNoTimerRunning      = errors.New("no timer is running")
+	ErrTimeEntryNotFound   = errors.New("time entry not found")
+	ErrInvalidTimeEntry    = errors.New("invalid time entry")
+	ErrInvalidPomodoro     = errors.New("invalid pomodoro length")
+	ErrInvalidReportRange  = errors.New("invalid report range")
+	ErrInvalidReportPeriod = errors.New("invalid report period")
+)
+
+const (
+	// MaxEntryDuration caps a single entry, so a timer left running overnight
+	// does not count as days of work.
+	MaxEntryDuration = 12 * time.Hour
+
+	DefaultWorkMinutes  = 25
+	DefaultBreakMinutes = 5
+	maxWorkMinutes      = 120
+	maxBreakMinutes     = 60
+
+	maxReportRange = 366 * 24 * time.Hour
+)
+
+type Service interface {
+	// Current returns the running timer, or ErrNoTimerRunning.
+	Current(ctx context.Context) (*TimeEntry, error)
+	Start(ctx context.Context, dto *StartTimerDTO) (*TimeEntry, error)
+	StartPomodoro(ctx context.Context, dto *PomodoroDTO) (*TimeEntry, error)
+	Stop(ctx context.Context) (*TimeEntry, error)
+	AddManual(ctx context.Context, dto *ManualEntryDTO) (*TimeEntry, error)
+	ListByTask(ctx context.Context, taskID string) ([]*TimeEntry, error)
+	Delete(ctx context.Context, entryID string) error
+
+	TaskTotals(ctx context.Context, taskID string) (*TimeTotals, error)
+	ProjectTotals(ctx context.Context, projectID string) (*TimeTotals, error)
+	StudyTopicTotals(ctx context.Context, topicID string) (*TimeTotals, error)
+	Report(ctx context.Context, from, to util.LocalDateTime, period ReportPeriod) (*TimeReport, error)
+}
+
+type service struct {
+	repo           TimeEntryRepository
+	taskRepo       task.TaskRepository
+	projectService project.ProjectService
+	studyTopicRepo studytopic.StudyTopicRepository
+	userRepo       user.UserRepository
+	now            func() time.Time
+}
+
+func NewService(
+	repo TimeEntryRepository,
+	taskRepo task.TaskRepository,
+	projectService project.ProjectService,
+	studyTopicRepo studytopic.StudyTopicRepository,
+	userRepo user.UserRepository,
+) Service {
+	return &service{
+		repo:           repo,
+		taskRepo:       taskRepo,
+		projectService: projectService,
+		studyTopicRepo: studyTopicRepo,
+		userRepo:       userRepo,
+		now:            time.Now,
+	}
+}
+
+func (s *service) Current(ctx context.Context) (*TimeEntry, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := s.running(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.present(ctx, e), nil
+}
+
+func (s *service) Start(ctx context.Context, dto *StartTimerDTO) (*TimeEntry, error) {
+	return s.start(ctx, dto.TaskID, &TimeEntry{Kind: TIMER, Note: dto.Note})
+}
+
+func (s *service) StartPomodoro(ctx context.Context, dto *PomodoroDTO) (*TimeEntry, error) {
+	work, brk := dto.WorkMinutes, dto.BreakMinutes
+	if work == 0 {
+		work = DefaultWorkMinutes
+	}
+	if brk == 0 {
+		brk = DefaultBreakMinutes
+	}
+	if work < 1 || work > maxWorkMinutes || brk < 1 || brk > maxBreakMinutes {
+		return nil, ErrInvalidPomodoro
+	}
+
+	return s.start(ctx, dto.TaskID, &TimeEntry{Kind: POMODORO, Note: dto.Note, WorkMinutes: work, BreakMinutes: brk})
+}
+
+func (s *service) Stop(ctx context.Context) (*TimeEntry, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := s.running(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	finish(e, s.now())
+	if err := s.repo.Update(e); err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to stop timer")
+		return nil, err
+	}
+
+	config.WithContext(ctx).WithField("time_entry_id", e.ID).Info("Timer stopped")
+	return s.present(ctx, e), nil
+}
+
+func (s *service) AddManual(ctx context.Context, dto *ManualEntryDTO) (*TimeEntry, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.findTask(ctx, dto.TaskID, userID); err != nil {
+		return nil, err
+	}
+
+	loc := s.userLocation(ctx, userID)
+	start, end := dto.StartedAt.Localize(loc).Time, dto.EndedAt.Localize(loc).Time
+	now := s.now()
+	if start.IsZero() || !end.After(start) || end.After(now) || end.Sub(start) > MaxEntryDuration {
+		return nil, ErrInvalidTimeEntry
+	}
+
+	e := &TimeEntry{
+		ID:              uuid.New(),
+		TaskID:          dto.TaskID,
+		UserID:          userID,
+		Kind:            MANUAL,
+		Note:            dto.Note,
+		StartedAt:       start,
+		EndedAt:         &end,
+		DurationSeconds: int64(end.Sub(start).Seconds()),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := s.repo.Create(e); err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to create time entry")
+		return nil, err
+	}
+
+	return s.present(ctx, e), nil
+}
+
+func (s *service) ListByTask(ctx context.Context, taskID string) ([]*TimeEntry, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tid, err := s.parseUUID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.findTask(ctx, tid, userID); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.repo.ListByTask(tid, userID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list time entries")
+		return nil, err
+	}
+
+	for _, e := range entries {
+		s.present(ctx, e)
+	}
+	return entries, nil
+}
+
+func (s *service) Delete(ctx context.Context, entryID string) error {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	id, err := s.parseUUID(ctx, entryID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(id, userID); err != nil {
+		if !errors.Is(err, ErrTimeEntryNotFound) {
+			config.WithContext(ctx).WithError(err).Error("Failed to delete time entry")
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *service) TaskTotals(ctx context.Context, taskID string) (*TimeTotals, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tid, err := s.parseUUID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.findTask(ctx, tid, userID); err != nil {
+		return nil, err
+	}
+
+	return s.totals(ctx, userID, ScopeTask, tid)
+}
+
+func (s *service) ProjectTotals(ctx context.Context, projectID string) (*TimeTotals, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pid, err := s.parseUUID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.projectService.GetProjectByID(ctx, pid.String()); err != nil {
+		config.WithContext(ctx).WithError(err).WithField("project_id", pid).Warn("Project not found")
+		return nil, ErrProjectNotFound
+	}
+
+	return s.totals(ctx, userID, ScopeProject, pid)
+}
+
+func (s *service) StudyTopicTotals(ctx context.Context, topicID string) (*TimeTotals, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tid, err := s.parseUUID(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+	topic, err := s.studyTopicRepo.GetByID(tid.String())
+	if err != nil || topic == nil || topic.UserID != userID {
+		config.WithContext(ctx).WithError(err).WithField("study_topic_id", tid).Warn("Study topic not found")
+		return nil, ErrStudyTopicNotFound
+	}
+
+	return s.totals(ctx, userID, ScopeStudyTopic, tid)
+}
+
+// Report sums the tracked time per day or week between the from and to
+// dates, both inclusive. Without dates it covers the last seven days, or the
+// last four weeks.
+func (s *service) Report(ctx context.Context, from, to util.LocalDateTime, period ReportPeriod) (*TimeReport, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if period == "" {
+		period = DAY
+	}
+	if period != DAY && period != WEEK {
+		return nil, ErrInvalidReportPeriod
+	}
+
+	loc := s.userLocation(ctx, userID)
+	end := util.DateOnly(s.now().In(loc))
+	if !to.IsZero() {
+		end = util.DateOnly(to.Localize(loc).Time)
+	}
+	start := end.AddDate(0, 0, -6)
+	if period == WEEK {
+		start = end.AddDate(0, 0, -27)
+	}
+	if !from.IsZero() {
+		start = util.DateOnly(from.Localize(loc).Time)
+	}
+	start = periodStart(start, period)
+	if end.Before(start) || end.Sub(start) > maxReportRange {
+		return nil, ErrInvalidReportRange
+	}
+
+	entries, err := s.repo.ListFinishedBetween(userID, start, end.AddDate(0, 0, 1))
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list time entries for report")
+		return nil, err
+	}
+
+	return BuildReport(entries, start, end, period), nil
+}
+
+// ============= Helper Methods =============
+
+func (s *service) start(ctx context.Context, taskID uuid.UUID, e *TimeEntry) (*TimeEntry, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.findTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.running(ctx, userID); err == nil {
+		return nil, ErrTimerRunning
+	} else if !errors.Is(err, ErrNoTimerRunning) {
+		return nil, err
+	}
+
+	now := s.now()
+	e.ID = uuid.New()
+	e.TaskID = taskID
+	e.UserID = userID
+	e.StartedAt = now
+	e.CreatedAt = now
+	e.UpdatedAt = now
+	if err := s.repo.Create(e); err != nil {
+		if !errors.Is(err, ErrTimerRunning) {
+			config.WithContext(ctx).WithError(err).Error("Failed to start timer")
+		}
+		return nil, err
+	}
+
+	config.WithContext(ctx).WithFields(map[string]interface{}{
+		"time_entry_id": e.ID,
+		"kind":          e.Kind,
+	}).Info("Timer started")
+	return s.present(ctx, e), nil
+}
+
+// running loads the user's running timer. A Pomodoro session whose focus
+// time is over is closed on the way, so it no longer blocks a new timer.
+func (s *service) running(ctx context.Context, userID uuid.UUID) (*TimeEntry, error) {
+	e, err := s.repo.FindRunning(userID)
+	if err != nil {
+		if !errors.Is(err, ErrNoTimerRunning) {
+			config.WithContext(ctx).WithError(err).Error("Failed to load running timer")
+		}
+		return nil, err
+	}
+
+	now := s.now()
+	if e.Kind != POMODORO || now.Before(e.focusEnd()) {
+		return e, nil
+	}
+
+	finish(e, now)
+	if err := s.repo.Update(e); err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to finish pomodoro session")
+		return nil, err
+	}
+	return nil, ErrNoTimerRunning
+}
+
+// finish stops e at the given time, capped by the Pomodoro focus time and
+// MaxEntryDuration.
+func finish(e *TimeEntry, at time.Time) {
+	end := at
+	if limit := e.StartedAt.Add(MaxEntryDuration); end.After(limit) {
+		end = limit
+	}
+	if e.Kind == POMODORO && end.After(e.focusEnd()) {
+		end = e.focusEnd()
+	}
+	if end.Before(e.StartedAt) {
+		end = e.StartedAt
+	}
+
+	e.EndedAt = &end
+	e.DurationSeconds = int64(end.Sub(e.StartedAt).Seconds())
+	e.UpdatedAt = at
+}
+
+// present fills in the computed fields and moves the times to the user's
+// time zone.
+func (s *service) present(ctx context.Context, e *TimeEntry) *TimeEntry {
+	loc := s.userLocation(ctx, e.UserID)
+	e.StartedAt = e.StartedAt.In(loc)
+	if e.EndedAt != nil {
+		ended := e.EndedAt.In(loc)
+		e.EndedAt = &ended
+	} else {
+		e.ElapsedSeconds = int64(s.now().Sub(e.StartedAt).Seconds())
+	}
+
+	if e.Kind == POMODORO {
+		focusEnd := e.focusEnd().In(loc)
+		breakFrom := focusEnd
+		if e.EndedAt != nil {
+			breakFrom = *e.EndedAt
+		}
+		breakEnd := breakFrom.Add(time.Duration(e.BreakMinutes) * time.Minute)
+		e.FocusEndsAt = &focusEnd
+		e.BreakEndsAt = &breakEnd
+	}
+	return e
+}
+
+func (s *service) totals(ctx context.Context, userID uuid.UUID, scope TotalsScope, id uuid.UUID) (*TimeTotals, error) {
+	tasks, err := s.repo.TaskTotals(userID, scope, id)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to sum tracked time")
+		return nil, err
+	}
+
+	totals := &TimeTotals{Tasks: tasks}
+	for _, t := range tasks {
+		totals.EstimatedSeconds += t.EstimatedSeconds
+		totals.ActualSeconds += t.ActualSeconds
+	}
+	return totals, nil
+}
+
+func (s *service) findTask(ctx context.Context, taskID, userID uuid.UUID) (*task.Task, error) {
+	t, err := s.taskRepo.FindByIdAndUserId(taskID, userID)
+	if err != nil {
+		if errors.Is(err, task.ErrNotFound) {
+			return nil, ErrTaskNotFound
+		}
+		config.WithContext(ctx).WithError(err).Error("Failed to load task")
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *service) userLocation(ctx context.Context, userID uuid.UUID) *time.Location {
+	u, err := s.userRepo.GetByID(userID.String())
+	if err != nil || u == nil {
+		config.WithContext(ctx).WithError(err).Warn("Failed to load user time zone, using default")
+		return util.DefaultLocation()
+	}
+	return u.Location()
+}
+
+func (s *service) getUserID(ctx context.Context) (uuid.UUID, error) {
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Warn("Unauthorized access attempt")
+		return uuid.Nil, ErrUnauthorized
+	}
+	return uuid.MustParse(claims.UserID), nil
+}
+
+func (s *service) parseUUID(ctx context.Context, id string) (uuid.UUID, error) {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Warnf("Invalid ID: %s", id)
+		return uuid.Nil, ErrInvalidID
+	}
+	return parsedID, nil
+}
@@ -0,0 +1,265 @@
+package timetracking_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	timetracking "github.com/saulo-duarte/chronos-lambda/internal/time_tracking"
+	"github.com/saulo-duarte/chronos-lambda/internal/user"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+)
+
+func TestMain(m *testing.M) {
+	config.Init()
+	os.Exit(m.Run())
+}
+
+type fakeEntryRepo struct {
+	timetracking.TimeEntryRepository
+	entries map[uuid.UUID]*timetracking.TimeEntry
+}
+
+func (f *fakeEntryRepo) Create(e *timetracking.TimeEntry) error {
+	if e.Running() {
+		if _, err := f.FindRunning(e.UserID); err == nil {
+			return timetracking.ErrTimerRunning
+		}
+	}
+	copied := *e
+	f.entries[e.ID] = &copied
+	return nil
+}
+
+func (f *fakeEntryRepo) Update(e *timetracking.TimeEntry) error {
+	copied := *e
+	f.entries[e.ID] = &copied
+	return nil
+}
+
+func (f *fakeEntryRepo) FindRunning(userID uuid.UUID) (*timetracking.TimeEntry, error) {
+	for _, e := range f.entries {
+		if e.UserID == userID && e.Running() {
+			copied := *e
+			return &copied, nil
+		}
+	}
+	return nil, timetracking.ErrNoTimerRunning
+}
+
+func (f *fakeEntryRepo) TaskTotals(userID uuid.UUID, scope timetracking.TotalsScope, id uuid.UUID) ([]*timetracking.TaskTotal, error) {
+	return []*timetracking.TaskTotal{}, nil
+}
+
+type fakeTaskRepo struct {
+	task.TaskRepository
+	tasks map[uuid.UUID]*task.Task
+}
+
+func (f *fakeTaskRepo) FindByIdAndUserId(id, userId uuid.UUID) (*task.Task, error) {
+	if t, ok := f.tasks[id]; ok && t.UserID == userId {
+		return t, nil
+	}
+	return nil, task.ErrNotFound
+}
+
+type fakeTopicRepo struct {
+	studytopic.StudyTopicRepository
+	topics map[uuid.UUID]*studytopic.StudyTopic
+}
+
+func (f *fakeTopicRepo) GetByID(id string) (*studytopic.StudyTopic, error) {
+	return f.topics[uuid.MustParse(id)], nil
+}
+
+type fakeUserRepo struct {
+	user.UserRepository
+}
+
+func (f *fakeUserRepo) GetByID(id string) (*user.User, error) {
+	return &user.User{ID: uuid.MustParse(id)}, nil
+}
+
+func TestTimers(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.UserDataKeyID, userID.String())
+	ctx = context.WithValue(ctx, auth.UserDataKeyRole, "user")
+
+	tk := &task.Task{ID: uuid.New(), UserID: userID, Name: "Estudar"}
+	repo := &fakeEntryRepo{entries: map[uuid.UUID]*timetracking.TimeEntry{}}
+	tasks := &fakeTaskRepo{tasks: map[uuid.UUID]*task.Task{tk.ID: tk}}
+	svc := timetracking.NewService(repo, tasks, nil, nil, &fakeUserRepo{})
+
+	t.Run("OnlyOneTimerRuns", func(t *testing.T) {
+		if _, err := svc.Start(ctx, &timetracking.StartTimerDTO{TaskID: tk.ID}); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		_, err := svc.StartPomodoro(ctx, &timetracking.PomodoroDTO{TaskID: tk.ID})
+		if !errors.Is(err, timetracking.ErrTimerRunning) {
+			t.Errorf("Segundo cronômetro deveria ser recusado, erro: %v", err)
+		}
+
+		stopped, err := svc.Stop(ctx)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if stopped.EndedAt == nil {
+			t.Error("Cronômetro parado deveria ter horário de término")
+		}
+		if _, err := svc.Current(ctx); !errors.Is(err, timetracking.ErrNoTimerRunning) {
+			t.Errorf("Nenhum cronômetro deveria estar rodando, erro: %v", err)
+		}
+	})
+
+	t.Run("RejectsUnknownTask", func(t *testing.T) {
+		_, err := svc.Start(ctx, &timetracking.StartTimerDTO{TaskID: uuid.New()})
+		if !errors.Is(err, timetracking.ErrTaskNotFound) {
+			t.Errorf("Tarefa inexistente deveria falhar, erro: %v", err)
+		}
+	})
+
+	t.Run("ValidatesPomodoroLengths", func(t *testing.T) {
+		_, err := svc.StartPomodoro(ctx, &timetracking.PomodoroDTO{TaskID: tk.ID, WorkMinutes: 500})
+		if !errors.Is(err, timetracking.ErrInvalidPomodoro) {
+			t.Errorf("Duração de foco inválida deveria falhar, erro: %v", err)
+		}
+	})
+
+	t.Run("ExpiredPomodoroStopsAtFocusEnd", func(t *testing.T) {
+		started := time.Now().Add(-40 * time.Minute)
+		expired := &timetracking.TimeEntry{
+			ID:           uuid.New(),
+			TaskID:       tk.ID,
+			UserID:       userID,
+			Kind:         timetracking.POMODORO,
+			StartedAt:    started,
+			WorkMinutes:  25,
+			BreakMinutes: 5,
+		}
+		repo.entries[expired.ID] = expired
+
+		entry, err := svc.Start(ctx, &timetracking.StartTimerDTO{TaskID: tk.ID})
+		if err != nil {
+			t.Fatalf("Pomodoro encerrado não deveria bloquear novo cronômetro: %v", err)
+		}
+		if got := repo.entries[expired.ID].DurationSeconds; got != 25*60 {
+			t.Errorf("Pomodoro deveria contar só o tempo de foco, contou %ds", got)
+		}
+		if entry.Kind != timetracking.TIMER {
+			t.Errorf("Tipo esperado TIMER, recebido %s", entry.Kind)
+		}
+		if _, err := svc.Stop(ctx); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+	})
+
+	t.Run("ValidatesManualEntries", func(t *testing.T) {
+		now := time.Now()
+		cases := map[string]*timetracking.ManualEntryDTO{
+			"FimAntesDoInicio": {TaskID: tk.ID, StartedAt: util.LocalDateTime{Time: now.Add(-time.Hour)}, EndedAt: util.LocalDateTime{Time: now.Add(-2 * time.Hour)}},
+			"NoFuturo":         {TaskID: tk.ID, StartedAt: util.LocalDateTime{Time: now}, EndedAt: util.LocalDateTime{Time: now.Add(time.Hour)}},
+			"LongoDemais":      {TaskID: tk.ID, StartedAt: util.LocalDateTime{Time: now.Add(-30 * time.Hour)}, EndedAt: util.LocalDateTime{Time: now.Add(-time.Hour)}},
+		}
+		for name, dto := range cases {
+			if _, err := svc.AddManual(ctx, dto); !errors.Is(err, timetracking.ErrInvalidTimeEntry) {
+				t.Errorf("%s: esperado ErrInvalidTimeEntry, erro: %v", name, err)
+			}
+		}
+
+		entry, err := svc.AddManual(ctx, &timetracking.ManualEntryDTO{
+			TaskID:    tk.ID,
+			StartedAt: util.LocalDateTime{Time: now.Add(-90 * time.Minute)},
+			EndedAt:   util.LocalDateTime{Time: now.Add(-30 * time.Minute)},
+		})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if entry.DurationSeconds != 3600 {
+			t.Errorf("Duração esperada 3600s, recebida %ds", entry.DurationSeconds)
+		}
+	})
+}
+
+func TestStudyTopicTotals(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.UserDataKeyID, userID.String())
+	ctx = context.WithValue(ctx, auth.UserDataKeyRole, "user")
+
+	own := &studytopic.StudyTopic{ID: uuid.New(), UserID: userID}
+	foreign := &studytopic.StudyTopic{ID: uuid.New(), UserID: uuid.New()}
+	topics := &fakeTopicRepo{topics: map[uuid.UUID]*studytopic.StudyTopic{own.ID: own, foreign.ID: foreign}}
+	repo := &fakeEntryRepo{entries: map[uuid.UUID]*timetracking.TimeEntry{}}
+	svc := timetracking.NewService(repo, &fakeTaskRepo{}, nil, topics, &fakeUserRepo{})
+
+	t.Run("OwnTopic", func(t *testing.T) {
+		if _, err := svc.StudyTopicTotals(ctx, own.ID.String()); err != nil {
+			t.Errorf("Erro inesperado: %v", err)
+		}
+	})
+
+	t.Run("MissingTopic", func(t *testing.T) {
+		if _, err := svc.StudyTopicTotals(ctx, uuid.NewString()); !errors.Is(err, timetracking.ErrStudyTopicNotFound) {
+			t.Errorf("Tópico inexistente deveria falhar com não encontrado, erro: %v", err)
+		}
+	})
+
+	t.Run("OtherUsersTopic", func(t *testing.T) {
+		if _, err := svc.StudyTopicTotals(ctx, foreign.ID.String()); !errors.Is(err, timetracking.ErrStudyTopicNotFound) {
+			t.Errorf("Tópico de outro usuário deveria falhar com não encontrado, erro: %v", err)
+		}
+	})
+}
+
+func TestBuildReport(t *testing.T) {
+	loc := util.DefaultLocation()
+	at := func(day, hour int) time.Time { return time.Date(2025, 3, day, hour, 0, 0, 0, loc) }
+	ended := func(t time.Time) *time.Time { return &t }
+
+	entries := []*timetracking.TimeEntry{
+		// Crosses midnight: 2h on the 3rd, 1h on the 4th.
+		{Kind: timetracking.TIMER, StartedAt: at(3, 22), EndedAt: ended(at(4, 1)), DurationSeconds: 3 * 3600},
+		{Kind: timetracking.POMODORO, StartedAt: at(5, 9), EndedAt: ended(at(5, 9).Add(25 * time.Minute)), DurationSeconds: 25 * 60, WorkMinutes: 25},
+		// Interrupted Pomodoro: counts as time but not as a session.
+		{Kind: timetracking.POMODORO, StartedAt: at(5, 10), EndedAt: ended(at(5, 10).Add(10 * time.Minute)), DurationSeconds: 10 * 60, WorkMinutes: 25},
+		{Kind: timetracking.MANUAL, StartedAt: at(10, 14), EndedAt: ended(at(10, 15)), DurationSeconds: 3600},
+	}
+
+	t.Run("SplitsDays", func(t *testing.T) {
+		report := timetracking.BuildReport(entries, at(3, 0), at(5, 0), timetracking.DAY)
+		if len(report.Buckets) != 3 {
+			t.Fatalf("Esperados 3 dias, recebidos %d", len(report.Buckets))
+		}
+		expected := []int64{2 * 3600, 3600, 35 * 60}
+		for i, seconds := range expected {
+			if report.Buckets[i].Seconds != seconds {
+				t.Errorf("Dia %s: esperado %ds, recebido %ds", report.Buckets[i].Start, seconds, report.Buckets[i].Seconds)
+			}
+		}
+		if report.Buckets[2].Pomodoros != 1 {
+			t.Errorf("Esperado 1 pomodoro concluído, recebido %d", report.Buckets[2].Pomodoros)
+		}
+		if report.TotalSeconds != 3*3600+35*60 {
+			t.Errorf("Total incorreto: %ds", report.TotalSeconds)
+		}
+	})
+
+	t.Run("GroupsWeeksFromMonday", func(t *testing.T) {
+		report := timetracking.BuildReport(entries, at(3, 0), at(16, 0), timetracking.WEEK)
+		if len(report.Buckets) != 2 {
+			t.Fatalf("Esperadas 2 semanas, recebidas %d", len(report.Buckets))
+		}
+		if report.Buckets[0].Start != "2025-03-03" || report.Buckets[1].Start != "2025-03-10" {
+			t.Errorf("Semanas deveriam começar na segunda-feira: %s, %s", report.Buckets[0].Start, report.Buckets[1].Start)
+		}
+		if report.Buckets[1].Seconds != 3600 {
+			t.Errorf("Segunda semana: esperado 3600s, recebido %ds", report.Buckets[1].Seconds)
+		}
+	})
+}
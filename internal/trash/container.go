@@ -0,0 +1,33 @@
+package trash
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	"gorm.io/gorm"
+)
+
+type TrashContainer struct {
+	Handler *Handler
+	Service Service
+}
+
+func NewTrashContainer(db *gorm.DB, taskService task.TaskService, parentTrash task.ParentTrashService) *TrashContainer {
+	service := NewService(NewRepository(db), taskService, parentTrash, retentionFromEnv())
+
+	return &TrashContainer{
+		Handler: NewHandler(service),
+		Service: service,
+	}
+}
+
+// retentionFromEnv reads TRASH_RETENTION_DAYS; zero means the default.
+func retentionFromEnv() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
+	if err != nil || days <= 0 {
+		return 0
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
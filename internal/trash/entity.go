@@ -0,0 +1,70 @@
+package trash
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ItemType names a kind of record that goes to the trash on delete.
+type ItemType string
+
+const (
+	TaskItem         ItemType = "task"
+	ProjectItem      ItemType = "project"
+	StudySubjectItem ItemType = "study-subject"
+	QuizItem         ItemType = "quiz"
+	AnnualGoalItem   ItemType = "annual-goal"
+)
+
+// ItemTypes lists every trashable type, in the order the trash is emptied.
+var ItemTypes = []ItemType{TaskItem, ProjectItem, QuizItem, StudySubjectItem, AnnualGoalItem}
+
+// table is where an item type is stored and the column shown as its title.
+type table struct {
+	name  string
+	title string
+	// where narrows the listing, e.g. to hide subtasks trashed with their
+	// parent: they are restored and purged with it.
+	where string
+	// tasks selects, by the item's id, the tasks trashed along with it.
+	// Their foreign key would only unlink them, so purge deletes them first.
+	tasks string
+}
+
+var tables = map[ItemType]table{
+	TaskItem: {
+		name:  "tasks",
+		title: "name",
+		where: "NOT EXISTS (SELECT 1 FROM tasks p WHERE p.id = tasks.parent_id AND p.deleted_at IS NOT NULL)" +
+			" AND NOT EXISTS (SELECT 1 FROM projects p WHERE p.id = tasks.project_id AND p.deleted_at = tasks.deleted_at)" +
+			" AND NOT EXISTS (SELECT 1 FROM study_topics st JOIN study_subjects s ON s.id = st.subject_id" +
+			" WHERE st.id = tasks.study_topic_id AND s.deleted_at = tasks.deleted_at)",
+	},
+	ProjectItem: {name: "projects", title: "title", tasks: "project_id = ?"},
+	StudySubjectItem: {
+		name:  "study_subjects",
+		title: "name",
+		tasks: "study_topic_id IN (SELECT id FROM study_topics WHERE subject_id = ?)",
+	},
+	QuizItem: {
+		name:  "quizzes",
+		title: "topic",
+		where: "NOT EXISTS (SELECT 1 FROM study_subjects s WHERE s.id = quizzes.subject_id AND s.deleted_at = quizzes.deleted_at)",
+	},
+	AnnualGoalItem: {name: "annual_goals", title: "title"},
+}
+
+func (t ItemType) IsValid() bool {
+	_, ok := tables[t]
+	return ok
+}
+
+// Item is a trashed record of any type.
+type Item struct {
+	Type      ItemType  `json:"type"`
+	ID        uuid.UUID `json:"id"`
+	Title     string    `json:"title"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at" gorm:"-"`
+}
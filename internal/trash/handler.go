@@ -0,0 +1,94 @@
+package trash
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/i18n"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{service: s}
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	items, err := h.service.List(r.Context(), r.URL.Query().Get("type"))
+	if err != nil {
+		if !writeTrashError(w, r, err) {
+			log.WithError(err).Error("Failed to list trash")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, items)
+}
+
+func (h *Handler) Restore(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	if err := h.service.Restore(r.Context(), chi.URLParam(r, "type"), chi.URLParam(r, "id")); err != nil {
+		if !writeTrashError(w, r, err) {
+			log.WithError(err).Error("Failed to restore trash item")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]string{
+		"message": "item restored successfully",
+	})
+}
+
+func (h *Handler) Purge(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	if err := h.service.Purge(r.Context(), chi.URLParam(r, "type"), chi.URLParam(r, "id")); err != nil {
+		if !writeTrashError(w, r, err) {
+			log.WithError(err).Error("Failed to purge trash item")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) Empty(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	purged, err := h.service.Empty(r.Context())
+	if err != nil {
+		if !writeTrashError(w, r, err) {
+			log.WithError(err).Error("Failed to empty trash")
+			i18n.Error(w, r, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]int64{"purged": purged})
+}
+
+func writeTrashError(w http.ResponseWriter, r *http.Request, err error) bool {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrInvalidID):
+		i18n.Error(w, r, "invalid id", http.StatusBadRequest)
+	case errors.Is(err, ErrInvalidItemType):
+		i18n.Error(w, r, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrItemNotFound):
+		i18n.Error(w, r, err.Error(), http.StatusNotFound)
+	default:
+		return false
+	}
+	return true
+}
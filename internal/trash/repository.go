@@ -0,0 +1,133 @@
+package trash
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrNotFound = errors.New("trash item not found")
+
+type Repository interface {
+	// List returns the user's trashed items of the given types, most
+	// recently deleted first.
+	List(userID uuid.UUID, types []ItemType) ([]*Item, error)
+	Restore(t ItemType, id, userID uuid.UUID) error
+	// Purge deletes a trashed item for good, with the tasks trashed along
+	// with it; subtasks, topics, quizzes, questions and the like go with it
+	// through their foreign keys.
+	Purge(t ItemType, id, userID uuid.UUID) error
+	PurgeUser(userID uuid.UUID) (int64, error)
+	PurgeDeletedBefore(cutoff time.Time) (int64, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) List(userID uuid.UUID, types []ItemType) ([]*Item, error) {
+	queries := make([]string, 0, len(types))
+	args := make([]interface{}, 0, len(types))
+	for _, t := range types {
+		tbl := tables[t]
+		query := "SELECT '" + string(t) + "' AS type, id, " + tbl.title + " AS title, deleted_at FROM " + tbl.name +
+			" WHERE user_id = ? AND deleted_at IS NOT NULL"
+		if tbl.where != "" {
+			query += " AND " + tbl.where
+		}
+		queries = append(queries, query)
+		args = append(args, userID)
+	}
+
+	items := make([]*Item, 0)
+	if len(queries) == 0 {
+		return items, nil
+	}
+	err := r.db.Raw(strings.Join(queries, " UNION ALL ")+" ORDER BY deleted_at DESC", args...).Scan(&items).Error
+	return items, err
+}
+
+func (r *repository) Restore(t ItemType, id, userID uuid.UUID) error {
+	result := r.db.Table(tables[t].name).
+		Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userID).
+		UpdateColumn("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *repository) Purge(t ItemType, id, userID uuid.UUID) error {
+	tbl := tables[t]
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if tbl.tasks != "" {
+			err := tx.Exec("DELETE FROM tasks WHERE "+tbl.tasks+" AND user_id = ? AND deleted_at = "+
+				"(SELECT deleted_at FROM "+tbl.name+" WHERE id = ? AND user_id = ?)", id, userID, id, userID).Error
+			if err != nil {
+				return err
+			}
+		}
+		result := tx.Exec("DELETE FROM "+tbl.name+" WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return deleteOrphanedNotes(tx)
+	})
+}
+
+func (r *repository) PurgeUser(userID uuid.UUID) (int64, error) {
+	return r.purge("user_id = ? AND deleted_at IS NOT NULL", userID)
+}
+
+func (r *repository) PurgeDeletedBefore(cutoff time.Time) (int64, error) {
+	return r.purge("deleted_at < ?", cutoff)
+}
+
+func (r *repository) purge(where string, arg interface{}) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, t := range ItemTypes {
+			result := tx.Exec("DELETE FROM "+tables[t].name+" WHERE "+where, arg)
+			if result.Error != nil {
+				return result.Error
+			}
+			purged += result.RowsAffected
+		}
+		return deleteOrphanedNotes(tx)
+	})
+	return purged, err
+}
+
+// notedTables are the tables whose rows can have comments and activity
+// entries. Those point at their entity without a foreign key, so they are
+// removed by hand once the entity, or the project it cascaded from, is gone.
+var notedTables = map[string]string{
+	"task":    "tasks",
+	"project": "projects",
+}
+
+func deleteOrphanedNotes(tx *gorm.DB) error {
+	for entityType, table := range notedTables {
+		for _, notes := range []string{"comments", "activity_log"} {
+			err := tx.Exec("DELETE FROM "+notes+" n WHERE n.entity_type = ? AND NOT EXISTS "+
+				"(SELECT 1 FROM "+table+" e WHERE e.id = n.entity_id)", entityType).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
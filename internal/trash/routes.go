@@ -0,0 +1,16 @@
+package trash
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+func Routes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.List)
+	r.Delete("/", h.Empty)
+	r.Post("/{type}/{id}/restore", h.Restore)
+	r.Delete("/{type}/{id}", h.Purge)
+
+	return r
+}
@@ -0,0 +1,217 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+)
+
+var (
+	ErrUnauthorized    = errors.New("unauthorized")
+	ErrInvalidID       = errors.New("invalid id format")
+	ErrInvalidItemType = errors.New("invalid trash item type")
+	ErrItemNotFound    = ErrNotFound
+)
+
+// DefaultRetention is how long items stay in the trash when
+// TRASH_RETENTION_DAYS is not set.
+const DefaultRetention = 30 * 24 * time.Hour
+
+type Service interface {
+	// List returns the trash, optionally only the items of one type.
+	List(ctx context.Context, itemType string) ([]*Item, error)
+	Restore(ctx context.Context, itemType, id string) error
+	Purge(ctx context.Context, itemType, id string) error
+	// Empty purges everything in the user's trash and returns how many
+	// items were removed.
+	Empty(ctx context.Context) (int64, error)
+	// PurgeExpired purges every item trashed longer than the retention
+	// period. It runs as a scheduled job.
+	PurgeExpired(ctx context.Context) error
+}
+
+type service struct {
+	repo        Repository
+	taskService task.TaskService
+	parentTrash task.ParentTrashService
+	retention   time.Duration
+	now         func() time.Time
+}
+
+func NewService(repo Repository, taskService task.TaskService, parentTrash task.ParentTrashService, retention time.Duration) Service {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &service{
+		repo:        repo,
+		taskService: taskService,
+		parentTrash: parentTrash,
+		retention:   retention,
+		now:         time.Now,
+	}
+}
+
+func (s *service) List(ctx context.Context, itemType string) ([]*Item, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	types := ItemTypes
+	if itemType != "" {
+		t, err := parseItemType(itemType)
+		if err != nil {
+			return nil, err
+		}
+		types = []ItemType{t}
+	}
+
+	items, err := s.repo.List(userID, types)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to list trash")
+		return nil, err
+	}
+	for _, item := range items {
+		item.PurgeAt = item.DeletedAt.Add(s.retention)
+	}
+	return items, nil
+}
+
+// Restore goes through the task service for tasks, which brings back their
+// subtasks and calendar events as well. Projects and study subjects bring
+// back the tasks, and quizzes, trashed with them the same way.
+func (s *service) Restore(ctx context.Context, itemType, id string) error {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	t, err := parseItemType(itemType)
+	if err != nil {
+		return err
+	}
+
+	itemID, err := s.parseUUID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if t == TaskItem {
+		if _, err := s.taskService.RestoreTask(ctx, itemID.String()); err != nil {
+			if errors.Is(err, task.ErrTaskNotFound) {
+				return ErrItemNotFound
+			}
+			return err
+		}
+		return nil
+	}
+
+	switch t {
+	case ProjectItem:
+		err = s.parentTrash.RestoreProject(ctx, itemID, userID)
+	case StudySubjectItem:
+		err = s.parentTrash.RestoreStudySubject(ctx, itemID, userID)
+	default:
+		err = s.repo.Restore(t, itemID, userID)
+	}
+	if errors.Is(err, task.ErrNotFound) {
+		err = ErrNotFound
+	}
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			config.WithContext(ctx).WithError(err).Error("Failed to restore trash item")
+		}
+		return err
+	}
+
+	config.WithContext(ctx).WithField("item_type", t).WithField("item_id", itemID).Info("Item restored from trash")
+	return nil
+}
+
+func (s *service) Purge(ctx context.Context, itemType, id string) error {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	t, err := parseItemType(itemType)
+	if err != nil {
+		return err
+	}
+
+	itemID, err := s.parseUUID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Purge(t, itemID, userID); err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			config.WithContext(ctx).WithError(err).Error("Failed to purge trash item")
+		}
+		return err
+	}
+
+	config.WithContext(ctx).WithField("item_type", t).WithField("item_id", itemID).Info("Item purged from trash")
+	return nil
+}
+
+func (s *service) Empty(ctx context.Context) (int64, error) {
+	userID, err := s.getUserID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	purged, err := s.repo.PurgeUser(userID)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to empty trash")
+		return 0, err
+	}
+
+	config.WithContext(ctx).WithField("user_id", userID).Infof("Trash emptied: %d items purged", purged)
+	return purged, nil
+}
+
+func (s *service) PurgeExpired(ctx context.Context) error {
+	cutoff := s.now().Add(-s.retention)
+	purged, err := s.repo.PurgeDeletedBefore(cutoff)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Failed to purge expired trash")
+		return err
+	}
+
+	config.WithContext(ctx).Infof("Purged %d items trashed before %s", purged, cutoff.Format(time.RFC3339))
+	return nil
+}
+
+// ============= Helper Methods =============
+
+func (s *service) getUserID(ctx context.Context) (uuid.UUID, error) {
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Warn("Unauthorized access attempt")
+		return uuid.Nil, ErrUnauthorized
+	}
+	return uuid.MustParse(claims.UserID), nil
+}
+
+func (s *service) parseUUID(ctx context.Context, id string) (uuid.UUID, error) {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Warnf("Invalid ID: %s", id)
+		return uuid.Nil, ErrInvalidID
+	}
+	return parsedID, nil
+}
+
+func parseItemType(value string) (ItemType, error) {
+	t := ItemType(value)
+	if !t.IsValid() {
+		return "", ErrInvalidItemType
+	}
+	return t, nil
+}
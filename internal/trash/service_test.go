@@ -0,0 +1,168 @@
+package trash_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	"github.com/saulo-duarte/chronos-lambda/internal/trash"
+)
+
+func TestMain(m *testing.M) {
+	config.Init()
+	os.Exit(m.Run())
+}
+
+type fakeRepo struct {
+	trash.Repository
+	items    []*trash.Item
+	restored []uuid.UUID
+	cutoff   time.Time
+}
+
+func (f *fakeRepo) List(userID uuid.UUID, types []trash.ItemType) ([]*trash.Item, error) {
+	var items []*trash.Item
+	for _, item := range f.items {
+		for _, t := range types {
+			if item.Type == t {
+				items = append(items, item)
+			}
+		}
+	}
+	return items, nil
+}
+
+func (f *fakeRepo) Restore(t trash.ItemType, id, userID uuid.UUID) error {
+	for _, item := range f.items {
+		if item.Type == t && item.ID == id {
+			f.restored = append(f.restored, id)
+			return nil
+		}
+	}
+	return trash.ErrNotFound
+}
+
+func (f *fakeRepo) PurgeDeletedBefore(cutoff time.Time) (int64, error) {
+	f.cutoff = cutoff
+	return 0, nil
+}
+
+type fakeTaskService struct {
+	task.TaskService
+	restored []string
+}
+
+func (f *fakeTaskService) RestoreTask(ctx context.Context, id string) (*task.Task, error) {
+	if id == uuid.Nil.String() {
+		return nil, task.ErrTaskNotFound
+	}
+	f.restored = append(f.restored, id)
+	return &task.Task{ID: uuid.MustParse(id)}, nil
+}
+
+type fakeParentTrash struct {
+	task.ParentTrashService
+	projects map[uuid.UUID]bool
+	restored []uuid.UUID
+}
+
+func (f *fakeParentTrash) RestoreProject(ctx context.Context, projectID, userID uuid.UUID) error {
+	if !f.projects[projectID] {
+		return task.ErrNotFound
+	}
+	f.restored = append(f.restored, projectID)
+	return nil
+}
+
+func TestTrash(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.UserDataKeyID, userID.String())
+	ctx = context.WithValue(ctx, auth.UserDataKeyRole, "user")
+
+	deletedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	project := &trash.Item{Type: trash.ProjectItem, ID: uuid.New(), Title: "Projeto", DeletedAt: deletedAt}
+	goal := &trash.Item{Type: trash.AnnualGoalItem, ID: uuid.New(), Title: "Meta", DeletedAt: deletedAt}
+	repo := &fakeRepo{items: []*trash.Item{project, goal}}
+	tasks := &fakeTaskService{}
+	parents := &fakeParentTrash{projects: map[uuid.UUID]bool{project.ID: true}}
+	svc := trash.NewService(repo, tasks, parents, 7*24*time.Hour)
+
+	t.Run("ListFiltersByTypeAndShowsPurgeDate", func(t *testing.T) {
+		items, err := svc.List(ctx, "project")
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if len(items) != 1 || items[0].ID != project.ID {
+			t.Fatalf("Esperado apenas o projeto, recebido %v", items)
+		}
+		if want := deletedAt.AddDate(0, 0, 7); !items[0].PurgeAt.Equal(want) {
+			t.Errorf("Data de exclusão definitiva esperada %s, recebida %s", want, items[0].PurgeAt)
+		}
+
+		if _, err := svc.List(ctx, "folder"); !errors.Is(err, trash.ErrInvalidItemType) {
+			t.Errorf("Tipo desconhecido deveria falhar, erro: %v", err)
+		}
+	})
+
+	t.Run("RestoresTasksThroughTaskService", func(t *testing.T) {
+		id := uuid.New()
+		if err := svc.Restore(ctx, "task", id.String()); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if len(tasks.restored) != 1 || tasks.restored[0] != id.String() {
+			t.Errorf("Tarefa deveria ser restaurada pelo serviço de tarefas: %v", tasks.restored)
+		}
+		if len(repo.restored) != 0 {
+			t.Error("Tarefa não deveria ser restaurada direto no repositório")
+		}
+
+		if err := svc.Restore(ctx, "task", uuid.Nil.String()); !errors.Is(err, trash.ErrItemNotFound) {
+			t.Errorf("Tarefa fora da lixeira deveria falhar, erro: %v", err)
+		}
+	})
+
+	t.Run("RestoresProjectWithItsTasks", func(t *testing.T) {
+		if err := svc.Restore(ctx, "project", project.ID.String()); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if len(parents.restored) != 1 || parents.restored[0] != project.ID {
+			t.Errorf("Projeto deveria ser restaurado com suas tarefas: %v", parents.restored)
+		}
+		if len(repo.restored) != 0 {
+			t.Error("Projeto não deveria ser restaurado direto no repositório, sem as tarefas")
+		}
+
+		if err := svc.Restore(ctx, "project", uuid.New().String()); !errors.Is(err, trash.ErrItemNotFound) {
+			t.Errorf("Projeto fora da lixeira deveria falhar, erro: %v", err)
+		}
+	})
+
+	t.Run("RestoresOtherItems", func(t *testing.T) {
+		if err := svc.Restore(ctx, "annual-goal", goal.ID.String()); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if len(repo.restored) != 1 || repo.restored[0] != goal.ID {
+			t.Errorf("Meta deveria ser restaurada: %v", repo.restored)
+		}
+		if err := svc.Restore(ctx, "quiz", goal.ID.String()); !errors.Is(err, trash.ErrItemNotFound) {
+			t.Errorf("Tipo errado deveria falhar, erro: %v", err)
+		}
+	})
+
+	t.Run("PurgeExpiredUsesRetention", func(t *testing.T) {
+		before := time.Now().Add(-7 * 24 * time.Hour)
+		if err := svc.PurgeExpired(context.Background()); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		after := time.Now().Add(-7 * 24 * time.Hour)
+		if repo.cutoff.Before(before) || repo.cutoff.After(after) {
+			t.Errorf("Corte deveria ser 7 dias atrás, recebido %s", repo.cutoff)
+		}
+	})
+}
@@ -4,30 +4,44 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
 )
 
 type User struct {
-	ID                          uuid.UUID `json:"id" db:"id"`
-	ProviderID                  string    `json:"provider_id" db:"provider_id"`
-	Username                    string    `json:"username" db:"username"`
-	Email                       string    `json:"email" db:"email"`
-	AvatarURL                   string    `json:"avatar_url" db:"avatar_url"`
-	Role                        string    `json:"role" db:"role"`
-	EncryptedGoogleAccessToken  string    `json:"-" db:"encrypted_google_access_token"`
-	EncryptedGoogleRefreshToken string    `json:"-" db:"encrypted_google_refresh_token"`
-	CreatedAt                   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt                   time.Time `json:"updated_at" db:"updated_at"`
+	ID                           uuid.UUID  `json:"id" db:"id"`
+	ProviderID                   string     `json:"provider_id" db:"provider_id"`
+	Username                     string     `json:"username" db:"username"`
+	Email                        string     `json:"email" db:"email"`
+	AvatarURL                    string     `json:"avatar_url" db:"avatar_url"`
+	Role                         string     `json:"role" db:"role"`
+	Timezone                     string     `json:"timezone" db:"timezone"`
+	EncryptedGoogleAccessToken   string     `json:"-" db:"encrypted_google_access_token"`
+	EncryptedGoogleRefreshToken  string     `json:"-" db:"encrypted_google_refresh_token"`
+	GoogleTokenExpiry            *time.Time `json:"-" db:"google_token_expiry"`
+	GoogleCalendarDisconnectedAt *time.Time `json:"-" db:"google_calendar_disconnected_at"`
+	CreatedAt                    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt                    time.Time  `json:"updated_at" db:"updated_at"`
 }
 
+const (
+	CalendarConnected    = "connected"
+	CalendarDisconnected = "disconnected"
+	CalendarNotConnected = "not_connected"
+)
+
 type UserResponse struct {
 	ID        uuid.UUID `json:"id"`
 	Username  string    `json:"username"`
 	Email     string    `json:"email"`
 	AvatarURL string    `json:"avatar_url"`
 	Role      string    `json:"role"`
+	Timezone  string    `json:"timezone"`
 	IsActive  bool      `json:"is_active"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	CalendarStatus         string     `json:"calendar_status"`
+	CalendarDisconnectedAt *time.Time `json:"calendar_disconnected_at,omitempty"`
 }
 
 func (u *User) ToResponse() *UserResponse {
@@ -37,11 +51,33 @@ func (u *User) ToResponse() *UserResponse {
 		Email:     u.Email,
 		AvatarURL: u.AvatarURL,
 		Role:      u.Role,
+		Timezone:  u.Location().String(),
 		CreatedAt: u.CreatedAt,
 		UpdatedAt: u.UpdatedAt,
+
+		CalendarStatus:         u.CalendarStatus(),
+		CalendarDisconnectedAt: u.GoogleCalendarDisconnectedAt,
+	}
+}
+
+// CalendarStatus reports whether task sync can reach the user's Google
+// Calendar. A revoked grant stays "disconnected" until the next login.
+func (u *User) CalendarStatus() string {
+	switch {
+	case u.EncryptedGoogleAccessToken == "":
+		return CalendarNotConnected
+	case u.GoogleCalendarDisconnectedAt != nil:
+		return CalendarDisconnected
+	default:
+		return CalendarConnected
 	}
 }
 
+// Location is the user's time zone, or the default one when unset.
+func (u *User) Location() *time.Location {
+	return util.LoadLocation(u.Timezone)
+}
+
 func (u *User) HasRole(role string) bool {
 	return u.Role == role
 }
@@ -2,12 +2,14 @@ package user
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"os"
 	"time"
 
 	"github.com/saulo-duarte/chronos-lambda/internal/auth"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/i18n"
 )
 
 var FRONTEND_URL = os.Getenv("FRONTEND_URL")
@@ -43,19 +45,19 @@ func (h *Handler) GoogleLogin(w http.ResponseWriter, r *http.Request) {
 	var payload auth.AuthResult
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 		log.WithError(err).Error("Corpo da requisição inválido")
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	if payload.ProviderID == "" || payload.Email == "" {
-		http.Error(w, "providerID and email are required", http.StatusBadRequest)
+		i18n.Error(w, r, "providerID and email are required", http.StatusBadRequest)
 		return
 	}
 
 	user, jwtToken, refreshToken, err := h.service.LoginWithGoogleUser(r.Context(), &payload)
 	if err != nil {
 		log.WithError(err).Error("Falha no login via Google")
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal error", http.StatusInternalServerError)
 		return
 	}
 
@@ -104,25 +106,59 @@ func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {
 	claims, err := auth.GetUserClaimsFromContext(r.Context())
 	if err != nil {
 		log.WithError(err).Error("Falha ao obter claims do contexto")
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal error", http.StatusInternalServerError)
 		return
 	}
 
 	userID := claims.UserID
 
 	if userID == "" {
-		http.Error(w, "userID is required", http.StatusBadRequest)
+		i18n.Error(w, r, "userID is required", http.StatusBadRequest)
 		return
 	}
 	user, err := h.service.GetByID(r.Context(), userID)
 	if err != nil {
 		log.WithError(err).Error("Erro ao buscar usuário")
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		i18n.Error(w, r, "internal error", http.StatusInternalServerError)
 		return
 	}
 	if user == nil {
-		http.Error(w, "user not found", http.StatusNotFound)
+		i18n.Error(w, r, "user not found", http.StatusNotFound)
 		return
 	}
 	config.JSON(w, http.StatusOK, user.ToResponse())
 }
+
+func (h *Handler) UpdateTimezone(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	claims, err := auth.GetUserClaimsFromContext(r.Context())
+	if err != nil {
+		i18n.Error(w, r, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var payload struct {
+		Timezone string `json:"timezone"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		i18n.Error(w, r, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.service.UpdateTimezone(r.Context(), claims.UserID, payload.Timezone)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidTimezone):
+			i18n.Error(w, r, "invalid timezone", http.StatusBadRequest)
+		case errors.Is(err, ErrUserNotFound):
+			i18n.Error(w, r, "user not found", http.StatusNotFound)
+		default:
+			log.WithError(err).Error("Erro ao atualizar fuso horário")
+			i18n.Error(w, r, "internal error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, user.ToResponse())
+}
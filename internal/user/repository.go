@@ -2,6 +2,7 @@ package user
 
 import (
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -13,6 +14,9 @@ type UserRepository interface {
 	GetUserEncryptedGoogleCalendarAccessToken(id string) (string, error)
 	Update(u *User) error
 	Delete(id string) error
+	UpdateGoogleTokens(id, encryptedAccessToken, encryptedRefreshToken string, expiry time.Time) error
+	MarkGoogleCalendarDisconnected(id string, at time.Time) error
+	UpdateTimezone(id, timezone string) error
 }
 
 type userRepository struct {
@@ -67,3 +71,28 @@ func (r *userRepository) Update(u *User) error {
 func (r *userRepository) Delete(id string) error {
 	return r.db.Delete(&User{}, "id = ?", id).Error
 }
+
+// UpdateGoogleTokens stores a refreshed access token. The refresh token is
+// only replaced when Google rotated it.
+func (r *userRepository) UpdateGoogleTokens(id, encryptedAccessToken, encryptedRefreshToken string, expiry time.Time) error {
+	updates := map[string]interface{}{
+		"encrypted_google_access_token": encryptedAccessToken,
+		"google_token_expiry":           expiry,
+	}
+	if encryptedRefreshToken != "" {
+		updates["encrypted_google_refresh_token"] = encryptedRefreshToken
+	}
+	return r.db.Model(&User{}).Where("id = ?", id).UpdateColumns(updates).Error
+}
+
+func (r *userRepository) MarkGoogleCalendarDisconnected(id string, at time.Time) error {
+	return r.db.Model(&User{}).Where("id = ?", id).
+		UpdateColumn("google_calendar_disconnected_at", at).Error
+}
+
+func (r *userRepository) UpdateTimezone(id, timezone string) error {
+	return r.db.Model(&User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"timezone":   timezone,
+		"updated_at": time.Now(),
+	}).Error
+}
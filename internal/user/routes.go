@@ -6,5 +6,6 @@ func Routes(h *Handler) chi.Router {
 	r := chi.NewRouter()
 
 	r.Get("/me", h.GetUser)
+	r.Put("/me/timezone", h.UpdateTimezone)
 	return r
 }
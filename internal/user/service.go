@@ -8,10 +8,12 @@ import (
 	"github.com/google/uuid"
 	"github.com/saulo-duarte/chronos-lambda/internal/auth"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
 )
 
 var (
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserNotFound    = errors.New("user not found")
+	ErrInvalidTimezone = errors.New("invalid timezone")
 )
 
 type UserService interface {
@@ -19,6 +21,7 @@ type UserService interface {
 	Login(ctx context.Context, providerID string) (*User, string, string, error)
 	RefreshToken(ctx context.Context, tokenString string) (string, error)
 	GetByID(ctx context.Context, userID string) (*User, error)
+	UpdateTimezone(ctx context.Context, userID, timezone string) (*User, error)
 }
 
 type userService struct {
@@ -41,6 +44,30 @@ func (s *userService) GetByID(ctx context.Context, userID string) (*User, error)
 	return user, nil
 }
 
+func (s *userService) UpdateTimezone(ctx context.Context, userID, timezone string) (*User, error) {
+	log := config.WithContext(ctx)
+
+	if !util.IsValidTimezone(timezone) {
+		return nil, ErrInvalidTimezone
+	}
+
+	if err := s.repo.UpdateTimezone(userID, timezone); err != nil {
+		log.WithError(err).Error("Falha ao atualizar fuso horário do usuário")
+		return nil, err
+	}
+
+	user, err := s.repo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	log.WithField("user_id", userID).Infof("Fuso horário atualizado para %s", timezone)
+	return user, nil
+}
+
 func (s *userService) LoginWithGoogleUser(ctx context.Context, authResult *auth.AuthResult) (*User, string, string, error) {
 	log := config.WithContext(ctx)
 
@@ -93,7 +120,12 @@ func (s *userService) LoginWithGoogleUser(ctx context.Context, authResult *auth.
 		user.Email = authResult.Email
 		user.AvatarURL = authResult.Picture
 		user.EncryptedGoogleAccessToken = encryptedAccessToken
-		user.EncryptedGoogleRefreshToken = encryptedRefreshToken
+		// Google only sends a refresh token on first consent; keep the stored one otherwise.
+		if encryptedRefreshToken != "" {
+			user.EncryptedGoogleRefreshToken = encryptedRefreshToken
+		}
+		user.GoogleTokenExpiry = nil
+		user.GoogleCalendarDisconnectedAt = nil
 		user.UpdatedAt = time.Now()
 		if err := s.repo.Update(user); err != nil {
 			log.WithError(err).Error("Falha ao atualizar usuário existente")
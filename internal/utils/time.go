@@ -7,11 +7,19 @@ import (
 	"time"
 )
 
+// LocalDateTime is a wall-clock date time as exchanged with the frontend
+// ("2006-01-02T15:04:05", no offset). Values parsed without an offset are
+// floating: they are read in the default location until Localize pins them
+// to the user's time zone.
 type LocalDateTime struct {
 	time.Time
+	floating bool
 }
 
-const layout = "2006-01-02T15:04:05"
+const (
+	layout     = "2006-01-02T15:04:05"
+	dateLayout = "2006-01-02"
+)
 
 var saoPauloLocation *time.Location
 
@@ -23,6 +31,63 @@ func init() {
 	}
 }
 
+func DefaultLocation() *time.Location {
+	return saoPauloLocation
+}
+
+// LoadLocation resolves an IANA time zone name, falling back to the default
+// location for empty or unknown names.
+func LoadLocation(name string) *time.Location {
+	if name == "" {
+		return saoPauloLocation
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return saoPauloLocation
+	}
+	return loc
+}
+
+// IsValidTimezone reports whether name is a known IANA time zone.
+func IsValidTimezone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+// Localize returns the value in loc. A floating value keeps its wall clock
+// (09:00 stays 09:00 in loc); a value with a known instant is converted.
+func (ldt LocalDateTime) Localize(loc *time.Location) LocalDateTime {
+	if ldt.IsZero() {
+		return ldt
+	}
+	if ldt.floating {
+		t := ldt.Time
+		return LocalDateTime{Time: time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)}
+	}
+	return LocalDateTime{Time: ldt.Time.In(loc)}
+}
+
+// LocalizePtr is Localize for optional fields.
+func LocalizePtr(ldt *LocalDateTime, loc *time.Location) *LocalDateTime {
+	if ldt == nil {
+		return nil
+	}
+	localized := ldt.Localize(loc)
+	return &localized
+}
+
+// DateOnly truncates t to midnight of its calendar day in its own location.
+func DateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func FormatDate(t time.Time) string {
+	return t.Format(dateLayout)
+}
+
 func ToTimePtr(ldt *LocalDateTime) *time.Time {
 	if ldt == nil {
 		return nil
@@ -31,24 +96,47 @@ func ToTimePtr(ldt *LocalDateTime) *time.Time {
 	return &t
 }
 
+// ParseLocalDateTime parses "2006-01-02T15:04:05" or a bare "2006-01-02"
+// (midnight) in the default location, as used by query-string filters.
+func ParseLocalDateTime(s string) (LocalDateTime, error) {
+	if t, err := time.ParseInLocation(layout, s, saoPauloLocation); err == nil {
+		return LocalDateTime{Time: t, floating: true}, nil
+	}
+	t, err := time.ParseInLocation(dateLayout, s, saoPauloLocation)
+	if err != nil {
+		return LocalDateTime{}, err
+	}
+	return LocalDateTime{Time: t, floating: true}, nil
+}
+
+// UnmarshalJSON accepts the wall-clock layout, which yields a floating value,
+// or RFC 3339 with an explicit offset.
 func (ldt *LocalDateTime) UnmarshalJSON(b []byte) error {
 	s := strings.Trim(string(b), `"`)
 	if s == "" || s == "null" {
 		return nil
 	}
-	t, err := time.ParseInLocation(layout, s, saoPauloLocation)
+	if t, err := time.ParseInLocation(layout, s, saoPauloLocation); err == nil {
+		ldt.Time = t
+		ldt.floating = true
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
 	if err != nil {
 		return err
 	}
 	ldt.Time = t
+	ldt.floating = false
 	return nil
 }
 
+// MarshalJSON writes the wall clock in the value's own location, so callers
+// Localize before responding.
 func (ldt LocalDateTime) MarshalJSON() ([]byte, error) {
 	if ldt.IsZero() {
 		return []byte(`null`), nil
 	}
-	return []byte(`"` + ldt.In(saoPauloLocation).Format(layout) + `"`), nil
+	return []byte(`"` + ldt.Format(layout) + `"`), nil
 }
 
 func (ldt LocalDateTime) Equal(other LocalDateTime) bool {
@@ -70,7 +158,7 @@ func (ldt *LocalDateTime) Scan(value interface{}) error {
 
 	switch v := value.(type) {
 	case time.Time:
-		ldt.Time = v
+		ldt.Time = v.In(saoPauloLocation)
 		return nil
 	case []byte:
 		parsed, err := time.ParseInLocation(layout, string(v), saoPauloLocation)
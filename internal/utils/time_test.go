@@ -0,0 +1,58 @@
+package util_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	util "github.com/saulo-duarte/chronos-lambda/internal/utils"
+)
+
+func TestLocalDateTimeLocalize(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("tzdata indisponível: %v", err)
+	}
+
+	t.Run("FloatingValueKeepsWallClock", func(t *testing.T) {
+		var ldt util.LocalDateTime
+		if err := json.Unmarshal([]byte(`"2025-03-10T09:00:00"`), &ldt); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+
+		local := ldt.Localize(tokyo)
+		expected := time.Date(2025, 3, 10, 9, 0, 0, 0, tokyo)
+		if !local.Time.Equal(expected) {
+			t.Errorf("Horário deveria ser 09:00 em Tóquio. Esperado: %v, Recebido: %v", expected, local.Time)
+		}
+
+		out, _ := json.Marshal(local)
+		if string(out) != `"2025-03-10T09:00:00"` {
+			t.Errorf("Serialização incorreta: %s", out)
+		}
+	})
+
+	t.Run("OffsetValueIsConverted", func(t *testing.T) {
+		var ldt util.LocalDateTime
+		if err := json.Unmarshal([]byte(`"2025-03-10T00:00:00Z"`), &ldt); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+
+		out, _ := json.Marshal(ldt.Localize(tokyo))
+		if string(out) != `"2025-03-10T09:00:00"` {
+			t.Errorf("Instante deveria ser convertido para o fuso do usuário: %s", out)
+		}
+	})
+}
+
+func TestLoadLocation(t *testing.T) {
+	if util.LoadLocation("") != util.DefaultLocation() {
+		t.Error("Fuso vazio deveria usar o fuso padrão")
+	}
+	if util.LoadLocation("Marte/Olympus") != util.DefaultLocation() {
+		t.Error("Fuso desconhecido deveria usar o fuso padrão")
+	}
+	if util.IsValidTimezone("Marte/Olympus") || util.IsValidTimezone("") {
+		t.Error("Fuso inválido aceito")
+	}
+}
@@ -5,20 +5,23 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	chiadapter "github.com/awslabs/aws-lambda-go-api-proxy/chi"
 	"github.com/go-chi/chi/v5"
 
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
 	"github.com/saulo-duarte/chronos-lambda/internal/container"
+	"github.com/saulo-duarte/chronos-lambda/internal/migrations"
 	"github.com/saulo-duarte/chronos-lambda/internal/router"
 )
 
 var chiLambda *chiadapter.ChiLambdaV2
 var chiRouter *chi.Mux
 
-func init() {
+func setupRouter() *container.Container {
 	c := container.New()
 
 	r := router.New(router.RouterConfig{
@@ -30,11 +33,20 @@ func init() {
 		AIQuizHandler:       c.AIQuizContainer.Handler,
 		QuizHandler:         c.QuizContainer.Handler,
 		AnnualGoalHandler:   c.AnnualGoalContainer.Handler,
+		CalendarHandler:     c.GoogleCalendarContainer.Handler,
+		ICalHandler:         c.ICalContainer.Handler,
+		TimeTrackingHandler: c.TimeTrackingContainer.Handler,
+		TrashHandler:        c.TrashContainer.Handler,
+		TagHandler:          c.TagContainer.Handler,
+		ActivityHandler:     c.ActivityContainer.Handler,
+		CommentHandler:      c.CommentContainer.Handler,
+		AttachmentHandler:   c.AttachmentContainer.Handler,
 	})
 
 	chiRouter = r.(*chi.Mux)
 
 	chiLambda = chiadapter.NewV2(chiRouter)
+	return c
 }
 
 func Handler(ctx context.Context, req events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
@@ -45,15 +57,83 @@ func Handler(ctx context.Context, req events.APIGatewayV2HTTPRequest) (events.AP
 	return resp, err
 }
 
+func runMigrations(command string) {
+	ctx := context.Background()
+	config.Init()
+
+	if err := config.Connect(ctx, os.Getenv("DATABASE_DSN")); err != nil {
+		log.Fatalf("Falha ao conectar ao banco de dados: %v", err)
+	}
+
+	sqlDB, err := config.DB.DB()
+	if err != nil {
+		log.Fatalf("Falha ao obter a instância sql.DB: %v", err)
+	}
+
+	migrator, err := migrations.New(sqlDB)
+	if err != nil {
+		log.Fatalf("Falha ao carregar migrações: %v", err)
+	}
+
+	if err := migrator.Run(ctx, command); err != nil {
+		log.Fatalf("Falha ao executar migrate %s: %v", command, err)
+	}
+}
+
+// runJob runs a background job once per invocation when deployed as a
+// scheduled Lambda, or once and exits when run from the command line.
+func runJob(name string, job func(ctx context.Context) error) {
+	if os.Getenv("AWS_LAMBDA_RUNTIME_API") != "" {
+		lambda.Start(job)
+		return
+	}
+
+	if err := job(context.Background()); err != nil {
+		log.Fatalf("Falha ao executar %s: %v", name, err)
+	}
+}
+
+func outboxInterval() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("CALENDAR_OUTBOX_INTERVAL")); err == nil && d > 0 {
+		return d
+	}
+	return 30 * time.Second
+}
+
 func main() {
 	runMode := os.Getenv("RUN_MODE")
 
-	if runMode == "local" {
+	switch runMode {
+	case "migrate":
+		command := "up"
+		if len(os.Args) > 1 {
+			command = os.Args[1]
+		}
+		runMigrations(command)
+	case "calendar-sync":
+		c := container.New()
+		runJob("calendar-sync", c.TaskContainer.CalendarSync.PullAllUsers)
+	case "calendar-outbox":
+		c := container.New()
+		runJob("calendar-outbox", func(ctx context.Context) error {
+			_, err := c.TaskContainer.CalendarOutbox.Drain(ctx)
+			return err
+		})
+	case "trash-retention":
+		c := container.New()
+		runJob("trash-retention", c.TrashContainer.Service.PurgeExpired)
+	case "attachment-cleanup":
+		c := container.New()
+		runJob("attachment-cleanup", c.AttachmentContainer.Service.PurgeBlobs)
+	case "local":
+		c := setupRouter()
+		go c.TaskContainer.CalendarOutbox.Run(context.Background(), outboxInterval())
 		log.Println("Iniciando servidor HTTP local em :3000")
 		if err := http.ListenAndServe(":3000", chiRouter); err != nil {
 			log.Fatalf("Falha ao iniciar servidor local: %v", err)
 		}
-	} else {
+	default:
+		setupRouter()
 		lambda.Start(Handler)
 	}
 }